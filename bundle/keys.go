@@ -21,6 +21,10 @@ func NewVerificationConfig(keys map[string]*KeyConfig, id, scope string, exclude
 	return v1.NewVerificationConfig(keys, id, scope, exclude)
 }
 
+// X509VerificationConfig represents the configuration used to verify bundle
+// signatures made with a certificate carried in the JWT "x5c" header.
+type X509VerificationConfig = v1.X509VerificationConfig
+
 // SigningConfig represents the key configuration used to generate a signed bundle
 type SigningConfig = v1.SigningConfig
 
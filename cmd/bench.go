@@ -57,7 +57,7 @@ func newBenchmarkEvalParams() benchmarkCommandParams {
 	return benchmarkCommandParams{
 		evalCommandParams: evalCommandParams{
 			outputFormat: formats.Flag(formats.Pretty, formats.JSON, formats.GoBench),
-			target:       util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm}),
+			target:       util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm, compile.TargetPlan}),
 			schema:       &schemaFlags{},
 			capabilities: newCapabilitiesFlag(),
 		},
@@ -20,6 +20,7 @@ import (
 	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/compile"
 	"github.com/open-policy-agent/opa/v1/keys"
+	"github.com/open-policy-agent/opa/v1/loader"
 	"github.com/open-policy-agent/opa/v1/util"
 )
 
@@ -40,6 +41,7 @@ type (
 		debug              bool
 		algorithm          string
 		key                string
+		cert               string
 		scope              string
 		pubKey             string
 		pubKeyID           string
@@ -52,6 +54,7 @@ type (
 		followSymlinks     bool
 		wasmIncludePrint   bool
 		planAddons         []string
+		delta              string
 		stderr             io.Writer
 	}
 	// deferredFileWriter is a wrapper around [*os.File] that defers the creation of the file until
@@ -212,11 +215,28 @@ to the output bundle. It will use the key specified by the --signing-key flag to
 the token in the ".signatures.json" file.
 
 To include additional claims in the payload use the --claims-file flag to provide a JSON file
-containing optional claims.
+containing optional claims. The --signing-cert flag embeds the certificate chain of the signing
+key in the "x5c" header of the JWT.
 
 For more information on the format of the ".signatures.json" file
 see https://www.openpolicyagent.org/docs/latest/management-bundles/#signature-format.
 
+Delta Bundles
+-------------
+
+The 'build' command can output a delta bundle instead of a snapshot bundle with the
+--delta flag. A delta bundle contains the JSON Patch operations turning the data of
+the base bundle at the given path into the data of the bundle being built:
+
+    $ ` + executable + ` build --bundle foo --delta bundle.tar.gz --output delta.tar.gz
+
+Delta bundles can only patch data. If policy files, manifest roots or wasm resolvers
+changed from the base bundle, the 'build' command fails and a snapshot bundle must be
+built instead. Delta bundles can't be signed.
+
+For more information on delta bundles, see
+https://www.openpolicyagent.org/docs/latest/management-bundles/#delta-bundles.
+
 Capabilities
 ------------
 
@@ -284,6 +304,7 @@ against ` + brand + ` v0.22.0:
 	buildCommand.Flags().StringVar(&buildParams.ns, "partial-namespace", "partial", "set the namespace to use for partially evaluated files in an optimized bundle")
 	buildCommand.Flags().BoolVar(&buildParams.followSymlinks, "follow-symlinks", false, "follow symlinks in the input set of paths when building the bundle")
 	buildCommand.Flags().BoolVar(&buildParams.wasmIncludePrint, "wasm-include-print", false, "enable print statements inside of WebAssembly modules compiled by the compiler")
+	buildCommand.Flags().StringVar(&buildParams.delta, "delta", "", "build a delta bundle of the data changes from the base bundle at the given path")
 	buildCommand.Flags().StringArrayVar(&buildParams.planAddons, "plan-addons", []string{}, "include optional extra data in the plan; supported value: unplanned_rules (requires --target=plan)")
 
 	addBundleModeFlag(buildCommand.Flags(), &buildParams.bundleMode, false)
@@ -299,6 +320,7 @@ against ` + brand + ` v0.22.0:
 
 	// bundle signing config
 	addSigningKeyFlag(buildCommand.Flags(), &buildParams.key)
+	addSigningCertFlag(buildCommand.Flags(), &buildParams.cert)
 	addSigningPluginFlag(buildCommand.Flags(), &buildParams.plugin)
 	addClaimsFileFlag(buildCommand.Flags(), &buildParams.claimsFile)
 
@@ -315,7 +337,7 @@ func dobuild(params buildParams, args []string) error {
 		return err
 	}
 
-	bsc, err := buildSigningConfig(params.key, params.algorithm, params.claimsFile, params.plugin)
+	bsc, err := buildSigningConfig(params.key, params.algorithm, params.claimsFile, params.plugin, params.cert)
 	if err != nil {
 		return err
 	}
@@ -326,6 +348,10 @@ func dobuild(params buildParams, args []string) error {
 			return errors.New("enable bundle mode (ie. --bundle) to verify or sign bundle files or directories")
 		}
 
+		if params.delta != "" {
+			return errors.New("enable bundle mode (ie. --bundle) to build delta bundles")
+		}
+
 		for _, arg := range args {
 			stat, err := os.Stat(arg)
 			if err != nil || !stat.IsDir() {
@@ -344,6 +370,17 @@ func dobuild(params buildParams, args []string) error {
 		}
 	}
 
+	var base *bundle.Bundle
+	if params.delta != "" {
+		base, err = loader.NewFileLoader().
+			WithRegoVersion(params.regoVersion()).
+			WithSkipBundleVerification(true).
+			AsBundle(params.delta)
+		if err != nil {
+			return fmt.Errorf("failed to load base bundle: %w", err)
+		}
+	}
+
 	out := &deferredFileWriter{path: params.outputFile}
 	defer out.Close()
 
@@ -364,7 +401,8 @@ func dobuild(params buildParams, args []string) error {
 		WithBundleSigningConfig(bsc).
 		WithPartialNamespace(params.ns).
 		WithFollowSymlinks(params.followSymlinks).
-		WithPlanAddons(params.planAddons)
+		WithPlanAddons(params.planAddons).
+		WithDeltaBase(base)
 
 	compiler = compiler.WithRegoVersion(params.regoVersion())
 
@@ -416,14 +454,14 @@ func buildVerificationConfig(pubKey, pubKeyID, alg, scope string, excludeFiles [
 	return bundle.NewVerificationConfig(confMap, pubKeyID, scope, excludeFiles), nil
 }
 
-func buildSigningConfig(key, alg, claimsFile, plugin string) (*bundle.SigningConfig, error) {
+func buildSigningConfig(key, alg, claimsFile, plugin, certChain string) (*bundle.SigningConfig, error) {
 	if key == "" {
-		if plugin != "" || claimsFile != "" {
+		if plugin != "" || claimsFile != "" || certChain != "" {
 			return nil, errSigningConfigIncomplete
 		}
 		return nil, nil
 	}
-	return bundle.NewSigningConfig(key, alg, claimsFile).WithPlugin(plugin), nil
+	return bundle.NewSigningConfig(key, alg, claimsFile).WithPlugin(plugin).WithCertChain(certChain), nil
 }
 
 func capabilitiesForParamsVersion(params buildParams) func() *ast.Capabilities {
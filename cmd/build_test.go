@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/open-policy-agent/opa/internal/file/archive"
 	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/loader"
 	"github.com/open-policy-agent/opa/v1/util"
 	"github.com/open-policy-agent/opa/v1/util/test"
@@ -352,9 +353,9 @@ func TestBuildVerificationConfigError(t *testing.T) {
 
 func TestBuildSigningConfigError(t *testing.T) {
 	tests := []struct {
-		note                    string
-		key, plugin, claimsFile string
-		expErr                  bool
+		note                          string
+		key, plugin, claimsFile, cert string
+		expErr                        bool
 	}{
 		{
 			note: "key+plugin+claimsFile unset",
@@ -369,10 +370,15 @@ func TestBuildSigningConfigError(t *testing.T) {
 			claimsFile: "claims",
 			expErr:     true,
 		},
+		{
+			note:   "key unset with cert",
+			cert:   "cert.pem",
+			expErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.note, func(t *testing.T) {
-			_, err := buildSigningConfig(tc.key, defaultTokenSigningAlg, tc.claimsFile, tc.plugin)
+			_, err := buildSigningConfig(tc.key, defaultTokenSigningAlg, tc.claimsFile, tc.plugin, tc.cert)
 			switch {
 			case tc.expErr && err == nil:
 				t.Fatal("Expected error but got nil")
@@ -3481,3 +3487,69 @@ func TestBuildPlanJSONOutputBytes(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildDeltaBundle(t *testing.T) {
+	files := map[string]string{
+		"/src/policy.rego": "package a\n\np := data.a.b",
+		"/src/data.json":   `{"a": {"b": 1, "c": 2}}`,
+	}
+
+	test.WithTempFS(files, func(root string) {
+		src := filepath.Join(root, "src")
+		base := filepath.Join(root, "base.tar.gz")
+
+		params := newBuildParams()
+		params.bundleMode = true
+		params.outputFile = base
+		if err := dobuild(params, []string{src}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(src, "data.json"), []byte(`{"a": {"b": 3, "d": 4}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		params = newBuildParams()
+		params.bundleMode = true
+		params.delta = base
+		params.outputFile = filepath.Join(root, "delta.tar.gz")
+		if err := dobuild(params, []string{src}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := loader.NewFileLoader().AsBundle(params.outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Type() != bundle.DeltaBundleType {
+			t.Fatalf("expected delta bundle, got %v", b.Type())
+		}
+		exp := []bundle.PatchOperation{
+			{Op: "remove", Path: "/a/c"},
+			{Op: "upsert", Path: "/a/b", Value: json.Number("3")},
+			{Op: "upsert", Path: "/a/d", Value: json.Number("4")},
+		}
+		if !reflect.DeepEqual(exp, b.Patch.Data) {
+			t.Fatalf("expected patch operations %v, got %v", exp, b.Patch.Data)
+		}
+
+		// policy changes can't be patched
+		if err := os.WriteFile(filepath.Join(src, "policy.rego"), []byte("package a\n\np := data.a.d"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		params.outputFile = filepath.Join(root, "delta2.tar.gz")
+		err = dobuild(params, []string{src})
+		if err == nil || !strings.Contains(err.Error(), "policy files changed") {
+			t.Fatalf("expected policy change error, got %v", err)
+		}
+		if _, err := os.Stat(params.outputFile); !os.IsNotExist(err) {
+			t.Fatalf("expected no output file, got %v", err)
+		}
+
+		params.bundleMode = false
+		err = dobuild(params, []string{src})
+		if err == nil || !strings.Contains(err.Error(), "to build delta bundles") {
+			t.Fatalf("expected bundle mode error, got %v", err)
+		}
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/open-policy-agent/opa/cmd/formats"
 	"github.com/open-policy-agent/opa/cmd/internal/env"
 	pr "github.com/open-policy-agent/opa/internal/presentation"
+	"github.com/open-policy-agent/opa/internal/sarif"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/loader"
@@ -37,7 +38,7 @@ type checkParams struct {
 
 func newCheckParams() checkParams {
 	return checkParams{
-		format:       formats.Flag(formats.Pretty, formats.JSON),
+		format:       formats.Flag(formats.Pretty, formats.JSON, formats.SARIF),
 		capabilities: newCapabilitiesFlag(),
 		schema:       &schemaFlags{},
 	}
@@ -173,6 +174,30 @@ func outputErrors(format string, err error) {
 	}
 }
 
+// addSARIFErrors adds results for the errors in err to run. Results are
+// reported for rules named by the error codes.
+func addSARIFErrors(run *sarif.Run, err error) {
+	for _, e := range pr.NewOutputErrors(err) {
+		run.AddCodeResult(e.Code, sarif.LevelError, e.Message, e.Location)
+	}
+}
+
+// sarifErrors returns a SARIF log of the errors in err, which may be nil,
+// reported by command.
+func sarifErrors(command string, err error) *sarif.Log {
+	log := sarif.New(command)
+	addSARIFErrors(log.Run(), err)
+	return log
+}
+
+// outputSARIF writes a SARIF log of the errors in err, which may be nil, to
+// stdout.
+func outputSARIF(command string, err error) {
+	if err := sarifErrors(command, err).Write(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+}
+
 func initCheck(root *cobra.Command, _ string) {
 	checkParams := newCheckParams()
 
@@ -183,7 +208,11 @@ func initCheck(root *cobra.Command, _ string) {
 	
 If the 'check' command succeeds in parsing and compiling the source file(s), no output
 is produced. If the parsing or compiling fails, 'check' will output the errors
-and exit with a non-zero exit code.`,
+and exit with a non-zero exit code.
+
+With '--format sarif', 'check' always outputs a SARIF 2.1.0 log for code scanning
+tools. Errors are reported for rules named by their error codes, e.g.
+"rego_type_error".`,
 
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
@@ -196,7 +225,12 @@ and exit with a non-zero exit code.`,
 			cmd.SilenceErrors = true
 			cmd.SilenceUsage = true
 
-			if err := checkModules(checkParams, args); err != nil {
+			err := checkModules(checkParams, args)
+			if checkParams.format.String() == formats.SARIF {
+				outputSARIF("opa check", err)
+				return err
+			}
+			if err != nil {
 				outputErrors(checkParams.format.String(), err)
 				return err
 			}
@@ -36,16 +36,20 @@ func Command(rootCommand *cobra.Command, brand string) *cobra.Command {
 	initBuild(rootCommand, brand)
 	initCapabilities(rootCommand, brand)
 	initCheck(rootCommand, brand)
+	initDebug(rootCommand, brand)
 	initDeps(rootCommand, brand)
 	initEval(rootCommand, brand)
 	initExec(rootCommand, brand)
+	initFilter(rootCommand, brand)
 	initFmt(rootCommand, brand)
 	initInspect(rootCommand, brand)
+	initLint(rootCommand, brand)
 	initOracle(rootCommand, brand)
 	initParse(rootCommand, brand)
 	initRefactor(rootCommand, brand)
 	initRun(rootCommand, brand)
 	initSign(rootCommand, brand)
+	initStorage(rootCommand, brand)
 	initTest(rootCommand, brand)
 	initVersion(rootCommand, brand)
 	return rootCommand
@@ -0,0 +1,150 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/dap"
+	internal_logging "github.com/open-policy-agent/opa/internal/logging"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type debugCommandParams struct {
+	address  string
+	logLevel *util.EnumFlag
+}
+
+func newDebugCommandParams() debugCommandParams {
+	return debugCommandParams{
+		logLevel: util.NewEnumFlag("error", []string{"debug", "info", "error"}),
+	}
+}
+
+func initDebug(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newDebugCommandParams()
+
+	debugCommand := &cobra.Command{
+		Use:   "debug",
+		Short: "Serve the Debug Adapter Protocol for debugging Rego",
+		Long: `Serve the Debug Adapter Protocol (DAP) for debugging Rego.
+
+The 'debug' command starts a debug adapter that editors and other DAP clients can
+connect to in order to debug Rego policy evaluation with ` + brand + `. Clients can
+set breakpoints, step through evaluation and inspect stack frames and variables.
+
+By default, the adapter communicates over stdin and stdout, which is what most
+editors expect when they start the debug adapter themselves:
+
+    $ ` + executable + ` debug
+
+Alternatively, the adapter can listen on a TCP address and serve clients that
+connect to it, one session per connection:
+
+    $ ` + executable + ` debug --address localhost:4711
+
+Clients aren't authenticated, and a 'launch' request can read any file the
+adapter has access to, so only loopback addresses are accepted.
+
+Debug sessions are started with a DAP 'launch' request. The following launch
+arguments are supported:
+
+    command              the type of session to launch; only "eval" is supported
+    query                the query to evaluate, e.g. "data.example.allow"
+    input                the input document
+    inputPath            path to a JSON or YAML file with the input document
+    dataPaths            paths to policy and data files or directories
+    bundlePaths          paths to bundles
+    stopOnEntry          stop on the first evaluation step
+    stopOnResult         stop when evaluation has produced its result
+    stopOnFail           stop when an expression fails
+    enablePrint          forward print() output to the client
+    ruleIndexing         enable rule indexing
+    strictBuiltinErrors  treat built-in function errors as fatal
+
+Log messages are written to stderr.
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return errors.New("unexpected arguments")
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			if err := doDebug(ctx, params, os.Stdin, os.Stdout, os.Stderr); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	debugCommand.Flags().StringVarP(&params.address, "address", "a", "", "listen on a loopback TCP address instead of using stdin and stdout")
+	debugCommand.Flags().VarP(params.logLevel, "log-level", "l", "set log level")
+
+	root.AddCommand(debugCommand)
+}
+
+func doDebug(ctx context.Context, params debugCommandParams, stdin io.Reader, stdout, stderr io.Writer) error {
+	level, err := internal_logging.GetLevel(params.logLevel.String())
+	if err != nil {
+		return err
+	}
+
+	logger := logging.New()
+	logger.SetOutput(stderr)
+	logger.SetLevel(level)
+
+	if params.address == "" {
+		return dap.NewAdapter(stdin, stdout, dap.Logger(logger)).Serve(ctx)
+	}
+
+	if err := validateDebugAddress(params.address); err != nil {
+		return err
+	}
+
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", params.address)
+	if err != nil {
+		return err
+	}
+
+	return dap.Serve(ctx, l, logger)
+}
+
+// validateDebugAddress ensures the unauthenticated debug adapter can't be
+// reached from other hosts.
+func validateDebugAddress(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("address %q must be a loopback address", addr)
+}
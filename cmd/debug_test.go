@@ -0,0 +1,143 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/dap"
+	"github.com/open-policy-agent/opa/v1/logging"
+)
+
+func dapMessage(t *testing.T, seq int, command string) string {
+	t.Helper()
+
+	bs, err := json.Marshal(map[string]any{"seq": seq, "type": "request", "command": command})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(bs), bs)
+}
+
+func readDAPMessages(t *testing.T, r io.Reader, n int) []map[string]any {
+	t.Helper()
+
+	br := bufio.NewReader(r)
+	msgs := make([]map[string]any, 0, n)
+	for range n {
+		header, err := textproto.NewReader(br).ReadMIMEHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs := make([]byte, length)
+		if _, err := io.ReadFull(br, bs); err != nil {
+			t.Fatal(err)
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(bs, &msg); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestDebugStdio(t *testing.T) {
+	t.Parallel()
+
+	stdin := strings.NewReader(dapMessage(t, 1, "initialize") + dapMessage(t, 2, "disconnect"))
+	var stdout, stderr bytes.Buffer
+
+	if err := doDebug(t.Context(), newDebugCommandParams(), stdin, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := readDAPMessages(t, &stdout, 2)
+	for i, command := range []string{"initialize", "disconnect"} {
+		if msgs[i]["command"] != command || msgs[i]["success"] != true {
+			t.Errorf("unexpected response %d: %v", i, msgs[i])
+		}
+	}
+}
+
+func TestDebugTCP(t *testing.T) {
+	t.Parallel()
+
+	var lc net.ListenConfig
+	l, err := lc.Listen(t.Context(), "tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- dap.Serve(ctx, l, logging.NewNoOpLogger())
+	}()
+
+	var d net.Dialer
+	conn, err := d.DialContext(t.Context(), "tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, dapMessage(t, 1, "initialize")); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := readDAPMessages(t, conn, 1)
+	if msgs[0]["command"] != "initialize" || msgs[0]["success"] != true {
+		t.Fatalf("unexpected response: %v", msgs[0])
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("debug server did not stop")
+	}
+}
+
+func TestDebugAddressMustBeLoopback(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []string{":4711", "0.0.0.0:4711", "192.0.2.1:4711", "example.com:4711", "localhost"} {
+		t.Run(addr, func(t *testing.T) {
+			t.Parallel()
+
+			params := newDebugCommandParams()
+			params.address = addr
+			if err := doDebug(t.Context(), params, nil, io.Discard, io.Discard); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	for _, addr := range []string{"localhost:4711", "127.0.0.1:4711", "[::1]:4711"} {
+		if err := validateDebugAddress(addr); err != nil {
+			t.Errorf("expected %q to be accepted, got %v", addr, err)
+		}
+	}
+}
@@ -110,7 +110,7 @@ func newEvalCommandParams() evalCommandParams {
 			formats.Discard,
 		),
 		explain:         newExplainFlag([]string{explainModeOff, explainModeFull, explainModeNotes, explainModeFails, explainModeDebug}),
-		target:          util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm}),
+		target:          util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm, compile.TargetPlan}),
 		count:           1,
 		profileCriteria: newrepeatedStringFlag([]string{}),
 		profileLimit:    newIntFlag(defaultProfileLimit),
@@ -704,8 +704,8 @@ func setupEval(args []string, params evalCommandParams) (*evalContext, error) {
 		tracer = topdown.NewBufferTracer()
 		evalArgs = append(evalArgs, rego.EvalQueryTracer(tracer))
 
-		if params.target.String() == compile.TargetWasm {
-			fmt.Fprintf(os.Stderr, "warning: explain mode \"%v\" is not supported with wasm target\n", params.explain.String())
+		if target := params.target.String(); target != compile.TargetRego {
+			fmt.Fprintf(os.Stderr, "warning: explain mode \"%v\" is not supported with %v target\n", params.explain.String(), target)
 		}
 	}
 
@@ -934,9 +934,15 @@ func generateOptimizedBundle(params evalCommandParams, asBundle bool, filter loa
 		capabilities = ast.CapabilitiesForThisVersion(ast.CapabilitiesRegoVersion(params.regoVersion()))
 	}
 
+	// Plans are created when the query is prepared, from the optimized policies.
+	target := params.target.String()
+	if target == compile.TargetPlan {
+		target = compile.TargetRego
+	}
+
 	compiler := compile.New().
 		WithCapabilities(capabilities).
-		WithTarget(params.target.String()).
+		WithTarget(target).
 		WithAsBundle(asBundle).
 		WithBundleLazyLoadingMode(bundle.HasExtension()).
 		WithOptimizationLevel(params.optimizationLevel).
@@ -0,0 +1,339 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/compile"
+	fileurl "github.com/open-policy-agent/opa/internal/file/url"
+	pr "github.com/open-policy-agent/opa/internal/presentation"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	rego_compile "github.com/open-policy-agent/opa/v1/rego/compile"
+	"github.com/open-policy-agent/opa/v1/runtime/info"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// filterTargets maps the values of --target to the target and dialect of the
+// filter compilation, like the Accept header does for the Compile API.
+var filterTargets = map[string][2]string{
+	"ucast":         {"ucast", "all"},
+	"postgresql":    {"sql", "postgresql"},
+	"mysql":         {"sql", "mysql"},
+	"sqlserver":     {"sql", "sqlserver"},
+	"sqlite":        {"sql", "sqlite"},
+	"mongo":         {"mongo", ""},
+	"elasticsearch": {"elasticsearch", ""},
+}
+
+type filterCommandParams struct {
+	dataPaths       repeatedStringFlag
+	bundlePaths     repeatedStringFlag
+	inputPath       string
+	stdinInput      bool
+	ignore          []string
+	target          *util.EnumFlag
+	mappingsPath    string
+	unknowns        []string
+	maskRule        string
+	disableInlining []string
+	outputFormat    *util.EnumFlag
+	timeout         time.Duration
+	v0Compatible    bool
+	v1Compatible    bool
+}
+
+func (p *filterCommandParams) regoVersion() ast.RegoVersion {
+	if p.v0Compatible {
+		return ast.RegoV0
+	} else if p.v1Compatible {
+		return ast.RegoV1
+	}
+	return ast.DefaultRegoVersion
+}
+
+func newFilterCommandParams() filterCommandParams {
+	return filterCommandParams{
+		target:       util.NewEnumFlag("ucast", []string{"ucast", "postgresql", "mysql", "sqlserver", "sqlite", "mongo", "elasticsearch"}),
+		outputFormat: formats.Flag(formats.JSON, formats.Pretty),
+	}
+}
+
+// filterOutput mirrors the response of the Compile API.
+type filterOutput struct {
+	Errors pr.OutputErrors `json:"errors,omitempty"`
+	Result *filterResult   `json:"result,omitempty"`
+}
+
+type filterResult struct {
+	Query any            `json:"query"`
+	Masks map[string]any `json:"masks,omitempty"`
+}
+
+func initFilter(root *cobra.Command, _ string) {
+	executable := root.Name()
+
+	params := newFilterCommandParams()
+
+	filterCommand := &cobra.Command{
+		Use:   "filter <path>",
+		Short: "Compile a policy into a data filter",
+		Long: `Compile a policy into a data filter and print it.
+
+The 'filter' command partially evaluates the rule at the given slash-separated
+path, and translates the result into a filter for the chosen target, like the
+Compile API (/v1/compile/{path}) does:
+
+    $ ` + executable + ` filter --data policy.rego --input input.json --target postgresql filters/include
+
+Policies and data are loaded with the --data and --bundle flags, like with the
+'eval' command.
+
+If the partially evaluated policy uses constructs that can't be translated for
+the target, each of them is reported as an error, and the command exits with a
+non-zero status. This makes the command suitable for checking filter policies
+in CI.
+
+Unknowns and Masks
+------------------
+
+The unknowns and the mask rule are taken from the rule's 'compile' annotations,
+unless they're set using the --unknowns and --mask-rule flags. Mask rules that
+aren't data references are relative to the package of the filtering rule.
+
+Targets
+-------
+
+Set the target with the --target flag:
+
+    --target=ucast          : output UCAST conditions
+    --target=postgresql     : output a PostgreSQL WHERE clause
+    --target=mysql          : output a MySQL WHERE clause
+    --target=sqlserver      : output a SQL Server WHERE clause
+    --target=sqlite         : output a SQLite WHERE clause
+    --target=mongo          : output a MongoDB query filter
+    --target=elasticsearch  : output an Elasticsearch bool query
+
+Mappings
+--------
+
+The --mappings flag takes a JSON or YAML file with the mappings of the tables
+and columns, in the format used for 'targetSQLTableMappings' in Compile API
+requests:
+
+    {
+      "postgresql": {
+        "fruit": {"$self": "fruit_table", "name": "name_col"}
+      }
+    }
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("specify exactly one filter rule path")
+			}
+			if params.stdinInput && params.inputPath != "" {
+				return errors.New("specify --stdin-input or --input but not both")
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := filter(args[0], params, os.Stdout, os.Stderr); err != nil {
+				if _, ok := err.(regoError); !ok {
+					fmt.Fprintln(os.Stderr, err)
+				}
+				return newExitErrorWrap(2, err)
+			}
+			return nil
+		},
+	}
+
+	filterCommand.Flags().VarP(params.target, "target", "t", "set the target of the filter")
+	filterCommand.Flags().StringVarP(&params.mappingsPath, "mappings", "", "", "set path of the table and column mappings file")
+	filterCommand.Flags().StringVarP(&params.maskRule, "mask-rule", "", "", "set the rule to evaluate for column masks")
+	filterCommand.Flags().StringArrayVarP(&params.disableInlining, "disable-inlining", "", []string{}, "set paths of documents to exclude from inlining")
+	filterCommand.Flags().DurationVar(&params.timeout, "timeout", 0, "set filter compilation timeout (default unlimited)")
+
+	addUnknownsFlag(filterCommand.Flags(), &params.unknowns, nil)
+	addDataFlag(filterCommand.Flags(), &params.dataPaths)
+	addBundleFlag(filterCommand.Flags(), &params.bundlePaths)
+	addInputFlag(filterCommand.Flags(), &params.inputPath)
+	addInputStdinFlag(filterCommand.Flags(), &params.stdinInput)
+	addIgnoreFlag(filterCommand.Flags(), &params.ignore)
+	addOutputFormat(filterCommand.Flags(), params.outputFormat)
+	addV0CompatibleFlag(filterCommand.Flags(), &params.v0Compatible, false)
+	addV1CompatibleFlag(filterCommand.Flags(), &params.v1Compatible, false)
+
+	root.AddCommand(filterCommand)
+}
+
+func filter(path string, params filterCommandParams, w io.Writer, stderr io.Writer) error {
+	ctx := context.Background()
+	if params.timeout != 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, params.timeout)
+		defer cancel()
+	}
+
+	result, err := compileFilter(ctx, path, params, stderr)
+	out := filterOutput{Errors: pr.NewOutputErrors(err)}
+	if err == nil && result.Query != nil {
+		out.Result = &filterResult{Query: result.Query, Masks: result.Masks}
+	}
+
+	switch params.outputFormat.String() {
+	case formats.Pretty:
+		if err := prettyFilter(w, stderr, out); err != nil {
+			return err
+		}
+	default:
+		if err := pr.JSON(w, out); err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		// The errors have been printed above, don't print them twice.
+		return regoError{wrapped: err}
+	}
+	return nil
+}
+
+func compileFilter(ctx context.Context, path string, params filterCommandParams, stderr io.Writer) (rego_compile.Filter, error) {
+	t := filterTargets[params.target.String()]
+	target, dialect := t[0], t[1]
+
+	runtimeInfo, err := info.New()
+	if err != nil {
+		return rego_compile.Filter{}, err
+	}
+
+	regoArgs := []func(*rego.Rego){
+		rego.Runtime(runtimeInfo),
+		rego.SetRegoVersion(params.regoVersion()),
+		rego.SkipBundleVerification(true),
+		rego.DisableInlining(params.disableInlining),
+		rego.EnablePrintStatements(true),
+		rego.PrintHook(topdown.NewPrintHook(stderr)),
+	}
+
+	if len(params.dataPaths.v) > 0 {
+		regoArgs = append(regoArgs, rego.Load(params.dataPaths.v, ignored(params.ignore).Apply))
+	}
+
+	if params.bundlePaths.isFlagSet() {
+		for _, bundleDir := range params.bundlePaths.v {
+			regoArgs = append(regoArgs, rego.LoadBundle(bundleDir))
+		}
+		regoArgs = append(regoArgs, rego.WithFilter(buildCommandLoaderFilter(true, params.ignore)))
+	}
+
+	var evalArgs []rego.EvalOption
+	inputBytes, err := readInputBytes(evalCommandParams{inputPath: params.inputPath, stdinInput: params.stdinInput})
+	if err != nil {
+		return rego_compile.Filter{}, err
+	}
+	if inputBytes != nil {
+		var input any
+		if err := util.Unmarshal(inputBytes, &input); err != nil {
+			return rego_compile.Filter{}, fmt.Errorf("unable to parse input: %s", err.Error())
+		}
+		inputValue, err := ast.InterfaceToValue(input)
+		if err != nil {
+			return rego_compile.Filter{}, fmt.Errorf("unable to process input: %s", err.Error())
+		}
+		evalArgs = append(evalArgs, rego.EvalParsedInput(inputValue))
+	}
+
+	var mappings map[string]any
+	if params.mappingsPath != "" {
+		p, err := fileurl.Clean(params.mappingsPath)
+		if err != nil {
+			return rego_compile.Filter{}, err
+		}
+		bs, err := os.ReadFile(p)
+		if err != nil {
+			return rego_compile.Filter{}, err
+		}
+		if err := util.Unmarshal(bs, &mappings); err != nil {
+			return rego_compile.Filter{}, fmt.Errorf("unable to parse mappings: %s", err.Error())
+		}
+	}
+
+	unknowns := make([]*ast.Term, len(params.unknowns))
+	for i := range params.unknowns {
+		unknowns[i], err = ast.ParseTerm(params.unknowns[i])
+		if err != nil {
+			return rego_compile.Filter{}, err
+		}
+		if _, ok := unknowns[i].Value.(ast.Ref); !ok {
+			return rego_compile.Filter{}, errIllegalUnknownsArg
+		}
+	}
+
+	maskRule, err := compile.ParseMaskRule(path, params.maskRule)
+	if err != nil {
+		return rego_compile.Filter{}, err
+	}
+
+	prepared, err := rego_compile.New(
+		rego_compile.Target(target, dialect),
+		rego_compile.Path(path),
+		rego_compile.ParsedUnknowns(unknowns...),
+		rego_compile.MaskRule(maskRule),
+		rego_compile.Mappings(mappings),
+		rego_compile.Rego(regoArgs...),
+	).Prepare(ctx)
+	if err != nil {
+		return rego_compile.Filter{}, err
+	}
+
+	fs, err := prepared.Compile(ctx, evalArgs...)
+	if err != nil {
+		return rego_compile.Filter{}, err
+	}
+	return fs.For(target, dialect), nil
+}
+
+// prettyFilter prints SQL WHERE clauses as they are, and other filters as
+// JSON. If the policy doesn't allow anything, "undefined" is printed, like
+// for undefined results of 'eval'.
+func prettyFilter(w io.Writer, errW io.Writer, out filterOutput) error {
+	if out.Errors != nil {
+		_, err := fmt.Fprintln(errW, out.Errors)
+		return err
+	}
+	if out.Result == nil {
+		_, err := fmt.Fprintln(w, "undefined")
+		return err
+	}
+	if s, ok := out.Result.Query.(string); ok {
+		if _, err := fmt.Fprintln(w, s); err != nil {
+			return err
+		}
+	} else if err := pr.JSON(w, out.Result.Query); err != nil {
+		return err
+	}
+	if len(out.Result.Masks) > 0 {
+		if _, err := fmt.Fprintln(w, "\nmasks:"); err != nil {
+			return err
+		}
+		return pr.JSON(w, out.Result.Masks)
+	}
+	return nil
+}
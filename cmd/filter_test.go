@@ -0,0 +1,201 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/v1/util/test"
+)
+
+func TestFilter(t *testing.T) {
+	files := map[string]string{
+		"filters.rego": `package filters
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+#   mask_rule: masks
+include if input.fruits.name in input.names
+include if input.fruits.colour == "red"
+
+masks.fruits.supplier.replace.value := "***"
+`,
+		"input.json":    `{"names": ["apple", "banana"]}`,
+		"mappings.json": `{"postgresql": {"fruits": {"$self": "fruit_table"}}}`,
+	}
+
+	tests := []struct {
+		note   string
+		target string
+		format string
+		exp    string
+	}{
+		{
+			note:   "postgresql, json",
+			target: "postgresql",
+			format: formats.JSON,
+			exp: `{
+  "result": {
+    "query": "WHERE (fruit_table.name IN (E'apple', E'banana') OR fruit_table.colour = E'red')",
+    "masks": {
+      "fruits": {
+        "supplier": {
+          "replace": {
+            "value": "***"
+          }
+        }
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			note:   "mysql, pretty",
+			target: "mysql",
+			format: formats.Pretty,
+			exp: `WHERE (fruits.name IN ('apple', 'banana') OR fruits.colour = 'red')
+
+masks:
+{
+  "fruits": {
+    "supplier": {
+      "replace": {
+        "value": "***"
+      }
+    }
+  }
+}
+`,
+		},
+	}
+
+	test.WithTempFS(files, func(root string) {
+		for _, tc := range tests {
+			t.Run(tc.note, func(t *testing.T) {
+				params := newFilterCommandParams()
+				_ = params.dataPaths.Set(filepath.Join(root, "filters.rego"))
+				params.inputPath = filepath.Join(root, "input.json")
+				params.mappingsPath = filepath.Join(root, "mappings.json")
+				_ = params.target.Set(tc.target)
+				_ = params.outputFormat.Set(tc.format)
+
+				var stdout, stderr bytes.Buffer
+				if err := filter("filters/include", params, &stdout, &stderr); err != nil {
+					t.Fatalf("unexpected error: %v, stderr: %s", err, stderr.String())
+				}
+				if diff := cmp.Diff(tc.exp, stdout.String()); diff != "" {
+					t.Errorf("unexpected output (-want, +got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
+
+func TestFilterUnknownsAndUndefined(t *testing.T) {
+	files := map[string]string{
+		"filters.rego": `package filters
+
+include if {
+	input.user == "alice"
+	input.fruits.owner == input.user
+}
+`,
+		"alice.json": `{"user": "alice"}`,
+		"bob.json":   `{"user": "bob"}`,
+	}
+
+	tests := []struct {
+		note  string
+		input string
+		exp   string
+	}{
+		{
+			note:  "defined",
+			input: "alice.json",
+			exp:   "WHERE fruits.owner = 'alice'\n",
+		},
+		{
+			note:  "nothing allowed",
+			input: "bob.json",
+			exp:   "undefined\n",
+		},
+	}
+
+	test.WithTempFS(files, func(root string) {
+		for _, tc := range tests {
+			t.Run(tc.note, func(t *testing.T) {
+				params := newFilterCommandParams()
+				_ = params.dataPaths.Set(filepath.Join(root, "filters.rego"))
+				params.inputPath = filepath.Join(root, tc.input)
+				params.unknowns = []string{"input.fruits"}
+				_ = params.target.Set("sqlite")
+				_ = params.outputFormat.Set(formats.Pretty)
+
+				var stdout, stderr bytes.Buffer
+				if err := filter("filters/include", params, &stdout, &stderr); err != nil {
+					t.Fatalf("unexpected error: %v, stderr: %s", err, stderr.String())
+				}
+				if diff := cmp.Diff(tc.exp, stdout.String()); diff != "" {
+					t.Errorf("unexpected output (-want, +got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
+
+func TestFilterConstraintErrors(t *testing.T) {
+	files := map[string]string{
+		"filters.rego": `package filters
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+include if is_number(input.fruits.price)
+include if not input.fruits.name == "apple"
+`,
+	}
+
+	test.WithTempFS(files, func(root string) {
+		params := newFilterCommandParams()
+		_ = params.dataPaths.Set(filepath.Join(root, "filters.rego"))
+		_ = params.target.Set("postgresql")
+
+		var stdout, stderr bytes.Buffer
+		err := filter("filters/include", params, &stdout, &stderr)
+		if _, ok := err.(regoError); !ok {
+			t.Fatalf("expected rego error, got %v", err)
+		}
+
+		var out struct {
+			Errors []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Errors) != 1 {
+			t.Fatalf("expected one error, got %v", stdout.String())
+		}
+		if exp, act := "pe_fragment_error", out.Errors[0].Code; exp != act {
+			t.Errorf("expected code %q, got %q", exp, act)
+		}
+		if exp, act := "invalid builtin `is_number`", out.Errors[0].Message; !strings.Contains(act, exp) {
+			t.Errorf("expected message %q, got %q", exp, act)
+		}
+	})
+}
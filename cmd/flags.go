@@ -111,6 +111,10 @@ func addSigningKeyFlag(fs *pflag.FlagSet, key *string) {
 	fs.StringVarP(key, "signing-key", "", "", "set the secret (HMAC) or path of the PEM file containing the private key (RSA and ECDSA)")
 }
 
+func addSigningCertFlag(fs *pflag.FlagSet, cert *string) {
+	fs.StringVarP(cert, "signing-cert", "", "", "set path of the PEM file containing the certificate chain (leaf first) of the signing key to embed in the signature")
+}
+
 func addSigningPluginFlag(fs *pflag.FlagSet, plugin *string) {
 	fs.StringVarP(plugin, "signing-plugin", "", "", "name of the plugin to use for signing/verification (see https://www.openpolicyagent.org/docs/latest/management-bundles/#signature-plugin)")
 }
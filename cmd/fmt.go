@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,10 +16,13 @@ import (
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/spf13/cobra"
 
+	"github.com/open-policy-agent/opa/cmd/formats"
 	"github.com/open-policy-agent/opa/cmd/internal/env"
 	fileurl "github.com/open-policy-agent/opa/internal/file/url"
+	"github.com/open-policy-agent/opa/internal/sarif"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/format"
+	"github.com/open-policy-agent/opa/v1/util"
 )
 
 type fmtCommandParams struct {
@@ -32,11 +36,13 @@ type fmtCommandParams struct {
 	checkResult      bool
 	dropV0Imports    bool
 	capabilitiesFlag *capabilitiesFlag
+	format           *util.EnumFlag
 }
 
 func newFmtCommandParams() *fmtCommandParams {
 	return &fmtCommandParams{
 		capabilitiesFlag: newCapabilitiesFlag(),
+		format:           formats.Flag(formats.Pretty, formats.SARIF),
 	}
 }
 
@@ -106,6 +112,10 @@ func union(a, b []string) []string {
 }
 
 func opaFmt(args []string, fmtParams *fmtCommandParams) int {
+	if fmtParams.format.String() == formats.SARIF {
+		return opaFmtSARIF(args, fmtParams, os.Stdout)
+	}
+
 	if len(args) == 0 {
 		if err := formatStdin(fmtParams, os.Stdin, os.Stdout); err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -158,25 +168,13 @@ func formatFile(params *fmtCommandParams, out io.Writer, filename string, info o
 		return newError("failed to open file: %v", err)
 	}
 
-	opts := format.Opts{
-		RegoVersion:   params.regoVersion(),
-		DropV0Imports: params.dropV0Imports,
-		Capabilities:  params.capabilities(),
-		ParserOptions: params.parserOptions(),
-	}
-
-	formatted, err := format.SourceWithOpts(filename, contents, opts)
+	formatted, err := format.SourceWithOpts(filename, contents, params.formatOpts())
 	if err != nil {
 		return newError("failed to format Rego source file: %v", err)
 	}
 
 	if params.checkResult {
-		popts := ast.ParserOptions{
-			RegoVersion:  params.regoVersion(),
-			Capabilities: params.parserCapabilities(),
-		}
-		_, err := ast.ParseModuleWithOpts("formatted", string(formatted), popts)
-		if err != nil {
+		if err := params.checkFormatted(formatted); err != nil {
 			return newError("%s was successfully formatted, but the result is invalid: %v\n\nTo inspect the formatted Rego, you can turn off this check with --check-result=false.", filename, err)
 		}
 	}
@@ -233,6 +231,92 @@ func formatFile(params *fmtCommandParams, out io.Writer, filename string, info o
 	return nil
 }
 
+func (p *fmtCommandParams) formatOpts() format.Opts {
+	return format.Opts{
+		RegoVersion:   p.regoVersion(),
+		DropV0Imports: p.dropV0Imports,
+		Capabilities:  p.capabilities(),
+		ParserOptions: p.parserOptions(),
+	}
+}
+
+// checkFormatted asserts that the formatted source can be parsed.
+func (p *fmtCommandParams) checkFormatted(formatted []byte) error {
+	popts := ast.ParserOptions{
+		RegoVersion:  p.regoVersion(),
+		Capabilities: p.parserCapabilities(),
+	}
+	_, err := ast.ParseModuleWithOpts("formatted", string(formatted), popts)
+	return err
+}
+
+// opaFmtSARIF reports the files that would change if formatted, and the files
+// that can't be formatted, as a SARIF log written to w. Files that would change
+// are reported for the rego_format_error rule, as errors if '--fail' is set and
+// as warnings otherwise.
+func opaFmtSARIF(args []string, params *fmtCommandParams, w io.Writer) int {
+	log := sarif.New("opa fmt")
+	run := log.Run()
+
+	level := sarif.LevelWarning
+	if params.fail {
+		level = sarif.LevelError
+	}
+
+	exit := 0
+	for _, filename := range args {
+		filename, err := fileurl.Clean(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		err = filepath.Walk(filename, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(path) != ".rego" {
+				return err
+			}
+
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			formatted, err := format.SourceWithOpts(path, contents, params.formatOpts())
+			if err != nil {
+				addSARIFErrors(run, err)
+				exit = 2
+				return nil
+			}
+
+			if params.checkResult {
+				if err := params.checkFormatted(formatted); err != nil {
+					run.AddCodeResult(ast.FormatErr, sarif.LevelError, fmt.Sprintf("File was successfully formatted, but the result is invalid: %v", err), &ast.Location{File: path})
+					exit = 2
+					return nil
+				}
+			}
+
+			if !bytes.Equal(contents, formatted) {
+				run.AddCodeResult(ast.FormatErr, level, "File would be reformatted by 'opa fmt'", &ast.Location{File: path})
+				if params.fail {
+					exit = 2
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if err := log.Write(w); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return exit
+}
+
 func formatStdin(params *fmtCommandParams, r io.Reader, w io.Writer) error {
 	contents, err := io.ReadAll(r)
 	if err != nil {
@@ -302,6 +386,10 @@ to stdout from the 'fmt' command.
 If the '--fail' option is supplied, the 'fmt' command will return a non zero exit
 code if a file would be reformatted.
 
+If the '--format sarif' option is supplied together with '-l' or '--fail', the
+'fmt' command will output a SARIF 2.1.0 log of the files that would change and
+the files that can't be formatted, for code scanning tools.
+
 The 'fmt' command can be run in several compatibility modes for consuming and outputting
 different Rego versions:
 
@@ -320,7 +408,18 @@ different Rego versions:
   * v1 Rego is formatted to be compatible with v0 AND v1
   * v0 Rego is rejected
 `,
-		PreRunE: func(cmd *cobra.Command, _ []string) error {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if fmtParams.format.String() == formats.SARIF {
+				if !fmtParams.list && !fmtParams.fail {
+					return errors.New("the sarif format requires the --list or --fail flag")
+				}
+				if fmtParams.overwrite || fmtParams.diff {
+					return errors.New("the sarif format can't be used with the --write or --diff flags")
+				}
+				if len(args) == 0 {
+					return errors.New("the sarif format requires file paths")
+				}
+			}
 			return env.CmdFlags.CheckEnvironmentVariables(cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -344,6 +443,7 @@ different Rego versions:
 	formatCommand.Flags().BoolVar(&fmtParams.checkResult, "check-result", true, "assert that the formatted code is valid and can be successfully parsed")
 	formatCommand.Flags().BoolVar(&fmtParams.dropV0Imports, "drop-v0-imports", false, "drop v0 imports from the formatted code, such as 'rego.v1' and 'future.keywords'")
 	addCapabilitiesFlag(formatCommand.Flags(), fmtParams.capabilitiesFlag)
+	addOutputFormat(formatCommand.Flags(), fmtParams.format)
 
 	root.AddCommand(formatCommand)
 }
@@ -19,6 +19,7 @@ const (
 	Source       option = "source"
 	Raw          option = "raw"
 	Discard      option = "discard"
+	SARIF        option = "sarif"
 	SortNone     option = "none"
 	SortDuration option = "duration"
 )
@@ -0,0 +1,276 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/lint"
+	pr "github.com/open-policy-agent/opa/internal/presentation"
+	"github.com/open-policy-agent/opa/internal/sarif"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type lintParams struct {
+	format       *util.EnumFlag
+	rules        []string
+	noBuiltins   bool
+	enable       []string
+	disable      []string
+	ignore       []string
+	listRules    bool
+	v0Compatible bool
+}
+
+func newLintParams() lintParams {
+	return lintParams{
+		format: formats.Flag(formats.Pretty, formats.JSON, formats.SARIF),
+	}
+}
+
+func (p *lintParams) regoVersion() ast.RegoVersion {
+	if p.v0Compatible {
+		return ast.RegoV0
+	}
+	return ast.DefaultRegoVersion
+}
+
+// opaLint lints the modules at paths and writes the report to w. It returns
+// exit code 1 if a violation at error level was found.
+func opaLint(ctx context.Context, paths []string, params lintParams, w io.Writer) (int, error) {
+	linter := lint.New().
+		WithEnabledRules(params.enable).
+		WithDisabledRules(params.disable).
+		WithoutBuiltinRules(params.noBuiltins)
+
+	if len(params.rules) > 0 {
+		result, err := loader.NewFileLoader().
+			WithRegoVersion(ast.RegoV1).
+			WithProcessAnnotation(true).
+			Filtered(params.rules, ignoredOnlyRego(params.ignore).Apply)
+		if err != nil {
+			return 0, err
+		}
+		linter = linter.WithRuleModules(result.ParsedModules())
+	}
+
+	if params.listRules {
+		rules, err := linter.Rules()
+		if err != nil {
+			return 0, err
+		}
+		return 0, writeLintRules(w, params.format.String(), rules)
+	}
+
+	result, err := loader.NewFileLoader().
+		WithRegoVersion(params.regoVersion()).
+		WithProcessAnnotation(true).
+		Filtered(paths, ignoredOnlyRego(params.ignore).Apply)
+	if err != nil {
+		return 0, err
+	}
+
+	report, err := linter.WithInputModules(result.ParsedModules()).Lint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	switch params.format.String() {
+	case formats.JSON:
+		err = pr.JSON(w, report)
+	case formats.SARIF:
+		err = lintSARIF(report).Write(w)
+	default:
+		err = writeLintPretty(w, report)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if report.Summary.NumErrors > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func writeLintPretty(w io.Writer, report *lint.Report) error {
+	for _, v := range report.Violations {
+		loc := v.Location.String()
+		if v.Location.File == "" {
+			loc = "<aggregate>"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s: %s\n", loc, v.Level, v.Rule, v.Description); err != nil {
+			return err
+		}
+	}
+
+	s := report.Summary
+	if len(report.Violations) > 0 {
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintf(w, "%d %s scanned, %d %s found (%d %s, %d %s)\n",
+		s.FilesScanned, plural(s.FilesScanned, "file"),
+		s.NumViolations, plural(s.NumViolations, "violation"),
+		s.NumErrors, plural(s.NumErrors, "error"),
+		s.NumWarnings, plural(s.NumWarnings, "warning"))
+	return err
+}
+
+func writeLintRules(w io.Writer, format string, rules []*lint.Rule) error {
+	if format == formats.JSON {
+		return pr.JSON(w, rules)
+	}
+
+	for _, r := range rules {
+		state := ""
+		if !r.Enabled {
+			state = " (disabled)"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s%s\n", r.ID(), r.Level, r.Title, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lintSARIF(report *lint.Report) *sarif.Log {
+	log := sarif.New("opa lint")
+	run := log.Run()
+
+	for _, r := range report.Rules {
+		if !r.Enabled {
+			continue
+		}
+		rule := &sarif.ReportingDescriptor{
+			ID:         r.ID(),
+			Name:       r.Name,
+			HelpURI:    r.HelpURL,
+			Properties: map[string]any{"category": r.Category},
+		}
+		if r.Title != "" {
+			rule.ShortDescription = &sarif.Message{Text: r.Title}
+		}
+		if r.Description != "" {
+			rule.FullDescription = &sarif.Message{Text: r.Description}
+		}
+		run.AddRule(rule)
+	}
+
+	for _, v := range report.Violations {
+		var locs []sarif.Location
+		if v.Location.File != "" {
+			locs = append(locs, sarif.NewLocation(v.Location.File, v.Location.Row, v.Location.Col))
+		}
+		run.AddResult(v.Rule, v.Level, v.Description, locs...)
+	}
+
+	return log
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return s
+	}
+	return s + "s"
+}
+
+func initLint(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newLintParams()
+
+	lintCommand := &cobra.Command{
+		Use:   "lint <path> [path [...]]",
+		Short: "Lint Rego source files",
+		Long: `Lint Rego source files.
+
+The 'lint' command checks Rego source files for style issues, likely bugs and
+other problems that aren't compilation errors. Violations are reported in the
+file:row:col: level: rule: description format by default:
+
+    $ ` + executable + ` lint policies/
+    policies/authz.rego:3:1: warning: imports/unused-import: Import users is unused
+
+    1 file scanned, 1 violation found (0 errors, 1 warning)
+
+Lint rules are written in Rego and evaluated against the AST of each module, in
+the format output by '` + executable + ` parse --format json'. Rules are packages
+under data.lint.rules named by category and rule name, and are described by
+their METADATA annotations:
+
+    # METADATA
+    # title: No import of data
+    # description: Import the packages that are used instead.
+    # custom:
+    #   level: warning
+    package lint.rules.imports["no-data-import"]
+
+    report contains {"description": "Import of data", "location": imp.location} if {
+        some imp in input.imports
+        count(imp.path.value) == 1
+    }
+
+A 'report' rule is evaluated for each module, with the module as input. Rules
+that check properties across modules collect entries from each module in an
+'aggregate' rule, and report violations from an 'aggregate_report' rule, which
+is evaluated once with the entries of all modules in input.aggregate.
+
+Custom rules are loaded with the '--rules' flag. Rules can be disabled and
+enabled by ID or category with the '--disable' and '--enable' flags, and
+'--list-rules' lists the available rules.
+
+The 'lint' command exits with a non-zero exit code if a violation at error level
+is found. The SARIF format (--format sarif) is supported by code scanning tools
+of CI systems.
+
+` + brand + ` uses the Rego v1 syntax for rules, and for the linted modules unless
+'--v0-compatible' is set.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !params.listRules {
+				return errors.New("specify at least one file")
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			exit, err := opaLint(cmd.Context(), args, params, os.Stdout)
+			if err != nil {
+				if params.format.String() == formats.SARIF {
+					outputSARIF("opa lint", err)
+				} else {
+					outputErrors(params.format.String(), err)
+				}
+				return err
+			}
+			if exit != 0 {
+				return newExitError(exit)
+			}
+			return nil
+		},
+	}
+
+	lintCommand.Flags().StringSliceVar(&params.rules, "rules", []string{}, "set paths of files or directories with custom lint rules")
+	lintCommand.Flags().BoolVar(&params.noBuiltins, "no-builtin-rules", false, "only evaluate custom lint rules")
+	lintCommand.Flags().StringSliceVar(&params.enable, "enable", []string{}, "enable rules or categories of rules that are disabled by default")
+	lintCommand.Flags().StringSliceVar(&params.disable, "disable", []string{}, "disable rules or categories of rules")
+	lintCommand.Flags().BoolVar(&params.listRules, "list-rules", false, "list the available lint rules")
+	addIgnoreFlag(lintCommand.Flags(), &params.ignore)
+	addOutputFormat(lintCommand.Flags(), params.format)
+	addV0CompatibleFlag(lintCommand.Flags(), &params.v0Compatible, false)
+
+	root.AddCommand(lintCommand)
+}
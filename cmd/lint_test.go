@@ -0,0 +1,176 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/internal/sarif"
+)
+
+func writeLintFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for file, content := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLintPretty(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"policy.rego": "package policy\n\nimport data.users\n\nallowAll := true\n",
+	})
+
+	var buf bytes.Buffer
+	exit, err := opaLint(context.Background(), []string{filepath.Join(root, "policy.rego")}, newLintParams(), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exit != 0 {
+		t.Fatalf("expected exit code 0 for warnings, got %d", exit)
+	}
+
+	file := filepath.Join(root, "policy.rego")
+	exp := file + ":3:1: warning: imports/unresolved-import: Import data.users doesn't refer to a known package\n" +
+		file + ":3:1: warning: imports/unused-import: Import users is unused\n" +
+		file + ":5:1: warning: style/prefer-snake-case: Rule allowAll is not in snake_case\n" +
+		"\n1 file scanned, 3 violations found (0 errors, 3 warnings)\n"
+	if buf.String() != exp {
+		t.Fatalf("expected:\n%s\ngot:\n%s", exp, buf.String())
+	}
+}
+
+func TestLintCustomRulesExitCode(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"policies/policy.rego": "package policy\n\nallow := true\n",
+		"rules/no-allow.rego": `# METADATA
+# title: No unconditional allow
+package lint.rules.custom["no-allow"]
+
+report contains {"description": "allow is always true", "location": rule.location} if {
+	some rule in input.rules
+	rule.head.ref[0].value == "allow"
+	not rule.body[1]
+}
+`,
+	})
+
+	params := newLintParams()
+	params.rules = []string{filepath.Join(root, "rules")}
+	_ = params.format.Set(formats.JSON)
+
+	var buf bytes.Buffer
+	exit, err := opaLint(context.Background(), []string{filepath.Join(root, "policies")}, params, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exit != 1 {
+		t.Fatalf("expected exit code 1 for errors, got %d", exit)
+	}
+
+	var report struct {
+		Violations []struct {
+			Rule  string `json:"rule"`
+			Level string `json:"level"`
+		} `json:"violations"`
+		Summary struct {
+			NumErrors int `json:"num_errors"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].Rule != "custom/no-allow" || report.Violations[0].Level != "error" || report.Summary.NumErrors != 1 {
+		t.Fatalf("unexpected report: %s", buf.String())
+	}
+
+	// disabled rules don't fail
+	params.disable = []string{"custom"}
+	exit, err = opaLint(context.Background(), []string{filepath.Join(root, "policies")}, params, &bytes.Buffer{})
+	if err != nil || exit != 0 {
+		t.Fatalf("expected exit code 0, got %d (err: %v)", exit, err)
+	}
+}
+
+func TestLintSARIF(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"policy.rego": "package policy\n\nx := net.cidr_overlap(\"10.0.0.0/8\", \"10.0.0.1\")\n",
+	})
+
+	params := newLintParams()
+	_ = params.format.Set(formats.SARIF)
+
+	var buf bytes.Buffer
+	exit, err := opaLint(context.Background(), []string{root}, params, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exit != 1 {
+		t.Fatalf("expected exit code 1, got %d", exit)
+	}
+
+	var log sarif.Log
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != sarif.Version || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+
+	run := log.Runs[0]
+	if len(run.Results) != 1 {
+		t.Fatalf("expected 1 result, got %s", buf.String())
+	}
+	result := run.Results[0]
+	if result.RuleID != "bugs/deprecated-builtin" || result.Level != sarif.LevelError || result.RuleIndex == nil ||
+		run.Tool.Driver.Rules[*result.RuleIndex].ID != result.RuleID {
+		t.Fatalf("unexpected result: %s", buf.String())
+	}
+	loc := result.Locations[0].PhysicalLocation
+	if !strings.HasSuffix(loc.ArtifactLocation.URI, "/policy.rego") || loc.Region.StartLine != 3 || loc.Region.StartColumn != 6 {
+		t.Fatalf("unexpected location: %s", buf.String())
+	}
+}
+
+func TestLintListRules(t *testing.T) {
+	t.Parallel()
+
+	params := newLintParams()
+	params.listRules = true
+
+	var buf bytes.Buffer
+	if _, err := opaLint(context.Background(), nil, params, &buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		"bugs/deprecated-builtin: error: Call to deprecated built-in function\n",
+		"docs/missing-metadata: warning: Package without METADATA (disabled)\n",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected %q in:\n%s", exp, buf.String())
+		}
+	}
+}
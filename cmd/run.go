@@ -20,6 +20,7 @@ import (
 
 	"github.com/open-policy-agent/opa/cmd/internal/env"
 	fileurl "github.com/open-policy-agent/opa/internal/file/url"
+	"github.com/open-policy-agent/opa/v1/debug"
 	"github.com/open-policy-agent/opa/v1/runtime"
 	"github.com/open-policy-agent/opa/v1/server"
 	"github.com/open-policy-agent/opa/v1/util"
@@ -55,12 +56,13 @@ type runCmdParams struct {
 	skipKnownSchemaCheck bool
 	excludeVerifyFiles   []string
 	cipherSuites         []string
+	debugTokenFile       string
 }
 
 func newRunParams() runCmdParams {
 	return runCmdParams{
 		rt:             runtime.NewParams(),
-		authentication: util.NewEnumFlag("off", []string{"token", "tls", "off"}),
+		authentication: util.NewEnumFlag("off", []string{"token", "tls", "jwt", "off"}),
 		authorization:  util.NewEnumFlag("off", []string{"basic", "off"}),
 		minTLSVersion:  util.NewEnumFlag("1.2", []string{"1.0", "1.1", "1.2", "1.3"}),
 		logLevel:       util.NewEnumFlag("info", []string{"debug", "info", "error"}),
@@ -225,6 +227,7 @@ See https://godoc.org/crypto/tls#pkg-constants for more information.
 	runCommand.Flags().StringVarP(&cmdParams.rt.HistoryPath, "history", "H", historyPath(brand), "set path of history file")
 	cmdParams.rt.Addrs = runCommand.Flags().StringSliceP("addr", "a", []string{defaultLocalAddr}, "set listening address of the server (e.g., [ip]:<port> for TCP, unix://<path> for UNIX domain socket)")
 	cmdParams.rt.DiagnosticAddrs = runCommand.Flags().StringSlice("diagnostic-addr", []string{}, "set read-only diagnostic listening address of the server for /health and /metric APIs (e.g., [ip]:<port> for TCP, unix://<path> for UNIX domain socket)")
+	cmdParams.rt.GRPCAddrs = runCommand.Flags().StringSlice("grpc-addr", []string{}, "set listening address of the gRPC API (e.g., [ip]:<port> for TCP, unix://<path> for UNIX domain socket)")
 	cmdParams.rt.UnixSocketPerm = runCommand.Flags().String("unix-socket-perm", "755", "specify the permissions for the Unix domain socket if used to listen for incoming connections")
 	runCommand.Flags().BoolVar(&cmdParams.rt.H2CEnabled, "h2c", false, "enable H2C for HTTP listeners")
 	runCommand.Flags().StringVarP(&cmdParams.rt.OutputFormat, "format", "f", "pretty", "set shell output format, i.e, pretty, json")
@@ -247,6 +250,9 @@ See https://godoc.org/crypto/tls#pkg-constants for more information.
 	runCommand.Flags().IntVar(&cmdParams.rt.ShutdownWaitPeriod, "shutdown-wait-period", 0, "set the time (in seconds) that the server will wait before initiating shutdown")
 	runCommand.Flags().BoolVar(&cmdParams.skipKnownSchemaCheck, "skip-known-schema-check", false, "disables type checking on known input schemas")
 	runCommand.Flags().StringSliceVar(&cmdParams.cipherSuites, "tls-cipher-suites", []string{}, "set list of enabled TLS 1.0–1.2 cipher suites (IANA)")
+	runCommand.Flags().StringVar(&cmdParams.rt.DebugAddr, "debug-addr", "", "serve the Debug Adapter Protocol on a TCP address, letting debug clients attach to decisions (requires --debug-token-file, and a loopback address unless --tls-cert-file is set)")
+	runCommand.Flags().StringVar(&cmdParams.debugTokenFile, "debug-token-file", "", "set path of file containing the token debug clients must present to attach")
+	runCommand.Flags().DurationVar(&cmdParams.rt.DebugTimeout, "debug-timeout", debug.DefaultAttachTimeout, "set maximum time a decision can be held by a debug client")
 	addConfigOverrides(runCommand.Flags(), &cmdParams.rt.ConfigOverrides)
 	addConfigOverrideFiles(runCommand.Flags(), &cmdParams.rt.ConfigOverrideFiles)
 	addBundleModeFlag(runCommand.Flags(), &cmdParams.rt.BundleMode, false)
@@ -286,6 +292,7 @@ func initRuntime(ctx context.Context, params runCmdParams, args []string, addrSe
 	authenticationSchemes := map[string]server.AuthenticationScheme{
 		"token": server.AuthenticationToken,
 		"tls":   server.AuthenticationTLS,
+		"jwt":   server.AuthenticationJWT,
 		"off":   server.AuthenticationOff,
 	}
 
@@ -337,6 +344,14 @@ func initRuntime(ctx context.Context, params runCmdParams, args []string, addrSe
 	params.rt.MinTLSVersion = minTLSVersions[params.minTLSVersion.String()]
 	params.rt.Certificate = cert
 
+	if params.debugTokenFile != "" {
+		token, err := readDebugToken(params.debugTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		params.rt.DebugToken = token
+	}
+
 	timestampFormat := params.logTimestampFormat
 	if timestampFormat == "" {
 		timestampFormat = os.Getenv("OPA_LOG_TIMESTAMP_FORMAT")
@@ -465,3 +480,21 @@ func loadCertPool(tlsCACertFile string) (*x509.CertPool, error) {
 	}
 	return pool, nil
 }
+
+func readDebugToken(path string) (string, error) {
+	path, err := fileurl.Clean(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid debug token file path: %w", err)
+	}
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read debug token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(bs))
+	if token == "" {
+		return "", fmt.Errorf("debug token file %s is empty", path)
+	}
+	return token, nil
+}
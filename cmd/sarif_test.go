@@ -0,0 +1,194 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/internal/sarif"
+)
+
+// sarifResults returns the results of a SARIF log as "rule level file:line"
+// strings, with file names relative to root.
+func sarifResults(t *testing.T, root string, bs []byte) []string {
+	t.Helper()
+
+	var log sarif.Log
+	if err := json.Unmarshal(bs, &log); err != nil {
+		t.Fatalf("invalid SARIF log: %v\n%s", err, bs)
+	}
+	if log.Version != sarif.Version || log.Schema != sarif.Schema || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", bs)
+	}
+
+	run := log.Runs[0]
+	results := make([]string, 0, len(run.Results))
+	for _, r := range run.Results {
+		if r.RuleIndex == nil || run.Tool.Driver.Rules[*r.RuleIndex].ID != r.RuleID {
+			t.Fatalf("result without rule: %s", bs)
+		}
+		s := r.RuleID + " " + r.Level
+		for _, loc := range r.Locations {
+			rel, err := filepath.Rel(root, sarifPath(t, loc.PhysicalLocation.ArtifactLocation))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s += " " + filepath.ToSlash(rel)
+			if region := loc.PhysicalLocation.Region; region != nil {
+				s += ":" + strconv.Itoa(region.StartLine)
+			}
+		}
+		results = append(results, s)
+	}
+	return results
+}
+
+// sarifPath returns the file path of an artifact location.
+func sarifPath(t *testing.T, loc sarif.ArtifactLocation) string {
+	t.Helper()
+
+	u, err := url.Parse(loc.URI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case loc.URIBaseID == sarif.SrcRoot && u.Scheme == "":
+		p, err := filepath.Abs(filepath.FromSlash(u.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	case loc.URIBaseID == "" && u.Scheme == "file":
+		if runtime.GOOS == "windows" {
+			return filepath.FromSlash(strings.TrimPrefix(u.Path, "/"))
+		}
+		return u.Path
+	}
+	t.Fatalf("unexpected artifact location: %+v", loc)
+	return ""
+}
+
+func TestCheckSARIF(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"a.rego": "package a\n\nx := 1 + \"foo\"\n",
+		"b.rego": "package b\n\nx := upper(1)\n",
+	})
+
+	params := newCheckParams()
+	_ = params.format.Set(formats.SARIF)
+
+	err := checkModules(params, []string{root})
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+
+	var buf bytes.Buffer
+	if err := sarifErrors("opa check", err).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		"rego_type_error error a.rego:3",
+		"rego_type_error error b.rego:3",
+	}
+	if act := sarifResults(t, root, buf.Bytes()); !slices.Equal(act, exp) {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+
+	// a log without results is written for valid policies
+	buf.Reset()
+	if err := sarifErrors("opa check", nil).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if act := sarifResults(t, root, buf.Bytes()); len(act) != 0 {
+		t.Fatalf("expected no results, got %v", act)
+	}
+}
+
+func TestTestSARIF(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"policy_test.rego": `package policy_test
+
+test_pass if true
+
+test_fail if {
+	1 == 2
+}
+`,
+	})
+
+	var buf bytes.Buffer
+	params := newTestCommandParams()
+	params.output = &buf
+	params.errOutput = &bytes.Buffer{}
+	params.count = 1
+	_ = params.outputFormat.Set(formats.SARIF)
+
+	if exit := opaTest([]string{root}, params); exit != 2 {
+		t.Fatalf("expected exit code 2, got %d", exit)
+	}
+
+	exp := []string{"rego_test_failure error policy_test.rego:5"}
+	if act := sarifResults(t, root, buf.Bytes()); !slices.Equal(act, exp) {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+}
+
+func TestFmtSARIF(t *testing.T) {
+	t.Parallel()
+
+	root := writeLintFiles(t, map[string]string{
+		"formatted.rego":   "package formatted\n\nallow := true\n",
+		"unformatted.rego": "package unformatted\nallow   :=    true\n",
+		"invalid.rego":     "package invalid\n\nallow := }\n",
+	})
+
+	params := newFmtCommandParams()
+	params.list = true
+	_ = params.format.Set(formats.SARIF)
+
+	var buf bytes.Buffer
+	if exit := opaFmtSARIF([]string{root}, params, &buf); exit != 2 {
+		t.Fatalf("expected exit code 2 for parse error, got %d", exit)
+	}
+
+	exp := []string{
+		"rego_parse_error error invalid.rego:3",
+		"rego_parse_error error invalid.rego:3",
+		"rego_format_error warning unformatted.rego",
+	}
+	if act := sarifResults(t, root, buf.Bytes()); !slices.Equal(act, exp) {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+
+	// unformatted files are errors with --fail
+	params.fail = true
+	buf.Reset()
+	if exit := opaFmtSARIF([]string{filepath.Join(root, "unformatted.rego")}, params, &buf); exit != 2 {
+		t.Fatalf("expected exit code 2, got %d", exit)
+	}
+	exp = []string{"rego_format_error error unformatted.rego"}
+	if act := sarifResults(t, root, buf.Bytes()); !slices.Equal(act, exp) {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+
+	buf.Reset()
+	if exit := opaFmtSARIF([]string{filepath.Join(root, "formatted.rego")}, params, &buf); exit != 0 {
+		t.Fatalf("expected exit code 0, got %d", exit)
+	}
+}
@@ -26,6 +26,7 @@ import (
 type signCmdParams struct {
 	algorithm      string
 	key            string
+	cert           string
 	claimsFile     string
 	outputFilePath string
 	bundleMode     bool
@@ -130,6 +131,11 @@ exists in the actual bundle. The file content is hashed using SHA256.
 To include additional claims in the payload use the --claims-file flag to provide
 a JSON file containing optional claims.
 
+To embed the certificate chain of the signing key in the "x5c" header of the JWT use
+the --signing-cert flag to provide a PEM file containing the chain, leaf certificate
+first. Bundles signed this way can be verified against a trusted CA instead of a
+configured public key.
+
 For more information on the format of the ".signatures.json" file see
 https://www.openpolicyagent.org/docs/latest/management-bundles/#signature-format.
 `,
@@ -156,6 +162,7 @@ https://www.openpolicyagent.org/docs/latest/management-bundles/#signature-format
 
 	// bundle signing config
 	addSigningKeyFlag(signCommand.Flags(), &cmdParams.key)
+	addSigningCertFlag(signCommand.Flags(), &cmdParams.cert)
 	addClaimsFileFlag(signCommand.Flags(), &cmdParams.claimsFile)
 	addSigningAlgFlag(signCommand.Flags(), &cmdParams.algorithm, defaultTokenSigningAlg)
 	addSigningPluginFlag(signCommand.Flags(), &cmdParams.plugin)
@@ -181,7 +188,7 @@ func doSign(args []string, params signCmdParams) error {
 		return err
 	}
 
-	signingConfig, err := buildSigningConfig(params.key, params.algorithm, params.claimsFile, params.plugin)
+	signingConfig, err := buildSigningConfig(params.key, params.algorithm, params.claimsFile, params.plugin, params.cert)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,282 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/config"
+	pr "github.com/open-policy-agent/opa/internal/presentation"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/disk"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type storageCommandParams struct {
+	configFile          string
+	configOverrides     []string
+	configOverrideFiles []string
+	outputFormat        *util.EnumFlag
+}
+
+func newStorageCommandParams() storageCommandParams {
+	return storageCommandParams{
+		outputFormat: formats.Flag(formats.Pretty, formats.JSON),
+	}
+}
+
+func initStorage(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newStorageCommandParams()
+
+	storageCommand := &cobra.Command{
+		Use:   "storage",
+		Short: "Back up and restore the " + brand + " disk store",
+		Long: `Back up and restore the ` + brand + ` disk store.
+
+The 'storage' commands operate on the disk store configured in the 'storage.disk'
+section of an ` + brand + ` configuration file. Backups contain the data and policies of
+the store and can be restored into stores with different partitions, e.g. to
+bootstrap new instances without downloading all bundles again.
+
+The disk store can only be opened by one process at a time, so 'backup' and
+'restore' must not be run against the directory of a running ` + brand + ` instance.
+Running instances are backed up through the GET /v1/storage/backup API instead,
+which writes backups in the same format.
+`,
+	}
+
+	backupCommand := &cobra.Command{
+		Use:   "backup <path>",
+		Short: "Write a snapshot of the disk store to a file",
+		Long: `Write a snapshot of the disk store to a file.
+
+The 'backup' command writes a consistent snapshot of the data and policies in
+the disk store to a gzip-compressed file:
+
+    $ ` + executable + ` storage backup --config-file config.yaml snapshot.gz
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStorageParams(&params, args); err != nil {
+				return err
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := doStorageBackup(context.Background(), params, args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	restoreCommand := &cobra.Command{
+		Use:   "restore <path>",
+		Short: "Replace the contents of the disk store with a snapshot",
+		Long: `Replace the contents of the disk store with a snapshot.
+
+The 'restore' command replaces the data and policies in the disk store with a
+snapshot written by 'backup'. The store's directory is created if it doesn't
+exist and 'storage.disk.auto_create' is enabled. If restoring fails, the store
+is left unchanged.
+
+    $ ` + executable + ` storage restore --config-file config.yaml snapshot.gz
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStorageParams(&params, args); err != nil {
+				return err
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := doStorageRestore(context.Background(), params, args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	inspectCommand := &cobra.Command{
+		Use:   "inspect <path>",
+		Short: "Summarize the contents of a snapshot",
+		Long: `Summarize the contents of a snapshot.
+
+The 'inspect' command reads a snapshot written by 'backup', checks that it is
+complete, and lists the policies and top-level data keys it contains:
+
+    $ ` + executable + ` storage inspect snapshot.gz
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("specify exactly one snapshot file")
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := doStorageInspect(params, args[0], os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	for _, c := range []*cobra.Command{backupCommand, restoreCommand} {
+		addConfigFileFlag(c.Flags(), &params.configFile)
+		addConfigOverrides(c.Flags(), &params.configOverrides)
+		addConfigOverrideFiles(c.Flags(), &params.configOverrideFiles)
+	}
+	addOutputFormat(inspectCommand.Flags(), params.outputFormat)
+
+	storageCommand.AddCommand(backupCommand, restoreCommand, inspectCommand)
+	root.AddCommand(storageCommand)
+}
+
+func validateStorageParams(p *storageCommandParams, args []string) error {
+	if len(args) != 1 {
+		return errors.New("specify exactly one snapshot file")
+	}
+	if p.configFile == "" && len(p.configOverrides) == 0 && len(p.configOverrideFiles) == 0 {
+		return errors.New("specify the disk store configuration with --config-file")
+	}
+	return nil
+}
+
+// openDiskStore opens the disk store configured by the command's config file.
+func openDiskStore(ctx context.Context, params storageCommandParams) (*disk.Store, error) {
+	raw, err := config.Load(params.configFile, params.configOverrides, params.configOverrideFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := disk.OptionsFromConfig(raw, "")
+	if err != nil {
+		return nil, fmt.Errorf("parse disk store configuration: %w", err)
+	}
+	if opts == nil {
+		return nil, errors.New("no disk store configured, set storage.disk in the configuration")
+	}
+
+	store, err := disk.New(ctx, logging.NewNoOpLogger(), nil, *opts)
+	if err != nil {
+		return nil, fmt.Errorf("initialize disk store: %w", err)
+	}
+	return store, nil
+}
+
+func doStorageBackup(ctx context.Context, params storageCommandParams, path string) (err error) {
+	store, err := openDiskStore(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer store.Close(ctx)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	return storage.Txn(ctx, store, storage.TransactionParams{}, func(txn storage.Transaction) error {
+		return store.Backup(ctx, txn, f)
+	})
+}
+
+func doStorageRestore(ctx context.Context, params storageCommandParams, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	store, err := openDiskStore(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer store.Close(ctx)
+
+	return storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.Restore(ctx, txn, f)
+	})
+}
+
+func doStorageInspect(params storageCommandParams, path string, out io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := disk.InspectBackup(f)
+	if err != nil {
+		return err
+	}
+
+	if params.outputFormat.String() == formats.JSON {
+		return pr.JSON(out, info)
+	}
+
+	t := generateTableWithKeys(out, "field", "value")
+	lines := [][]string{
+		{"Version", strconv.Itoa(info.Version)},
+		{"Created", info.Created.Format(time.RFC3339)},
+		{"Partitions", strings.Join(info.Partitions, "\n")},
+		{"Policies", strconv.Itoa(len(info.Policies))},
+		{"Data Roots", strings.Join(info.Roots, "\n")},
+		{"Data Keys", strconv.Itoa(info.DataKeys)},
+		{"Data Size", strconv.Itoa(info.DataBytes) + " bytes"},
+	}
+	if err := t.Bulk(lines); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "SNAPSHOT:")
+	if err := t.Render(); err != nil {
+		return err
+	}
+
+	if len(info.Policies) > 0 {
+		t := generateTableWithKeys(out, "id")
+		for _, id := range info.Policies {
+			if err := t.Append([]string{truncateFileName(id)}); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintln(out, "POLICIES:")
+		return t.Render()
+	}
+
+	return nil
+}
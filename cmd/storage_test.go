@@ -0,0 +1,173 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func writeStorageConfig(t *testing.T, dir string, partitions ...string) storageCommandParams {
+	t.Helper()
+
+	bs, err := json.Marshal(map[string]any{
+		"storage": map[string]any{
+			"disk": map[string]any{"directory": dir, "auto_create": true, "partitions": partitions},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, bs, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	params := newStorageCommandParams()
+	params.configFile = path
+	return params
+}
+
+func TestStorageBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+	snapshot := filepath.Join(root, "snapshot.gz")
+	data := util.MustUnmarshalJSON([]byte(`{"users": {"alice": {"admin": true}, "bob": {"admin": false}}}`))
+
+	source := writeStorageConfig(t, filepath.Join(root, "source"), "/users/*")
+	store, err := openDiskStore(ctx, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		if err := store.Write(ctx, txn, storage.AddOp, storage.RootPath, data); err != nil {
+			return err
+		}
+		return store.UpsertPolicy(ctx, txn, "authz.rego", []byte("package authz"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doStorageBackup(ctx, source, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	target := writeStorageConfig(t, filepath.Join(root, "target"))
+	if err := doStorageRestore(ctx, target, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = openDiskStore(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close(ctx)
+
+	txn := storage.NewTransactionOrDie(ctx, store)
+	defer store.Abort(ctx, txn)
+
+	actual, err := store.Read(ctx, txn, storage.RootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, actual) {
+		t.Fatalf("expected data %v, got %v", data, actual)
+	}
+	if bs, err := store.GetPolicy(ctx, txn, "authz.rego"); err != nil || string(bs) != "package authz" {
+		t.Fatalf("expected restored policy, got %q (err: %v)", bs, err)
+	}
+}
+
+func TestStorageBackupErrors(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	snapshot := filepath.Join(root, "snapshot.gz")
+
+	params := newStorageCommandParams()
+	params.configOverrides = []string{"services.acme.url=https://example.com"}
+
+	err := doStorageBackup(context.Background(), params, snapshot)
+	if err == nil || !strings.Contains(err.Error(), "no disk store configured") {
+		t.Fatalf("expected configuration error, got %v", err)
+	}
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Fatalf("expected no snapshot file, got %v", err)
+	}
+
+	if err := validateStorageParams(&storageCommandParams{}, []string{snapshot}); err == nil {
+		t.Fatal("expected error without configuration")
+	}
+}
+
+func TestStorageInspect(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+	snapshot := filepath.Join(root, "snapshot.gz")
+
+	params := writeStorageConfig(t, filepath.Join(root, "store"))
+	store, err := openDiskStore(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		if err := store.Write(ctx, txn, storage.AddOp, storage.MustParsePath("/roles"), map[string]any{"admin": []any{"alice"}}); err != nil {
+			return err
+		}
+		return store.UpsertPolicy(ctx, txn, "authz.rego", []byte("package authz"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close(ctx)
+
+	if err := doStorageBackup(ctx, params, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doStorageInspect(params, snapshot, &buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{"SNAPSHOT:", "roles", "POLICIES:", "authz.rego"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected output to contain %q, got:\n%s", exp, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := params.outputFormat.Set(formats.JSON); err != nil {
+		t.Fatal(err)
+	}
+	if err := doStorageInspect(params, snapshot, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var info map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info["data_keys"] != 1.0 || !reflect.DeepEqual(info["policies"], []any{"authz.rego"}) {
+		t.Fatalf("unexpected inspect output: %v", info)
+	}
+}
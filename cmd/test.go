@@ -73,9 +73,9 @@ type testCommandParams struct {
 func newTestCommandParams() testCommandParams {
 	return testCommandParams{
 		sortTests:    formats.Flag(formats.SortNone, formats.SortDuration),
-		outputFormat: formats.Flag(formats.Pretty, formats.JSON, formats.GoBench),
+		outputFormat: formats.Flag(formats.Pretty, formats.JSON, formats.GoBench, formats.SARIF),
 		explain:      newExplainFlag([]string{explainModeFails, explainModeFull, explainModeNotes, explainModeDebug}),
-		target:       util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm}),
+		target:       util.NewEnumFlag(compile.TargetRego, []string{compile.TargetRego, compile.TargetWasm, compile.TargetPlan}),
 		capabilities: newCapabilitiesFlag(),
 		schema:       &schemaFlags{},
 		output:       os.Stdout,
@@ -452,6 +452,10 @@ func compileAndSetupTests(ctx context.Context, testParams testCommandParams, sto
 				Output: testParams.output,
 				Sort:   testParams.sortTests.String(),
 			}
+		case formats.SARIF:
+			reporter = tester.SARIFReporter{
+				Output: testParams.output,
+			}
 		case formats.GoBench:
 			goBench = true
 			fallthrough
@@ -556,6 +560,10 @@ Example benchmark run:
 
 The optional "gobench" output format conforms to the Go Benchmark Data Format.
 
+The optional "sarif" output format reports failed tests as a SARIF 2.1.0 log for
+code scanning tools. Failed tests are reported for the rule "rego_test_failure",
+and tests that encountered an error for a rule named by the error code.
+
 The --watch flag can be used to monitor policy and data file-system changes. When a change is detected, ` + brand + ` reloads
 the policy and data and then re-runs the tests. Watching individual files (rather than directories) is generally not
 recommended as some updates might cause them to be dropped by OPA.
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/open-policy-agent/opa/internal/levenshtein"
+	"github.com/open-policy-agent/opa/internal/ref"
 	"github.com/open-policy-agent/opa/internal/ucast"
 	"github.com/open-policy-agent/opa/v1/ast"
 )
@@ -56,6 +58,42 @@ func QueriesToSQL(queries []ast.Body, mappings map[string]any, dialect string) (
 	return sql, nil
 }
 
+// QueriesToMongo translates the queries into a MongoDB filter document, and
+// returns it along with the collection it applies to, as
+//
+//	{"collection": <name>, "filter": <filter document>}
+//
+// The collection is omitted if the filter is unconditional.
+func QueriesToMongo(queries []ast.Body, mappings map[string]any) (map[string]any, error) {
+	u := BodiesToUCAST(queries, &Opts{Translations: mappings})
+	if u == nil { // unconditional YES
+		return map[string]any{"filter": map[string]any{}}, nil
+	}
+	collection, filter, err := u.AsMongo()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"collection": collection, "filter": filter}, nil
+}
+
+// QueriesToElasticsearch translates the queries into an Elasticsearch query,
+// and returns it along with the index it applies to, as
+//
+//	{"index": <name>, "query": <bool query>}
+//
+// The index is omitted if the query is unconditional.
+func QueriesToElasticsearch(queries []ast.Body, mappings map[string]any) (map[string]any, error) {
+	u := BodiesToUCAST(queries, &Opts{Translations: mappings})
+	if u == nil { // unconditional YES
+		return map[string]any{"query": map[string]any{"match_all": map[string]any{}}}, nil
+	}
+	index, query, err := u.AsElasticsearch()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"index": index, "query": query}, nil
+}
+
 func ExtractUnknownsFromAnnotations(comp *ast.Compiler, ref ast.Ref) ([]ast.Ref, []*ast.Error) {
 	// find ast.Rule for ref
 	rules := comp.GetRulesExact(ref)
@@ -101,6 +139,27 @@ func ExtractMaskRuleRefFromAnnotations(comp *ast.Compiler, ref ast.Ref) (ast.Ref
 	return maskRuleFromAnnotationsSet(comp.GetAnnotationSet(), rule)
 }
 
+// ParseMaskRule parses the mask rule given for the filter rule at the slash
+// separated data path. Like with the Compile API, mask rules that aren't data
+// references are relative to the package of the filter rule.
+func ParseMaskRule(path, maskRule string) (ast.Ref, error) {
+	if maskRule == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(maskRule, "data.") {
+		rule, err := ref.ParseDataPath(path)
+		if err != nil {
+			return nil, err
+		}
+		maskRule = rule[:len(rule)-1].String() + "." + maskRule
+	}
+	r, err := ast.ParseRef(maskRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mask rule: %w", err)
+	}
+	return r, nil
+}
+
 func maskRuleFromAnnotationsSet(as *ast.AnnotationSet, rule *ast.Rule) (ast.Ref, *ast.Error) {
 	if as == nil {
 		return nil, nil
@@ -170,3 +229,34 @@ func FuzzyRuleNameMatchHint(comp *ast.Compiler, input string) string {
 	}
 	return msg
 }
+
+// ExtractFromModuleAnnotations is like ExtractUnknownsFromAnnotations and
+// ExtractMaskRuleRefFromAnnotations combined, for modules that are not at
+// hand as an *ast.Compiler.
+func ExtractFromModuleAnnotations(modules []*ast.Module, ref ast.Ref) ([]ast.Ref, ast.Ref, []*ast.Error) {
+	var rule *ast.Rule
+	for _, mod := range modules {
+		for _, r := range mod.Rules {
+			if r.Ref().Equal(ref) {
+				rule = r
+				break
+			}
+		}
+	}
+	if rule == nil {
+		return nil, nil, nil
+	}
+	as, errs := ast.BuildAnnotationSet(modules)
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	unknowns, errs := unknownsFromAnnotationsSet(as, rule)
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	maskRule, err := maskRuleFromAnnotationsSet(as, rule)
+	if err != nil {
+		return nil, nil, []*ast.Error{err}
+	}
+	return unknowns, maskRule, nil
+}
@@ -68,7 +68,7 @@ type Constraints interface {
 }
 
 // NewConstraints returns a new Constraint object based on the type
-// requested, ucast or sql.
+// requested, ucast, sql, mongo or elasticsearch.
 func NewConstraints(typ, variant string) (*Constraint, error) {
 	c := Constraint{Target: strings.ToUpper(typ), Variant: variant, Features: NewSet[string]()}
 	switch typ {
@@ -99,6 +99,15 @@ func NewConstraints(typ, variant string) (*Constraint, error) {
 			c.Variant = ""
 			c.Builtins = ucastBuiltins
 		}
+	case "mongo", "elasticsearch":
+		if variant != "" {
+			return nil, fmt.Errorf("unsupported variant for %s: %s", typ, variant)
+		}
+		c.Builtins = allBuiltins
+		c.Features.Add("not", "existence-ref")
+		if typ == "mongo" {
+			c.Features.Add("field-ref") // via $expr
+		}
 	default:
 		return nil, fmt.Errorf("unknown target/dialect combination: %s/%s", typ, variant)
 	}
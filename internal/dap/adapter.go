@@ -0,0 +1,586 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/open-policy-agent/opa/v1/ast/location"
+	"github.com/open-policy-agent/opa/v1/debug"
+	"github.com/open-policy-agent/opa/v1/logging"
+)
+
+// LaunchArguments are the arguments of the launch request.
+type LaunchArguments struct {
+	Command             string   `json:"command"`
+	Query               string   `json:"query"`
+	Input               any      `json:"input"`
+	InputPath           string   `json:"inputPath"`
+	BundlePaths         []string `json:"bundlePaths"`
+	DataPaths           []string `json:"dataPaths"`
+	StopOnEntry         bool     `json:"stopOnEntry"`
+	StopOnResult        bool     `json:"stopOnResult"`
+	StopOnFail          bool     `json:"stopOnFail"`
+	EnablePrint         bool     `json:"enablePrint"`
+	RuleIndexing        bool     `json:"ruleIndexing"`
+	StrictBuiltinErrors bool     `json:"strictBuiltinErrors"`
+}
+
+// Launcher starts a debug session for a launch request. The session must be
+// returned in a stopped state.
+type Launcher func(ctx context.Context, d debug.Debugger, args LaunchArguments) (debug.Session, error)
+
+// AttachArguments are the arguments of the attach request.
+type AttachArguments struct {
+	Token        string `json:"token"`
+	StopOnEntry  bool   `json:"stopOnEntry"`
+	StopOnResult bool   `json:"stopOnResult"`
+	StopOnFail   bool   `json:"stopOnFail"`
+}
+
+// Attacher attaches a debug session to a running OPA for an attach request.
+// Attachers are responsible for authenticating the client, e.g. by the token
+// in the request arguments.
+type Attacher func(ctx context.Context, d debug.Debugger, args AttachArguments) (debug.Session, error)
+
+// conditionalBreakpointer is implemented by sessions supporting conditional
+// breakpoints, such as debug.AttachedSession.
+type conditionalBreakpointer interface {
+	AddConditionalBreakpoint(loc location.Location, condition string) (debug.Breakpoint, error)
+}
+
+// Adapter serves the Debug Adapter Protocol for a single client, and maps its
+// requests onto a debug.Session.
+type Adapter struct {
+	codec    *Codec
+	logger   logging.Logger
+	launcher Launcher
+	attacher Attacher
+
+	mtx        sync.Mutex // guards seq, terminated and writes to codec
+	seq        int
+	terminated bool
+
+	session     debug.Session
+	breakpoints map[string][]debug.BreakpointID
+}
+
+// AdapterOption configures an Adapter.
+type AdapterOption func(*Adapter)
+
+// Logger sets the logger used by the adapter and the debugger.
+func Logger(logger logging.Logger) AdapterOption {
+	return func(a *Adapter) {
+		a.logger = logger
+	}
+}
+
+// WithLauncher overrides how the launch request starts debug sessions.
+func WithLauncher(launcher Launcher) AdapterOption {
+	return func(a *Adapter) {
+		a.launcher = launcher
+	}
+}
+
+// WithAttacher enables the attach request, starting debug sessions with attacher.
+// Without an attacher, attach requests are rejected.
+func WithAttacher(attacher Attacher) AdapterOption {
+	return func(a *Adapter) {
+		a.attacher = attacher
+	}
+}
+
+// NewAdapter returns a new Adapter reading requests from r and writing
+// responses and events to w.
+func NewAdapter(r io.Reader, w io.Writer, opts ...AdapterOption) *Adapter {
+	a := &Adapter{
+		codec:       NewCodec(r, w),
+		logger:      logging.NewNoOpLogger(),
+		launcher:    launchEval,
+		breakpoints: map[string][]debug.BreakpointID{},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+func launchEval(ctx context.Context, d debug.Debugger, args LaunchArguments) (debug.Session, error) {
+	if args.Command != "" && args.Command != "eval" {
+		return nil, fmt.Errorf("unsupported launch command: %q", args.Command)
+	}
+
+	return d.LaunchEval(ctx, debug.LaunchEvalProperties{
+		LaunchProperties: debug.LaunchProperties{
+			BundlePaths:         args.BundlePaths,
+			DataPaths:           args.DataPaths,
+			StopOnResult:        args.StopOnResult,
+			StopOnEntry:         args.StopOnEntry,
+			StopOnFail:          args.StopOnFail,
+			EnablePrint:         args.EnablePrint,
+			StrictBuiltinErrors: args.StrictBuiltinErrors,
+			RuleIndexing:        args.RuleIndexing,
+		},
+		Query:     args.Query,
+		Input:     args.Input,
+		InputPath: args.InputPath,
+	})
+}
+
+// Serve handles requests until the client disconnects, the stream is closed
+// or ctx is cancelled. Any active session is terminated before returning.
+func (a *Adapter) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		if a.session != nil {
+			_ = a.session.Terminate()
+		}
+	}()
+
+	reqs := make(chan *Request)
+	errs := make(chan error, 1)
+
+	go func() {
+		for {
+			req, err := a.codec.ReadRequest()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case req := <-reqs:
+			done, err := a.handle(ctx, req)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+func (a *Adapter) handle(ctx context.Context, req *Request) (bool, error) {
+	a.logger.Debug("Received request: %s", req.Command)
+
+	var body any
+	var err error
+	done := false
+
+	switch req.Command {
+	case "initialize":
+		body = Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsTerminateRequest:         true,
+			SupportTerminateDebuggee:         true,
+			SupportsSingleThreadExecution:    true,
+			SupportsConditionalBreakpoints:   true,
+		}
+	case "launch":
+		err = a.launch(ctx, req)
+		if err == nil {
+			defer a.sendEvent("initialized", nil)
+		}
+	case "attach":
+		err = a.attach(ctx, req)
+		if err == nil {
+			defer a.sendEvent("initialized", nil)
+		}
+	case "configurationDone":
+		err = a.withSession(func(s debug.Session) error { return s.ResumeAll() })
+	case "setBreakpoints":
+		body, err = a.setBreakpoints(req)
+	case "threads":
+		body, err = a.threads()
+	case "stackTrace":
+		body, err = a.stackTrace(req)
+	case "scopes":
+		body, err = a.scopes(req)
+	case "variables":
+		body, err = a.variables(req)
+	case "continue":
+		body, err = a.step(req, debug.Session.Resume)
+		if err == nil {
+			body = map[string]any{"allThreadsContinued": false}
+		}
+	case "next":
+		body, err = a.step(req, debug.Session.StepOver)
+	case "stepIn":
+		body, err = a.step(req, debug.Session.StepIn)
+	case "stepOut":
+		body, err = a.step(req, debug.Session.StepOut)
+	case "terminate":
+		err = a.withSession(func(s debug.Session) error { return s.Terminate() })
+	case "disconnect":
+		if a.session != nil {
+			err = a.session.Terminate()
+			a.session = nil
+		}
+		done = true
+	default:
+		err = fmt.Errorf("unsupported command: %q", req.Command)
+	}
+
+	resp := Response{
+		ProtocolMessage: ProtocolMessage{Type: TypeResponse},
+		RequestSeq:      req.Seq,
+		Success:         err == nil,
+		Command:         req.Command,
+		Body:            body,
+	}
+	if err != nil {
+		a.logger.Debug("Request %s failed: %v", req.Command, err)
+		resp.Message = err.Error()
+		resp.Body = nil
+	}
+
+	return done, a.send(&resp, &resp.ProtocolMessage)
+}
+
+func (a *Adapter) launch(ctx context.Context, req *Request) error {
+	if a.session != nil {
+		return errors.New("debug session already launched")
+	}
+
+	var args LaunchArguments
+	if err := unmarshalArguments(req, &args); err != nil {
+		return err
+	}
+
+	s, err := a.launcher(ctx, a.newDebugger(), args)
+	if err != nil {
+		return err
+	}
+
+	a.session = s
+	return nil
+}
+
+func (a *Adapter) attach(ctx context.Context, req *Request) error {
+	if a.attacher == nil {
+		return errors.New("attaching is not supported")
+	}
+
+	if a.session != nil {
+		return errors.New("debug session already started")
+	}
+
+	var args AttachArguments
+	if err := unmarshalArguments(req, &args); err != nil {
+		return err
+	}
+
+	s, err := a.attacher(ctx, a.newDebugger(), args)
+	if err != nil {
+		return err
+	}
+
+	a.session = s
+	return nil
+}
+
+func (a *Adapter) newDebugger() debug.Debugger {
+	return debug.NewDebugger(
+		debug.SetLogger(a.logger),
+		debug.SetEventHandler(a.handleDebugEvent),
+	)
+}
+
+func (a *Adapter) withSession(f func(debug.Session) error) error {
+	if a.session == nil {
+		return errors.New("no active debug session")
+	}
+	return f(a.session)
+}
+
+func (a *Adapter) setBreakpoints(req *Request) (any, error) {
+	var args struct {
+		Source      Source             `json:"source"`
+		Breakpoints []SourceBreakpoint `json:"breakpoints"`
+	}
+	if err := unmarshalArguments(req, &args); err != nil {
+		return nil, err
+	}
+
+	if a.session == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	path := args.Source.Path
+	for _, id := range a.breakpoints[path] {
+		if _, err := a.session.RemoveBreakpoint(id); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]debug.BreakpointID, 0, len(args.Breakpoints))
+	result := make([]Breakpoint, 0, len(args.Breakpoints))
+	for _, sbp := range args.Breakpoints {
+		loc := location.Location{File: path, Row: sbp.Line}
+
+		var bp debug.Breakpoint
+		var err error
+		if sbp.Condition != "" {
+			cbp, ok := a.session.(conditionalBreakpointer)
+			if !ok {
+				result = append(result, Breakpoint{Message: "conditional breakpoints are not supported in this session", Source: &args.Source, Line: sbp.Line})
+				continue
+			}
+			bp, err = cbp.AddConditionalBreakpoint(loc, sbp.Condition)
+			if err != nil {
+				result = append(result, Breakpoint{Message: err.Error(), Source: &args.Source, Line: sbp.Line})
+				continue
+			}
+		} else {
+			bp, err = a.session.AddBreakpoint(loc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ids = append(ids, bp.ID())
+		result = append(result, Breakpoint{
+			ID:       int(bp.ID()),
+			Verified: true,
+			Source:   &args.Source,
+			Line:     sbp.Line,
+		})
+	}
+	a.breakpoints[path] = ids
+
+	return map[string]any{"breakpoints": result}, nil
+}
+
+func (a *Adapter) threads() (any, error) {
+	if a.session == nil {
+		return map[string]any{"threads": []Thread{}}, nil
+	}
+
+	ts, err := a.session.Threads()
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]Thread, 0, len(ts))
+	for _, t := range ts {
+		threads = append(threads, Thread{ID: int(t.ID()), Name: t.Name()})
+	}
+
+	return map[string]any{"threads": threads}, nil
+}
+
+func (a *Adapter) stackTrace(req *Request) (any, error) {
+	var args struct {
+		ThreadID   int `json:"threadId"`
+		StartFrame int `json:"startFrame"`
+		Levels     int `json:"levels"`
+	}
+	if err := unmarshalArguments(req, &args); err != nil {
+		return nil, err
+	}
+
+	if a.session == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	trace, err := a.session.StackTrace(debug.ThreadID(args.ThreadID))
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(trace)
+	start := min(max(args.StartFrame, 0), total)
+	end := total
+	if args.Levels > 0 {
+		end = min(start+args.Levels, total)
+	}
+
+	frames := make([]StackFrame, 0, end-start)
+	for _, f := range trace[start:end] {
+		frame := StackFrame{ID: int(f.ID()), Name: f.Name()}
+		if loc := f.Location(); loc != nil {
+			frame.Source = source(loc)
+			frame.Line = loc.Row
+			frame.Column = loc.Col
+		}
+		frames = append(frames, frame)
+	}
+
+	return map[string]any{"stackFrames": frames, "totalFrames": total}, nil
+}
+
+func (a *Adapter) scopes(req *Request) (any, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := unmarshalArguments(req, &args); err != nil {
+		return nil, err
+	}
+
+	if a.session == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	ss, err := a.session.Scopes(debug.FrameID(args.FrameID))
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]Scope, 0, len(ss))
+	for _, s := range ss {
+		scope := Scope{
+			Name:               s.Name(),
+			VariablesReference: int(s.VariablesReference()),
+			NamedVariables:     s.NamedVariables(),
+		}
+		if loc := s.Location(); loc != nil {
+			scope.Source = source(loc)
+			scope.Line = loc.Row
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return map[string]any{"scopes": scopes}, nil
+}
+
+func (a *Adapter) variables(req *Request) (any, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := unmarshalArguments(req, &args); err != nil {
+		return nil, err
+	}
+
+	if a.session == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	vs, err := a.session.Variables(debug.VarRef(args.VariablesReference))
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]Variable, 0, len(vs))
+	for _, v := range vs {
+		variables = append(variables, Variable{
+			Name:               v.Name(),
+			Value:              v.Value(),
+			Type:               v.Type(),
+			VariablesReference: int(v.VariablesReference()),
+		})
+	}
+
+	return map[string]any{"variables": variables}, nil
+}
+
+func (a *Adapter) step(req *Request, f func(debug.Session, debug.ThreadID) error) (any, error) {
+	var args struct {
+		ThreadID int `json:"threadId"`
+	}
+	if err := unmarshalArguments(req, &args); err != nil {
+		return nil, err
+	}
+
+	return nil, a.withSession(func(s debug.Session) error {
+		return f(s, debug.ThreadID(args.ThreadID))
+	})
+}
+
+func (a *Adapter) handleDebugEvent(e debug.Event) {
+	switch e.Type {
+	case debug.StoppedEventType:
+		a.sendEvent("stopped", map[string]any{
+			"reason":   e.Message,
+			"threadId": int(e.Thread),
+		})
+	case debug.ExceptionEventType:
+		a.sendEvent("stopped", map[string]any{
+			"reason":   "exception",
+			"threadId": int(e.Thread),
+			"text":     e.Message,
+		})
+	case debug.StdoutEventType:
+		a.sendEvent("output", map[string]any{
+			"category": "stdout",
+			"output":   e.Message + "\n",
+		})
+	case debug.ThreadEventType:
+		a.sendEvent("thread", map[string]any{
+			"reason":   e.Message,
+			"threadId": int(e.Thread),
+		})
+	case debug.TerminatedEventType:
+		a.mtx.Lock()
+		already := a.terminated
+		a.terminated = true
+		a.mtx.Unlock()
+		if !already {
+			a.sendEvent("terminated", nil)
+		}
+	}
+}
+
+func (a *Adapter) sendEvent(event string, body any) {
+	e := Event{
+		ProtocolMessage: ProtocolMessage{Type: TypeEvent},
+		Event:           event,
+		Body:            body,
+	}
+	if err := a.send(&e, &e.ProtocolMessage); err != nil {
+		a.logger.Error("Failed to send %s event: %v", event, err)
+	}
+}
+
+func (a *Adapter) send(msg any, pm *ProtocolMessage) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.seq++
+	pm.Seq = a.seq
+
+	return a.codec.WriteMessage(msg)
+}
+
+func unmarshalArguments(req *Request, x any) error {
+	if len(req.Arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Arguments, x); err != nil {
+		return fmt.Errorf("invalid %s arguments: %w", req.Command, err)
+	}
+	return nil
+}
+
+func source(loc *location.Location) *Source {
+	if loc.File == "" {
+		return nil
+	}
+	return &Source{Name: filepath.Base(loc.File), Path: loc.File}
+}
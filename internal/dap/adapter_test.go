@@ -0,0 +1,350 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/debug"
+)
+
+type testClient struct {
+	t     *testing.T
+	codec *Codec
+	seq   int
+	msgs  chan map[string]any
+}
+
+func newTestClient(t *testing.T, opts ...AdapterOption) (*testClient, *Adapter) {
+	t.Helper()
+
+	clientR, adapterW := io.Pipe()
+	adapterR, clientW := io.Pipe()
+
+	t.Cleanup(func() {
+		_ = clientW.Close()
+		_ = adapterW.Close()
+	})
+
+	c := &testClient{
+		t:     t,
+		codec: NewCodec(clientR, clientW),
+		msgs:  make(chan map[string]any, 100),
+	}
+
+	go func() {
+		for {
+			bs, err := c.codec.readMessage()
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			var msg map[string]any
+			if err := json.Unmarshal(bs, &msg); err != nil {
+				close(c.msgs)
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+
+	return c, NewAdapter(adapterR, adapterW, opts...)
+}
+
+func (c *testClient) request(command string, args any) map[string]any {
+	c.t.Helper()
+
+	c.seq++
+	req := map[string]any{"seq": c.seq, "type": "request", "command": command}
+	if args != nil {
+		req["arguments"] = args
+	}
+	if err := c.codec.WriteMessage(req); err != nil {
+		c.t.Fatal(err)
+	}
+
+	resp := c.next(func(msg map[string]any) bool {
+		return msg["type"] == TypeResponse && msg["request_seq"] == float64(c.seq)
+	})
+	if resp["success"] != true {
+		c.t.Fatalf("request %s failed: %v", command, resp["message"])
+	}
+	return resp
+}
+
+func (c *testClient) event(name string) map[string]any {
+	c.t.Helper()
+
+	return c.next(func(msg map[string]any) bool {
+		return msg["type"] == TypeEvent && msg["event"] == name
+	})
+}
+
+func (c *testClient) next(match func(map[string]any) bool) map[string]any {
+	c.t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			if !ok {
+				c.t.Fatal("connection closed")
+			}
+			if match(msg) {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatal("timed out waiting for message")
+		}
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	c := NewCodec(&buf, &buf)
+
+	if err := c.WriteMessage(Request{
+		ProtocolMessage: ProtocolMessage{Seq: 1, Type: TypeRequest},
+		Command:         "threads",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "Content-Length: ") {
+		t.Fatalf("expected Content-Length header, got %q", buf.String())
+	}
+
+	req, err := c.ReadRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Seq != 1 || req.Command != "threads" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+
+	if _, err := c.ReadRequest(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestCodecInvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"missing length":   "Foo: bar\r\n\r\n{}",
+		"invalid length":   "Content-Length: abc\r\n\r\n{}",
+		"not a request":    "Content-Length: 27\r\n\r\n{\"seq\":1,\"type\":\"response\"}",
+		"malformed json":   "Content-Length: 2\r\n\r\n{]",
+		"truncated body":   "Content-Length: 10\r\n\r\n{}",
+		"too large":        "Content-Length: 1073741824\r\n\r\n{}",
+		"malformed header": "Content-Length 10\r\n\r\n{}",
+	}
+
+	for note, msg := range tests {
+		t.Run(note, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewCodec(strings.NewReader(msg), io.Discard)
+			if _, err := c.ReadRequest(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestAdapterLaunchEval(t *testing.T) {
+	t.Parallel()
+
+	policy := `package test
+
+allow if {
+	x := input.x
+	x > 1
+}
+`
+	policyPath := filepath.Join(t.TempDir(), "policy.rego")
+	if err := os.WriteFile(policyPath, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, a := newTestClient(t)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- a.Serve(t.Context())
+	}()
+
+	resp := c.request("initialize", map[string]any{"adapterID": "opa"})
+	if caps := resp["body"].(map[string]any); caps["supportsConfigurationDoneRequest"] != true {
+		t.Fatalf("unexpected capabilities: %v", caps)
+	}
+
+	c.request("launch", map[string]any{
+		"command":   "eval",
+		"query":     "data.test.allow",
+		"input":     map[string]any{"x": 2},
+		"dataPaths": []string{policyPath},
+	})
+	c.event("initialized")
+
+	resp = c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": policyPath},
+		"breakpoints": []map[string]any{{"line": 4}},
+	})
+	bps := resp["body"].(map[string]any)["breakpoints"].([]any)
+	if len(bps) != 1 || bps[0].(map[string]any)["verified"] != true {
+		t.Fatalf("unexpected breakpoints: %v", bps)
+	}
+
+	c.request("configurationDone", nil)
+
+	stopped := c.event("stopped")["body"].(map[string]any)
+	if stopped["reason"] != "breakpoint" {
+		t.Fatalf("expected breakpoint stop, got %v", stopped)
+	}
+	threadID := stopped["threadId"]
+
+	threads := c.request("threads", nil)["body"].(map[string]any)["threads"].([]any)
+	if len(threads) != 1 {
+		t.Fatalf("expected 1 thread, got %v", threads)
+	}
+
+	frames := c.request("stackTrace", map[string]any{"threadId": threadID})["body"].(map[string]any)["stackFrames"].([]any)
+	if len(frames) == 0 {
+		t.Fatal("expected stack frames")
+	}
+	top := frames[0].(map[string]any)
+	if top["line"] != float64(4) || top["source"].(map[string]any)["path"] != policyPath {
+		t.Fatalf("unexpected top frame: %v", top)
+	}
+
+	scopes := c.request("scopes", map[string]any{"frameId": top["id"]})["body"].(map[string]any)["scopes"].([]any)
+
+	var inputRef any
+	for _, s := range scopes {
+		s := s.(map[string]any)
+		if s["name"] == "Input" {
+			inputRef = s["variablesReference"]
+		}
+	}
+	if inputRef == nil {
+		t.Fatalf("expected Input scope, got %v", scopes)
+	}
+
+	vars := c.request("variables", map[string]any{"variablesReference": inputRef})["body"].(map[string]any)["variables"].([]any)
+	if len(vars) != 1 || vars[0].(map[string]any)["value"] != `{"x": 2}` {
+		t.Fatalf("unexpected input variables: %v", vars)
+	}
+
+	c.request("continue", map[string]any{"threadId": threadID})
+
+	output := c.event("output")["body"].(map[string]any)
+	if !strings.Contains(output["output"].(string), `"value": true`) {
+		t.Fatalf("unexpected output: %v", output)
+	}
+
+	c.event("terminated")
+
+	c.request("disconnect", nil)
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("adapter did not stop after disconnect")
+	}
+}
+
+func TestAdapterUnsupportedCommand(t *testing.T) {
+	t.Parallel()
+
+	c, a := newTestClient(t)
+	go func() {
+		_ = a.Serve(t.Context())
+	}()
+
+	c.seq++
+	if err := c.codec.WriteMessage(map[string]any{"seq": c.seq, "type": "request", "command": "evaluate"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := c.next(func(msg map[string]any) bool { return msg["type"] == TypeResponse })
+	if resp["success"] != false || resp["message"] != `unsupported command: "evaluate"` {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
+func TestAdapterAttach(t *testing.T) {
+	t.Parallel()
+
+	attacher := func(ctx context.Context, d debug.Debugger, args AttachArguments) (debug.Session, error) {
+		if args.Token != "secret" {
+			return nil, errors.New("unauthorized")
+		}
+		return d.Attach(ctx, debug.AttachProperties{})
+	}
+
+	c, a := newTestClient(t, WithAttacher(attacher))
+	go func() {
+		_ = a.Serve(t.Context())
+	}()
+
+	c.seq++
+	if err := c.codec.WriteMessage(map[string]any{"seq": c.seq, "type": "request", "command": "attach"}); err != nil {
+		t.Fatal(err)
+	}
+	resp := c.next(func(msg map[string]any) bool { return msg["type"] == TypeResponse })
+	if resp["success"] != false || resp["message"] != "unauthorized" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+
+	c.request("attach", map[string]any{"token": "secret"})
+	c.event("initialized")
+
+	resp = c.request("setBreakpoints", map[string]any{
+		"source": map[string]any{"path": "policy.rego"},
+		"breakpoints": []map[string]any{
+			{"line": 3, "condition": `input.user == "alice"`},
+			{"line": 4, "condition": `input.user ==`},
+		},
+	})
+	bps := resp["body"].(map[string]any)["breakpoints"].([]any)
+	if len(bps) != 2 || bps[0].(map[string]any)["verified"] != true || bps[1].(map[string]any)["verified"] != false {
+		t.Fatalf("unexpected breakpoints: %v", bps)
+	}
+}
+
+func TestAdapterAttachUnsupported(t *testing.T) {
+	t.Parallel()
+
+	c, a := newTestClient(t)
+	go func() {
+		_ = a.Serve(t.Context())
+	}()
+
+	c.seq++
+	if err := c.codec.WriteMessage(map[string]any{"seq": c.seq, "type": "request", "command": "attach"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := c.next(func(msg map[string]any) bool { return msg["type"] == TypeResponse })
+	if resp["success"] != false || resp["message"] != "attaching is not supported" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package dap implements the subset of the Debug Adapter Protocol (DAP)
+// needed to expose the debug package to DAP-capable editors.
+// See: https://microsoft.github.io/debug-adapter-protocol/specification
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+const contentLengthHeader = "Content-Length"
+
+// maxMessageBytes bounds the size of messages read from clients, matching the
+// server's default request body limit (server.decoding.max_length).
+const maxMessageBytes = 256 * 1024 * 1024
+
+// Message types of the base protocol.
+const (
+	TypeRequest  = "request"
+	TypeResponse = "response"
+	TypeEvent    = "event"
+)
+
+// ProtocolMessage is the base of all messages sent over the protocol.
+type ProtocolMessage struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"`
+}
+
+// Request is a client or debug adapter initiated request.
+type Request struct {
+	ProtocolMessage
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Response is the response to a Request.
+type Response struct {
+	ProtocolMessage
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+// Event is a debug adapter initiated event.
+type Event struct {
+	ProtocolMessage
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// Capabilities are the features supported by the debug adapter, returned in
+// response to the initialize request.
+type Capabilities struct {
+	SupportsConfigurationDoneRequest bool `json:"supportsConfigurationDoneRequest,omitempty"`
+	SupportsTerminateRequest         bool `json:"supportsTerminateRequest,omitempty"`
+	SupportTerminateDebuggee         bool `json:"supportTerminateDebuggee,omitempty"`
+	SupportsSingleThreadExecution    bool `json:"supportsSingleThreadExecutionRequests,omitempty"`
+	SupportsConditionalBreakpoints   bool `json:"supportsConditionalBreakpoints,omitempty"`
+}
+
+// Source is a descriptor for source code.
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// SourceBreakpoint is a breakpoint requested by the client.
+type SourceBreakpoint struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column,omitempty"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// Breakpoint is a breakpoint reported by the debug adapter.
+type Breakpoint struct {
+	ID       int     `json:"id,omitempty"`
+	Verified bool    `json:"verified"`
+	Message  string  `json:"message,omitempty"`
+	Source   *Source `json:"source,omitempty"`
+	Line     int     `json:"line,omitempty"`
+}
+
+// Thread is a thread of execution.
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// StackFrame is a single frame of a stack trace.
+type StackFrame struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Source    *Source `json:"source,omitempty"`
+	Line      int     `json:"line"`
+	Column    int     `json:"column"`
+	EndLine   int     `json:"endLine,omitempty"`
+	EndColumn int     `json:"endColumn,omitempty"`
+}
+
+// Scope is a named container for variables.
+type Scope struct {
+	Name               string  `json:"name"`
+	VariablesReference int     `json:"variablesReference"`
+	NamedVariables     int     `json:"namedVariables,omitempty"`
+	Expensive          bool    `json:"expensive"`
+	Source             *Source `json:"source,omitempty"`
+	Line               int     `json:"line,omitempty"`
+}
+
+// Variable is a name/value pair.
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// Codec reads and writes base protocol messages, framed by a Content-Length
+// header, from and to an underlying stream.
+type Codec struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// NewCodec returns a new Codec reading from r and writing to w.
+func NewCodec(r io.Reader, w io.Writer) *Codec {
+	return &Codec{r: bufio.NewReader(r), w: w}
+}
+
+// ReadRequest reads the next request from the stream. Messages that are not
+// requests are rejected, as clients only ever send requests.
+func (c *Codec) ReadRequest() (*Request, error) {
+	bs, err := c.readMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	var req Request
+	if err := json.Unmarshal(bs, &req); err != nil {
+		return nil, fmt.Errorf("malformed message: %w", err)
+	}
+
+	if req.Type != TypeRequest {
+		return nil, fmt.Errorf("unexpected message type %q", req.Type)
+	}
+
+	return &req, nil
+}
+
+func (c *Codec) readMessage() ([]byte, error) {
+	tp := textproto.NewReader(c.r)
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+
+	v := header.Get(contentLengthHeader)
+	if v == "" {
+		return nil, fmt.Errorf("missing %s header", contentLengthHeader)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s header: %q", contentLengthHeader, v)
+	}
+
+	if n > maxMessageBytes {
+		return nil, fmt.Errorf("message size %d exceeds limit of %d bytes", n, maxMessageBytes)
+	}
+
+	bs := make([]byte, n)
+	if _, err := io.ReadFull(c.r, bs); err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// WriteMessage writes msg to the stream. Callers are responsible for
+// serializing concurrent writes.
+func (c *Codec) WriteMessage(msg any) error {
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(c.w, "%s: %d\r\n\r\n", contentLengthHeader, len(bs)); err != nil {
+		return err
+	}
+
+	_, err = c.w.Write(bs)
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dap
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+)
+
+// Serve accepts connections on l and serves each of them with an Adapter of its
+// own, configured with opts, until ctx is cancelled. The listener is closed
+// when Serve returns.
+func Serve(ctx context.Context, l net.Listener, logger logging.Logger, opts ...AdapterOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	logger.Info("Debug adapter listening on %s", l.Addr())
+
+	opts = append([]AdapterOption{Logger(logger)}, opts...)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+
+			logger.Debug("Client connected: %s", conn.RemoteAddr())
+			if err := NewAdapter(conn, conn, opts...).Serve(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("Debug session failed: %v", err)
+			}
+			logger.Debug("Client disconnected: %s", conn.RemoteAddr())
+		}()
+	}
+}
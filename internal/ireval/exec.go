@@ -0,0 +1,602 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package ireval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/ir"
+	"github.com/open-policy-agent/opa/v1/topdown"
+)
+
+// Statements and blocks report how execution continues: statements that are
+// undefined break out of the innermost block, like the br instructions that
+// the Wasm compiler emits for them.
+const (
+	next = -1      // continue with the next statement
+	done = -2      // stop scanning, see execScan
+	ret  = 1 << 30 // return from the current function
+)
+
+// frame holds the locals of a plan or function call.
+type frame []ast.Value
+
+func (f *frame) get(l ir.Local) ast.Value {
+	if int(l) < len(*f) {
+		return (*f)[l]
+	}
+	return nil
+}
+
+func (f *frame) set(l ir.Local, v ast.Value) {
+	for int(l) >= len(*f) {
+		*f = append(*f, nil)
+	}
+	(*f)[l] = v
+}
+
+type eval struct {
+	*Interpreter
+	ctx           context.Context
+	opts          EvalOpts
+	bctx          topdown.BuiltinContext
+	memo          []map[string]ast.Value
+	result        ast.Set
+	returned      ast.Value
+	builtinErrors []error
+}
+
+// execBlock executes the statements of block. It returns next if all of them
+// were executed, or the number of enclosing blocks to break out of.
+func (e *eval) execBlock(locals *frame, block *ir.Block) (int, error) {
+	for _, stmt := range block.Stmts {
+		r, err := e.execStmt(locals, stmt)
+		if err != nil {
+			return 0, err
+		}
+		if r != next {
+			return r, nil
+		}
+	}
+	return next, nil
+}
+
+// exit returns how execution continues after a nested block that returned r.
+func exit(r int) int {
+	switch {
+	case r == ret:
+		return ret
+	case r <= 0:
+		return next
+	default:
+		return r - 1
+	}
+}
+
+func (e *eval) execStmt(locals *frame, stmt ir.Stmt) (int, error) {
+	switch stmt := stmt.(type) {
+	case *ir.ResultSetAddStmt:
+		e.result.Add(ast.NewTerm(locals.get(stmt.Value)))
+	case *ir.ReturnLocalStmt:
+		e.returned = locals.get(stmt.Source)
+		return ret, nil
+	case *ir.BlockStmt:
+		for _, block := range stmt.Blocks {
+			r, err := e.execBlock(locals, block)
+			if err != nil {
+				return 0, err
+			}
+			if r = exit(r); r != next {
+				return r, nil
+			}
+		}
+	case *ir.BreakStmt:
+		return int(stmt.Index), nil
+	case *ir.CallStmt:
+		return e.execCall(locals, stmt)
+	case *ir.CallDynamicStmt:
+		return e.execCallDynamic(locals, stmt)
+	case *ir.WithStmt:
+		return e.execWith(locals, stmt)
+	case *ir.AssignVarStmt:
+		locals.set(stmt.Target, e.operand(locals, stmt.Source))
+	case *ir.AssignVarOnceStmt:
+		v := e.operand(locals, stmt.Source)
+		if prev := locals.get(stmt.Target); prev != nil && prev.Compare(v) != 0 {
+			return 0, e.conflict(stmt.Location, "var assignment conflict")
+		}
+		locals.set(stmt.Target, v)
+	case *ir.AssignIntStmt:
+		locals.set(stmt.Target, ast.Number(strconv.FormatInt(stmt.Value, 10)))
+	case *ir.ScanStmt:
+		return e.execScan(locals, stmt)
+	case *ir.NopStmt:
+	case *ir.NotStmt:
+		r, err := e.execBlock(locals, stmt.Block)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case r == next:
+			return 0, nil
+		case r == 0:
+		default:
+			return exit(r), nil
+		}
+	case *ir.DotStmt:
+		src, ok := stmt.Source.Value.(ir.Local)
+		if !ok {
+			return 0, nil
+		}
+		v := get(locals.get(src), e.operand(locals, stmt.Key))
+		if v == nil {
+			return 0, nil
+		}
+		locals.set(stmt.Target, v)
+	case *ir.LenStmt:
+		n, ok := length(e.operand(locals, stmt.Source))
+		if !ok {
+			return 0, nil
+		}
+		locals.set(stmt.Target, ast.InternedValue(n))
+	case *ir.EqualStmt:
+		a, b := e.operand(locals, stmt.A), e.operand(locals, stmt.B)
+		if a == nil || b == nil || a.Compare(b) != 0 {
+			return 0, nil
+		}
+	case *ir.NotEqualStmt:
+		a, b := e.operand(locals, stmt.A), e.operand(locals, stmt.B)
+		if a == nil || b == nil || a.Compare(b) == 0 {
+			return 0, nil
+		}
+	case *ir.MakeNullStmt:
+		locals.set(stmt.Target, ast.InternedNullValue)
+	case *ir.MakeNumberIntStmt:
+		locals.set(stmt.Target, ast.Number(strconv.FormatInt(stmt.Value, 10)))
+	case *ir.MakeNumberRefStmt:
+		locals.set(stmt.Target, ast.Number(e.policy.Static.Strings[stmt.Index].Value))
+	case *ir.MakeArrayStmt:
+		locals.set(stmt.Target, ast.NewArray())
+	case *ir.MakeObjectStmt:
+		locals.set(stmt.Target, ast.NewObject())
+	case *ir.MakeSetStmt:
+		locals.set(stmt.Target, ast.NewSet())
+	case *ir.IsArrayStmt:
+		if _, ok := e.localOperand(locals, stmt.Source).(*ast.Array); !ok {
+			return 0, nil
+		}
+	case *ir.IsObjectStmt:
+		if _, ok := e.localOperand(locals, stmt.Source).(ast.Object); !ok {
+			return 0, nil
+		}
+	case *ir.IsSetStmt:
+		if _, ok := e.localOperand(locals, stmt.Source).(ast.Set); !ok {
+			return 0, nil
+		}
+	case *ir.IsDefinedStmt:
+		if locals.get(stmt.Source) == nil {
+			return 0, nil
+		}
+	case *ir.IsUndefinedStmt:
+		if locals.get(stmt.Source) != nil {
+			return 0, nil
+		}
+	case *ir.ResetLocalStmt:
+		locals.set(stmt.Target, nil)
+	case *ir.ArrayAppendStmt:
+		if arr, ok := locals.get(stmt.Array).(*ast.Array); ok {
+			locals.set(stmt.Array, arr.Append(ast.NewTerm(e.operand(locals, stmt.Value))))
+		}
+	case *ir.ObjectInsertStmt:
+		if obj, ok := locals.get(stmt.Object).(ast.Object); ok {
+			obj.Insert(ast.NewTerm(e.operand(locals, stmt.Key)), ast.NewTerm(e.operand(locals, stmt.Value)))
+		}
+	case *ir.ObjectInsertOnceStmt:
+		obj, ok := locals.get(stmt.Object).(ast.Object)
+		if !ok {
+			break
+		}
+		k, v := ast.NewTerm(e.operand(locals, stmt.Key)), e.operand(locals, stmt.Value)
+		if prev := obj.Get(k); prev != nil {
+			if prev.Value.Compare(v) != 0 {
+				return 0, e.conflict(stmt.Location, "object insert conflict")
+			}
+			break
+		}
+		obj.Insert(k, ast.NewTerm(v))
+	case *ir.ObjectMergeStmt:
+		locals.set(stmt.Target, merge(locals.get(stmt.A), locals.get(stmt.B)))
+	case *ir.SetAddStmt:
+		if set, ok := locals.get(stmt.Set).(ast.Set); ok {
+			set.Add(ast.NewTerm(e.operand(locals, stmt.Value)))
+		}
+	default:
+		return 0, fmt.Errorf("illegal statement: %T", stmt)
+	}
+	return next, nil
+}
+
+func (e *eval) operand(locals *frame, op ir.Operand) ast.Value {
+	switch v := op.Value.(type) {
+	case ir.Local:
+		return locals.get(v)
+	case ir.StringIndex:
+		return ast.String(e.policy.Static.Strings[v].Value)
+	case ir.Bool:
+		return ast.Boolean(v)
+	}
+	return nil
+}
+
+// localOperand returns the value of op if it's a local. Type checks on
+// constants are undefined, like in Wasm.
+func (e *eval) localOperand(locals *frame, op ir.Operand) ast.Value {
+	if l, ok := op.Value.(ir.Local); ok {
+		return locals.get(l)
+	}
+	return nil
+}
+
+func (e *eval) execScan(locals *frame, stmt *ir.ScanStmt) (int, error) {
+	iter := func(k, v ast.Value) (int, error) {
+		if err := e.checkCancel(); err != nil {
+			return 0, err
+		}
+		locals.set(stmt.Key, k)
+		locals.set(stmt.Value, v)
+		r, err := e.execBlock(locals, stmt.Block)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case r <= 0:
+			return next, nil
+		case r == 1:
+			return done, nil
+		case r == ret:
+			return ret, nil
+		default:
+			return r - 2, nil
+		}
+	}
+
+	r := next
+	var err error
+	stop := func(result int, e error) bool {
+		r, err = result, e
+		return err != nil || r != next
+	}
+
+	switch src := locals.get(stmt.Source).(type) {
+	case *ast.Array:
+		for i := range src.Len() {
+			if stop(iter(ast.InternedValue(i), src.Elem(i).Value)) {
+				break
+			}
+		}
+	case ast.Object:
+		_ = src.Until(func(k, v *ast.Term) bool {
+			return stop(iter(k.Value, v.Value))
+		})
+	case ast.Set:
+		_ = src.Until(func(x *ast.Term) bool {
+			return stop(iter(x.Value, x.Value))
+		})
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if r == done {
+		return next, nil
+	}
+	return r, nil
+}
+
+func (e *eval) execWith(locals *frame, stmt *ir.WithStmt) (int, error) {
+	save := locals.get(stmt.Local)
+	value := e.operand(locals, stmt.Value)
+
+	if len(stmt.Path) == 0 {
+		locals.set(stmt.Local, value)
+	} else {
+		path := make([]string, len(stmt.Path))
+		for i, idx := range stmt.Path {
+			path[i] = e.policy.Static.Strings[idx].Value
+		}
+		locals.set(stmt.Local, upsert(save, path, value))
+	}
+
+	e.memo = append(e.memo, map[string]ast.Value{})
+	r, err := e.execBlock(locals, stmt.Block)
+	e.memo = e.memo[:len(e.memo)-1]
+	locals.set(stmt.Local, save)
+
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case r == next:
+		return next, nil
+	case r == 0:
+		return 0, nil
+	default:
+		return exit(r), nil
+	}
+}
+
+func (e *eval) execCall(locals *frame, stmt *ir.CallStmt) (int, error) {
+	args := make([]ast.Value, len(stmt.Args))
+	for i := range stmt.Args {
+		args[i] = e.operand(locals, stmt.Args[i])
+	}
+
+	if fn, ok := e.funcs[stmt.Func]; ok {
+		v, err := e.call(fn, args)
+		if err != nil {
+			return 0, err
+		}
+		if v == nil {
+			return 0, nil
+		}
+		locals.set(stmt.Result, v)
+		return next, nil
+	}
+
+	bi, ok := e.builtins[stmt.Func]
+	if !ok {
+		return 0, fmt.Errorf("undefined function: %q", stmt.Func)
+	}
+
+	v, err := e.callBuiltin(bi, stmt, args)
+	if err != nil {
+		return 0, err
+	}
+	if bi.void {
+		return next, nil
+	}
+	if v == nil {
+		return 0, nil
+	}
+	locals.set(stmt.Result, v)
+	return next, nil
+}
+
+func (e *eval) callBuiltin(bi *builtin, stmt *ir.CallStmt, args []ast.Value) (ast.Value, error) {
+	operands := make([]*ast.Term, len(args))
+	for i := range args {
+		if args[i] == nil {
+			return nil, nil
+		}
+		operands[i] = ast.NewTerm(args[i])
+	}
+
+	bctx := e.bctx
+	bctx.Location = e.location(stmt.Location)
+
+	var result ast.Value
+	var results []*ast.Term
+	err := bi.fn(bctx, operands, func(t *ast.Term) error {
+		switch {
+		case t == nil: // void
+		case bi.relation:
+			results = append(results, t)
+		default:
+			result = t.Value
+		}
+		return nil
+	})
+	if err != nil {
+		var halt topdown.Halt
+		if errors.As(err, &halt) {
+			return nil, halt.Err
+		}
+		e.builtinErrors = append(e.builtinErrors, err)
+		return nil, nil
+	}
+
+	if bi.relation {
+		return ast.NewArray(results...), nil
+	}
+	return result, nil
+}
+
+// call calls fn and returns the value it returned, or nil if it was undefined.
+// Functions without other arguments than input and data are memoized.
+func (e *eval) call(fn *ir.Func, args []ast.Value) (ast.Value, error) {
+	if err := e.checkCancel(); err != nil {
+		return nil, err
+	}
+
+	memoize := len(fn.Params) == 2
+	memo := e.memo[len(e.memo)-1]
+	if memoize {
+		if v, ok := memo[fn.Name]; ok {
+			return v, nil
+		}
+	}
+
+	locals := make(frame, 0, len(fn.Params))
+	for i, p := range fn.Params {
+		if i < len(args) {
+			locals.set(p, args[i])
+		}
+	}
+
+	var v ast.Value
+	for _, block := range fn.Blocks {
+		r, err := e.execBlock(&locals, block)
+		if err != nil {
+			return nil, err
+		}
+		if r == ret {
+			v, e.returned = e.returned, nil
+			break
+		}
+	}
+
+	if memoize && v != nil {
+		memo[fn.Name] = v
+	}
+	return v, nil
+}
+
+func (e *eval) execCallDynamic(locals *frame, stmt *ir.CallDynamicStmt) (int, error) {
+	path := make([]string, len(stmt.Path))
+	for i := range stmt.Path {
+		s, ok := e.operand(locals, stmt.Path[i]).(ast.String)
+		if !ok {
+			return 0, nil
+		}
+		path[i] = string(s)
+	}
+
+	fn, ok := e.paths[pathKey(path)]
+	if !ok {
+		return 0, nil
+	}
+
+	args := make([]ast.Value, len(stmt.Args))
+	for i := range stmt.Args {
+		args[i] = locals.get(stmt.Args[i])
+	}
+
+	v, err := e.call(fn, args)
+	if err != nil {
+		return 0, err
+	}
+	if v == nil {
+		// The function was found, so the lookup doesn't fall back to the base
+		// documents: break out of the blocks the planner put around the call.
+		return 3, nil
+	}
+	locals.set(stmt.Result, v)
+	return next, nil
+}
+
+func (e *eval) checkCancel() error {
+	if e.opts.Cancel != nil && e.opts.Cancel.Cancelled() {
+		msg := "caller cancelled query execution"
+		if err := e.ctx.Err(); err != nil {
+			msg = err.Error()
+		}
+		return &topdown.Error{Code: topdown.CancelErr, Message: msg}
+	}
+	return nil
+}
+
+func (e *eval) conflict(loc ir.Location, msg string) error {
+	return &topdown.Error{Code: topdown.ConflictErr, Message: msg, Location: e.location(loc)}
+}
+
+func (e *eval) location(loc ir.Location) *ast.Location {
+	if loc.Row == 0 {
+		return nil
+	}
+	l := &ast.Location{Row: loc.Row, Col: loc.Col, Text: loc.Text}
+	if files := e.policy.Static.Files; loc.File >= 0 && loc.File < len(files) {
+		l.File = files[loc.File].Value
+	}
+	return l
+}
+
+// get returns the value of x at key k, or nil if it's undefined.
+func get(x, k ast.Value) ast.Value {
+	if x == nil || k == nil {
+		return nil
+	}
+	switch x := x.(type) {
+	case *ast.Array:
+		n, ok := k.(ast.Number)
+		if !ok {
+			return nil
+		}
+		i, ok := n.Int()
+		if !ok || i < 0 || i >= x.Len() {
+			return nil
+		}
+		return x.Elem(i).Value
+	case ast.Object:
+		if v := x.Get(ast.NewTerm(k)); v != nil {
+			return v.Value
+		}
+	case ast.Set:
+		if t := ast.NewTerm(k); x.Contains(t) {
+			return k
+		}
+	}
+	return nil
+}
+
+func length(x ast.Value) (int, bool) {
+	switch x := x.(type) {
+	case *ast.Array:
+		return x.Len(), true
+	case ast.Object:
+		return x.Len(), true
+	case ast.Set:
+		return x.Len(), true
+	case ast.String:
+		return len([]rune(string(x))), true
+	}
+	return 0, false
+}
+
+// merge merges the objects a and b recursively, with the values of a taking
+// precedence over the values of b.
+func merge(a, b ast.Value) ast.Value {
+	if a == nil {
+		return b
+	}
+	objA, ok := a.(ast.Object)
+	if !ok {
+		return a
+	}
+	objB, ok := b.(ast.Object)
+	if !ok {
+		return a
+	}
+
+	result := ast.NewObject()
+	objA.Foreach(func(k, v *ast.Term) {
+		if other := objB.Get(k); other != nil {
+			result.Insert(k, ast.NewTerm(merge(v.Value, other.Value)))
+		} else {
+			result.Insert(k, v)
+		}
+	})
+	objB.Foreach(func(k, v *ast.Term) {
+		if objA.Get(k) == nil {
+			result.Insert(k, v)
+		}
+	})
+	return result
+}
+
+// upsert returns a copy of x with value inserted at path. Missing and
+// non-object nodes along the path are replaced by objects.
+func upsert(x ast.Value, path []string, value ast.Value) ast.Value {
+	obj := ast.NewObject()
+	if x, ok := x.(ast.Object); ok {
+		x.Foreach(obj.Insert)
+	}
+
+	k := ast.StringTerm(path[0])
+	if len(path) == 1 {
+		obj.Insert(k, ast.NewTerm(value))
+		return obj
+	}
+
+	var child ast.Value
+	if v := obj.Get(k); v != nil {
+		child = v.Value
+	}
+	obj.Insert(k, ast.NewTerm(upsert(child, path[1:], value)))
+	return obj
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package ireval implements an interpreter for IR plans (see the ir package).
+//
+// The interpreter executes the statements of a plan directly on AST values,
+// following the semantics of the Wasm compiler. Built-in functions are
+// dispatched to the topdown implementations.
+package ireval
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/ir"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
+	"github.com/open-policy-agent/opa/v1/topdown/print"
+	"github.com/open-policy-agent/opa/v1/types"
+)
+
+// Interpreter evaluates the plans of a policy. An Interpreter can be used
+// concurrently.
+type Interpreter struct {
+	policy   *ir.Policy
+	plans    map[string]*ir.Plan
+	funcs    map[string]*ir.Func
+	paths    map[string]*ir.Func
+	builtins map[string]*builtin
+}
+
+type builtin struct {
+	fn       topdown.BuiltinFunc
+	void     bool
+	relation bool
+}
+
+// EvalOpts contains the options of a single evaluation.
+type EvalOpts struct {
+	Entrypoint                  string
+	Input                       ast.Value // nil if the input is undefined
+	Data                        ast.Value
+	Metrics                     metrics.Metrics
+	Time                        time.Time
+	Seed                        io.Reader
+	Runtime                     *ast.Term
+	Cancel                      topdown.Cancel
+	InterQueryBuiltinCache      cache.InterQueryCache
+	InterQueryBuiltinValueCache cache.InterQueryValueCache
+	NDBuiltinCache              builtins.NDBCache
+	PrintHook                   print.Hook
+	Capabilities                *ast.Capabilities
+	StrictBuiltinErrors         bool
+	BuiltinErrorList            *[]topdown.Error
+}
+
+// New returns an interpreter for policy. Built-in functions called by the
+// policy are looked up in the topdown implementations, and in custom, which
+// takes precedence for functions that aren't built into OPA.
+func New(policy *ir.Policy, custom map[string]*topdown.Builtin) (*Interpreter, error) {
+	i := &Interpreter{
+		policy:   policy,
+		plans:    map[string]*ir.Plan{},
+		funcs:    map[string]*ir.Func{},
+		paths:    map[string]*ir.Func{},
+		builtins: map[string]*builtin{},
+	}
+
+	if policy.Plans != nil {
+		for _, plan := range policy.Plans.Plans {
+			i.plans[plan.Name] = plan
+		}
+	}
+
+	if policy.Funcs != nil {
+		for _, fn := range policy.Funcs.Funcs {
+			i.funcs[fn.Name] = fn
+			if len(fn.Path) > 0 {
+				i.paths[pathKey(fn.Path)] = fn
+			}
+		}
+	}
+
+	if policy.Static != nil {
+		for _, bi := range policy.Static.BuiltinFuncs {
+			b, err := lookupBuiltin(bi, custom)
+			if err != nil {
+				return nil, err
+			}
+			i.builtins[bi.Name] = b
+		}
+	}
+
+	return i, nil
+}
+
+// lookupBuiltin resolves the built-in function the same way topdown does.
+func lookupBuiltin(bi *ir.BuiltinFunc, custom map[string]*topdown.Builtin) (*builtin, error) {
+	if decl, ok := ast.BuiltinMap[bi.Name]; ok {
+		if fn := topdown.GetBuiltin(bi.Name); fn != nil {
+			return &builtin{fn: fn, void: decl.Decl.Result() == nil, relation: decl.Relation}, nil
+		}
+		if c, ok := custom[bi.Name]; ok {
+			return &builtin{fn: c.Func, void: decl.Decl.Result() == nil, relation: decl.Relation}, nil
+		}
+	}
+
+	if c, ok := custom[bi.Name]; ok {
+		var decl *types.Function = bi.Decl
+		if c.Decl != nil {
+			decl = c.Decl.Decl
+		}
+		return &builtin{fn: c.Func, void: decl != nil && decl.Result() == nil, relation: c.Decl != nil && c.Decl.Relation}, nil
+	}
+
+	return nil, fmt.Errorf("undefined function: %q", bi.Name)
+}
+
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// Eval evaluates the plan named by the entrypoint, or the first plan of the
+// policy if no entrypoint is set, and returns its result set.
+func (i *Interpreter) Eval(ctx context.Context, opts EvalOpts) (ast.Set, error) {
+	plan, err := i.plan(opts.Entrypoint)
+	if err != nil {
+		return nil, err
+	}
+
+	t := opts.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	seed := opts.Seed
+	if seed == nil {
+		seed = rand.Reader
+	}
+
+	e := &eval{
+		Interpreter: i,
+		ctx:         ctx,
+		opts:        opts,
+		bctx: topdown.BuiltinContext{
+			Context:                     ctx,
+			Metrics:                     opts.Metrics,
+			Seed:                        seed,
+			Time:                        ast.NumberTerm(json.Number(strconv.FormatInt(t.UnixNano(), 10))),
+			Cancel:                      opts.Cancel,
+			Runtime:                     opts.Runtime,
+			Cache:                       builtins.Cache{},
+			InterQueryBuiltinCache:      opts.InterQueryBuiltinCache,
+			InterQueryBuiltinValueCache: opts.InterQueryBuiltinValueCache,
+			NDBuiltinCache:              opts.NDBuiltinCache,
+			PrintHook:                   opts.PrintHook,
+			Capabilities:                opts.Capabilities,
+		},
+		memo:   []map[string]ast.Value{{}},
+		result: ast.NewSet(),
+	}
+
+	locals := frame{opts.Input, opts.Data}
+	for _, block := range plan.Blocks {
+		if _, err := e.execBlock(&locals, block); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(e.builtinErrors) > 0 {
+		if opts.StrictBuiltinErrors {
+			return nil, e.builtinErrors[0]
+		}
+		if opts.BuiltinErrorList != nil {
+			for _, err := range e.builtinErrors {
+				var tdErr *topdown.Error
+				if errors.As(err, &tdErr) {
+					*opts.BuiltinErrorList = append(*opts.BuiltinErrorList, *tdErr)
+				} else {
+					*opts.BuiltinErrorList = append(*opts.BuiltinErrorList, topdown.Error{
+						Code:    topdown.BuiltinErr,
+						Message: err.Error(),
+					})
+				}
+			}
+		}
+	}
+
+	return e.result, nil
+}
+
+func (i *Interpreter) plan(entrypoint string) (*ir.Plan, error) {
+	if entrypoint == "" {
+		if i.policy.Plans == nil || len(i.policy.Plans.Plans) == 0 {
+			return nil, errors.New("policy has no plans")
+		}
+		return i.policy.Plans.Plans[0], nil
+	}
+	plan, ok := i.plans[entrypoint]
+	if !ok {
+		return nil, fmt.Errorf("unknown entrypoint %q", entrypoint)
+	}
+	return plan, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package ireval_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/test/cases"
+)
+
+const caseDir = "../../v1/test/cases/testdata/v1"
+
+// exceptions lists the test cases that the planner doesn't support.
+var exceptions = map[string]string{
+	"data/toplevel integer": "https://github.com/open-policy-agent/opa/issues/3711",
+	"data/nested integer":   "https://github.com/open-policy-agent/opa/issues/3711",
+}
+
+// TestPlanTopdownDifferential evaluates the test cases shared by the
+// evaluators with the plan target and with topdown, and compares the results.
+func TestPlanTopdownDifferential(t *testing.T) {
+	for _, tc := range cases.MustLoad(caseDir).Sorted().Cases {
+		name := fmt.Sprintf("%s/%s", strings.TrimPrefix(tc.Filename, caseDir), tc.Note)
+		t.Run(name, func(t *testing.T) {
+			if reason, ok := exceptions[tc.Note]; ok {
+				t.Skip(reason)
+			}
+
+			for k, v := range tc.Env {
+				t.Setenv(k, v)
+			}
+
+			exp, expErr := evalCase(tc, "rego")
+			act, actErr := evalCase(tc, "plan")
+
+			switch {
+			case expErr != nil && actErr != nil:
+			case expErr != nil:
+				t.Fatalf("expected error %v, got result %v", expErr, act)
+			case actErr != nil:
+				t.Fatalf("expected result %v, got error %v", exp, actErr)
+			case exp != act:
+				t.Fatalf("expected result\n%v\ngot\n%v", exp, act)
+			}
+		})
+	}
+}
+
+func evalCase(tc cases.TestCase, target string) (string, error) {
+	ctx := context.Background()
+
+	opts := []func(*rego.Rego){
+		rego.Query(tc.Query),
+		rego.Target(target),
+		rego.StrictBuiltinErrors(tc.StrictError),
+		rego.Capabilities(ast.CapabilitiesForThisVersion(ast.CapabilitiesExperimentalKeywords(tc.ExperimentalKeywords))),
+	}
+	for i := range tc.Modules {
+		opts = append(opts, rego.Module(fmt.Sprintf("test-%d.rego", i), tc.Modules[i]))
+	}
+	if tc.Data != nil {
+		opts = append(opts, rego.Store(inmem.NewFromObject(*tc.Data)))
+	}
+	if tc.InputTerm != nil {
+		opts = append(opts, rego.ParsedInput(ast.MustParseTerm(*tc.InputTerm).Value))
+	} else if tc.Input != nil {
+		opts = append(opts, rego.Input(*tc.Input))
+	}
+
+	rs, err := rego.New(opts...).Eval(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	results := make([]string, 0, len(rs))
+	for _, r := range rs {
+		bindings := map[string]any{}
+		for k, v := range r.Bindings {
+			if !strings.HasPrefix(k, "__") && !strings.HasPrefix(k, "^") {
+				bindings[k] = v
+			}
+		}
+		bs, err := json.Marshal(bindings)
+		if err != nil {
+			return "", err
+		}
+		results = append(results, string(bs))
+	}
+	slices.Sort(results)
+	return strings.Join(results, "\n"), nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package kafka
+
+import (
+	"testing"
+)
+
+func TestMurmur2(t *testing.T) {
+	// test vectors of the Java client's Utils.murmur2
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for in, exp := range tests {
+		if act := murmur2([]byte(in)); act != exp {
+			t.Errorf("murmur2(%q): expected %d, got %d", in, exp, act)
+		}
+	}
+}
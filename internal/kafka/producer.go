@@ -0,0 +1,359 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package kafka implements a minimal producer for the Kafka protocol. It
+// publishes records to topics of Kafka, or any broker implementing the Kafka
+// protocol, like Redpanda.
+package kafka
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Acks are the acknowledgements required from the brokers for records to be
+// considered written.
+type Acks int
+
+const (
+	AcksAll    Acks = iota // wait for all in-sync replicas to write the records
+	AcksLeader             // wait for the leader to write the records
+	AcksNone               // don't wait for the leader to write the records
+)
+
+// value returns the value of the acks field of produce requests.
+func (a Acks) value() int16 {
+	switch a {
+	case AcksLeader:
+		return 1
+	case AcksNone:
+		return 0
+	}
+	return -1
+}
+
+const defaultTimeout = 10 * time.Second
+
+// Config is the configuration of a Producer.
+type Config struct {
+	Brokers     []string      // bootstrap brokers, as host:port
+	ClientID    string        // client ID sent with all requests
+	Acks        Acks          // required acknowledgements, defaults to AcksAll
+	Compression int16         // compression of record batches, CompressionNone or CompressionGzip
+	Timeout     time.Duration // timeout of each request, defaults to 10 seconds
+	TLS         *tls.Config   // TLS configuration, nil to connect without TLS
+}
+
+// Producer publishes records to topics. It's safe for concurrent use.
+type Producer struct {
+	config Config
+
+	mtx           sync.Mutex
+	conns         map[string]net.Conn
+	brokers       map[int32]string
+	topics        map[string][]Partition
+	correlationID int32
+	sticky        int
+}
+
+// NewProducer returns a new Producer. Connections to the brokers are made on
+// first use.
+func NewProducer(config Config) *Producer {
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	return &Producer{
+		config:  config,
+		conns:   map[string]net.Conn{},
+		brokers: map[int32]string{},
+		topics:  map[string][]Partition{},
+	}
+}
+
+// Produce publishes the records to the topic. Records with a key are
+// published to the partition the key hashes to, all records without a key
+// are published to the same partition, picked round-robin across calls.
+//
+// If an error is returned, some of the records may have been published
+// nonetheless: retrying leads to at-least-once delivery.
+func (p *Producer) Produce(ctx context.Context, topic string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	partitions, err := p.partitions(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	// group the records by partition, and the partitions by leader
+	byPartition := map[int32][]Record{}
+	sticky := partitions[p.sticky%len(partitions)].Index
+	p.sticky++
+	for _, r := range records {
+		index := sticky
+		if r.Key != nil {
+			index = partitions[partition(r.Key, len(partitions))].Index
+		}
+		byPartition[index] = append(byPartition[index], r)
+	}
+
+	byLeader := map[int32][]ProducePartition{}
+	for _, part := range partitions {
+		rs, ok := byPartition[part.Index]
+		if !ok {
+			continue
+		}
+		if part.ErrorCode != ErrNone || part.Leader < 0 {
+			delete(p.topics, topic)
+			return fmt.Errorf("topic %s partition %d: %w", topic, part.Index, Error{Code: ErrLeaderNotAvailable})
+		}
+		byLeader[part.Leader] = append(byLeader[part.Leader], ProducePartition{Index: part.Index, Records: rs})
+	}
+
+	var errs []error
+	for leader, parts := range byLeader {
+		if err := p.produce(ctx, leader, topic, parts); err != nil {
+			// metadata may be stale, refresh it on the next attempt
+			delete(p.topics, topic)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Producer) produce(ctx context.Context, leader int32, topic string, parts []ProducePartition) error {
+	addr, ok := p.brokers[leader]
+	if !ok {
+		return fmt.Errorf("kafka: unknown broker %d", leader)
+	}
+
+	req := ProduceRequest{
+		Acks:      p.config.Acks.value(),
+		TimeoutMs: int32(p.config.Timeout.Milliseconds()),
+		Topics:    []ProduceTopic{{Name: topic, Partitions: parts}},
+	}
+	var e Encoder
+	if err := req.Encode(&e, p.config.Compression); err != nil {
+		return err
+	}
+
+	d, err := p.roundTrip(ctx, addr, APIKeyProduce, ProduceVersion, e.Bytes(), p.config.Acks != AcksNone)
+	if err != nil || d == nil {
+		return err
+	}
+
+	var resp ProduceResponse
+	if err := resp.Decode(d); err != nil {
+		p.closeConn(addr)
+		return fmt.Errorf("kafka: invalid produce response: %w", err)
+	}
+
+	var errs []error
+	for _, t := range resp.Topics {
+		for _, part := range t.Partitions {
+			if part.ErrorCode != ErrNone {
+				errs = append(errs, fmt.Errorf("topic %s partition %d: %w", t.Name, part.Index, Error{Code: part.ErrorCode}))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// partitions returns the partitions of the topic, from the cached metadata or
+// by requesting it from the brokers.
+func (p *Producer) partitions(ctx context.Context, topic string) ([]Partition, error) {
+	if partitions, ok := p.topics[topic]; ok {
+		return partitions, nil
+	}
+
+	var e Encoder
+	EncodeMetadataRequest(&e, []string{topic})
+
+	addrs := make([]string, 0, len(p.brokers)+len(p.config.Brokers))
+	for _, addr := range p.brokers {
+		addrs = append(addrs, addr)
+	}
+	addrs = append(addrs, p.config.Brokers...)
+
+	var errs []error
+	for _, addr := range addrs {
+		d, err := p.roundTrip(ctx, addr, APIKeyMetadata, MetadataVersion, e.Bytes(), true)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var resp MetadataResponse
+		if err := resp.Decode(d); err != nil {
+			p.closeConn(addr)
+			errs = append(errs, fmt.Errorf("kafka: invalid metadata response: %w", err))
+			continue
+		}
+
+		for _, b := range resp.Brokers {
+			p.brokers[b.NodeID] = net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port)))
+		}
+		for _, t := range resp.Topics {
+			if t.Name != topic {
+				continue
+			}
+			if t.ErrorCode != ErrNone {
+				return nil, fmt.Errorf("topic %s: %w", topic, Error{Code: t.ErrorCode})
+			}
+			if len(t.Partitions) == 0 {
+				return nil, fmt.Errorf("topic %s: no partitions", topic)
+			}
+			// Keys hash to partition IDs, and brokers don't necessarily list
+			// partitions in order.
+			partitions := slices.SortedFunc(slices.Values(t.Partitions), func(a, b Partition) int {
+				return cmp.Compare(a.Index, b.Index)
+			})
+			p.topics[topic] = partitions
+			return partitions, nil
+		}
+		return nil, fmt.Errorf("topic %s: %w", topic, Error{Code: ErrUnknownTopicOrPartition})
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("kafka: no brokers configured")
+	}
+	return nil, fmt.Errorf("kafka: fetch metadata: %w", errors.Join(errs...))
+}
+
+// roundTrip sends a request to the broker at addr, and returns a decoder for
+// the body of its response, if a response is expected.
+func (p *Producer) roundTrip(ctx context.Context, addr string, apiKey, version int16, body []byte, response bool) (*Decoder, error) {
+	conn, err := p.conn(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(p.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		p.closeConn(addr)
+		return nil, err
+	}
+
+	p.correlationID++
+	id := p.correlationID
+	h := RequestHeader{APIKey: apiKey, APIVersion: version, CorrelationID: id, ClientID: p.config.ClientID}
+	if err := WriteRequest(conn, h, body); err != nil {
+		p.closeConn(addr)
+		return nil, fmt.Errorf("kafka: write to %s: %w", addr, err)
+	}
+	if !response {
+		return nil, nil
+	}
+
+	frame, err := ReadFrame(conn)
+	if err != nil {
+		p.closeConn(addr)
+		return nil, fmt.Errorf("kafka: read from %s: %w", addr, err)
+	}
+	d := NewDecoder(frame)
+	if got := d.Int32(); got != id {
+		p.closeConn(addr)
+		return nil, fmt.Errorf("kafka: unexpected correlation ID %d from %s, expected %d", got, addr, id)
+	}
+	return d, nil
+}
+
+func (p *Producer) conn(ctx context.Context, addr string) (net.Conn, error) {
+	if conn, ok := p.conns[addr]; ok {
+		return conn, nil
+	}
+
+	dialer := &net.Dialer{Timeout: p.config.Timeout}
+	var conn net.Conn
+	var err error
+	if p.config.TLS != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.config.TLS}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kafka: connect to %s: %w", addr, err)
+	}
+	p.conns[addr] = conn
+	return conn, nil
+}
+
+func (p *Producer) closeConn(addr string) {
+	if conn, ok := p.conns[addr]; ok {
+		_ = conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+// Close closes all connections to the brokers.
+func (p *Producer) Close() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for addr := range p.conns {
+		p.closeConn(addr)
+	}
+	return nil
+}
+
+// WriteRequest writes the size-prefixed request to w.
+func WriteRequest(w io.Writer, h RequestHeader, body []byte) error {
+	var e Encoder
+	e.Int32(0) // size, set below
+	h.encode(&e)
+	e.buf = append(e.buf, body...)
+	return writeFrame(w, e.buf)
+}
+
+// WriteResponse writes the size-prefixed response to the request with the
+// given correlation ID to w.
+func WriteResponse(w io.Writer, correlationID int32, body []byte) error {
+	var e Encoder
+	e.Int32(0) // size, set below
+	e.Int32(correlationID)
+	e.buf = append(e.buf, body...)
+	return writeFrame(w, e.buf)
+}
+
+func writeFrame(w io.Writer, frame []byte) error {
+	size := len(frame) - 4
+	frame[0], frame[1], frame[2], frame[3] = byte(size>>24), byte(size>>16), byte(size>>8), byte(size)
+	_, err := w.Write(frame)
+	return err
+}
+
+// maxFrameSize guards against allocating huge buffers for corrupt frames.
+const maxFrameSize = 100 << 20
+
+// ReadFrame reads a size-prefixed request or response from r.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := int32(size[0])<<24 | int32(size[1])<<16 | int32(size[2])<<8 | int32(size[3])
+	if n < 0 || n > maxFrameSize {
+		return nil, fmt.Errorf("kafka: invalid frame size %d", n)
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
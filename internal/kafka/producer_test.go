@@ -0,0 +1,137 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package kafka_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/kafka"
+	kafkatest "github.com/open-policy-agent/opa/internal/kafka/test"
+)
+
+func TestProducerKeyedRecords(t *testing.T) {
+	broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 3))
+	t.Cleanup(broker.Stop)
+
+	p := kafka.NewProducer(kafka.Config{Brokers: []string{broker.Addr()}, Acks: kafka.AcksAll, Compression: kafka.CompressionGzip})
+	t.Cleanup(func() { _ = p.Close() })
+
+	var records []kafka.Record
+	for i := range 10 {
+		records = append(records, kafka.Record{
+			Key:       fmt.Appendf(nil, "key-%d", i%4),
+			Value:     fmt.Appendf(nil, "value-%d", i),
+			Timestamp: time.Now(),
+		})
+	}
+	if err := p.Produce(t.Context(), "decisions", records); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, act := len(records), len(broker.Records("decisions")); exp != act {
+		t.Fatalf("expected %d records, got %d", exp, act)
+	}
+
+	// all records with the same key end up in the same partition, in order
+	partitions := map[string]int32{}
+	for i := range int32(3) {
+		last := map[string]string{}
+		for _, r := range broker.PartitionRecords("decisions", i) {
+			if p, ok := partitions[string(r.Key)]; ok && p != i {
+				t.Errorf("key %s in partitions %d and %d", r.Key, p, i)
+			}
+			partitions[string(r.Key)] = i
+			if prev, ok := last[string(r.Key)]; ok && prev >= string(r.Value) {
+				t.Errorf("key %s: %s after %s", r.Key, r.Value, prev)
+			}
+			last[string(r.Key)] = string(r.Value)
+		}
+	}
+
+	// keys are assigned partitions like the Java client's default partitioner does
+	exp := map[string]int32{"key-0": 1, "key-1": 0, "key-2": 2, "key-3": 2}
+	for key, p := range exp {
+		if partitions[key] != p {
+			t.Errorf("key %s: expected partition %d, got %d", key, p, partitions[key])
+		}
+	}
+
+	if exp, act := 1, broker.Requests(kafka.APIKeyMetadata); exp != act {
+		t.Errorf("expected %d metadata requests, got %d", exp, act)
+	}
+}
+
+func TestProducerUnkeyedRecords(t *testing.T) {
+	broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 2))
+	t.Cleanup(broker.Stop)
+
+	p := kafka.NewProducer(kafka.Config{Brokers: []string{broker.Addr()}})
+	t.Cleanup(func() { _ = p.Close() })
+
+	for i := range 2 {
+		records := []kafka.Record{{Value: fmt.Appendf(nil, "a-%d", i)}, {Value: fmt.Appendf(nil, "b-%d", i)}}
+		if err := p.Produce(t.Context(), "decisions", records); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the records of one call go to the same partition, calls are round-robin
+	for i := range int32(2) {
+		if exp, act := 2, len(broker.PartitionRecords("decisions", i)); exp != act {
+			t.Errorf("partition %d: expected %d records, got %d", i, exp, act)
+		}
+	}
+}
+
+func TestProducerErrors(t *testing.T) {
+	broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 1))
+	t.Cleanup(broker.Stop)
+
+	p := kafka.NewProducer(kafka.Config{Brokers: []string{broker.Addr()}})
+	t.Cleanup(func() { _ = p.Close() })
+
+	records := []kafka.Record{{Key: []byte("k"), Value: []byte("v")}}
+
+	err := p.Produce(t.Context(), "unknown", records)
+	if exp := (kafka.Error{Code: kafka.ErrUnknownTopicOrPartition}); !errors.Is(err, exp) {
+		t.Fatalf("expected %v, got %v", exp, err)
+	}
+
+	broker.FailProduce(kafka.ErrNotLeaderForPartition)
+	err = p.Produce(t.Context(), "decisions", records)
+	if exp := (kafka.Error{Code: kafka.ErrNotLeaderForPartition}); !errors.Is(err, exp) {
+		t.Fatalf("expected %v, got %v", exp, err)
+	}
+	if len(broker.Records("decisions")) != 0 {
+		t.Fatal("expected no records")
+	}
+
+	// metadata is refreshed after errors, and the retry succeeds
+	if err := p.Produce(t.Context(), "decisions", records); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 1, len(broker.Records("decisions")); exp != act {
+		t.Errorf("expected %d records, got %d", exp, act)
+	}
+	if exp, act := 3, broker.Requests(kafka.APIKeyMetadata); exp != act {
+		t.Errorf("expected %d metadata requests, got %d", exp, act)
+	}
+}
+
+func TestProducerBrokerUnavailable(t *testing.T) {
+	broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 1))
+	addr := broker.Addr()
+	broker.Stop()
+
+	p := kafka.NewProducer(kafka.Config{Brokers: []string{addr}, Timeout: time.Second})
+	t.Cleanup(func() { _ = p.Close() })
+
+	if err := p.Produce(t.Context(), "decisions", []kafka.Record{{Value: []byte("v")}}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,616 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// API keys and versions of the requests implemented. These versions are
+// supported by all brokers since Kafka 0.11.
+const (
+	APIKeyProduce  int16 = 0
+	APIKeyMetadata int16 = 3
+
+	ProduceVersion  int16 = 3
+	MetadataVersion int16 = 1
+)
+
+// Error codes returned by brokers that are handled by the producer. See
+// https://kafka.apache.org/protocol#protocol_error_codes for all of them.
+const (
+	ErrNone                    int16 = 0
+	ErrUnknownTopicOrPartition int16 = 3
+	ErrLeaderNotAvailable      int16 = 5
+	ErrNotLeaderForPartition   int16 = 6
+	ErrRequestTimedOut         int16 = 7
+	ErrMessageTooLarge         int16 = 10
+	ErrNotEnoughReplicas       int16 = 19
+)
+
+// Attributes of record batches.
+const (
+	CompressionNone int16 = 0
+	CompressionGzip int16 = 1
+
+	compressionMask int16 = 0x07
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// Error is an error code returned by a broker.
+type Error struct {
+	Code int16
+}
+
+func (e Error) Error() string {
+	switch e.Code {
+	case ErrUnknownTopicOrPartition:
+		return "kafka: unknown topic or partition"
+	case ErrLeaderNotAvailable:
+		return "kafka: leader not available"
+	case ErrNotLeaderForPartition:
+		return "kafka: not leader for partition"
+	case ErrRequestTimedOut:
+		return "kafka: request timed out"
+	case ErrMessageTooLarge:
+		return "kafka: message too large"
+	case ErrNotEnoughReplicas:
+		return "kafka: not enough replicas"
+	}
+	return fmt.Sprintf("kafka: error code %d", e.Code)
+}
+
+// Record is a message published to a topic.
+type Record struct {
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+}
+
+// Broker is a broker of a cluster as described by a metadata response.
+type Broker struct {
+	NodeID int32
+	Host   string
+	Port   int32
+}
+
+// Partition is a partition of a topic as described by a metadata response.
+type Partition struct {
+	ErrorCode int16
+	Index     int32
+	Leader    int32
+}
+
+// Topic is a topic as described by a metadata response.
+type Topic struct {
+	ErrorCode  int16
+	Name       string
+	Partitions []Partition
+}
+
+// MetadataResponse is the response to a metadata request (version 1).
+type MetadataResponse struct {
+	Brokers    []Broker
+	Controller int32
+	Topics     []Topic
+}
+
+// ProduceRequest is a produce request (version 3).
+type ProduceRequest struct {
+	Acks      int16
+	TimeoutMs int32
+	Topics    []ProduceTopic
+}
+
+// ProduceTopic holds the record batches to append to the partitions of a topic.
+type ProduceTopic struct {
+	Name       string
+	Partitions []ProducePartition
+}
+
+// ProducePartition holds the records to append to a partition.
+type ProducePartition struct {
+	Index   int32
+	Records []Record
+}
+
+// ProduceResponse is the response to a produce request (version 3).
+type ProduceResponse struct {
+	Topics []ProduceTopicResponse
+}
+
+// ProduceTopicResponse holds the results of appending to the partitions of a
+// topic.
+type ProduceTopicResponse struct {
+	Name       string
+	Partitions []ProducePartitionResponse
+}
+
+// ProducePartitionResponse holds the result of appending to a partition.
+type ProducePartitionResponse struct {
+	Index      int32
+	ErrorCode  int16
+	BaseOffset int64
+}
+
+// Encoder appends the protocol's primitive types to a buffer.
+type Encoder struct {
+	buf []byte
+}
+
+func (e *Encoder) Bytes() []byte { return e.buf }
+
+func (e *Encoder) Int8(v int8)   { e.buf = append(e.buf, byte(v)) }
+func (e *Encoder) Int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *Encoder) Int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *Encoder) Int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *Encoder) Varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *Encoder) Bool(v bool) {
+	if v {
+		e.Int8(1)
+	} else {
+		e.Int8(0)
+	}
+}
+
+func (e *Encoder) String(s string) {
+	e.Int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// NullableString encodes the empty string as null.
+func (e *Encoder) NullableString(s string) {
+	if s == "" {
+		e.Int16(-1)
+		return
+	}
+	e.String(s)
+}
+
+func (e *Encoder) Bytes32(bs []byte) {
+	if bs == nil {
+		e.Int32(-1)
+		return
+	}
+	e.Int32(int32(len(bs)))
+	e.buf = append(e.buf, bs...)
+}
+
+func (e *Encoder) VarintBytes(bs []byte) {
+	if bs == nil {
+		e.Varint(-1)
+		return
+	}
+	e.Varint(int64(len(bs)))
+	e.buf = append(e.buf, bs...)
+}
+
+// Decoder reads the protocol's primitive types from a buffer. The first error
+// encountered is kept, and all subsequent reads return zero values.
+type Decoder struct {
+	buf []byte
+	err error
+}
+
+func NewDecoder(bs []byte) *Decoder {
+	return &Decoder{buf: bs}
+}
+
+// Err returns the first error encountered while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) Remaining() int {
+	return len(d.buf)
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	bs := d.buf[:n]
+	d.buf = d.buf[n:]
+	return bs
+}
+
+func (d *Decoder) Int8() int8 {
+	if bs := d.next(1); bs != nil {
+		return int8(bs[0])
+	}
+	return 0
+}
+
+func (d *Decoder) Int16() int16 {
+	if bs := d.next(2); bs != nil {
+		return int16(binary.BigEndian.Uint16(bs))
+	}
+	return 0
+}
+
+func (d *Decoder) Int32() int32 {
+	if bs := d.next(4); bs != nil {
+		return int32(binary.BigEndian.Uint32(bs))
+	}
+	return 0
+}
+
+func (d *Decoder) Int64() int64 {
+	if bs := d.next(8); bs != nil {
+		return int64(binary.BigEndian.Uint64(bs))
+	}
+	return 0
+}
+
+func (d *Decoder) Varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("kafka: invalid varint")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *Decoder) Bool() bool {
+	return d.Int8() != 0
+}
+
+func (d *Decoder) String() string {
+	n := d.Int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+func (d *Decoder) Bytes32() []byte {
+	n := d.Int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+func (d *Decoder) VarintBytes() []byte {
+	n := d.Varint()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// ArrayLen reads the length of an array, treating null arrays as empty.
+func (d *Decoder) ArrayLen() int {
+	n := d.Int32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.buf) { // every element takes at least one byte
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	return int(n)
+}
+
+// RequestHeader is the header of every request (version 1).
+type RequestHeader struct {
+	APIKey        int16
+	APIVersion    int16
+	CorrelationID int32
+	ClientID      string
+}
+
+func (h RequestHeader) encode(e *Encoder) {
+	e.Int16(h.APIKey)
+	e.Int16(h.APIVersion)
+	e.Int32(h.CorrelationID)
+	e.NullableString(h.ClientID)
+}
+
+// DecodeRequestHeader reads a request header.
+func DecodeRequestHeader(d *Decoder) RequestHeader {
+	return RequestHeader{
+		APIKey:        d.Int16(),
+		APIVersion:    d.Int16(),
+		CorrelationID: d.Int32(),
+		ClientID:      d.String(),
+	}
+}
+
+// EncodeMetadataRequest encodes a metadata request body for the given topics.
+func EncodeMetadataRequest(e *Encoder, topics []string) {
+	e.Int32(int32(len(topics)))
+	for _, t := range topics {
+		e.String(t)
+	}
+}
+
+// DecodeMetadataRequest decodes a metadata request body.
+func DecodeMetadataRequest(d *Decoder) []string {
+	topics := make([]string, d.ArrayLen())
+	for i := range topics {
+		topics[i] = d.String()
+	}
+	return topics
+}
+
+func (r *MetadataResponse) Encode(e *Encoder) {
+	e.Int32(int32(len(r.Brokers)))
+	for _, b := range r.Brokers {
+		e.Int32(b.NodeID)
+		e.String(b.Host)
+		e.Int32(b.Port)
+		e.NullableString("") // rack
+	}
+	e.Int32(r.Controller)
+	e.Int32(int32(len(r.Topics)))
+	for _, t := range r.Topics {
+		e.Int16(t.ErrorCode)
+		e.String(t.Name)
+		e.Bool(false) // is_internal
+		e.Int32(int32(len(t.Partitions)))
+		for _, p := range t.Partitions {
+			e.Int16(p.ErrorCode)
+			e.Int32(p.Index)
+			e.Int32(p.Leader)
+			e.Int32(1) // replicas
+			e.Int32(p.Leader)
+			e.Int32(1) // in-sync replicas
+			e.Int32(p.Leader)
+		}
+	}
+}
+
+func (r *MetadataResponse) Decode(d *Decoder) error {
+	r.Brokers = make([]Broker, d.ArrayLen())
+	for i := range r.Brokers {
+		r.Brokers[i] = Broker{NodeID: d.Int32(), Host: d.String(), Port: d.Int32()}
+		_ = d.String() // rack
+	}
+	r.Controller = d.Int32()
+	r.Topics = make([]Topic, d.ArrayLen())
+	for i := range r.Topics {
+		t := &r.Topics[i]
+		t.ErrorCode = d.Int16()
+		t.Name = d.String()
+		_ = d.Bool() // is_internal
+		t.Partitions = make([]Partition, d.ArrayLen())
+		for j := range t.Partitions {
+			t.Partitions[j] = Partition{ErrorCode: d.Int16(), Index: d.Int32(), Leader: d.Int32()}
+			for range d.ArrayLen() { // replicas
+				_ = d.Int32()
+			}
+			for range d.ArrayLen() { // in-sync replicas
+				_ = d.Int32()
+			}
+		}
+	}
+	return d.Err()
+}
+
+// Encode encodes the produce request body, with the records of each partition
+// in a single record batch.
+func (r *ProduceRequest) Encode(e *Encoder, compression int16) error {
+	e.NullableString("") // transactional_id
+	e.Int16(r.Acks)
+	e.Int32(r.TimeoutMs)
+	e.Int32(int32(len(r.Topics)))
+	for _, t := range r.Topics {
+		e.String(t.Name)
+		e.Int32(int32(len(t.Partitions)))
+		for _, p := range t.Partitions {
+			e.Int32(p.Index)
+			batch, err := EncodeRecordBatch(p.Records, compression)
+			if err != nil {
+				return err
+			}
+			e.Bytes32(batch)
+		}
+	}
+	return nil
+}
+
+func (r *ProduceRequest) Decode(d *Decoder) error {
+	_ = d.String() // transactional_id
+	r.Acks = d.Int16()
+	r.TimeoutMs = d.Int32()
+	r.Topics = make([]ProduceTopic, d.ArrayLen())
+	for i := range r.Topics {
+		t := &r.Topics[i]
+		t.Name = d.String()
+		t.Partitions = make([]ProducePartition, d.ArrayLen())
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			p.Index = d.Int32()
+			batches := d.Bytes32()
+			if d.Err() != nil {
+				break
+			}
+			records, err := DecodeRecordBatches(batches)
+			if err != nil {
+				return err
+			}
+			p.Records = records
+		}
+	}
+	return d.Err()
+}
+
+func (r *ProduceResponse) Encode(e *Encoder) {
+	e.Int32(int32(len(r.Topics)))
+	for _, t := range r.Topics {
+		e.String(t.Name)
+		e.Int32(int32(len(t.Partitions)))
+		for _, p := range t.Partitions {
+			e.Int32(p.Index)
+			e.Int16(p.ErrorCode)
+			e.Int64(p.BaseOffset)
+			e.Int64(-1) // log_append_time_ms
+		}
+	}
+	e.Int32(0) // throttle_time_ms
+}
+
+func (r *ProduceResponse) Decode(d *Decoder) error {
+	r.Topics = make([]ProduceTopicResponse, d.ArrayLen())
+	for i := range r.Topics {
+		t := &r.Topics[i]
+		t.Name = d.String()
+		t.Partitions = make([]ProducePartitionResponse, d.ArrayLen())
+		for j := range t.Partitions {
+			t.Partitions[j] = ProducePartitionResponse{Index: d.Int32(), ErrorCode: d.Int16(), BaseOffset: d.Int64()}
+			_ = d.Int64() // log_append_time_ms
+		}
+	}
+	_ = d.Int32() // throttle_time_ms
+	return d.Err()
+}
+
+// EncodeRecordBatch encodes the records into a record batch (magic 2).
+func EncodeRecordBatch(records []Record, compression int16) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, errors.New("kafka: empty record batch")
+	}
+
+	base := records[0].Timestamp.UnixMilli()
+	maxTimestamp := base
+	var recs Encoder
+	for i, r := range records {
+		ts := r.Timestamp.UnixMilli()
+		maxTimestamp = max(maxTimestamp, ts)
+
+		var rec Encoder
+		rec.Int8(0) // attributes
+		rec.Varint(ts - base)
+		rec.Varint(int64(i))
+		rec.VarintBytes(r.Key)
+		rec.VarintBytes(r.Value)
+		rec.Varint(0) // headers
+
+		recs.Varint(int64(len(rec.buf)))
+		recs.buf = append(recs.buf, rec.buf...)
+	}
+
+	payload := recs.buf
+	switch compression {
+	case CompressionNone:
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		payload = buf.Bytes()
+	default:
+		return nil, fmt.Errorf("kafka: unsupported compression %d", compression)
+	}
+
+	// everything covered by the CRC: attributes up to the end of the batch
+	var body Encoder
+	body.Int16(compression)
+	body.Int32(int32(len(records) - 1)) // last offset delta
+	body.Int64(base)
+	body.Int64(maxTimestamp)
+	body.Int64(-1) // producer ID
+	body.Int16(-1) // producer epoch
+	body.Int32(-1) // base sequence
+	body.Int32(int32(len(records)))
+	body.buf = append(body.buf, payload...)
+
+	var e Encoder
+	e.Int64(0)                        // base offset
+	e.Int32(int32(len(body.buf) + 9)) // batch length: leader epoch, magic, CRC, body
+	e.Int32(-1)                       // partition leader epoch
+	e.Int8(2)                         // magic
+	e.buf = binary.BigEndian.AppendUint32(e.buf, crc32.Checksum(body.buf, crc32c))
+	e.buf = append(e.buf, body.buf...)
+	return e.buf, nil
+}
+
+// DecodeRecordBatches decodes the records of consecutive record batches.
+func DecodeRecordBatches(bs []byte) ([]Record, error) {
+	var records []Record
+	d := NewDecoder(bs)
+	for d.Remaining() > 0 {
+		_ = d.Int64() // base offset
+		batch := NewDecoder(d.next(int(d.Int32())))
+		if d.Err() != nil {
+			return nil, d.Err()
+		}
+		_ = batch.Int32() // partition leader epoch
+		if magic := batch.Int8(); magic != 2 {
+			return nil, fmt.Errorf("kafka: unsupported record batch version %d", magic)
+		}
+		crc := uint32(batch.Int32())
+		if batch.Err() == nil && crc32.Checksum(batch.buf, crc32c) != crc {
+			return nil, errors.New("kafka: record batch CRC mismatch")
+		}
+		attributes := batch.Int16()
+		_ = batch.Int32() // last offset delta
+		base := batch.Int64()
+		_ = batch.Int64() // max timestamp
+		_ = batch.Int64() // producer ID
+		_ = batch.Int16() // producer epoch
+		_ = batch.Int32() // base sequence
+		n := batch.Int32()
+		if batch.Err() != nil {
+			return nil, batch.Err()
+		}
+
+		payload := batch.buf
+		switch attributes & compressionMask {
+		case CompressionNone:
+		case CompressionGzip:
+			r, err := gzip.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			if payload, err = io.ReadAll(r); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("kafka: unsupported compression %d", attributes&compressionMask)
+		}
+
+		rd := NewDecoder(payload)
+		for range n {
+			rec := NewDecoder(rd.next(int(rd.Varint())))
+			_ = rec.Int8() // attributes
+			ts := base + rec.Varint()
+			_ = rec.Varint() // offset delta
+			r := Record{Key: rec.VarintBytes(), Value: rec.VarintBytes(), Timestamp: time.UnixMilli(ts)}
+			if rec.Err() != nil {
+				return nil, rec.Err()
+			}
+			records = append(records, r)
+		}
+		if rd.Err() != nil {
+			return nil, rd.Err()
+		}
+	}
+	return records, d.Err()
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordBatchRoundtrip(t *testing.T) {
+	for _, compression := range []int16{CompressionNone, CompressionGzip} {
+		records := []Record{
+			{Key: []byte("a"), Value: []byte(`{"x":1}`), Timestamp: time.UnixMilli(1000)},
+			{Value: []byte(`{"x":2}`), Timestamp: time.UnixMilli(1500)},
+		}
+		bs, err := EncodeRecordBatch(records, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act, err := DecodeRecordBatches(bs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(records, act) {
+			t.Errorf("compression %d: expected %v, got %v", compression, records, act)
+		}
+
+		bs[len(bs)-1]++
+		if _, err := DecodeRecordBatches(bs); err == nil || err.Error() != "kafka: record batch CRC mismatch" {
+			t.Errorf("compression %d: expected CRC mismatch, got %v", compression, err)
+		}
+	}
+}
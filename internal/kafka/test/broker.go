@@ -0,0 +1,242 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package test provides a local single-node broker speaking the subset of the
+// Kafka protocol used by the producer in internal/kafka, for testing
+// integrations without a Kafka cluster.
+package test
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/open-policy-agent/opa/internal/kafka"
+)
+
+// Topic creates a topic with the given number of partitions on the broker.
+func Topic(name string, partitions int32) func(*Broker) {
+	return func(b *Broker) {
+		b.topics[name] = make([][]kafka.Record, partitions)
+	}
+}
+
+// Broker is a local broker for test purposes. It stores all records produced
+// in memory.
+type Broker struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mtx      sync.Mutex
+	conns    map[net.Conn]struct{}
+	topics   map[string][][]kafka.Record
+	failures []int16
+	requests map[int16]int
+}
+
+// NewBroker starts a new Broker listening on a random local port.
+func NewBroker(opts ...func(*Broker)) (*Broker, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		listener: l,
+		conns:    map[net.Conn]struct{}{},
+		topics:   map[string][][]kafka.Record{},
+		requests: map[int16]int{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// MustNewBroker returns a new Broker or panics if an error occurs.
+func MustNewBroker(opts ...func(*Broker)) *Broker {
+	b, err := NewBroker(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Addr returns the address of the broker, as host:port.
+func (b *Broker) Addr() string {
+	return b.listener.Addr().String()
+}
+
+// Stop closes the listener and all connections, and waits for them to be
+// done.
+func (b *Broker) Stop() {
+	_ = b.listener.Close()
+	b.mtx.Lock()
+	for c := range b.conns {
+		_ = c.Close()
+	}
+	b.mtx.Unlock()
+	b.wg.Wait()
+}
+
+// FailProduce makes the next produce requests fail with the given error
+// codes, one per request, for all partitions.
+func (b *Broker) FailProduce(codes ...int16) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.failures = append(b.failures, codes...)
+}
+
+// Requests returns the number of requests received with the given API key.
+func (b *Broker) Requests(apiKey int16) int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.requests[apiKey]
+}
+
+// Records returns the records of all partitions of the topic, ordered by
+// partition and offset.
+func (b *Broker) Records(topic string) []kafka.Record {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	var records []kafka.Record
+	for _, rs := range b.topics[topic] {
+		records = append(records, rs...)
+	}
+	return records
+}
+
+// PartitionRecords returns the records of one partition of the topic.
+func (b *Broker) PartitionRecords(topic string, partition int32) []kafka.Record {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if partitions := b.topics[topic]; int(partition) < len(partitions) {
+		return slices.Clone(partitions[partition])
+	}
+	return nil
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mtx.Lock()
+		b.conns[conn] = struct{}{}
+		b.mtx.Unlock()
+
+		b.wg.Add(1)
+		go b.serve(conn)
+	}
+}
+
+func (b *Broker) serve(conn net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mtx.Lock()
+		delete(b.conns, conn)
+		b.mtx.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		frame, err := kafka.ReadFrame(conn)
+		if err != nil {
+			return
+		}
+		d := kafka.NewDecoder(frame)
+		h := kafka.DecodeRequestHeader(d)
+		if d.Err() != nil {
+			return
+		}
+
+		b.mtx.Lock()
+		b.requests[h.APIKey]++
+		b.mtx.Unlock()
+
+		var e kafka.Encoder
+		switch {
+		case h.APIKey == kafka.APIKeyMetadata && h.APIVersion == kafka.MetadataVersion:
+			b.metadata(d, &e)
+		case h.APIKey == kafka.APIKeyProduce && h.APIVersion == kafka.ProduceVersion:
+			var req kafka.ProduceRequest
+			if err := req.Decode(d); err != nil {
+				return
+			}
+			b.produce(&req, &e)
+			if req.Acks == 0 { // no response expected
+				continue
+			}
+		default:
+			return // unsupported request: brokers close the connection
+		}
+
+		if err := kafka.WriteResponse(conn, h.CorrelationID, e.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (b *Broker) metadata(d *kafka.Decoder, e *kafka.Encoder) {
+	host, port, _ := net.SplitHostPort(b.Addr())
+	p, _ := strconv.Atoi(port)
+
+	resp := kafka.MetadataResponse{
+		Brokers:    []kafka.Broker{{NodeID: 0, Host: host, Port: int32(p)}},
+		Controller: 0,
+	}
+
+	b.mtx.Lock()
+	for _, name := range kafka.DecodeMetadataRequest(d) {
+		t := kafka.Topic{Name: name}
+		partitions, ok := b.topics[name]
+		if !ok {
+			t.ErrorCode = kafka.ErrUnknownTopicOrPartition
+		}
+		// Partitions are reported in reverse, as clients mustn't rely on
+		// their order.
+		for i := len(partitions) - 1; i >= 0; i-- {
+			t.Partitions = append(t.Partitions, kafka.Partition{Index: int32(i), Leader: 0})
+		}
+		resp.Topics = append(resp.Topics, t)
+	}
+	b.mtx.Unlock()
+
+	resp.Encode(e)
+}
+
+func (b *Broker) produce(req *kafka.ProduceRequest, e *kafka.Encoder) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	failure := kafka.ErrNone
+	if len(b.failures) > 0 {
+		failure, b.failures = b.failures[0], b.failures[1:]
+	}
+
+	var resp kafka.ProduceResponse
+	for _, t := range req.Topics {
+		tr := kafka.ProduceTopicResponse{Name: t.Name}
+		partitions := b.topics[t.Name]
+		for _, p := range t.Partitions {
+			pr := kafka.ProducePartitionResponse{Index: p.Index, ErrorCode: failure}
+			switch {
+			case failure != kafka.ErrNone:
+			case int(p.Index) >= len(partitions) || p.Index < 0:
+				pr.ErrorCode = kafka.ErrUnknownTopicOrPartition
+			default:
+				pr.BaseOffset = int64(len(partitions[p.Index]))
+				partitions[p.Index] = append(partitions[p.Index], p.Records...)
+			}
+			tr.Partitions = append(tr.Partitions, pr)
+		}
+		resp.Topics = append(resp.Topics, tr)
+	}
+	resp.Encode(e)
+}
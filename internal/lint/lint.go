@@ -0,0 +1,496 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package lint implements a linter for Rego policies. Lint rules are written in
+// Rego and evaluated against the JSON representation of the linted modules'
+// AST, as output by 'opa parse --format json'.
+//
+// Rules are packages under data.lint.rules, named by their category and name,
+// e.g. 'package lint.rules.style["prefer-snake-case"]'. Their METADATA
+// annotations provide the title and description of the rule, and these custom
+// attributes:
+//
+//	level   - "error" (default) or "warning"
+//	enabled - false to disable the rule unless it's enabled explicitly
+//
+// A rule reports violations in one of two modes:
+//
+//   - report contains violation: evaluated once for each module, with the
+//     module's AST as input.
+//   - aggregate contains entry, and aggregate_report contains violation:
+//     aggregate is evaluated for each module like report, and aggregate_report
+//     once for all modules, with the entries of all modules in
+//     input.aggregate. This allows rules to check properties across modules.
+//
+// Violations are objects with a "description" and a "location", which holds
+// the "row" and "col" of the violation and optionally its "file". The file of
+// the linted module is available in input.lint.file.
+package lint
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	astJSON "github.com/open-policy-agent/opa/v1/ast/json"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// Levels of violations.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+//go:embed rules
+var builtinRules embed.FS
+
+var rulesRef = ast.MustParseRef("data.lint.rules")
+
+// Rule describes a lint rule.
+type Rule struct {
+	Category    string `json:"category"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Level       string `json:"level"`
+	Enabled     bool   `json:"enabled"`
+	HelpURL     string `json:"help_url,omitempty"`
+	aggregate   bool
+}
+
+// ID returns the identifier of the rule, e.g. "style/prefer-snake-case".
+func (r *Rule) ID() string {
+	return r.Category + "/" + r.Name
+}
+
+// Location is the location of a violation.
+type Location struct {
+	File string `json:"file"`
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Row, l.Col)
+}
+
+// Violation is a violation of a rule.
+type Violation struct {
+	Rule        string   `json:"rule"`
+	Category    string   `json:"category"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description"`
+	Level       string   `json:"level"`
+	Location    Location `json:"location"`
+}
+
+// Summary summarizes a report.
+type Summary struct {
+	FilesScanned  int `json:"files_scanned"`
+	NumViolations int `json:"num_violations"`
+	NumErrors     int `json:"num_errors"`
+	NumWarnings   int `json:"num_warnings"`
+}
+
+// Report is the result of linting.
+type Report struct {
+	Violations []Violation `json:"violations"`
+	Rules      []*Rule     `json:"-"`
+	Summary    Summary     `json:"summary"`
+}
+
+// Linter lints Rego modules.
+type Linter struct {
+	modules         map[string]*ast.Module
+	ruleModules     map[string]*ast.Module
+	enabled         []string
+	disabled        []string
+	deprecated      []string
+	excludeBuiltins bool
+}
+
+// New returns a new Linter with the built-in rules.
+func New() *Linter {
+	var deprecated []string
+	for _, b := range ast.Builtins {
+		if b.IsDeprecated() {
+			deprecated = append(deprecated, b.Name)
+		}
+	}
+	slices.Sort(deprecated)
+
+	return &Linter{deprecated: deprecated}
+}
+
+// WithInputModules sets the modules to lint, keyed by their file names.
+func (l *Linter) WithInputModules(modules map[string]*ast.Module) *Linter {
+	l.modules = modules
+	return l
+}
+
+// WithRuleModules adds custom rules, keyed by their file names.
+func (l *Linter) WithRuleModules(modules map[string]*ast.Module) *Linter {
+	l.ruleModules = modules
+	return l
+}
+
+// WithEnabledRules enables rules that are disabled by default. Rules are
+// identified by their ID, or all rules of a category by the category name.
+func (l *Linter) WithEnabledRules(rules []string) *Linter {
+	l.enabled = rules
+	return l
+}
+
+// WithDisabledRules disables rules. Rules are identified by their ID, or all
+// rules of a category by the category name. Disabling takes precedence over
+// enabling.
+func (l *Linter) WithDisabledRules(rules []string) *Linter {
+	l.disabled = rules
+	return l
+}
+
+// WithoutBuiltinRules skips the built-in rules, so that only custom rules are
+// evaluated.
+func (l *Linter) WithoutBuiltinRules(yes bool) *Linter {
+	l.excludeBuiltins = yes
+	return l
+}
+
+// Rules returns the rules of the linter, compiling them if necessary.
+func (l *Linter) Rules() ([]*Rule, error) {
+	_, rules, err := l.compileRules()
+	return rules, err
+}
+
+// Lint lints the input modules and returns the violations found.
+func (l *Linter) Lint(ctx context.Context) (*Report, error) {
+	compiler, rules, err := l.compileRules()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range append(slices.Clone(l.enabled), l.disabled...) {
+		if !slices.ContainsFunc(rules, func(r *Rule) bool { return r.ID() == id || r.Category == id }) {
+			return nil, fmt.Errorf("unknown rule or category %q", id)
+		}
+	}
+
+	byID := make(map[string]*Rule, len(rules))
+	for _, r := range rules {
+		r.Enabled = (r.Enabled || l.matches(l.enabled, r)) && !l.matches(l.disabled, r)
+		byID[r.ID()] = r
+	}
+
+	store := inmem.NewFromObject(map[string]any{"lint": map[string]any{"deprecated_builtins": l.deprecated}})
+
+	prepare := func(query string) (rego.PreparedEvalQuery, error) {
+		return rego.New(
+			rego.Query(query),
+			rego.Compiler(compiler),
+			rego.Store(store),
+		).PrepareForEval(ctx)
+	}
+
+	reportQuery, err := prepare("data.lint.rules[category][name].report[violation]")
+	if err != nil {
+		return nil, err
+	}
+	aggregateQuery, err := prepare("data.lint.rules[category][name].aggregate[entry]")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Violations: []Violation{}, Rules: rules}
+	aggregates := map[string][]any{}
+
+	files := util.KeysSorted(l.modules)
+	for _, file := range files {
+		input, err := moduleInput(file, l.modules[file])
+		if err != nil {
+			return nil, err
+		}
+
+		rs, err := reportQuery.Eval(ctx, rego.EvalParsedInput(input))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, r := range rs {
+			if v, ok := violation(byID, r.Bindings, file); ok {
+				report.Violations = append(report.Violations, v)
+			}
+		}
+
+		rs, err = aggregateQuery.Eval(ctx, rego.EvalParsedInput(input))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, r := range rs {
+			id := fmt.Sprintf("%v/%v", r.Bindings["category"], r.Bindings["name"])
+			if rule, ok := byID[id]; ok && rule.Enabled {
+				aggregates[id] = append(aggregates[id], r.Bindings["entry"])
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if !rule.aggregate || !rule.Enabled {
+			continue
+		}
+
+		entries := aggregates[rule.ID()]
+		if entries == nil {
+			entries = []any{}
+		}
+		input, err := ast.InterfaceToValue(map[string]any{"aggregate": entries})
+		if err != nil {
+			return nil, err
+		}
+		// Only the rule's own aggregate_report is evaluated: the input holds
+		// the entries of this rule alone.
+		query, err := prepare(fmt.Sprintf("category := %v; name := %v; data.lint.rules[category][name].aggregate_report[violation]",
+			ast.StringTerm(rule.Category), ast.StringTerm(rule.Name)))
+		if err != nil {
+			return nil, err
+		}
+		rs, err := query.Eval(ctx, rego.EvalParsedInput(input))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			if v, ok := violation(byID, r.Bindings, ""); ok {
+				report.Violations = append(report.Violations, v)
+			}
+		}
+	}
+
+	slices.SortStableFunc(report.Violations, func(a, b Violation) int {
+		if c := strings.Compare(a.Location.File, b.Location.File); c != 0 {
+			return c
+		}
+		if a.Location.Row != b.Location.Row {
+			return a.Location.Row - b.Location.Row
+		}
+		if a.Location.Col != b.Location.Col {
+			return a.Location.Col - b.Location.Col
+		}
+		return strings.Compare(a.Rule, b.Rule)
+	})
+
+	report.Summary.FilesScanned = len(files)
+	report.Summary.NumViolations = len(report.Violations)
+	for _, v := range report.Violations {
+		if v.Level == LevelError {
+			report.Summary.NumErrors++
+		} else {
+			report.Summary.NumWarnings++
+		}
+	}
+
+	return report, nil
+}
+
+func (*Linter) matches(ids []string, r *Rule) bool {
+	return slices.Contains(ids, r.ID()) || slices.Contains(ids, r.Category)
+}
+
+// compileRules compiles the built-in and custom rules, and returns the rules
+// described by their annotations.
+func (l *Linter) compileRules() (*ast.Compiler, []*Rule, error) {
+	modules := make(map[string]*ast.Module, len(l.ruleModules))
+
+	if !l.excludeBuiltins {
+		err := fs.WalkDir(builtinRules, "rules", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			bs, err := builtinRules.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			m, err := ast.ParseModuleWithOpts(path, string(bs), ast.ParserOptions{ProcessAnnotation: true, RegoVersion: ast.RegoV1})
+			if err != nil {
+				return err
+			}
+			modules["builtin:"+path] = m
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for file, m := range l.ruleModules {
+		modules[file] = m
+	}
+
+	compiler := ast.NewCompiler().WithEnablePrintStatements(false)
+	if compiler.Compile(modules); compiler.Failed() {
+		return nil, nil, compiler.Errors
+	}
+
+	as := compiler.GetAnnotationSet()
+	seen := map[string]*Rule{}
+	var rules []*Rule
+
+	for _, file := range util.KeysSorted(modules) {
+		m := compiler.Modules[file]
+		path := m.Package.Path
+		if len(path) != len(rulesRef)+2 || !path.HasPrefix(rulesRef) {
+			continue
+		}
+		category, ok1 := path[len(rulesRef)].Value.(ast.String)
+		name, ok2 := path[len(rulesRef)+1].Value.(ast.String)
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		id := string(category) + "/" + string(name)
+		rule, ok := seen[id]
+		if !ok {
+			rule = &Rule{Category: string(category), Name: string(name), Level: LevelError, Enabled: true}
+			seen[id] = rule
+			rules = append(rules, rule)
+		}
+
+		for _, r := range m.Rules {
+			if r.Head.Name == "aggregate_report" {
+				rule.aggregate = true
+			}
+		}
+
+		if a := as.GetPackageScope(m.Package); a != nil {
+			if err := rule.setAnnotations(a); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b *Rule) int {
+		return strings.Compare(a.ID(), b.ID())
+	})
+
+	return compiler, rules, nil
+}
+
+func (r *Rule) setAnnotations(a *ast.Annotations) error {
+	if a.Title != "" {
+		r.Title = a.Title
+	}
+	if a.Description != "" {
+		r.Description = strings.TrimSpace(a.Description)
+	}
+	for _, rr := range a.RelatedResources {
+		r.HelpURL = rr.Ref.String()
+		break
+	}
+
+	switch level := a.Custom["level"]; level {
+	case nil:
+	case LevelError, LevelWarning:
+		r.Level = level.(string)
+	default:
+		return fmt.Errorf("rule %s: invalid level %v", r.ID(), level)
+	}
+
+	switch enabled := a.Custom["enabled"].(type) {
+	case nil:
+	case bool:
+		r.Enabled = enabled
+	default:
+		return fmt.Errorf("rule %s: invalid enabled value %v", r.ID(), enabled)
+	}
+
+	return nil
+}
+
+// moduleInput returns the input for evaluating rules against module m, which
+// is the JSON representation of the module's AST, including locations.
+func moduleInput(file string, m *ast.Module) (ast.Value, error) {
+	opts := astJSON.GetOptions()
+	astJSON.SetOptions(astJSON.Options{
+		MarshalOptions: astJSON.MarshalOptions{
+			ExcludeLocationFile: true,
+			IncludeLocation: astJSON.NodeToggle{
+				Term:           true,
+				Package:        true,
+				Comment:        true,
+				Import:         true,
+				Rule:           true,
+				Head:           true,
+				Expr:           true,
+				SomeDecl:       true,
+				Every:          true,
+				With:           true,
+				Annotations:    true,
+				AnnotationsRef: true,
+				Not:            true,
+				And:            true,
+				Or:             true,
+			},
+		},
+	})
+	defer astJSON.SetOptions(opts)
+
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var input map[string]any
+	if err := util.UnmarshalJSON(bs, &input); err != nil {
+		return nil, err
+	}
+	input["lint"] = map[string]any{"file": file}
+
+	return ast.InterfaceToValue(input)
+}
+
+// violation returns the violation in the bindings of a result, unless the rule
+// that reported it is disabled. Violations without file are attributed to
+// file.
+func violation(rules map[string]*Rule, bindings rego.Vars, file string) (Violation, bool) {
+	id := fmt.Sprintf("%v/%v", bindings["category"], bindings["name"])
+	rule, ok := rules[id]
+	if !ok || !rule.Enabled {
+		return Violation{}, false
+	}
+
+	v := Violation{
+		Rule:        id,
+		Category:    rule.Category,
+		Title:       rule.Title,
+		Description: rule.Title,
+		Level:       rule.Level,
+		Location:    Location{File: file},
+	}
+
+	obj, _ := bindings["violation"].(map[string]any)
+	if desc, ok := obj["description"].(string); ok {
+		v.Description = desc
+	}
+	if loc, ok := obj["location"].(map[string]any); ok {
+		if f, ok := loc["file"].(string); ok && f != "" {
+			v.Location.File = f
+		}
+		v.Location.Row = jsonInt(loc["row"])
+		v.Location.Col = jsonInt(loc["col"])
+	}
+
+	return v, true
+}
+
+func jsonInt(x any) int {
+	if n, ok := x.(json.Number); ok {
+		i, _ := n.Int64()
+		return int(i)
+	}
+	return 0
+}
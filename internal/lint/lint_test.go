@@ -0,0 +1,195 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+func parseModules(t *testing.T, files map[string]string) map[string]*ast.Module {
+	t.Helper()
+
+	modules := make(map[string]*ast.Module, len(files))
+	for file, src := range files {
+		m, err := ast.ParseModuleWithOpts(file, src, ast.ParserOptions{ProcessAnnotation: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		modules[file] = m
+	}
+	return modules
+}
+
+func violations(report *Report) []string {
+	vs := make([]string, 0, len(report.Violations))
+	for _, v := range report.Violations {
+		vs = append(vs, fmt.Sprintf("%v: %s: %s: %s", v.Location, v.Level, v.Rule, v.Description))
+	}
+	return vs
+}
+
+func TestLintBuiltinRules(t *testing.T) {
+	t.Parallel()
+
+	modules := parseModules(t, map[string]string{
+		"a.rego": `package a
+
+import data.b
+import data.c.d
+import data.e as f
+import rego.v1
+
+allowUsers if d.x
+
+g := net.cidr_overlap("10.0.0.0/8", "10.0.0.1")
+`,
+		"b/b.rego": `# METADATA
+# title: B
+package b.c
+
+x := data.b.y
+
+y := 1
+`,
+	})
+
+	report, err := New().WithInputModules(modules).WithEnabledRules([]string{"docs"}).Lint(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		"a.rego:1:1: warning: docs/missing-metadata: Package a has no METADATA",
+		"a.rego:3:1: warning: imports/unused-import: Import b is unused",
+		"a.rego:4:1: warning: imports/unresolved-import: Import data.c.d doesn't refer to a known package",
+		"a.rego:5:1: warning: imports/unresolved-import: Import data.e doesn't refer to a known package",
+		"a.rego:5:1: warning: imports/unused-import: Import f is unused",
+		"a.rego:8:1: warning: style/prefer-snake-case: Rule allowUsers is not in snake_case",
+		"a.rego:10:6: error: bugs/deprecated-builtin: Call to deprecated built-in function net.cidr_overlap",
+	}
+	if act := violations(report); !slices.Equal(act, exp) {
+		t.Fatalf("expected:\n%v\n\ngot:\n%v", exp, act)
+	}
+
+	if exp := (Summary{FilesScanned: 2, NumViolations: 7, NumErrors: 1, NumWarnings: 6}); report.Summary != exp {
+		t.Fatalf("expected summary %+v, got %+v", exp, report.Summary)
+	}
+}
+
+func TestLintCustomRules(t *testing.T) {
+	t.Parallel()
+
+	rules := parseModules(t, map[string]string{
+		"rules/custom.rego": `# METADATA
+# title: No foo
+# description: Rules must not be named foo.
+# related_resources:
+# - https://example.com/no-foo
+package lint.rules.custom["no-foo"]
+
+report contains {"description": "Rule foo", "location": rule.location} if {
+	some rule in input.rules
+	rule.head.ref[0].value == "foo"
+}
+`,
+		"rules/count.rego": `# METADATA
+# title: Too many packages
+# custom:
+#   level: warning
+package lint.rules.custom["max-packages"]
+
+aggregate contains input["package"].location
+
+aggregate_report contains {"description": sprintf("%d packages", [count(input.aggregate)])} if {
+	count(input.aggregate) > 1
+}
+`,
+	})
+	modules := parseModules(t, map[string]string{
+		"x.rego": "package x\n\nfoo := 1\n",
+		"y.rego": "package y\n\nfooBar := 1\n",
+	})
+
+	linter := New().WithInputModules(modules).WithRuleModules(rules)
+
+	report, err := linter.Lint(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		":0:0: warning: custom/max-packages: 2 packages",
+		"x.rego:3:1: error: custom/no-foo: Rule foo",
+		"y.rego:3:1: warning: style/prefer-snake-case: Rule fooBar is not in snake_case",
+	}
+	if act := violations(report); !slices.Equal(act, exp) {
+		t.Fatalf("expected:\n%v\n\ngot:\n%v", exp, act)
+	}
+
+	var rule *Rule
+	for _, r := range report.Rules {
+		if r.ID() == "custom/no-foo" {
+			rule = r
+		}
+	}
+	if rule == nil || rule.Title != "No foo" || rule.Description != "Rules must not be named foo." || rule.HelpURL != "https://example.com/no-foo" {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+
+	// only custom rules, with a disabled category
+	report, err = linter.WithoutBuiltinRules(true).WithDisabledRules([]string{"custom/max-packages"}).Lint(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = []string{"x.rego:3:1: error: custom/no-foo: Rule foo"}
+	if act := violations(report); !slices.Equal(act, exp) {
+		t.Fatalf("expected:\n%v\n\ngot:\n%v", exp, act)
+	}
+}
+
+func TestLintDisabledRules(t *testing.T) {
+	t.Parallel()
+
+	modules := parseModules(t, map[string]string{
+		"x.rego": "package x\n\nimport data.y\n\nfooBar := 1\n",
+	})
+
+	report, err := New().WithInputModules(modules).WithDisabledRules([]string{"imports", "style/prefer-snake-case"}).Lint(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations(report))
+	}
+
+	_, err = New().WithInputModules(modules).WithDisabledRules([]string{"style/unknown"}).Lint(context.Background())
+	if err == nil || err.Error() != `unknown rule or category "style/unknown"` {
+		t.Fatalf("expected unknown rule error, got %v", err)
+	}
+}
+
+func TestLintInvalidRule(t *testing.T) {
+	t.Parallel()
+
+	rules := parseModules(t, map[string]string{
+		"rules/invalid.rego": `# METADATA
+# custom:
+#   level: fatal
+package lint.rules.custom.invalid
+
+report := set()
+`,
+	})
+
+	_, err := New().WithRuleModules(rules).Lint(context.Background())
+	if err == nil || err.Error() != "rules/invalid.rego: rule custom/invalid: invalid level fatal" {
+		t.Fatalf("expected invalid level error, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+# METADATA
+# title: Call to deprecated built-in function
+# description: |
+#   Deprecated built-in functions are not available in Rego v1, and should be
+#   replaced by their successors.
+# custom:
+#   level: error
+package lint.rules.bugs["deprecated-builtin"]
+
+import data.lint.util
+
+report contains violation if {
+	some [ref, location] in util.calls(input.rules)
+	name := util.ref_name(ref)
+	name in data.lint.deprecated_builtins
+
+	violation := {
+		"description": sprintf("Call to deprecated built-in function %s", [name]),
+		"location": location,
+	}
+}
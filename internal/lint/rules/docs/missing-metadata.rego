@@ -0,0 +1,27 @@
+# METADATA
+# title: Package without METADATA
+# description: |
+#   Packages should be documented with a METADATA comment with package or
+#   subpackages scope. Test packages are exempt. This rule is disabled by
+#   default.
+# custom:
+#   level: warning
+#   enabled: false
+package lint.rules.docs["missing-metadata"]
+
+import data.lint.util
+
+report contains violation if {
+	not endswith(util.package_path[count(util.package_path) - 1], "_test")
+	not documented
+
+	violation := {
+		"description": sprintf("Package %s has no METADATA", [concat(".", util.package_path)]),
+		"location": input["package"].location,
+	}
+}
+
+documented if {
+	some annotation in input.annotations
+	annotation.scope in {"package", "subpackages"}
+}
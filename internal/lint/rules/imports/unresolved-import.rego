@@ -0,0 +1,48 @@
+# METADATA
+# title: Import of unknown package
+# description: |
+#   Imports of data should refer to a package of the linted policies. Imports
+#   of data loaded from JSON or YAML files are reported too, so this rule is
+#   best disabled for policies that import base documents.
+# custom:
+#   level: warning
+package lint.rules.imports["unresolved-import"]
+
+import data.lint.util
+
+aggregate contains {
+	"file": input.lint.file,
+	"package": util.package_path,
+	"imports": imports,
+}
+
+imports contains {"path": path, "location": imp.location} if {
+	some imp in input.imports
+	imp.path.value[0].value == "data"
+	path := [term.value | some term in array.slice(imp.path.value, 1, count(imp.path.value))]
+}
+
+aggregate_report contains violation if {
+	packages := {entry["package"] | some entry in input.aggregate}
+
+	some entry in input.aggregate
+	some imp in entry.imports
+	not resolved(imp.path, packages)
+
+	violation := {
+		"description": sprintf("Import data.%s doesn't refer to a known package", [concat(".", imp.path)]),
+		"location": object.union(imp.location, {"file": entry.file}),
+	}
+}
+
+resolved(path, packages) if {
+	some pkg in packages
+	prefix(pkg, path)
+}
+
+resolved(path, packages) if {
+	some pkg in packages
+	prefix(path, pkg)
+}
+
+prefix(a, b) if array.slice(b, 0, count(a)) == a
@@ -0,0 +1,34 @@
+# METADATA
+# title: Unused import
+# description: |
+#   Imports that aren't referenced by any rule of the module can be removed.
+# custom:
+#   level: warning
+package lint.rules.imports["unused-import"]
+
+report contains violation if {
+	some imp in input.imports
+	count(imp.path.value) > 1
+	not imp.path.value[0].value in {"rego", "future"}
+
+	name := imported_name(imp)
+	not used(name)
+
+	violation := {
+		"description": sprintf("Import %s is unused", [name]),
+		"location": imp.location,
+	}
+}
+
+imported_name(imp) := imp.alias
+
+imported_name(imp) := name if {
+	not imp.alias
+	name := imp.path.value[count(imp.path.value) - 1].value
+}
+
+used(name) if {
+	walk(input.rules, [_, term])
+	term.type == "var"
+	term.value == name
+}
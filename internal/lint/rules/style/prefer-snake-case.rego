@@ -0,0 +1,19 @@
+# METADATA
+# title: Prefer snake_case for rule names
+# description: |
+#   Rules and functions should be named in snake_case, like the built-in
+#   functions and the rules of most policies.
+# custom:
+#   level: warning
+package lint.rules.style["prefer-snake-case"]
+
+report contains violation if {
+	some rule in input.rules
+	name := rule.head.ref[0].value
+	not regex.match(`^[a-z0-9_]+$`, name)
+
+	violation := {
+		"description": sprintf("Rule %s is not in snake_case", [name]),
+		"location": rule.head.location,
+	}
+}
@@ -0,0 +1,21 @@
+# METADATA
+# description: Helpers shared by the built-in lint rules.
+package lint.util
+
+# ref_name returns the dotted name of a ref to a built-in function or rule,
+# e.g. "net.cidr_overlap".
+ref_name(ref) := concat(".", [term.value | some term in ref.value])
+
+# calls returns the calls in node, as pairs of the called ref and the location
+# of the call.
+calls(node) := {[term.value[0], term.location] |
+	walk(node, [_, term])
+	term.type == "call"
+} | {[expr.terms[0], expr.location] |
+	walk(node, [_, expr])
+	is_array(expr.terms)
+}
+
+# package_path returns the path of the linted package as strings, without the
+# leading "data".
+package_path := [term.value | some term in array.slice(input["package"].path, 1, count(input["package"].path))]
@@ -6,6 +6,7 @@ package prometheus
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -13,6 +14,8 @@ import (
 	"net/http"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -97,6 +100,17 @@ func (p *Provider) InstrumentHandler(handler http.Handler, label string) http.Ha
 	}))
 }
 
+// ObserveRequest records a request that wasn't served by an instrumented HTTP
+// handler, e.g. a gRPC call, the same way as InstrumentHandler records HTTP
+// requests. The method and code are those of the equivalent HTTP request.
+func (p *Provider) ObserveRequest(ctx context.Context, label, method string, code int, duration time.Duration) {
+	labels := prometheus.Labels{"code": strconv.Itoa(code), "handler": label, "method": strings.ToLower(method)}
+	p.durationHistogram.With(labels).Observe(duration.Seconds())
+	if ctx.Err() != nil {
+		p.cancellationCounters.With(labels).Inc()
+	}
+}
+
 // Info returns attributes that describe the metric provider.
 func (*Provider) Info() metrics.Info {
 	return metrics.Info{
@@ -0,0 +1,255 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package sarif implements the subset of the Static Analysis Results
+// Interchange Format (SARIF) 2.1.0 that OPA's commands report results in.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/version"
+)
+
+const (
+	// Version is the version of SARIF logs.
+	Version = "2.1.0"
+	// Schema is the JSON schema of SARIF logs.
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+	// SrcRoot is the base of relative artifact URIs, the working directory.
+	SrcRoot = "%SRCROOT%"
+
+	informationURI = "https://www.openpolicyagent.org/docs"
+)
+
+// Levels of results.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+	LevelNone    = "none"
+)
+
+// Rule IDs of results that aren't reported for an error with a code.
+const (
+	// TestFailure is the rule ID of failed tests.
+	TestFailure = "rego_test_failure"
+	// TestError is the rule ID of tests that failed with an error without code.
+	TestError = "rego_test_error"
+	// Error is the rule ID of other errors without code.
+	Error = "error"
+)
+
+// codeDescriptions describes the rules of results that are reported for errors
+// with a code, which are named by the code.
+var codeDescriptions = map[string]string{
+	ast.ParseErr:     "Rego parse error",
+	ast.CompileErr:   "Rego compile error",
+	ast.TypeErr:      "Rego type error",
+	ast.UnsafeVarErr: "Unsafe variable",
+	ast.RecursionErr: "Recursive rule",
+	ast.FormatErr:    "File is not formatted",
+	TestFailure:      "Test failed",
+	TestError:        "Test failed with an error",
+	Error:            "Error",
+}
+
+// Log is the root object of a SARIF log.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []*Run `json:"runs"`
+}
+
+// Run holds the results of one run of a tool.
+type Run struct {
+	Tool    Tool      `json:"tool"`
+	Results []*Result `json:"results"`
+}
+
+// Tool describes the tool that produced the results of a run.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver describes the component of the tool that produced the results.
+type Driver struct {
+	Name           string                 `json:"name"`
+	Version        string                 `json:"version,omitempty"`
+	InformationURI string                 `json:"informationUri,omitempty"`
+	Rules          []*ReportingDescriptor `json:"rules,omitempty"`
+}
+
+// ReportingDescriptor describes a rule that results are reported for.
+type ReportingDescriptor struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	ShortDescription *Message       `json:"shortDescription,omitempty"`
+	FullDescription  *Message       `json:"fullDescription,omitempty"`
+	HelpURI          string         `json:"helpUri,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+// Result is a single result reported by the tool.
+type Result struct {
+	RuleID    string     `json:"ruleId,omitempty"`
+	RuleIndex *int       `json:"ruleIndex,omitempty"`
+	Level     string     `json:"level,omitempty"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+// Message is the text of a message.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location is the location a result was reported at.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a region of an artifact.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation is the location of an artifact, e.g. a file.
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// Region is a region of an artifact. Lines and columns start at 1.
+type Region struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// New returns a log with a single run of the named OPA command.
+func New(command string) *Log {
+	return &Log{
+		Schema:  Schema,
+		Version: Version,
+		Runs: []*Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           command,
+				Version:        version.Version,
+				InformationURI: informationURI,
+			}},
+			Results: []*Result{},
+		}},
+	}
+}
+
+// Run returns the run of the log.
+func (l *Log) Run() *Run {
+	return l.Runs[0]
+}
+
+// AddRule adds rule to the rules of the run, unless a rule with the same ID
+// has been added before, and returns the rule's index.
+func (r *Run) AddRule(rule *ReportingDescriptor) int {
+	for i, other := range r.Tool.Driver.Rules {
+		if other.ID == rule.ID {
+			return i
+		}
+	}
+	r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule)
+	return len(r.Tool.Driver.Rules) - 1
+}
+
+// AddResult adds a result for the rule with the given ID, which must have been
+// added with AddRule, or an empty ID for results not reported by a rule.
+func (r *Run) AddResult(ruleID, level, message string, locations ...Location) {
+	result := &Result{
+		RuleID:    ruleID,
+		Level:     level,
+		Message:   Message{Text: message},
+		Locations: locations,
+	}
+	for i, rule := range r.Tool.Driver.Rules {
+		if rule.ID == ruleID {
+			result.RuleIndex = &i
+			break
+		}
+	}
+	r.Results = append(r.Results, result)
+}
+
+// AddCodeResult adds a result for an error with code at loc, which may be nil.
+// The result is reported for a rule named by the code, which is added to the
+// run if necessary. Errors without code are reported for the Error rule.
+func (r *Run) AddCodeResult(code, level, message string, loc *ast.Location) {
+	if code == "" {
+		code = Error
+	}
+
+	rule := &ReportingDescriptor{ID: code}
+	if desc, ok := codeDescriptions[code]; ok {
+		rule.ShortDescription = &Message{Text: desc}
+	}
+	r.AddRule(rule)
+
+	var locs []Location
+	if loc != nil && loc.File != "" {
+		locs = append(locs, NewLocation(loc.File, loc.Row, loc.Col))
+	}
+	r.AddResult(code, level, message, locs...)
+}
+
+// NewLocation returns the location of a file, and of a line and column in the
+// file if they are greater than zero.
+func NewLocation(file string, line, column int) Location {
+	loc := Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: NewArtifactLocation(file)}}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &Region{StartLine: line}
+		if column > 0 {
+			loc.PhysicalLocation.Region.StartColumn = column
+		}
+	}
+	return loc
+}
+
+// NewArtifactLocation returns the location of a file. Files in the working
+// directory are referenced relative to SrcRoot, other files by their absolute
+// file URI.
+func NewArtifactLocation(file string) ArtifactLocation {
+	if wd, err := os.Getwd(); err == nil {
+		abs := file
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(wd, abs)
+		}
+		if rel, err := filepath.Rel(wd, abs); err == nil && filepath.IsLocal(rel) {
+			file = rel
+		} else {
+			file = abs
+		}
+	}
+
+	if !filepath.IsAbs(file) {
+		return ArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(file)}).String(), URIBaseID: SrcRoot}
+	}
+
+	p := filepath.ToSlash(file)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive letter, e.g. file:///C:/policy.rego
+	}
+	return ArtifactLocation{URI: (&url.URL{Scheme: "file", Path: p}).String()}
+}
+
+// Write writes the log to w as indented JSON.
+func (l *Log) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
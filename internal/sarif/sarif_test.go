@@ -0,0 +1,76 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sarif
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewArtifactLocation(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := filepath.ToSlash(filepath.Dir(wd))
+	if !strings.HasPrefix(parent, "/") {
+		parent = "/" + parent
+	}
+
+	outside := "/etc/policy.rego"
+	outsideURI := "file:///etc/policy.rego"
+	if runtime.GOOS == "windows" {
+		outside, outsideURI = `C:\policies\policy.rego`, "file:///C:/policies/policy.rego"
+	}
+
+	tests := []struct {
+		note string
+		file string
+		exp  ArtifactLocation
+	}{
+		{
+			note: "relative",
+			file: filepath.Join("policies", "policy.rego"),
+			exp:  ArtifactLocation{URI: "policies/policy.rego", URIBaseID: SrcRoot},
+		},
+		{
+			note: "absolute in working directory",
+			file: filepath.Join(wd, "policies", "policy.rego"),
+			exp:  ArtifactLocation{URI: "policies/policy.rego", URIBaseID: SrcRoot},
+		},
+		{
+			note: "relative outside of working directory",
+			file: filepath.Join("..", "policy.rego"),
+			exp:  ArtifactLocation{URI: (&url.URL{Scheme: "file", Path: parent + "/policy.rego"}).String()},
+		},
+		{
+			note: "absolute",
+			file: outside,
+			exp:  ArtifactLocation{URI: outsideURI},
+		},
+		{
+			note: "escaped",
+			file: "my policy:1.rego",
+			exp:  ArtifactLocation{URI: "./my%20policy:1.rego", URIBaseID: SrcRoot},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+
+			if act := NewArtifactLocation(tc.file); act != tc.exp {
+				t.Fatalf("expected %+v, got %+v", tc.exp, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ucast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// container tracks the collection (or index) that the fields of a UCAST tree
+// belong to, for targets that query a single one of them, like MongoDB or
+// Elasticsearch. Fields are of the form "<container>.<path>".
+type container struct {
+	kind string
+	name string
+}
+
+// field returns the path of the field inside its container.
+func (c *container) field(f string) (string, error) {
+	name, path, ok := strings.Cut(f, ".")
+	if !ok || name == "" || path == "" {
+		return "", fmt.Errorf("field %q does not reference a %s", f, c.kind)
+	}
+	if c.name != "" && c.name != name {
+		return "", fmt.Errorf("field %q references %s %q, but the filter applies to %[2]s %[4]q", f, c.kind, name, c.name)
+	}
+	c.name = name
+	return path, nil
+}
+
+// children returns the nodes combined by a compound node.
+func (u *UCASTNode) children() ([]UCASTNode, error) {
+	if u.Value == nil {
+		return nil, fmt.Errorf("compound expression '%s' requires a value", u.Op)
+	}
+	values, ok := u.Value.([]UCASTNode)
+	if !ok {
+		return nil, errors.New("value must be an array")
+	}
+	if u.Op == "not" && len(values) != 1 {
+		return nil, errors.New("compound expression 'not' requires exactly one value")
+	}
+	return values, nil
+}
+
+// nativeValue converts the values of field nodes into their native Go
+// representation, so that they can be passed on to database drivers as-is.
+func nativeValue(v any) any {
+	switch v := v.(type) {
+	case Null:
+		return nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		vs := make([]any, len(v))
+		for i := range v {
+			vs[i] = nativeValue(v[i])
+		}
+		return vs
+	case map[string]any:
+		vs := make(map[string]any, len(v))
+		for k := range v {
+			vs[k] = nativeValue(v[k])
+		}
+		return vs
+	}
+	return v
+}
+
+func stringPattern(op string, v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("'%s' pattern requires string argument, got %v %[2]T", op, v)
+	}
+	return s, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ucast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var elasticsearchRangeOps = map[string]string{
+	"gt":  "gt",
+	"lt":  "lt",
+	"ge":  "gte",
+	"gte": "gte",
+	"le":  "lte",
+	"lte": "lte",
+}
+
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`)
+
+// AsElasticsearch translates the UCAST tree into an Elasticsearch bool query.
+// It also returns the name of the index that the query applies to: fields are
+// of the form "<index>.<field>", and all of them need to belong to the same
+// index. Equality is translated into term queries, so text fields should be
+// compared using their keyword (sub-)fields.
+func (u *UCASTNode) AsElasticsearch() (string, map[string]any, error) {
+	c := container{kind: "index"}
+	query, err := u.asElasticsearch(&c)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, ok := query["bool"]; !ok {
+		query = esBool("filter", query)
+	}
+	return c.name, query, nil
+}
+
+func esBool(occur string, queries ...any) map[string]any {
+	return map[string]any{"bool": map[string]any{occur: queries}}
+}
+
+func (u *UCASTNode) asElasticsearch(c *container) (map[string]any, error) {
+	switch u.Type {
+	case "field":
+		if u.Value == nil {
+			return nil, errors.New("field expression requires a value")
+		}
+		field, err := c.field(u.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := u.Value.(type) {
+		case FieldRef:
+			return nil, errors.New("field references are not supported by Elasticsearch")
+		case Null:
+			exists := map[string]any{"exists": map[string]any{"field": field}}
+			switch u.Op {
+			case "eq":
+				return esBool("must_not", exists), nil
+			case "ne":
+				return exists, nil
+			default:
+				return nil, errors.New("null value can only be used with 'eq' or 'ne' operators")
+			}
+		case []any:
+			if u.Op == "in" {
+				return map[string]any{"terms": map[string]any{field: nativeValue(v)}}, nil
+			}
+		}
+
+		switch u.Op {
+		case "eq":
+			return map[string]any{"term": map[string]any{field: nativeValue(u.Value)}}, nil
+		case "ne":
+			// must_not alone also matches documents without the field, which
+			// SQL's <> and Rego's != don't.
+			return map[string]any{"bool": map[string]any{
+				"filter":   []any{map[string]any{"exists": map[string]any{"field": field}}},
+				"must_not": []any{map[string]any{"term": map[string]any{field: nativeValue(u.Value)}}},
+			}}, nil
+		case "gt", "lt", "ge", "gte", "le", "lte":
+			return map[string]any{"range": map[string]any{field: map[string]any{elasticsearchRangeOps[u.Op]: nativeValue(u.Value)}}}, nil
+		case "in":
+			return nil, errors.New("field operator 'in' requires collection argument")
+		case "startswith":
+			s, err := stringPattern(u.Op, u.Value)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"prefix": map[string]any{field: map[string]any{"value": s}}}, nil
+		case "endswith", "contains":
+			s, err := stringPattern(u.Op, u.Value)
+			if err != nil {
+				return nil, err
+			}
+			pattern := "*" + wildcardEscaper.Replace(s)
+			if u.Op == "contains" {
+				pattern += "*"
+			}
+			return map[string]any{"wildcard": map[string]any{field: map[string]any{"value": pattern}}}, nil
+		}
+
+	case "compound":
+		nodes, err := u.children()
+		if err != nil {
+			return nil, err
+		}
+		queries := make([]any, len(nodes))
+		for i := range nodes {
+			if queries[i], err = nodes[i].asElasticsearch(c); err != nil {
+				return nil, err
+			}
+		}
+		switch u.Op {
+		case "and":
+			// filter context: no scoring, results are cacheable
+			return esBool("filter", queries...), nil
+		case "or":
+			return map[string]any{"bool": map[string]any{"should": queries, "minimum_should_match": 1}}, nil
+		case "not":
+			return esBool("must_not", queries...), nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized operator: %s", u.Op)
+}
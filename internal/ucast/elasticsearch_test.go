@@ -0,0 +1,152 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ucast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUCASTNodeAsElasticsearch(t *testing.T) {
+	t.Parallel()
+
+	filter := func(qs ...any) map[string]any {
+		return map[string]any{"bool": map[string]any{"filter": qs}}
+	}
+
+	tests := []struct {
+		Note   string
+		Source UCASTNode
+		Index  string
+		Result map[string]any
+		Error  string
+	}{
+		{
+			Note:   "Nil argument",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "users.name", Value: nil},
+			Error:  "field expression requires a value",
+		},
+		{
+			Note:   "equality",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "users.name", Value: "bob"},
+			Index:  "users",
+			Result: filter(map[string]any{"term": map[string]any{"name": "bob"}}),
+		},
+		{
+			Note:   "inequality excludes documents without the field",
+			Source: UCASTNode{Type: "field", Op: "ne", Field: "users.name", Value: "bob"},
+			Index:  "users",
+			Result: map[string]any{"bool": map[string]any{
+				"filter":   []any{map[string]any{"exists": map[string]any{"field": "name"}}},
+				"must_not": []any{map[string]any{"term": map[string]any{"name": "bob"}}},
+			}},
+		},
+		{
+			Note:   "null",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "users.name", Value: Null{}},
+			Index:  "users",
+			Result: map[string]any{"bool": map[string]any{"must_not": []any{map[string]any{"exists": map[string]any{"field": "name"}}}}},
+		},
+		{
+			Note:   "not null",
+			Source: UCASTNode{Type: "field", Op: "ne", Field: "users.name", Value: Null{}},
+			Index:  "users",
+			Result: filter(map[string]any{"exists": map[string]any{"field": "name"}}),
+		},
+		{
+			Note:   "range",
+			Source: UCASTNode{Type: "field", Op: "ge", Field: "users.age", Value: json.Number("18")},
+			Index:  "users",
+			Result: filter(map[string]any{"range": map[string]any{"age": map[string]any{"gte": int64(18)}}}),
+		},
+		{
+			Note:   "'in' expression",
+			Source: UCASTNode{Type: "field", Op: "in", Field: "users.role", Value: []any{"admin", "owner"}},
+			Index:  "users",
+			Result: filter(map[string]any{"terms": map[string]any{"role": []any{"admin", "owner"}}}),
+		},
+		{
+			Note:   "startswith",
+			Source: UCASTNode{Type: "field", Op: "startswith", Field: "users.name", Value: "b*o"},
+			Index:  "users",
+			Result: filter(map[string]any{"prefix": map[string]any{"name": map[string]any{"value": "b*o"}}}),
+		},
+		{
+			Note:   "endswith + pattern",
+			Source: UCASTNode{Type: "field", Op: "endswith", Field: "users.name", Value: `b*o?\`},
+			Index:  "users",
+			Result: filter(map[string]any{"wildcard": map[string]any{"name": map[string]any{"value": `*b\*o\?\\`}}}),
+		},
+		{
+			Note:   "contains + pattern",
+			Source: UCASTNode{Type: "field", Op: "contains", Field: "users.name", Value: "b*o"},
+			Index:  "users",
+			Result: filter(map[string]any{"wildcard": map[string]any{"name": map[string]any{"value": `*b\*o*`}}}),
+		},
+		{
+			Note: "compound expressions",
+			Source: UCASTNode{Type: "compound", Op: "or", Value: []UCASTNode{
+				{Type: "compound", Op: "and", Value: []UCASTNode{
+					{Type: "field", Op: "eq", Field: "users.role", Value: "admin"},
+					{Type: "field", Op: "gt", Field: "users.level", Value: json.Number("2")},
+				}},
+				{Type: "compound", Op: "not", Value: []UCASTNode{
+					{Type: "field", Op: "eq", Field: "users.suspended", Value: true},
+				}},
+			}},
+			Index: "users",
+			Result: map[string]any{"bool": map[string]any{
+				"should": []any{
+					filter(
+						map[string]any{"term": map[string]any{"role": "admin"}},
+						map[string]any{"range": map[string]any{"level": map[string]any{"gt": int64(2)}}},
+					),
+					map[string]any{"bool": map[string]any{"must_not": []any{
+						map[string]any{"term": map[string]any{"suspended": true}},
+					}}},
+				},
+				"minimum_should_match": 1,
+			}},
+		},
+		{
+			Note:   "field reference",
+			Source: UCASTNode{Type: "field", Op: "lt", Field: "users.spent", Value: FieldRef{Field: "users.budget"}},
+			Error:  "field references are not supported by Elasticsearch",
+		},
+		{
+			Note: "multiple indices",
+			Source: UCASTNode{Type: "compound", Op: "and", Value: []UCASTNode{
+				{Type: "field", Op: "eq", Field: "users.name", Value: "bob"},
+				{Type: "field", Op: "eq", Field: "tickets.assignee", Value: "bob"},
+			}},
+			Error: `field "tickets.assignee" references index "tickets", but the filter applies to index "users"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Note, func(t *testing.T) {
+			t.Parallel()
+
+			index, actual, err := tc.Source.AsElasticsearch()
+			if tc.Error != "" {
+				if err == nil || err.Error() != tc.Error {
+					t.Fatalf("expected error %q, got %v", tc.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if index != tc.Index {
+				t.Errorf("expected index %q, got %q", tc.Index, index)
+			}
+			if diff := cmp.Diff(tc.Result, actual); diff != "" {
+				t.Errorf("unexpected query (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
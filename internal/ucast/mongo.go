@@ -0,0 +1,137 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ucast
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var mongoOps = map[string]string{
+	"eq":  "$eq",
+	"ne":  "$ne",
+	"gt":  "$gt",
+	"lt":  "$lt",
+	"ge":  "$gte",
+	"gte": "$gte",
+	"le":  "$lte",
+	"lte": "$lte",
+	"in":  "$in",
+}
+
+// AsMongo translates the UCAST tree into a MongoDB query filter document. It
+// also returns the name of the collection that the filter applies to: fields
+// are of the form "<collection>.<field>", and all of them need to belong to
+// the same collection.
+func (u *UCASTNode) AsMongo() (string, map[string]any, error) {
+	c := container{kind: "collection"}
+	filter, err := u.asMongo(&c)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.name, filter, nil
+}
+
+// mongoField returns the path of a field inside its collection. Fields are
+// built from partially evaluated refs, so a dynamic key could put an operator
+// like "$where" in a field position: such fields are rejected.
+func mongoField(c *container, f string) (string, error) {
+	path, err := c.field(f)
+	if err != nil {
+		return "", err
+	}
+	for seg := range strings.SplitSeq(path, ".") {
+		if seg == "" || strings.HasPrefix(seg, "$") {
+			return "", fmt.Errorf("invalid field name: %q", path)
+		}
+	}
+	return path, nil
+}
+
+func (u *UCASTNode) asMongo(c *container) (map[string]any, error) {
+	switch u.Type {
+	case "field":
+		if u.Value == nil {
+			return nil, errors.New("field expression requires a value")
+		}
+		field, err := mongoField(c, u.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		switch u.Op {
+		case "startswith", "endswith", "contains":
+			s, err := stringPattern(u.Op, u.Value)
+			if err != nil {
+				return nil, err
+			}
+			pattern := regexp.QuoteMeta(s)
+			switch u.Op {
+			case "startswith":
+				pattern = "^" + pattern
+			case "endswith":
+				pattern += "$"
+			}
+			return map[string]any{field: map[string]any{"$regex": pattern}}, nil
+		}
+
+		op, ok := mongoOps[u.Op]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized operator: %s", u.Op)
+		}
+
+		switch v := u.Value.(type) {
+		case FieldRef:
+			if u.Op == "in" {
+				return nil, errors.New("field operator 'in' requires collection argument")
+			}
+			other, err := mongoField(c, v.Field)
+			if err != nil {
+				return nil, err
+			}
+			// Comparing two fields of a document requires an aggregation expression.
+			return map[string]any{"$expr": map[string]any{op: []any{"$" + field, "$" + other}}}, nil
+		case Null:
+			if u.Op != "eq" && u.Op != "ne" {
+				return nil, errors.New("null value can only be used with 'eq' or 'ne' operators")
+			}
+		case []any:
+		default:
+			if u.Op == "in" {
+				return nil, errors.New("field operator 'in' requires collection argument")
+			}
+			if u.Op == "ne" {
+				// $ne also matches documents without the field, which SQL's
+				// <> and Rego's != don't.
+				return map[string]any{field: map[string]any{"$exists": true, op: nativeValue(u.Value)}}, nil
+			}
+		}
+		return map[string]any{field: map[string]any{op: nativeValue(u.Value)}}, nil
+
+	case "compound":
+		nodes, err := u.children()
+		if err != nil {
+			return nil, err
+		}
+		filters := make([]any, len(nodes))
+		for i := range nodes {
+			if filters[i], err = nodes[i].asMongo(c); err != nil {
+				return nil, err
+			}
+		}
+		switch u.Op {
+		case "and":
+			return map[string]any{"$and": filters}, nil
+		case "or":
+			return map[string]any{"$or": filters}, nil
+		case "not":
+			// $not only applies to operator expressions of single fields, so
+			// arbitrary filters are negated with a single-element $nor.
+			return map[string]any{"$nor": filters}, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized operator: %s", u.Op)
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ucast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUCASTNodeAsMongo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		Note       string
+		Source     UCASTNode
+		Collection string
+		Result     map[string]any
+		Error      string
+	}{
+		{
+			Note:   "Nil argument",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "users.name", Value: nil},
+			Error:  "field expression requires a value",
+		},
+		{
+			Note:       "equality",
+			Source:     UCASTNode{Type: "field", Op: "eq", Field: "users.name", Value: "bob"},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$eq": "bob"}},
+		},
+		{
+			Note:       "inequality excludes documents without the field",
+			Source:     UCASTNode{Type: "field", Op: "ne", Field: "users.name", Value: "bob"},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$exists": true, "$ne": "bob"}},
+		},
+		{
+			Note:       "null",
+			Source:     UCASTNode{Type: "field", Op: "ne", Field: "users.name", Value: Null{}},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$ne": nil}},
+		},
+		{
+			Note:       "numbers are converted",
+			Source:     UCASTNode{Type: "field", Op: "in", Field: "users.age", Value: []any{json.Number("30"), json.Number("30.5")}},
+			Collection: "users",
+			Result:     map[string]any{"age": map[string]any{"$in": []any{int64(30), 30.5}}},
+		},
+		{
+			Note:       "nested field",
+			Source:     UCASTNode{Type: "field", Op: "ge", Field: "users.address.zip", Value: json.Number("10000")},
+			Collection: "users",
+			Result:     map[string]any{"address.zip": map[string]any{"$gte": int64(10000)}},
+		},
+		{
+			Note:       "startswith + pattern",
+			Source:     UCASTNode{Type: "field", Op: "startswith", Field: "users.name", Value: "b.o*"},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$regex": `^b\.o\*`}},
+		},
+		{
+			Note:       "endswith + pattern",
+			Source:     UCASTNode{Type: "field", Op: "endswith", Field: "users.name", Value: "b.o*"},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$regex": `b\.o\*$`}},
+		},
+		{
+			Note:       "contains + pattern",
+			Source:     UCASTNode{Type: "field", Op: "contains", Field: "users.name", Value: "b.o*"},
+			Collection: "users",
+			Result:     map[string]any{"name": map[string]any{"$regex": `b\.o\*`}},
+		},
+		{
+			Note:       "field reference",
+			Source:     UCASTNode{Type: "field", Op: "lt", Field: "users.spent", Value: FieldRef{Field: "users.budget"}},
+			Collection: "users",
+			Result:     map[string]any{"$expr": map[string]any{"$lt": []any{"$spent", "$budget"}}},
+		},
+		{
+			Note: "compound expressions",
+			Source: UCASTNode{Type: "compound", Op: "or", Value: []UCASTNode{
+				{Type: "compound", Op: "and", Value: []UCASTNode{
+					{Type: "field", Op: "eq", Field: "users.role", Value: "admin"},
+					{Type: "field", Op: "gt", Field: "users.level", Value: json.Number("2")},
+				}},
+				{Type: "compound", Op: "not", Value: []UCASTNode{
+					{Type: "field", Op: "eq", Field: "users.suspended", Value: false},
+				}},
+			}},
+			Collection: "users",
+			Result: map[string]any{"$or": []any{
+				map[string]any{"$and": []any{
+					map[string]any{"role": map[string]any{"$eq": "admin"}},
+					map[string]any{"level": map[string]any{"$gt": int64(2)}},
+				}},
+				map[string]any{"$nor": []any{
+					map[string]any{"suspended": map[string]any{"$eq": false}},
+				}},
+			}},
+		},
+		{
+			Note: "multiple collections",
+			Source: UCASTNode{Type: "compound", Op: "and", Value: []UCASTNode{
+				{Type: "field", Op: "eq", Field: "users.name", Value: "bob"},
+				{Type: "field", Op: "eq", Field: "tickets.assignee", Value: "bob"},
+			}},
+			Error: `field "tickets.assignee" references collection "tickets", but the filter applies to collection "users"`,
+		},
+		{
+			Note:   "no collection",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "name", Value: "bob"},
+			Error:  `field "name" does not reference a collection`,
+		},
+		{
+			Note:   "operator in field is rejected",
+			Source: UCASTNode{Type: "field", Op: "eq", Field: "users.$where", Value: "sleep(1000)"},
+			Error:  `invalid field name: "$where"`,
+		},
+		{
+			Note:   "'in' requires collection",
+			Source: UCASTNode{Type: "field", Op: "in", Field: "users.name", Value: "bob"},
+			Error:  "field operator 'in' requires collection argument",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Note, func(t *testing.T) {
+			t.Parallel()
+
+			collection, actual, err := tc.Source.AsMongo()
+			if tc.Error != "" {
+				if err == nil || err.Error() != tc.Error {
+					t.Fatalf("expected error %q, got %v", tc.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if collection != tc.Collection {
+				t.Errorf("expected collection %q, got %q", tc.Collection, collection)
+			}
+			if diff := cmp.Diff(tc.Result, actual); diff != "" {
+				t.Errorf("unexpected filter (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -41,3 +41,11 @@ type Config = v1.Config
 
 // Source is a configured bundle source to download bundles from
 type Source = v1.Source
+
+// Mirror is a service to download the bundle from if the source's service,
+// or the mirrors before it, fail to serve it.
+type Mirror = v1.Mirror
+
+// GitSource is a git repository to build the bundle from instead of
+// downloading it from a service.
+type GitSource = v1.GitSource
@@ -11,4 +11,7 @@ import (
 // Status represents the status of processing a decision log.
 type Status = v1.Status
 
+// BufferStatus represents the backlog of a persistent decision log buffer.
+type BufferStatus = v1.BufferStatus
+
 type HTTPError = v1.HTTPError
@@ -17,6 +17,7 @@ const (
 	AuthenticationOff   = v1.AuthenticationOff
 	AuthenticationToken = v1.AuthenticationToken
 	AuthenticationTLS   = v1.AuthenticationTLS
+	AuthenticationJWT   = v1.AuthenticationJWT
 )
 
 // AuthorizationScheme enumerates the supported authorization schemes. The authorization
@@ -58,6 +58,7 @@ package disk
 
 import (
 	"context"
+	"io"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -75,3 +76,12 @@ type Store = v1.Store
 func New(ctx context.Context, logger logging.Logger, prom prometheus.Registerer, opts Options) (*Store, error) {
 	return v1.New(ctx, logger, prom, opts)
 }
+
+// BackupInfo describes a backup written by Store.Backup.
+type BackupInfo = v1.BackupInfo
+
+// InspectBackup reads the backup from r, which must have been written by
+// Store.Backup, and returns a description of its contents.
+func InspectBackup(r io.Reader) (*BackupInfo, error) {
+	return v1.InspectBackup(r)
+}
@@ -17,5 +17,8 @@ type PrettyReporter = v1.PrettyReporter
 // JSONReporter reports test results as array of JSON objects.
 type JSONReporter = v1.JSONReporter
 
+// SARIFReporter reports failed tests as a SARIF log, for code scanning tools.
+type SARIFReporter = v1.SARIFReporter
+
 // JSONCoverageReporter reports coverage as a JSON structure.
 type JSONCoverageReporter = v1.JSONCoverageReporter
@@ -24,6 +24,18 @@ type InterQueryBuiltinValueCacheConfig = v1.InterQueryBuiltinValueCacheConfig
 // StaleEntryEvictionPeriodSeconds - time period between end of previous and start of new stale entry eviction routine
 type InterQueryBuiltinCacheConfig = v1.InterQueryBuiltinCacheConfig
 
+// SharedCacheConfig represents the configuration of the backend that the
+// inter-query cache shares its values through.
+type SharedCacheConfig = v1.SharedCacheConfig
+
+// RedisCacheConfig represents the configuration of the shared backend for
+// stores that speak the Redis protocol (RESP2).
+type RedisCacheConfig = v1.RedisCacheConfig
+
+// DiskCacheConfig represents the configuration of the directory that the
+// inter-query cache persists its values in, so that they survive restarts.
+type DiskCacheConfig = v1.DiskCacheConfig
+
 // ParseCachingConfig returns the config for the inter-query cache.
 func ParseCachingConfig(raw []byte) (*Config, error) {
 	return v1.ParseCachingConfig(raw)
@@ -35,6 +47,28 @@ type InterQueryCacheValue = v1.InterQueryCacheValue
 // InterQueryCache defines the interface for the inter-query cache.
 type InterQueryCache = v1.InterQueryCache
 
+// Backend defines the interface for stores that inter-query caches of several
+// OPA instances share.
+type Backend = v1.Backend
+
+// SharedInterQueryCache is implemented by inter-query caches that read through
+// and write through to a shared Backend.
+type SharedInterQueryCache = v1.SharedInterQueryCache
+
+// SerializableInterQueryCacheValue is implemented by inter-query cache values
+// that can be stored in a shared backend.
+type SerializableInterQueryCacheValue = v1.SerializableInterQueryCacheValue
+
+// InterQueryCacheValueDecoder decodes values encoded by the MarshalBinary
+// method of a SerializableInterQueryCacheValue.
+type InterQueryCacheValueDecoder = v1.InterQueryCacheValueDecoder
+
+// RegisterInterQueryCacheValueType registers the decoder for the values of a
+// SerializableInterQueryCacheValue type.
+func RegisterInterQueryCacheValueType(name string, decoder InterQueryCacheValueDecoder) {
+	v1.RegisterInterQueryCacheValueType(name, decoder)
+}
+
 // NewInterQueryCache returns a new inter-query cache.
 // The cache uses a FIFO eviction policy when it reaches the forced eviction threshold.
 // Parameters:
@@ -0,0 +1,162 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// ErrNoDataChanges is returned by NewDelta if the bundles have the same data,
+// as a delta bundle can't be empty.
+var ErrNoDataChanges = errors.New("bundles have the same data")
+
+// NewDelta returns a delta bundle of the patch operations turning the data of
+// the base bundle into the data of the target bundle. The delta bundle has the
+// manifest of the target bundle.
+//
+// Delta bundles can only patch data: if the bundles differ in policies, wasm
+// modules, plans, manifest roots or wasm resolvers, an error is returned.
+func NewDelta(base, target *Bundle) (*Bundle, error) {
+	if changed := changedPolicyFiles(base, target); len(changed) > 0 {
+		return nil, fmt.Errorf("delta bundles can only contain data changes, but policy files changed: %s", strings.Join(changed, ", "))
+	}
+	manifest := target.Manifest.Copy()
+	if !manifest.equalWasmResolversAndRoots(base.Manifest.Copy()) {
+		return nil, errors.New("delta bundles can only contain data changes, but manifest roots or wasm resolvers changed")
+	}
+
+	var ops []PatchOperation
+	diffData(&ops, "", base.Data, target.Data)
+	if len(ops) == 0 {
+		return nil, ErrNoDataChanges
+	}
+
+	delta := &Bundle{
+		Manifest: manifest,
+		Patch:    Patch{Data: ops},
+		Etag:     target.Etag,
+	}
+	delta.SetManifestProto(target.manifestProto)
+	return delta, nil
+}
+
+// changedPolicyFiles returns the paths of the policy, wasm and plan files
+// added, removed or changed from base to target, sorted.
+func changedPolicyFiles(base, target *Bundle) []string {
+	changed := map[string]struct{}{}
+
+	// Modules are matched by path, and otherwise by content: the paths of a
+	// bundle loaded from a directory and of one built from it may differ.
+	modules := map[string]ModuleFile{}
+	for _, mf := range base.Modules {
+		modules[mf.Path] = mf
+	}
+	var added []ModuleFile
+	for _, mf := range target.Modules {
+		other, ok := modules[mf.Path]
+		switch {
+		case !ok:
+			added = append(added, mf)
+			continue
+		case !equalModuleFiles(mf, other):
+			changed[mf.Path] = struct{}{}
+		}
+		delete(modules, mf.Path)
+	}
+	for _, mf := range added {
+		found := false
+		for path, other := range modules {
+			if equalModuleFiles(mf, other) {
+				delete(modules, path)
+				found = true
+				break
+			}
+		}
+		if !found {
+			changed[mf.Path] = struct{}{}
+		}
+	}
+	for path := range modules {
+		changed[path] = struct{}{}
+	}
+
+	raws := map[string][]byte{}
+	for _, wm := range base.WasmModules {
+		raws[wm.Path] = wm.Raw
+	}
+	for _, pm := range base.PlanModules {
+		raws[pm.Path] = pm.Raw
+	}
+	for _, wm := range target.WasmModules {
+		if raw, ok := raws[wm.Path]; !ok || !bytes.Equal(raw, wm.Raw) {
+			changed[wm.Path] = struct{}{}
+		}
+		delete(raws, wm.Path)
+	}
+	for _, pm := range target.PlanModules {
+		if raw, ok := raws[pm.Path]; !ok || !bytes.Equal(raw, pm.Raw) {
+			changed[pm.Path] = struct{}{}
+		}
+		delete(raws, pm.Path)
+	}
+	for path := range raws {
+		changed[path] = struct{}{}
+	}
+
+	return util.KeysSorted(changed)
+}
+
+// equalModuleFiles compares parsed modules if available, so that formatting
+// changes aren't considered changes.
+func equalModuleFiles(a, b ModuleFile) bool {
+	if a.Parsed != nil && b.Parsed != nil {
+		return a.Parsed.Equal(b.Parsed)
+	}
+	return bytes.Equal(a.Raw, b.Raw)
+}
+
+// diffData appends the patch operations turning base into target to ops.
+// Objects are patched key by key, any other value is replaced as a whole.
+func diffData(ops *[]PatchOperation, path string, base, target map[string]any) {
+	for _, key := range util.KeysSorted(base) {
+		if _, ok := target[key]; !ok {
+			*ops = append(*ops, PatchOperation{Op: "remove", Path: path + "/" + escapePatchPathSegment(key)})
+		}
+	}
+
+	for _, key := range util.KeysSorted(target) {
+		keyPath := path + "/" + escapePatchPathSegment(key)
+		value := target[key]
+
+		old, ok := base[key]
+		if !ok {
+			*ops = append(*ops, PatchOperation{Op: "upsert", Path: keyPath, Value: value})
+			continue
+		}
+
+		oldObj, ok1 := old.(map[string]any)
+		obj, ok2 := value.(map[string]any)
+		switch {
+		case ok1 && ok2:
+			diffData(ops, keyPath, oldObj, obj)
+		case util.Compare(old, value) != 0:
+			*ops = append(*ops, PatchOperation{Op: "upsert", Path: keyPath, Value: value})
+		}
+	}
+}
+
+// escapePatchPathSegment escapes a key for patch paths: '~' and '/' as defined
+// by JSON Pointer, and the remaining special characters as in URL paths.
+func escapePatchPathSegment(key string) string {
+	key = strings.ReplaceAll(key, "~", "~0")
+	key = strings.ReplaceAll(key, "/", "~1")
+	return url.PathEscape(key)
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func TestNewDelta(t *testing.T) {
+	t.Parallel()
+
+	base := &Bundle{
+		Manifest: Manifest{Revision: "a", Roots: &[]string{"a"}},
+		Data: map[string]any{"a": map[string]any{
+			"same":    map[string]any{"x": 1},
+			"changed": map[string]any{"x": 1, "y": []any{1, 2}},
+			"removed": "foo",
+			"type":    map[string]any{"x": 1},
+			"a/b~c":   1,
+		}},
+		Modules: []ModuleFile{moduleFile("/a/policy.rego", "package a\np := 1")},
+	}
+	target := &Bundle{
+		Manifest: Manifest{Revision: "b", Roots: &[]string{"a"}},
+		Data: map[string]any{"a": map[string]any{
+			"same":    map[string]any{"x": 1},
+			"changed": map[string]any{"x": 2, "y": []any{1, 2, 3}},
+			"added":   map[string]any{"x": 1},
+			"type":    "x",
+			"a/b~c":   2,
+			"a b":     3,
+		}},
+		Modules: []ModuleFile{moduleFile("/a/policy.rego", "package a\n\np := 1\n")},
+		Etag:    "etag",
+	}
+
+	delta, err := NewDelta(base, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if delta.Type() != DeltaBundleType {
+		t.Fatalf("expected delta bundle, got %v", delta.Type())
+	}
+	if delta.Manifest.Revision != "b" || delta.Etag != "etag" {
+		t.Fatalf("expected the manifest and etag of the target bundle, got %v and %q", delta.Manifest, delta.Etag)
+	}
+	if len(delta.Modules) != 0 || delta.Data != nil {
+		t.Fatal("expected only patch operations")
+	}
+
+	exp := []PatchOperation{
+		{Op: "remove", Path: "/a/removed"},
+		{Op: "upsert", Path: "/a/a%20b", Value: 3},
+		{Op: "upsert", Path: "/a/a~1b~0c", Value: 2},
+		{Op: "upsert", Path: "/a/added", Value: map[string]any{"x": 1}},
+		{Op: "upsert", Path: "/a/changed/x", Value: 2},
+		{Op: "upsert", Path: "/a/changed/y", Value: []any{1, 2, 3}},
+		{Op: "upsert", Path: "/a/type", Value: "x"},
+	}
+	if !reflect.DeepEqual(exp, delta.Patch.Data) {
+		t.Fatalf("expected patch operations:\n%v\n\ngot:\n%v", exp, delta.Patch.Data)
+	}
+}
+
+func TestNewDeltaActivate(t *testing.T) {
+	t.Parallel()
+
+	mod := "package a\np = true"
+	base := Bundle{
+		Manifest: Manifest{Revision: "1", Roots: &[]string{"a"}},
+		Data: unpack(map[string]any{
+			"a.b":     "foo",
+			"a.c.d":   []any{"x"},
+			"a.c.e/f": "bar",
+			"a.g~h":   map[string]any{"i": 1},
+		}),
+		Modules: []ModuleFile{moduleFile("/a/policy.rego", mod)},
+	}
+	target := Bundle{
+		Manifest: Manifest{Revision: "2", Roots: &[]string{"a"}},
+		Data: unpack(map[string]any{
+			"a.b":   "bar",
+			"a.c.d": []any{"x", "y"},
+			"a.g~h": map[string]any{"i": 2, "j k": 3},
+		}),
+		Modules: []ModuleFile{moduleFile("/a/policy.rego", mod)},
+	}
+
+	delta, err := NewDelta(&base, &target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).Write(*delta); err != nil {
+		t.Fatal(err)
+	}
+	deltaRoundtrip := must(NewReader(&buf).Read())(t)
+
+	store := inmem.New()
+	compiler := ast.NewCompiler()
+	mustActivate(t, store, &ActivateOpts{Compiler: compiler, Bundles: map[string]*Bundle{"bundle": &base}})
+	mustActivate(t, store, &ActivateOpts{Compiler: compiler, Bundles: map[string]*Bundle{"bundle": &deltaRoundtrip}})
+
+	txn := storage.NewTransactionOrDie(t.Context(), store)
+	defer store.Abort(t.Context(), txn)
+
+	act := mustRead(t, store, txn, storage.MustParsePath("/a"))
+	exp := target.Data["a"]
+	if err := util.RoundTrip(&exp); err != nil {
+		t.Fatal(err)
+	}
+	if util.Compare(exp, act) != 0 {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+	if rev, err := ReadBundleRevisionFromStore(t.Context(), store, txn, "bundle"); err != nil || rev != "2" {
+		t.Fatalf("expected revision 2, got %q (err: %v)", rev, err)
+	}
+}
+
+func TestNewDeltaErrors(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"a": map[string]any{"b": 1}}
+	newData := map[string]any{"a": map[string]any{"b": 2}}
+
+	tests := []struct {
+		note   string
+		base   *Bundle
+		target *Bundle
+		err    string
+	}{
+		{
+			note:   "no data changes",
+			base:   &Bundle{Data: data},
+			target: &Bundle{Data: data},
+			err:    ErrNoDataChanges.Error(),
+		},
+		{
+			note: "policy changed",
+			base: &Bundle{Data: data, Modules: []ModuleFile{
+				moduleFile("/a/a.rego", "package a\np := 1"),
+				moduleFile("/a/b.rego", "package a.b\np := 1"),
+			}},
+			target: &Bundle{Data: newData, Modules: []ModuleFile{
+				moduleFile("/a/a.rego", "package a\np := 2"),
+				moduleFile("/a/c.rego", "package a.c\np := 1"),
+			}},
+			err: "policy files changed: /a/a.rego, /a/b.rego, /a/c.rego",
+		},
+		{
+			note:   "wasm module changed",
+			base:   &Bundle{Data: data, WasmModules: []WasmModuleFile{{Path: "/policy.wasm", Raw: []byte("a")}}},
+			target: &Bundle{Data: newData, WasmModules: []WasmModuleFile{{Path: "/policy.wasm", Raw: []byte("b")}}},
+			err:    "policy files changed: /policy.wasm",
+		},
+		{
+			note:   "plan added",
+			base:   &Bundle{Data: data},
+			target: &Bundle{Data: newData, PlanModules: []PlanModuleFile{{Path: "/plan.json", Raw: []byte("{}")}}},
+			err:    "policy files changed: /plan.json",
+		},
+		{
+			note:   "roots changed",
+			base:   &Bundle{Manifest: Manifest{Roots: &[]string{"a"}}, Data: data},
+			target: &Bundle{Manifest: Manifest{Roots: &[]string{"a", "b"}}, Data: newData},
+			err:    "manifest roots or wasm resolvers changed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			_, err := NewDelta(tc.base, tc.target)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+
+	// modules moved to other paths aren't changes
+	base := &Bundle{Data: data, Modules: []ModuleFile{moduleFile("/old/a.rego", "package a\np := 1")}}
+	target := &Bundle{Data: newData, Modules: []ModuleFile{moduleFile("/new/a.rego", "package a\np := 1")}}
+	if _, err := NewDelta(base, target); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDelta(base, base); !errors.Is(err, ErrNoDataChanges) {
+		t.Fatalf("expected no data changes error, got %v", err)
+	}
+}
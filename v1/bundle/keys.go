@@ -30,9 +30,10 @@ type KeyConfig = keys.Config
 // VerificationConfig represents the key configuration used to verify a signed bundle
 type VerificationConfig struct {
 	PublicKeys map[string]*KeyConfig
-	KeyID      string   `json:"keyid"`
-	Scope      string   `json:"scope"`
-	Exclude    []string `json:"exclude_files"`
+	KeyID      string                  `json:"keyid"`
+	Scope      string                  `json:"scope"`
+	Exclude    []string                `json:"exclude_files"`
+	X509       *X509VerificationConfig `json:"x509,omitempty"`
 }
 
 // NewVerificationConfig return a new VerificationConfig
@@ -62,6 +63,10 @@ func (vc *VerificationConfig) ValidateAndInjectDefaults(keys map[string]*KeyConf
 			return fmt.Errorf("key id %s not found", vc.KeyID)
 		}
 	}
+
+	if vc.X509 != nil {
+		return vc.X509.validate()
+	}
 	return nil
 }
 
@@ -82,6 +87,7 @@ type SigningConfig struct {
 	Key        string
 	Algorithm  string
 	ClaimsPath string
+	CertChain  string
 }
 
 // NewSigningConfig return a new SigningConfig
@@ -106,6 +112,13 @@ func (s *SigningConfig) WithPlugin(plugin string) *SigningConfig {
 	return s
 }
 
+// WithCertChain sets the PEM encoded certificate chain, or the path to it, to
+// embed in the "x5c" header of the signed token
+func (s *SigningConfig) WithCertChain(chain string) *SigningConfig {
+	s.CertChain = chain
+	return s
+}
+
 // GetPrivateKey returns the private key or secret from the signing config
 func (s *SigningConfig) GetPrivateKey() (any, error) {
 	var keyData string
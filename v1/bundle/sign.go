@@ -10,6 +10,7 @@ import (
 
 	"github.com/lestrrat-go/jwx/v3/jwa"
 	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
@@ -78,7 +79,20 @@ func (*DefaultSigner) GenerateSignedToken(files []FileInfo, sc *SigningConfig, k
 
 	// Since v3.0.6, jwx will take the fast path for signing the token if
 	// there's exactly one WithKey in the options with no sub-options
-	signed, err := jwt.Sign(token, jwt.WithKey(alg, jwkKey))
+	var opts []jwt.Option
+	if sc.CertChain != "" {
+		chain, err := sc.certChain(privateKey)
+		if err != nil {
+			return "", err
+		}
+		hdrs := jws.NewHeaders()
+		if err := hdrs.Set(jws.X509CertChainKey, chain); err != nil {
+			return "", fmt.Errorf("failed to set certificate chain header: %w", err)
+		}
+		opts = append(opts, jws.WithProtectedHeaders(hdrs))
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(alg, jwkKey, opts...))
 	if err != nil {
 		return "", err
 	}
@@ -14,6 +14,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lestrrat-go/jwx/v3/jwa"
 	"github.com/lestrrat-go/jwx/v3/jws/jwsbb"
@@ -122,6 +123,21 @@ func verifyJWTSignature(token string, bvc *VerificationConfig) (*DecodedSignatur
 		return nil, fmt.Errorf("failed to split compact JWT: %w", err)
 	}
 
+	// Signatures made with a certificate carried in the "x5c" header are
+	// verified against the configured CA instead of a configured key.
+	if bvc.X509 != nil {
+		hdr := jwsbb.HeaderParseCompact(hdrb64)
+		x5c, err := jwsbb.HeaderGetStringArray(hdr, "x5c")
+		switch {
+		case err == nil:
+			return verifyX509JWTSignature(hdr, x5c, hdrb64, payloadb64, signatureb64, bvc)
+		case errors.Is(err, jwsbb.ErrHeaderNotFound()):
+			// no "x5c" in the header, fall back to the configured keys.
+		default:
+			return nil, fmt.Errorf("failed to extract certificate chain from headers: %w", err)
+		}
+	}
+
 	// check for the id of the key to use for JWT signature verification
 	// first in the OPA config. If not found, then check the JWT kid.
 	keyID := bvc.KeyID
@@ -144,14 +160,8 @@ func verifyJWTSignature(token string, bvc *VerificationConfig) (*DecodedSignatur
 
 	// Because we want to fallback to ds.KeyID when we can't find the
 	// keyID, we need to parse the payload here already.
-	decoder := base64.RawURLEncoding
-	payload := make([]byte, decoder.DecodedLen(len(payloadb64)))
-	if _, err := decoder.Decode(payload, payloadb64); err != nil {
-		return nil, fmt.Errorf("failed to base64 decode JWT payload: %w", err)
-	}
-
-	var ds DecodedSignature
-	if err := json.Unmarshal(payload, &ds); err != nil {
+	ds, err := decodeJWTPayload(payloadb64)
+	if err != nil {
 		return nil, err
 	}
 
@@ -182,18 +192,8 @@ func verifyJWTSignature(token string, bvc *VerificationConfig) (*DecodedSignatur
 		return nil, err
 	}
 
-	signature := make([]byte, decoder.DecodedLen(len(signatureb64)))
-	if _, err = decoder.Decode(signature, signatureb64); err != nil {
-		return nil, fmt.Errorf("failed to base64 decode JWT signature: %w", err)
-	}
-
-	signbuf := make([]byte, len(hdrb64)+1+len(payloadb64))
-	copy(signbuf, hdrb64)
-	signbuf[len(hdrb64)] = '.'
-	copy(signbuf[len(hdrb64)+1:], payloadb64)
-
-	if err := jwsbb.Verify(parsedKey, alg.String(), signbuf, signature); err != nil {
-		return nil, fmt.Errorf("failed to verify JWT signature: %w", err)
+	if err := verifyJWS(parsedKey, alg, hdrb64, payloadb64, signatureb64); err != nil {
+		return nil, err
 	}
 
 	// verify the scope
@@ -205,9 +205,72 @@ func verifyJWTSignature(token string, bvc *VerificationConfig) (*DecodedSignatur
 	if ds.Scope != scope {
 		return nil, errors.New("scope mismatch")
 	}
+	return ds, nil
+}
+
+func verifyX509JWTSignature(hdr jwsbb.Header, x5c []string, hdrb64, payloadb64, signatureb64 []byte, bvc *VerificationConfig) (*DecodedSignature, error) {
+	v, err := jwsbb.HeaderGetString(hdr, "alg")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract algorithm from headers: %w", err)
+	}
+
+	alg, ok := jwa.LookupSignatureAlgorithm(v)
+	if !ok || alg == jwa.NoSignature() || alg == jwa.HS256() || alg == jwa.HS384() || alg == jwa.HS512() {
+		return nil, fmt.Errorf("unsupported signature algorithm for certificate chains: %s", v)
+	}
+
+	publicKey, err := bvc.X509.verifyChain(x5c, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyJWS(publicKey, alg, hdrb64, payloadb64, signatureb64); err != nil {
+		return nil, err
+	}
+
+	ds, err := decodeJWTPayload(payloadb64)
+	if err != nil {
+		return nil, err
+	}
+
+	if ds.Scope != bvc.Scope {
+		return nil, errors.New("scope mismatch")
+	}
+	return ds, nil
+}
+
+func decodeJWTPayload(payloadb64 []byte) (*DecodedSignature, error) {
+	decoder := base64.RawURLEncoding
+	payload := make([]byte, decoder.DecodedLen(len(payloadb64)))
+	if _, err := decoder.Decode(payload, payloadb64); err != nil {
+		return nil, fmt.Errorf("failed to base64 decode JWT payload: %w", err)
+	}
+
+	var ds DecodedSignature
+	if err := json.Unmarshal(payload, &ds); err != nil {
+		return nil, err
+	}
 	return &ds, nil
 }
 
+func verifyJWS(key any, alg jwa.SignatureAlgorithm, hdrb64, payloadb64, signatureb64 []byte) error {
+	decoder := base64.RawURLEncoding
+	signature := make([]byte, decoder.DecodedLen(len(signatureb64)))
+	if _, err := decoder.Decode(signature, signatureb64); err != nil {
+		return fmt.Errorf("failed to base64 decode JWT signature: %w", err)
+	}
+
+	signbuf := make([]byte, len(hdrb64)+1+len(payloadb64))
+	copy(signbuf, hdrb64)
+	signbuf[len(hdrb64)] = '.'
+	copy(signbuf[len(hdrb64)+1:], payloadb64)
+
+	if err := jwsbb.Verify(key, alg.String(), signbuf, signature); err != nil {
+		return fmt.Errorf("failed to verify JWT signature: %w", err)
+	}
+	return nil
+}
+
 // VerifyBundleFile verifies the hash of a file in the bundle matches to that provided in the bundle's signature
 func VerifyBundleFile(path string, data bytes.Buffer, files map[string]FileInfo) error {
 	var file FileInfo
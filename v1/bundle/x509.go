@@ -0,0 +1,277 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/cert"
+)
+
+// X509VerificationConfig represents the configuration used to verify bundle
+// signatures made with a certificate carried in the JWT "x5c" header.
+type X509VerificationConfig struct {
+	CACert          string   `json:"ca_cert"`
+	CRLs            []string `json:"crls,omitempty"`
+	SubjectAltNames []string `json:"subject_alt_names,omitempty"`
+	ExtKeyUsages    []string `json:"ext_key_usages,omitempty"` // defaults to code_signing
+}
+
+// extKeyUsages are the extended key usages signing certificates can be
+// required to have.
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"any":              x509.ExtKeyUsageAny,
+	"code_signing":     x509.ExtKeyUsageCodeSigning,
+	"server_auth":      x509.ExtKeyUsageServerAuth,
+	"client_auth":      x509.ExtKeyUsageClientAuth,
+	"email_protection": x509.ExtKeyUsageEmailProtection,
+}
+
+// validate checks that the CA certificates and CRLs can be loaded. They are
+// loaded again on every verification so that updated files are picked up.
+func (c *X509VerificationConfig) validate() error {
+	if c.CACert == "" {
+		return errors.New("x509: missing CA certificate")
+	}
+	if _, err := c.keyUsages(); err != nil {
+		return err
+	}
+	if _, err := c.roots(); err != nil {
+		return err
+	}
+	_, err := c.revocationLists()
+	return err
+}
+
+// keyUsages returns the extended key usages the signing certificate must have,
+// which is code signing unless configured otherwise.
+func (c *X509VerificationConfig) keyUsages() ([]x509.ExtKeyUsage, error) {
+	if len(c.ExtKeyUsages) == 0 {
+		return []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, nil
+	}
+	usages := make([]x509.ExtKeyUsage, 0, len(c.ExtKeyUsages))
+	for _, name := range c.ExtKeyUsages {
+		usage, ok := extKeyUsages[name]
+		if !ok {
+			return nil, fmt.Errorf("x509: unknown extended key usage %q", name)
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+func (c *X509VerificationConfig) roots() (*x509.CertPool, error) {
+	bs, err := readPEMOrFile(c.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("x509: failed to read CA certificate: %w", err)
+	}
+	certs, err := parseCertificates(bs)
+	if err != nil {
+		return nil, fmt.Errorf("x509: failed to parse CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	for _, crt := range certs {
+		pool.AddCert(crt)
+	}
+	return pool, nil
+}
+
+func (c *X509VerificationConfig) revocationLists() ([]*x509.RevocationList, error) {
+	crls := make([]*x509.RevocationList, 0, len(c.CRLs))
+	for _, s := range c.CRLs {
+		bs, err := readPEMOrFile(s)
+		if err != nil {
+			return nil, fmt.Errorf("x509: failed to read CRL: %w", err)
+		}
+		if block, _ := pem.Decode(bs); block != nil {
+			bs = block.Bytes
+		}
+		crl, err := x509.ParseRevocationList(bs)
+		if err != nil {
+			return nil, fmt.Errorf("x509: failed to parse CRL: %w", err)
+		}
+		crls = append(crls, crl)
+	}
+	return crls, nil
+}
+
+// verifyChain verifies the certificate chain from the "x5c" header against the
+// configured CA, CRLs and subject alternative names, and returns the public key
+// of the leaf certificate.
+func (c *X509VerificationConfig) verifyChain(x5c []string, now time.Time) (crypto.PublicKey, error) {
+	if len(x5c) == 0 {
+		return nil, errors.New("x509: empty certificate chain")
+	}
+
+	chain := make([]*x509.Certificate, 0, len(x5c))
+	for _, s := range x5c {
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("x509: failed to decode certificate chain: %w", err)
+		}
+		crt, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("x509: failed to parse certificate chain: %w", err)
+		}
+		chain = append(chain, crt)
+	}
+
+	roots, err := c.roots()
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := c.keyUsages()
+	if err != nil {
+		return nil, err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, crt := range chain[1:] {
+		intermediates.AddCert(crt)
+	}
+
+	leaf := chain[0]
+	verified, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     usages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("x509: failed to verify certificate chain: %w", err)
+	}
+
+	crls, err := c.revocationLists()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(crls) > 0 {
+		if err := checkRevocation(verified[0], crls, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.SubjectAltNames) > 0 && !slices.ContainsFunc(subjectAltNames(leaf), func(san string) bool {
+		return slices.Contains(c.SubjectAltNames, san)
+	}) {
+		return nil, errors.New("x509: signing certificate does not match any allowed subject alternative name")
+	}
+
+	return leaf.PublicKey, nil
+}
+
+// checkRevocation checks every non-root certificate in the verified chain
+// against the CRLs issued by its issuer. CRLs past their next update are
+// rejected, as newer revocations may be missing from them.
+func checkRevocation(chain []*x509.Certificate, crls []*x509.RevocationList, now time.Time) error {
+	for i := 0; i < len(chain)-1; i++ {
+		crt, issuer := chain[i], chain[i+1]
+		for _, crl := range crls {
+			if !bytes.Equal(crl.RawIssuer, crt.RawIssuer) {
+				continue
+			}
+			if err := crl.CheckSignatureFrom(issuer); err != nil {
+				return fmt.Errorf("x509: invalid CRL for issuer %q: %w", issuer.Subject, err)
+			}
+			if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+				return fmt.Errorf("x509: CRL for issuer %q expired at %v", issuer.Subject, crl.NextUpdate)
+			}
+			for _, entry := range crl.RevokedCertificateEntries {
+				if entry.SerialNumber.Cmp(crt.SerialNumber) == 0 {
+					return fmt.Errorf("x509: certificate %q has been revoked", crt.Subject)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func subjectAltNames(crt *x509.Certificate) []string {
+	sans := make([]string, 0, len(crt.DNSNames)+len(crt.EmailAddresses)+len(crt.URIs)+len(crt.IPAddresses))
+	sans = append(sans, crt.DNSNames...)
+	sans = append(sans, crt.EmailAddresses...)
+	for _, u := range crt.URIs {
+		sans = append(sans, u.String())
+	}
+	for _, ip := range crt.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// certChain loads the PEM encoded certificate chain (leaf first) to embed in
+// the "x5c" header of a signed token, and checks that the leaf certificate
+// belongs to the signing key.
+func (s *SigningConfig) certChain(privateKey any) (*cert.Chain, error) {
+	bs, err := readPEMOrFile(s.CertChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate chain: %w", err)
+	}
+	certs, err := parseCertificates(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate chain: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("certificate chains require an asymmetric signing key")
+	}
+	pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(certs[0].PublicKey) {
+		return nil, errors.New("signing key does not match the leaf certificate")
+	}
+
+	var chain cert.Chain
+	for _, crt := range certs {
+		if err := chain.Add([]byte(base64.StdEncoding.EncodeToString(crt.Raw))); err != nil {
+			return nil, err
+		}
+	}
+	return &chain, nil
+}
+
+func parseCertificates(bs []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, bs = pem.Decode(bs)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, crt)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	return certs, nil
+}
+
+// readPEMOrFile returns s if it looks like PEM data, and otherwise reads the
+// file at path s.
+func readPEMOrFile(s string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(s), "-----BEGIN") {
+		return []byte(s), nil
+	}
+	return os.ReadFile(s)
+}
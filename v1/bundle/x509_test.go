@@ -0,0 +1,335 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, serial int64, parent *testCert, mod func(*x509.Certificate)) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "cert-" + big.NewInt(serial).String()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if mod != nil {
+		mod(tmpl)
+	}
+
+	issuer, issuerKey := tmpl, key
+	if parent != nil {
+		issuer, issuerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) pem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}))
+}
+
+func (c *testCert) keyPEM(t *testing.T) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func (c *testCert) crl(t *testing.T, revoked ...*testCert) string {
+	t.Helper()
+	return c.crlUntil(t, time.Now().Add(time.Hour), revoked...)
+}
+
+func (c *testCert) crlUntil(t *testing.T, nextUpdate time.Time, revoked ...*testCert) string {
+	t.Helper()
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, r := range revoked {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: r.cert.SerialNumber, RevocationTime: time.Now()})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                nextUpdate.Add(-2 * time.Hour),
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, c.cert, c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+}
+
+func TestX509SignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, 1, nil, nil)
+	intermediate := newTestCert(t, 2, root, func(c *x509.Certificate) { c.IsCA = true })
+	leaf := newTestCert(t, 3, intermediate, func(c *x509.Certificate) {
+		c.DNSNames = []string{"signer.example.com"}
+		c.EmailAddresses = []string{"signer@example.com"}
+		c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+	})
+	expired := newTestCert(t, 4, intermediate, func(c *x509.Certificate) {
+		c.NotBefore = time.Now().Add(-2 * time.Hour)
+		c.NotAfter = time.Now().Add(-time.Hour)
+		c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+	})
+	otherRoot := newTestCert(t, 5, nil, nil)
+	server := newTestCert(t, 6, intermediate, func(c *x509.Certificate) {
+		c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	})
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, []byte(root.pem()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := []FileInfo{{Name: "/data.json", Hash: "36669864a622563256817033b1fc53db", Algorithm: MD5.String()}}
+
+	sign := func(t *testing.T, signer *testCert, chain ...*testCert) string {
+		t.Helper()
+		var certs strings.Builder
+		for _, c := range chain {
+			certs.WriteString(c.pem())
+		}
+		sc := NewSigningConfig(signer.keyPEM(t), "ES256", "").WithCertChain(certs.String())
+		token, err := GenerateSignedToken(files, sc, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		note   string
+		token  func(*testing.T) string
+		config X509VerificationConfig
+		scope  string
+		err    string
+	}{
+		{
+			note:   "valid chain",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile},
+		},
+		{
+			note:   "inline CA and allowed SAN",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: root.pem(), SubjectAltNames: []string{"other.example.com", "signer@example.com"}},
+		},
+		{
+			note:   "SAN mismatch",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, SubjectAltNames: []string{"other.example.com"}},
+			err:    "does not match any allowed subject alternative name",
+		},
+		{
+			note:   "missing intermediate",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf) },
+			config: X509VerificationConfig{CACert: caFile},
+			err:    "failed to verify certificate chain",
+		},
+		{
+			note:   "untrusted CA",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: otherRoot.pem()},
+			err:    "failed to verify certificate chain",
+		},
+		{
+			note:   "expired certificate",
+			token:  func(t *testing.T) string { return sign(t, expired, expired, intermediate) },
+			config: X509VerificationConfig{CACert: caFile},
+			err:    "expired",
+		},
+		{
+			note:   "revoked leaf",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, CRLs: []string{root.crl(t), intermediate.crl(t, leaf)}},
+			err:    `certificate "CN=cert-3" has been revoked`,
+		},
+		{
+			note:   "revoked intermediate",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, CRLs: []string{root.crl(t, intermediate)}},
+			err:    `certificate "CN=cert-2" has been revoked`,
+		},
+		{
+			note:   "expired CRL",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, CRLs: []string{intermediate.crlUntil(t, time.Now().Add(-time.Minute))}},
+			err:    `CRL for issuer "CN=cert-2" expired`,
+		},
+		{
+			note:   "TLS server certificate",
+			token:  func(t *testing.T) string { return sign(t, server, server, intermediate) },
+			config: X509VerificationConfig{CACert: caFile},
+			err:    "incompatible key usage",
+		},
+		{
+			note:   "configured key usage",
+			token:  func(t *testing.T) string { return sign(t, server, server, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, ExtKeyUsages: []string{"server_auth"}},
+		},
+		{
+			note:   "unrelated revocation",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, CRLs: []string{intermediate.crl(t, expired)}},
+		},
+		{
+			note:   "CRL not signed by issuer",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile, CRLs: []string{newTestCert(t, 2, nil, func(c *x509.Certificate) { c.Subject = intermediate.cert.Subject }).crl(t)}},
+			err:    "invalid CRL",
+		},
+		{
+			note:   "scope mismatch",
+			token:  func(t *testing.T) string { return sign(t, leaf, leaf, intermediate) },
+			config: X509VerificationConfig{CACert: caFile},
+			scope:  "write",
+			err:    "scope mismatch",
+		},
+		{
+			note:   "no certificate chain",
+			token:  func(t *testing.T) string { return sign(t, leaf) },
+			config: X509VerificationConfig{CACert: caFile},
+			err:    "verification key ID is empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			bvc := NewVerificationConfig(nil, "", tc.scope, nil)
+			bvc.X509 = &tc.config
+			if err := bvc.ValidateAndInjectDefaults(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := VerifyBundleSignature(SignaturesConfig{Signatures: []string{tc.token(t)}}, bvc)
+			switch {
+			case tc.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestX509SigningErrors(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, 1, nil, nil)
+	leaf := newTestCert(t, 2, root, nil)
+
+	tests := []struct {
+		note string
+		sc   *SigningConfig
+		err  string
+	}{
+		{
+			note: "key does not match leaf",
+			sc:   NewSigningConfig(root.keyPEM(t), "ES256", "").WithCertChain(leaf.pem()),
+			err:  "signing key does not match the leaf certificate",
+		},
+		{
+			note: "symmetric key",
+			sc:   NewSigningConfig("secret", "HS256", "").WithCertChain(leaf.pem()),
+			err:  "certificate chains require an asymmetric signing key",
+		},
+		{
+			note: "no certificates",
+			sc:   NewSigningConfig(leaf.keyPEM(t), "ES256", "").WithCertChain(leaf.keyPEM(t)),
+			err:  "no PEM encoded certificates found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			_, err := GenerateSignedToken(nil, tc.sc, "")
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestX509VerificationConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, 1, nil, nil)
+
+	tests := []struct {
+		note   string
+		config X509VerificationConfig
+		err    string
+	}{
+		{
+			note: "missing CA",
+			err:  "x509: missing CA certificate",
+		},
+		{
+			note:   "CA file not found",
+			config: X509VerificationConfig{CACert: filepath.Join(t.TempDir(), "missing.pem")},
+			err:    "x509: failed to read CA certificate",
+		},
+		{
+			note:   "unknown key usage",
+			config: X509VerificationConfig{CACert: root.pem(), ExtKeyUsages: []string{"signing"}},
+			err:    `x509: unknown extended key usage "signing"`,
+		},
+		{
+			note:   "bad CRL",
+			config: X509VerificationConfig{CACert: root.pem(), CRLs: []string{root.pem()}},
+			err:    "x509: failed to parse CRL",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			bvc := &VerificationConfig{X509: &tc.config}
+			err := bvc.ValidateAndInjectDefaults(nil)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -98,9 +98,10 @@ type Compiler struct {
 	fsys                         fs.FS                      // file system to use when loading paths
 	ns                           string
 	regoVersion                  ast.RegoVersion
-	followSymlinks               bool      // optionally follow symlinks in the bundle directory when building the bundle
-	externalRefs                 []ast.Ref // external entrypoints provided dynamically
-	planAddons                   []string  // optional extra contents to include in the plan (e.g. unplanned_rules)
+	followSymlinks               bool           // optionally follow symlinks in the bundle directory when building the bundle
+	externalRefs                 []ast.Ref      // external entrypoints provided dynamically
+	planAddons                   []string       // optional extra contents to include in the plan (e.g. unplanned_rules)
+	deltaBase                    *bundle.Bundle // optionally, the bundle to output a delta bundle against
 }
 
 // New returns a new compiler instance that can be invoked.
@@ -291,6 +292,14 @@ func (c *Compiler) WithRegoVersion(v ast.RegoVersion) *Compiler {
 	return c
 }
 
+// WithDeltaBase sets the bundle to diff the output bundle against: if set, a
+// delta bundle of the data changes from the base bundle is output instead.
+// Building fails if policies changed, as delta bundles can only patch data.
+func (c *Compiler) WithDeltaBase(b *bundle.Bundle) *Compiler {
+	c.deltaBase = b
+	return c
+}
+
 // WithPlanAddons sets optional extra data to include in the generated plan.
 // The only supported value is "unplanned_rules".
 func (c *Compiler) WithPlanAddons(contents []string) *Compiler {
@@ -347,6 +356,9 @@ func (c *Compiler) Build(ctx context.Context) error {
 	if c.planFormat != PlanFormatJSON && c.target != TargetPlan {
 		return fmt.Errorf("plan format %q is only valid with target %q", c.planFormat, TargetPlan)
 	}
+	if c.deltaBase != nil && c.bsc != nil {
+		return errors.New("delta bundles can't be signed")
+	}
 
 	if err := c.init(); err != nil {
 		return err
@@ -456,6 +468,14 @@ func (c *Compiler) Build(ctx context.Context) error {
 		return err
 	}
 
+	if c.deltaBase != nil {
+		delta, err := bundle.NewDelta(c.deltaBase, c.bundle)
+		if err != nil {
+			return err
+		}
+		c.bundle = delta
+	}
+
 	if c.bsc != nil {
 		if err := c.bundle.GenerateSignature(c.bsc, c.keyID, false); err != nil {
 			return err
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
@@ -2995,6 +2996,55 @@ func TestCompilerSetMetadata(t *testing.T) {
 	}
 }
 
+func TestCompilerDeltaBase(t *testing.T) {
+	files := map[string]string{
+		"test.rego": `package test
+
+		p = true`,
+		"data.json": `{"a": {"b": 2}}`,
+	}
+
+	for _, useMemoryFS := range []bool{false, true} {
+		test.WithTestFS(files, useMemoryFS, func(root string, fsys fs.FS) {
+			base := &bundle.Bundle{
+				Data: map[string]any{"a": map[string]any{"b": json.Number("1")}},
+			}
+
+			compiler := New().
+				WithFS(fsys).
+				WithPaths(root).
+				WithRevision("deadbeef").
+				WithDeltaBase(base)
+			err := compiler.Build(t.Context())
+			if err == nil || !strings.Contains(err.Error(), "policy files changed") {
+				t.Fatal("expected policy change error but got:", err)
+			}
+
+			base.Modules = []bundle.ModuleFile{{
+				Path:   "test.rego",
+				Raw:    []byte("package test\n\np = true"),
+				Parsed: ast.MustParseModule("package test\n\np = true"),
+			}}
+			compiler = New().
+				WithFS(fsys).
+				WithPaths(root).
+				WithRevision("deadbeef").
+				WithDeltaBase(base)
+			if err := compiler.Build(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+
+			exp := []bundle.PatchOperation{{Op: "upsert", Path: "/a/b", Value: json.Number("2")}}
+			if compiler.bundle.Type() != bundle.DeltaBundleType || !reflect.DeepEqual(exp, compiler.bundle.Patch.Data) {
+				t.Fatal("expected delta bundle but got:", compiler.bundle)
+			}
+			if compiler.bundle.Manifest.Revision != "deadbeef" {
+				t.Fatal("expected revision to be set but got:", compiler.bundle.Manifest)
+			}
+		})
+	}
+}
+
 func TestCompilerSetRoots(t *testing.T) {
 	files := map[string]string{
 		"test.rego": `package test
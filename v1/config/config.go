@@ -30,6 +30,11 @@ type ServerConfig struct {
 	Encoding json.RawMessage `json:"encoding,omitempty"`
 	Decoding json.RawMessage `json:"decoding,omitempty"`
 
+	Authentication json.RawMessage `json:"authentication,omitempty"`
+	RateLimits     json.RawMessage `json:"rate_limits,omitempty"`
+
+	EnvoyExtAuthz json.RawMessage `json:"envoy_ext_authz,omitempty"`
+
 	LoggerPlugin *string `json:"logger_plugin,omitempty"`
 }
 
@@ -53,6 +58,18 @@ func (s *ServerConfig) Clone() *ServerConfig {
 		clone.Metrics = make(json.RawMessage, len(s.Metrics))
 		copy(clone.Metrics, s.Metrics)
 	}
+	if s.Authentication != nil {
+		clone.Authentication = make(json.RawMessage, len(s.Authentication))
+		copy(clone.Authentication, s.Authentication)
+	}
+	if s.RateLimits != nil {
+		clone.RateLimits = make(json.RawMessage, len(s.RateLimits))
+		copy(clone.RateLimits, s.RateLimits)
+	}
+	if s.EnvoyExtAuthz != nil {
+		clone.EnvoyExtAuthz = make(json.RawMessage, len(s.EnvoyExtAuthz))
+		copy(clone.EnvoyExtAuthz, s.EnvoyExtAuthz)
+	}
 	if s.LoggerPlugin != nil {
 		pluginName := *s.LoggerPlugin
 		clone.LoggerPlugin = &pluginName
@@ -322,6 +339,10 @@ func (c *Config) ActiveConfig() (any, error) {
 		return nil, err
 	}
 
+	if err := removeCacheCredentials(result["caching"]); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -464,6 +485,28 @@ func removeCryptoKeys(x any) error {
 	return nil
 }
 
+func removeCacheCredentials(x any) error {
+	switch x := x.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		path := []string{"inter_query_builtin_cache", "shared", "redis"}
+		var v any = x
+		for _, key := range path {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			if v, ok = obj[key]; !ok {
+				return nil
+			}
+		}
+		return removeKey(v, "password")
+	default:
+		return fmt.Errorf("illegal caching config type: %T", x)
+	}
+}
+
 func removeKey(x any, keys ...string) error {
 	val, ok := x.(map[string]any)
 	if !ok {
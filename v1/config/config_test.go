@@ -220,6 +220,13 @@ func TestActiveConfig(t *testing.T) {
 				}
 			}
 		},
+		"caching": {
+			"inter_query_builtin_cache": {
+				"shared": {
+					"redis": {"address": "localhost:6379", "password": "secret"}
+				}
+			}
+		},
 		"discovery": {"name": "config"}`
 
 	serviceObj := `"services": {
@@ -294,6 +301,13 @@ func TestActiveConfig(t *testing.T) {
 				}
 			}
 		},
+		"caching": {
+			"inter_query_builtin_cache": {
+				"shared": {
+					"redis": {"address": "localhost:6379"}
+				}
+			}
+		},
 		"default_authorization_decision": "/system/authz/allow",
 		"default_decision": "/system/main",
 		"discovery": {"name": "config"}`, version.Version)
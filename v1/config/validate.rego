@@ -96,11 +96,12 @@ _core_specs := [
 	{"pattern": ["decision_logs"], "keys": {
 		"plugin", "service", "partition_name", "reporting", "request_context",
 		"mask_decision", "drop_decision", "console", "resource", "nd_builtin_cache",
+		"kafka",
 	}},
 	{"pattern": ["decision_logs", "reporting"], "keys": {
 		"buffer_type", "buffer_size_limit_bytes", "buffer_size_limit_events",
 		"upload_size_limit_bytes", "min_delay_seconds", "max_delay_seconds",
-		"max_decisions_per_second", "trigger",
+		"max_decisions_per_second", "trigger", "buffer_path",
 	}},
 	{"pattern": ["decision_logs", "request_context"], "keys": {"http"}},
 	{"pattern": ["decision_logs", "request_context", "http"], "keys": {"headers"}},
@@ -126,16 +127,19 @@ _core_specs := [
 	{"pattern": ["bundle", "polling"], "keys": _polling_keys},
 	{"pattern": ["bundles", "*"], "keys": {
 		"service", "resource", "signing", "persist", "size_limit_bytes",
-		"trigger", "polling",
+		"trigger", "polling", "mirrors", "git",
 	}},
 	{"pattern": ["bundles", "*", "polling"], "keys": _polling_keys},
-	{"pattern": ["server"], "keys": {"metrics", "encoding", "decoding", "logger_plugin"}},
+	{"pattern": ["server"], "keys": {
+		"metrics", "encoding", "decoding", "authentication", "rate_limits",
+		"envoy_ext_authz", "logger_plugin",
+	}},
 	{"pattern": ["storage"], "keys": {"disk"}},
 	{"pattern": ["storage", "disk"], "keys": {"directory", "auto_create", "partitions", "badger"}},
 	{"pattern": ["caching"], "keys": {"inter_query_builtin_cache", "inter_query_builtin_value_cache"}},
 	{"pattern": ["caching", "inter_query_builtin_cache"], "keys": {
 		"max_size_bytes", "forced_eviction_threshold_percentage",
-		"stale_entry_eviction_period_seconds",
+		"stale_entry_eviction_period_seconds", "shared", "disk",
 	}},
 	{"pattern": ["caching", "inter_query_builtin_value_cache"], "keys": {"max_num_entries", "named"}},
 	{
@@ -87,6 +87,46 @@ test_no_warnings[tc.note] if {
 				"keys": {"my_key": {"key": "abc", "algorithm": "HS256"}},
 			},
 		},
+		{
+			"note": "kafka decision log sink",
+			"config": {"decision_logs": {"kafka": {"brokers": ["localhost:9092"], "topic": "decisions"}}},
+		},
+		{
+			"note": "disk decision log buffer",
+			"config": {
+				"services": [{"name": "s1", "url": "https://example.com"}],
+				"decision_logs": {
+					"service": "s1",
+					"reporting": {"buffer_type": "disk", "buffer_path": "/var/opa/logs"},
+				},
+			},
+		},
+		{
+			"note": "bundle mirrors",
+			"config": {
+				"services": [
+					{"name": "s1", "url": "https://example.com"},
+					{"name": "s2", "url": "https://mirror.example.com"},
+				],
+				"bundles": {"authz": {
+					"service": "s1",
+					"resource": "bundle.tar.gz",
+					"mirrors": [{"service": "s2"}],
+				}},
+			},
+		},
+		{
+			"note": "git bundle source",
+			"config": {"bundles": {"authz": {"git": {"repository": "https://example.com/policies.git", "ref": "main"}}}},
+		},
+		{
+			"note": "shared inter-query cache",
+			"config": {"caching": {"inter_query_builtin_cache": {"shared": {"redis": {"address": "localhost:6379"}}}}},
+		},
+		{
+			"note": "disk inter-query cache",
+			"config": {"caching": {"inter_query_builtin_cache": {"disk": {"directory": "/var/opa/cache"}}}},
+		},
 	]
 
 	config.warnings == set() with input as _input(tc.config)
@@ -0,0 +1,209 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/ast/location"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/topdown"
+)
+
+// DefaultAttachTimeout is the default maximum amount of time an evaluation can be held by an attached session.
+const DefaultAttachTimeout = 30 * time.Second
+
+// AttachProperties configures a session attached to evaluations performed outside the debugger.
+type AttachProperties struct {
+	StopOnResult bool
+	StopOnEntry  bool
+	StopOnFail   bool
+	SkipOps      []topdown.Op
+
+	// Store is the store evaluations read data from. If set, it's made available for inspection in the "Data" scope.
+	Store storage.Store
+
+	// Timeout is the maximum amount of time an evaluation can be held by the session, including time spent paused.
+	// Once it's exceeded, the evaluation is detached from the debugger and runs to completion without breaking.
+	// If not set, DefaultAttachTimeout is used.
+	Timeout time.Duration
+}
+
+// AttachedSession is a Session attached to evaluations performed outside the debugger.
+// Each evaluation that's debugged is represented by a thread of its own; threads are added as evaluations are offered
+// through Trace, and removed once they have completed.
+//
+// EXPERIMENTAL: This interface is under active development and is subject to change.
+type AttachedSession interface {
+	Session
+
+	// AddConditionalBreakpoint sets a breakpoint at the given location that only applies to evaluations for which
+	// condition is satisfied. The condition is a Rego query evaluated against the input of the evaluation,
+	// e.g. `input.user == "alice"`.
+	AddConditionalBreakpoint(loc location.Location, condition string) (Breakpoint, error)
+
+	// Trace offers an evaluation with the given input to the session. If any breakpoint of the session applies to the
+	// evaluation, a thread with the given name is started for it, and a tracer is returned that must be passed to the
+	// evaluation. The returned function must be called with the result set once the evaluation has completed.
+	// If the evaluation isn't to be debugged, a nil tracer is returned.
+	Trace(ctx context.Context, name string, input ast.Value) (topdown.QueryTracer, func(rego.ResultSet))
+}
+
+type attachedSession struct {
+	*session
+	store        storage.Store
+	timeout      time.Duration
+	lastThreadID ThreadID
+	terminated   bool
+}
+
+func (d *debugger) Attach(ctx context.Context, props AttachProperties) (AttachedSession, error) {
+	if props.SkipOps == nil {
+		props.SkipOps = defaultSkipOps
+	}
+
+	if props.Timeout <= 0 {
+		props.Timeout = DefaultAttachTimeout
+	}
+
+	varManager := newVariableManager(d.maxVariableLength)
+	s := newSession(ctx, d, varManager, LaunchProperties{
+		StopOnResult: props.StopOnResult,
+		StopOnEntry:  props.StopOnEntry,
+		StopOnFail:   props.StopOnFail,
+		SkipOps:      props.SkipOps,
+	}, nil)
+	s.attached = true
+
+	return &attachedSession{
+		session: s,
+		store:   props.Store,
+		timeout: props.Timeout,
+	}, nil
+}
+
+func (s *attachedSession) Trace(ctx context.Context, name string, input ast.Value) (topdown.QueryTracer, func(rego.ResultSet)) {
+	conditions, ok := s.match(input)
+	if !ok {
+		return nil, nil
+	}
+
+	s.mtx.Lock()
+	if s.terminated {
+		s.mtx.Unlock()
+		return nil, nil
+	}
+
+	tracer := newDebugTracer()
+	// Threads are 1-indexed, and IDs aren't reused once threads have completed.
+	s.lastThreadID++
+	t := newThread(s.lastThreadID, name, tracer, s.varManager, nil, s.store, s.d.logger)
+	t.eventHandler = s.handleEvent
+	t.state = &sessionThreadState{conditions: conditions}
+	s.threads = append(s.threads, t)
+	s.mtx.Unlock()
+
+	// The thread must keep consuming events after the session is terminated, or the evaluation would be blocked.
+	go func() {
+		s.runThread(context.WithoutCancel(s.ctx), t)
+
+		s.mtx.Lock()
+		s.threads = slices.DeleteFunc(s.threads, func(o *thread) bool {
+			return o == t
+		})
+		s.mtx.Unlock()
+	}()
+
+	timer := time.AfterFunc(s.timeout, func() {
+		s.d.logger.Info("Thread %d timed out after %v", t.id, s.timeout)
+		s.detach(t)
+	})
+	stop := context.AfterFunc(ctx, func() {
+		s.detach(t)
+	})
+
+	_ = t.resume()
+
+	return tracer, func(rs rego.ResultSet) {
+		timer.Stop()
+		stop()
+
+		if rs != nil {
+			tracer.resultSet = rs
+			s.result(t, rs)
+		}
+		_ = tracer.Close()
+	}
+}
+
+// match reports whether any breakpoint applies to an evaluation of the given input, along with the outcome of the
+// conditions of all breakpoints.
+func (s *attachedSession) match(input ast.Value) (map[BreakpointID]bool, bool) {
+	bps := s.breakpoints.all()
+	if len(bps) == 0 {
+		return nil, false
+	}
+
+	matched := false
+	conditions := make(map[BreakpointID]bool, len(bps))
+	for _, bp := range bps {
+		applies := s.conditionHolds(bp, input)
+		conditions[bp.ID()] = applies
+		matched = matched || applies
+	}
+	return conditions, matched
+}
+
+// detach releases the thread from the debugger, letting its evaluation run to completion without breaking.
+func (s *attachedSession) detach(t *thread) {
+	if t.detached.Swap(true) {
+		return
+	}
+	s.d.logger.Debug("Thread %d detached", t.id)
+	_ = t.resume()
+}
+
+func (s *attachedSession) Threads() ([]Thread, error) {
+	if s == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	threads := make([]Thread, 0, len(s.threads))
+	for _, t := range s.threads {
+		threads = append(threads, t)
+	}
+
+	return threads, nil
+}
+
+// Terminate stops offering evaluations to the session, and detaches all active threads so that their evaluations
+// can complete.
+func (s *attachedSession) Terminate() error {
+	if s == nil {
+		return errors.New("no active debug session")
+	}
+
+	s.mtx.Lock()
+	s.terminated = true
+	threads := slices.Clone(s.threads)
+	s.mtx.Unlock()
+
+	for _, t := range threads {
+		s.detach(t)
+	}
+
+	s.cancel()
+	s.d.sendEvent(Event{Type: TerminatedEventType})
+
+	return nil
+}
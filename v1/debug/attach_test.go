@@ -0,0 +1,235 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/ast/location"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+const attachTestModule = `package test
+
+allow if {
+	x := input.user
+	x != "mallory"
+}
+`
+
+type attachTestEval struct {
+	rs   rego.ResultSet
+	err  error
+	done chan struct{}
+}
+
+func prepareAttachTest(ctx context.Context, t *testing.T) rego.PreparedEvalQuery {
+	t.Helper()
+
+	pq, err := rego.New(
+		rego.Query("data.test.allow"),
+		rego.Module("test.rego", attachTestModule),
+	).PrepareForEval(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pq
+}
+
+func evalAttached(ctx context.Context, t *testing.T, s AttachedSession, pq rego.PreparedEvalQuery, user string) *attachTestEval {
+	t.Helper()
+
+	input := ast.NewObject(ast.Item(ast.StringTerm("user"), ast.StringTerm(user)))
+	tracer, done := s.Trace(ctx, "eval "+user, input)
+
+	opts := []rego.EvalOption{rego.EvalParsedInput(input)}
+	if tracer != nil {
+		opts = append(opts, rego.EvalQueryTracer(tracer))
+	}
+
+	e := &attachTestEval{done: make(chan struct{})}
+	go func() {
+		defer close(e.done)
+		e.rs, e.err = pq.Eval(ctx, opts...)
+		if done != nil {
+			done(e.rs)
+		}
+	}()
+	return e
+}
+
+func (e *attachTestEval) wait(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-e.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("evaluation did not complete")
+	}
+
+	if e.err != nil {
+		t.Fatal(e.err)
+	}
+	if !e.rs.Allowed() {
+		t.Fatalf("expected evaluation to be allowed, got %v", e.rs)
+	}
+}
+
+func TestAttachConditionalBreakpoint(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	pq := prepareAttachTest(ctx, t)
+
+	eh := newTestEventHandler()
+	d := NewDebugger(SetEventHandler(eh.HandleEvent))
+
+	s, err := d.Attach(ctx, AttachProperties{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.AddConditionalBreakpoint(location.Location{File: "test.rego", Row: 5}, "input.user =="); err == nil {
+		t.Fatal("expected error for invalid condition")
+	}
+
+	if _, err := s.AddConditionalBreakpoint(location.Location{File: "test.rego", Row: 5}, `input.user == "alice"`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Evaluations not satisfying the condition aren't debugged.
+	evalAttached(ctx, t, s, pq, "bob").wait(t)
+
+	e := evalAttached(ctx, t, s, pq, "alice")
+
+	stopped := eh.WaitFor(ctx, StoppedEventType)
+	if stopped == nil || stopped.Message != "breakpoint" {
+		t.Fatalf("expected breakpoint stop, got %v", stopped)
+	}
+
+	threads, err := s.Threads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 1 || threads[0].Name() != "eval alice" {
+		t.Fatalf("unexpected threads: %v", threads)
+	}
+
+	if top := topOfStack(t, s); top.Location().Row != 5 {
+		t.Fatalf("expected to be stopped on row 5, got %v", top.Location())
+	}
+
+	if err := s.Resume(stopped.Thread); err != nil {
+		t.Fatal(err)
+	}
+
+	result := eh.WaitFor(ctx, StdoutEventType)
+	if result == nil || result.Thread != stopped.Thread {
+		t.Fatalf("expected result output, got %v", result)
+	}
+
+	e.wait(t)
+
+	if exited := eh.WaitFor(ctx, ThreadEventType); exited == nil || exited.Message != "exited" {
+		t.Fatalf("expected thread to exit, got %v", exited)
+	}
+
+	// Threads are removed from the session once their evaluation has completed.
+	for {
+		threads, err := s.Threads()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(threads) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected no threads, got %v", threads)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	eh.IgnoreAll(ctx)
+
+	if err := s.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttachTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	pq := prepareAttachTest(ctx, t)
+
+	eh := newTestEventHandler()
+	d := NewDebugger(SetEventHandler(eh.HandleEvent))
+
+	s, err := d.Attach(ctx, AttachProperties{Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.AddBreakpoint(location.Location{File: "test.rego", Row: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := evalAttached(ctx, t, s, pq, "alice")
+
+	if stopped := eh.WaitFor(ctx, StoppedEventType); stopped == nil {
+		t.Fatal("expected evaluation to be stopped")
+	}
+
+	eh.IgnoreAll(ctx)
+
+	// The paused evaluation is released once the timeout expires.
+	e.wait(t)
+
+	if err := s.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttachTerminate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	pq := prepareAttachTest(ctx, t)
+
+	eh := newTestEventHandler()
+	d := NewDebugger(SetEventHandler(eh.HandleEvent))
+
+	s, err := d.Attach(ctx, AttachProperties{StopOnEntry: true, Timeout: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.AddBreakpoint(location.Location{File: "test.rego", Row: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := evalAttached(ctx, t, s, pq, "alice")
+
+	if stopped := eh.WaitFor(ctx, StoppedEventType); stopped == nil || stopped.Message != "entry" {
+		t.Fatalf("expected evaluation to be stopped on entry, got %v", stopped)
+	}
+
+	eh.IgnoreAll(ctx)
+
+	if err := s.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Terminating the session releases paused evaluations, and no new ones are debugged.
+	e.wait(t)
+
+	if tracer, _ := s.Trace(ctx, "eval", ast.NewObject()); tracer != nil {
+		t.Fatal("expected no tracer after termination")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/open-policy-agent/opa/v1/ast/location"
+	"github.com/open-policy-agent/opa/v1/rego"
 )
 
 type BreakpointID int
@@ -20,8 +21,9 @@ type Breakpoint interface {
 }
 
 type breakpoint struct {
-	id       BreakpointID
-	location location.Location
+	id        BreakpointID
+	location  location.Location
+	condition *rego.PreparedEvalQuery
 }
 
 func (b breakpoint) ID() BreakpointID {
@@ -73,12 +75,17 @@ func (bc *breakpointCollection) newID() BreakpointID {
 }
 
 func (bc *breakpointCollection) add(location location.Location) Breakpoint {
+	return bc.addWithCondition(location, nil)
+}
+
+func (bc *breakpointCollection) addWithCondition(location location.Location, condition *rego.PreparedEvalQuery) Breakpoint {
 	bc.mtx.Lock()
 	defer bc.mtx.Unlock()
 
 	bp := breakpoint{
-		id:       bc.newID(),
-		location: location,
+		id:        bc.newID(),
+		location:  location,
+		condition: condition,
 	}
 	bps := bc.breakpoints[bp.location.File]
 	bps = append(bps, bp)
@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	fileurl "github.com/open-policy-agent/opa/internal/file/url"
+	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/ast/location"
 	"github.com/open-policy-agent/opa/v1/logging"
 	"github.com/open-policy-agent/opa/v1/rego"
@@ -38,6 +39,11 @@ type Debugger interface {
 	// LaunchEval starts a new eval debug session with the given LaunchEvalProperties.
 	// The returned session is in a stopped state, and must be resumed to start execution.
 	LaunchEval(ctx context.Context, props LaunchEvalProperties, opts ...LaunchOption) (Session, error)
+
+	// Attach starts a new session with the given AttachProperties that is attached to evaluations performed outside
+	// the debugger, such as decisions served by a running OPA server. Evaluations are offered to the session through
+	// AttachedSession.Trace, and are debugged on a thread of their own if any breakpoint of the session applies to them.
+	Attach(ctx context.Context, props AttachProperties) (AttachedSession, error)
 }
 
 type debugger struct {
@@ -230,6 +236,8 @@ type LaunchProperties struct {
 	RuleIndexing        bool
 }
 
+var defaultSkipOps = []topdown.Op{topdown.IndexOp, topdown.RedoOp, topdown.SaveOp, topdown.UnifyOp}
+
 type LaunchOption func(options *launchOptions)
 
 type launchOptions struct {
@@ -283,7 +291,7 @@ func (d *debugger) LaunchEval(ctx context.Context, props LaunchEvalProperties, o
 	)
 
 	if props.SkipOps == nil {
-		props.SkipOps = []topdown.Op{topdown.IndexOp, topdown.RedoOp, topdown.SaveOp, topdown.UnifyOp}
+		props.SkipOps = defaultSkipOps
 	}
 
 	if props.EnablePrint {
@@ -393,6 +401,7 @@ type session struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	varManager     *variableManager
+	attached       bool
 	mtx            sync.Mutex
 }
 
@@ -427,23 +436,9 @@ func (s *session) start() error {
 
 	for _, t := range s.threads {
 		go func() {
-			s.d.logger.Debug("Thread %d started", t.id)
-			s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "started"})
-			if err := t.run(s.ctx); err != nil {
-				s.d.logger.Error("Thread %d failed: %v", t.id, err)
-			}
-			s.d.logger.Debug("Thread %d stopped", t.id)
-			s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "exited"})
+			s.runThread(s.ctx, t)
 
-			allStopped := true
-			for _, t := range s.threads {
-				if !t.done() {
-					allStopped = false
-					break
-				}
-			}
-
-			if allStopped {
+			if s.allThreadsDone() {
 				s.d.logger.Debug("All threads stopped")
 				s.d.sendEvent(Event{Type: TerminatedEventType})
 			}
@@ -453,16 +448,43 @@ func (s *session) start() error {
 	return nil
 }
 
+func (s *session) runThread(ctx context.Context, t *thread) {
+	s.d.logger.Debug("Thread %d started", t.id)
+	s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "started"})
+	if err := t.run(ctx); err != nil {
+		s.d.logger.Error("Thread %d failed: %v", t.id, err)
+	}
+	s.d.logger.Debug("Thread %d stopped", t.id)
+	s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "exited"})
+}
+
+// allThreadsDone reports whether all threads of the session are done.
+// Attached sessions are never done, as new threads are added to them as evaluations are offered.
+func (s *session) allThreadsDone() bool {
+	if s.attached {
+		return false
+	}
+
+	for _, t := range s.threads {
+		if !t.done() {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *session) thread(id ThreadID) (*thread, error) {
 	if s == nil {
 		return nil, errors.New("no active debug session")
 	}
 
-	index := int(id - 1)
-	if index < 0 || index >= len(s.threads) {
-		return nil, fmt.Errorf("invalid thread id: %d", id)
+	// Threads of attached sessions are removed once they have completed, so IDs don't map to indices.
+	for _, t := range s.threads {
+		if t.id == id {
+			return t, nil
+		}
 	}
-	return s.threads[index], nil
+	return nil, fmt.Errorf("invalid thread id: %d", id)
 }
 
 func (s *session) Resume(threadID ThreadID) error {
@@ -519,14 +541,7 @@ func (s *session) StepOver(threadID ThreadID) error {
 	}
 	if t.done() {
 		s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "exited"})
-		allStopped := true
-		for _, t := range s.threads {
-			if !t.done() {
-				allStopped = false
-				break
-			}
-		}
-		if allStopped {
+		if s.allThreadsDone() {
 			s.d.sendEvent(Event{Type: TerminatedEventType})
 		}
 	}
@@ -556,14 +571,7 @@ func (s *session) StepIn(threadID ThreadID) error {
 	}
 	if t.done() {
 		s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "exited"})
-		allStopped := true
-		for _, t := range s.threads {
-			if !t.done() {
-				allStopped = false
-				break
-			}
-		}
-		if allStopped {
+		if s.allThreadsDone() {
 			s.d.sendEvent(Event{Type: TerminatedEventType})
 		}
 	}
@@ -593,14 +601,7 @@ func (s *session) StepOut(threadID ThreadID) error {
 	}
 	if t.done() {
 		s.d.sendEvent(Event{Type: ThreadEventType, Thread: t.id, Message: "exited"})
-		allStopped := true
-		for _, t := range s.threads {
-			if !t.done() {
-				allStopped = false
-				break
-			}
-		}
-		if allStopped {
+		if s.allThreadsDone() {
 			s.d.sendEvent(Event{Type: TerminatedEventType})
 		}
 	}
@@ -628,6 +629,7 @@ type sessionThreadState struct {
 	entered     bool
 	ended       bool
 	prevQueryID uint64
+	conditions  map[BreakpointID]bool
 }
 
 func (s *sessionThreadState) String() string {
@@ -643,6 +645,15 @@ func (s *session) handleEvent(t *thread, stackIndex int, e *topdown.Event, ts th
 		state = &sessionThreadState{}
 	}
 
+	if t.detached.Load() {
+		// The thread has been detached from the debugger, and is allowed to run to completion without breaking.
+		if e == nil {
+			_ = t.close()
+			return stopAction, state, nil
+		}
+		return nopAction, state, nil
+	}
+
 	defer func() {
 		if e != nil {
 			state.prevQueryID = e.QueryID
@@ -700,7 +711,7 @@ func (s *session) handleEvent(t *thread, stackIndex int, e *topdown.Event, ts th
 
 	if e.Location.HasFile() {
 		for _, bp := range s.breakpoints.allForFilePath(e.Location.File) {
-			if bp.Location().Row == e.Location.Row {
+			if bp.Location().Row == e.Location.Row && s.breakpointApplies(bp, e, state) {
 				// if the last event also caused a breakpoint AND we're still on the same line, skip this breakpoint.
 				s.d.logger.Info("Thread %d stopped at breakpoint: %s:%d", t.id, e.Location.File, e.Location.Row)
 				s.d.sendEvent(Event{Type: StoppedEventType, Thread: t.id, Message: "breakpoint", stackIndex: stackIndex, stackEvent: e})
@@ -712,6 +723,48 @@ func (s *session) handleEvent(t *thread, stackIndex int, e *topdown.Event, ts th
 	return nopAction, state, nil
 }
 
+// breakpointApplies reports whether the condition of bp, if any, holds for the input of the evaluation.
+// As the input doesn't change during evaluation, the outcome is remembered for the thread.
+func (s *session) breakpointApplies(bp Breakpoint, e *topdown.Event, state *sessionThreadState) bool {
+	if applies, ok := state.conditions[bp.ID()]; ok {
+		return applies
+	}
+
+	var input ast.Value
+	if term := e.Input(); term != nil {
+		input = term.Value
+	}
+
+	applies := s.conditionHolds(bp, input)
+	if state.conditions == nil {
+		state.conditions = map[BreakpointID]bool{}
+	}
+	state.conditions[bp.ID()] = applies
+	return applies
+}
+
+func (s *session) conditionHolds(bp Breakpoint, input ast.Value) bool {
+	b, ok := bp.(breakpoint)
+	if !ok || b.condition == nil {
+		return true
+	}
+
+	rs, err := b.condition.Eval(s.ctx, rego.EvalParsedInput(input))
+	if err != nil {
+		s.d.logger.Debug("Failed to evaluate condition of breakpoint %d: %v", b.id, err)
+		return false
+	}
+
+	// Comparisons in queries are captured as expression values, so a condition like `input.user == "alice"`
+	// produces a result with a false value rather than no result when it's not satisfied.
+	for _, r := range rs {
+		if !slices.ContainsFunc(r.Expressions, func(expr *rego.ExpressionValue) bool { return expr.Value == false }) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *session) skipOp(op topdown.Op) bool {
 	return slices.Contains(s.properties.SkipOps, op)
 }
@@ -858,6 +911,22 @@ func (s *session) AddBreakpoint(loc location.Location) (Breakpoint, error) {
 	return s.breakpoints.add(loc), nil
 }
 
+func (s *session) AddConditionalBreakpoint(loc location.Location, condition string) (Breakpoint, error) {
+	if s == nil {
+		return nil, errors.New("no active debug session")
+	}
+
+	pq, err := rego.New(rego.Query(condition)).PrepareForEval(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid breakpoint condition: %w", err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.breakpoints.addWithCondition(loc, &pq), nil
+}
+
 func (s *session) RemoveBreakpoint(id BreakpointID) (Breakpoint, error) {
 	if s == nil {
 		return nil, errors.New("no active debug session")
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/ast/location"
@@ -48,6 +49,7 @@ type thread struct {
 	eventHandler    eventHandler
 	breakpointLatch latch
 	stopped         bool
+	detached        atomic.Bool
 	state           threadState
 	varManager      *variableManager
 	virtualCache    topdown.VirtualCache
@@ -31,6 +31,10 @@ import (
 
 const (
 	minRetryDelay = time.Millisecond * 100
+
+	// maxMirrorRetryDelay is the max time a failed mirror is skipped for,
+	// unless the max polling delay is longer.
+	maxMirrorRetryDelay = time.Minute * 5
 )
 
 // Update contains the result of a download. If an error occurred, the Error
@@ -43,6 +47,20 @@ type Update struct {
 	Metrics metrics.Metrics
 	Raw     io.Reader
 	Size    int
+	Service string // name of the service the update was downloaded from
+	Path    string // path the update was downloaded from
+}
+
+// Mirror is a server to download bundles from if the servers before it fail.
+type Mirror struct {
+	Client rest.Client
+	Path   string
+}
+
+type mirror struct {
+	Mirror
+	failures int       // number of consecutive failed downloads
+	retryAt  time.Time // time before which the mirror is skipped
 }
 
 // Downloader implements low-level OPA bundle downloading. Downloader can be
@@ -69,6 +87,9 @@ type Downloader struct {
 	lazyLoadingMode    bool
 	bundleName         string
 	bundleParserOpts   ast.ParserOptions
+	mirrors            []*mirror // servers in priority order, including the downloader's own, if mirrors are set
+	current            int       // index of the mirror client and path are set to
+	etagMirror         int       // index of the mirror etag was received from
 }
 
 type downloaderResponse struct {
@@ -144,6 +165,26 @@ func (d *Downloader) WithBundleParserOpts(opts ast.ParserOptions) *Downloader {
 	return d
 }
 
+// WithMirrors sets the servers to fail over to, in priority order, if the
+// downloader's server fails to serve bundles. Each download tries the servers
+// in order, skipping the ones that failed recently: a server that failed is
+// only retried after a backoff, and the downloader fails back to it once it
+// serves bundles again. The bundle etag is only sent to the server that
+// returned it.
+func (d *Downloader) WithMirrors(mirrors ...Mirror) *Downloader {
+	if len(mirrors) == 0 {
+		d.mirrors = nil
+		return d
+	}
+
+	d.mirrors = make([]*mirror, 0, len(mirrors)+1)
+	d.mirrors = append(d.mirrors, &mirror{Mirror: Mirror{Client: d.client, Path: d.path}})
+	for _, m := range mirrors {
+		d.mirrors = append(d.mirrors, &mirror{Mirror: m})
+	}
+	return d
+}
+
 // ClearCache is deprecated. Use SetCache instead.
 func (d *Downloader) ClearCache() {
 	d.etag = ""
@@ -274,10 +315,21 @@ func (d *Downloader) oneShot(ctx context.Context) error {
 	}
 
 	d.etag = resp.etag
+	d.etagMirror = d.current
 	d.longPollingEnabled = resp.longPoll
 
 	if d.f != nil {
-		if err := d.f(ctx, Update{ETag: resp.etag, Bundle: resp.b, Error: nil, Metrics: m, Raw: resp.raw, Size: resp.size}); err != nil {
+		u := Update{
+			ETag:    resp.etag,
+			Bundle:  resp.b,
+			Error:   nil,
+			Metrics: m,
+			Raw:     resp.raw,
+			Size:    resp.size,
+			Service: d.client.Service(),
+			Path:    d.path,
+		}
+		if err := d.f(ctx, u); err != nil {
 			return err
 		}
 	}
@@ -285,9 +337,90 @@ func (d *Downloader) oneShot(ctx context.Context) error {
 }
 
 func (d *Downloader) download(ctx context.Context, m metrics.Metrics) (*downloaderResponse, error) {
+	if len(d.mirrors) == 0 {
+		return d.downloadFrom(ctx, m)
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, i := range d.mirrorOrder(now) {
+		if i != d.current {
+			d.logger.Debug("Downloading bundle from service %q.", d.mirrors[i].Client.Service())
+			d.useMirror(i)
+		}
+
+		resp, err := d.downloadFrom(ctx, m)
+		mr := d.mirrors[i]
+		if err == nil {
+			mr.failures = 0
+			mr.retryAt = time.Time{}
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		mr.failures++
+		mr.retryAt = now.Add(d.mirrorRetryDelay(mr.failures))
+		d.logger.Warn("Bundle download from service %q failed: %v.", mr.Client.Service(), err)
+		errs = append(errs, fmt.Errorf("service %q: %w", mr.Client.Service(), err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// mirrorOrder returns the indices of the mirrors to try, in priority order:
+// the mirrors that aren't waiting for a retry, or all of them if all are.
+func (d *Downloader) mirrorOrder(now time.Time) []int {
+	order := make([]int, 0, len(d.mirrors))
+	for i, mr := range d.mirrors {
+		if !now.Before(mr.retryAt) {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		for i := range d.mirrors {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// useMirror sets the client and path to the ones of mirror i. The current
+// client is kept, as downloads update its headers and timeouts.
+func (d *Downloader) useMirror(i int) {
+	d.mirrors[d.current].Client = d.client
+	d.current = i
+	d.client = d.mirrors[i].Client
+	d.path = d.mirrors[i].Path
+	d.longPollingEnabled = d.config.Polling.LongPollingTimeoutSeconds != nil
+}
+
+// mirrorRetryDelay returns the time to skip a mirror for after it failed,
+// starting from the min polling delay with an exponential backoff.
+func (d *Downloader) mirrorRetryDelay(failures int) time.Duration {
+	minDelay := time.Duration(defaultMinDelaySeconds) * time.Second
+	maxDelay := maxMirrorRetryDelay
+	// the parsed delays are only set if the config was validated
+	if d.config.Polling.parsedMinDelaySeconds != nil {
+		minDelay = time.Duration(*d.config.Polling.parsedMinDelaySeconds)
+	}
+	if d.config.Polling.parsedMaxDelaySeconds != nil {
+		maxDelay = max(maxDelay, time.Duration(*d.config.Polling.parsedMaxDelaySeconds))
+	}
+	return util.DefaultBackoff(float64(minDelay), float64(maxDelay), failures)
+}
+
+func (d *Downloader) downloadFrom(ctx context.Context, m metrics.Metrics) (*downloaderResponse, error) {
 	d.logger.Debug("Download starting.")
 
-	d.client = d.client.WithHeader("If-None-Match", d.etag)
+	etag := d.etag
+	if d.current != d.etagMirror {
+		// etags of other servers are meaningless, and could be used to
+		// compute delta bundles against the wrong revision.
+		etag = ""
+	}
+	d.client = d.client.WithHeader("If-None-Match", etag)
 
 	preferences := []string{fmt.Sprintf("modes=%v,%v", defaultBundleMode, deltaBundleMode)}
 
@@ -18,10 +18,12 @@ import (
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/keys"
 	"github.com/open-policy-agent/opa/v1/logging"
 	"github.com/open-policy-agent/opa/v1/logging/test"
 	"github.com/open-policy-agent/opa/v1/metrics"
 	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/plugins/rest"
 )
 
 func TestStartStop(t *testing.T) {
@@ -1126,3 +1128,96 @@ func TestWarnOnNonBundleContentType(t *testing.T) {
 		t.Errorf("Expected log entry: %s", expectLogged)
 	}
 }
+
+func TestMirrorFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fixture := newTestFixture(t)
+	mirrorFixture := newTestFixture(t)
+	defer fixture.server.stop()
+	defer mirrorFixture.server.stop()
+
+	fixture.server.expEtag = "some etag value"
+	mirrorFixture.server.expEtag = "some etag value"
+
+	config := Config{}
+	if err := config.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	fixture.d = New(config, newTestClient(t, "primary", fixture.server), "/bundles/test/bundle1").
+		WithMirrors(Mirror{Client: newTestClient(t, "mirror", mirrorFixture.server), Path: "/bundles/test/bundle1"}).
+		WithCallback(fixture.oneShot)
+
+	expUpdate := func(service string, withBundle bool) {
+		t.Helper()
+
+		u := fixture.updates[len(fixture.updates)-1]
+		if u.Error != nil {
+			t.Fatal("Unexpected:", u.Error)
+		}
+		if u.Service != service || u.Path != "/bundles/test/bundle1" {
+			t.Fatalf("Expected update from %q but got %q (path %q)", service, u.Service, u.Path)
+		}
+		if (u.Bundle != nil) != withBundle {
+			t.Fatalf("Expected bundle %v but got %v", withBundle, u.Bundle)
+		}
+	}
+
+	// fail over to the mirror
+	fixture.server.expCode = 500
+	if err := fixture.d.oneShot(ctx); err != nil {
+		t.Fatal("Unexpected:", err)
+	}
+	expUpdate("mirror", true)
+
+	// the primary is skipped until it's due for a retry, even if it recovered
+	fixture.server.expCode = 0
+	if err := fixture.d.oneShot(ctx); err != nil {
+		t.Fatal("Unexpected:", err)
+	}
+	expUpdate("mirror", false)
+
+	// fail back to the primary, without sending it the etag of the mirror
+	fixture.d.mirrors[0].retryAt = time.Time{}
+	if err := fixture.d.oneShot(ctx); err != nil {
+		t.Fatal("Unexpected:", err)
+	}
+	expUpdate("primary", true)
+
+	if err := fixture.d.oneShot(ctx); err != nil {
+		t.Fatal("Unexpected:", err)
+	}
+	expUpdate("primary", false)
+
+	// all servers fail
+	fixture.server.expCode = 500
+	mirrorFixture.server.expCode = 404
+	err := fixture.d.oneShot(ctx)
+	if err == nil {
+		t.Fatal("Expected error but got nil")
+	}
+	for _, exp := range []string{`service "primary": server replied with Internal Server Error`, `service "mirror": server replied with Not Found`} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Fatalf("Expected error to contain %q but got %v", exp, err)
+		}
+	}
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 500 {
+		t.Fatalf("Expected HTTP error of the primary but got %v", err)
+	}
+	if fixture.d.mirrors[0].failures != 1 || fixture.d.mirrors[1].failures != 1 {
+		t.Fatalf("Expected one failure per server but got %d and %d", fixture.d.mirrors[0].failures, fixture.d.mirrors[1].failures)
+	}
+}
+
+func newTestClient(t *testing.T, name string, ts *testServer) rest.Client {
+	t.Helper()
+
+	client, err := rest.New(fmt.Appendf(nil, `{"name": %q, "url": %q}`, name, ts.server.URL), map[string]*keys.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
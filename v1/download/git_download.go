@@ -0,0 +1,384 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/compile"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// GitDownloader implements bundle downloading from a git repository. Instead of
+// fetching a bundle file, it fetches a commit of the repository and builds the
+// bundle from its files the same way as 'opa build --bundle' does. The commit
+// SHA is used as the bundle revision and etag.
+//
+// The downloader runs the git executable, so remotes can be anything git can
+// fetch from, e.g. local paths or smart HTTP(S) URLs. Credentials are handled
+// by git itself, e.g. through credential helpers.
+type GitDownloader struct {
+	config           Config                              // downloader configuration for tuning polling and other downloader behaviour
+	repository       string                              // repository to fetch from
+	ref              string                              // branch or tag to fetch
+	path             string                              // directory in the repository to build the bundle from
+	localRepoPath    string                              // path of the local repository commits are fetched into
+	stop             chan chan struct{}                  // used to signal plugin to stop running
+	f                func(context.Context, Update) error // callback function invoked when download updates occur
+	etag             string                              // SHA of the last commit built
+	wg               sync.WaitGroup
+	triggerWG        sync.WaitGroup
+	logger           logging.Logger
+	mtx              sync.Mutex // serializes git operations on the local repository
+	stateMtx         sync.Mutex
+	stopped          bool
+	stopOnce         sync.Once
+	bundleParserOpts ast.ParserOptions
+	capabilities     *ast.Capabilities
+}
+
+// NewGit returns a new GitDownloader that can be started. The ref is the branch
+// or tag of the repository to build bundles from.
+func NewGit(config Config, repository, ref string) *GitDownloader {
+	return &GitDownloader{
+		config:     config,
+		repository: repository,
+		ref:        ref,
+		stop:       make(chan chan struct{}),
+		logger:     logging.Get(),
+	}
+}
+
+// WithCallback registers a function f to be called when download updates occur.
+func (d *GitDownloader) WithCallback(f func(context.Context, Update) error) *GitDownloader {
+	d.f = f
+	return d
+}
+
+// WithLogger sets the logger used by the downloader.
+func (d *GitDownloader) WithLogger(logger logging.Logger) *GitDownloader {
+	d.logger = logger
+	return d
+}
+
+// WithLogAttrs sets an optional set of key/value pair attributes to include in
+// log messages emitted by the downloader.
+func (d *GitDownloader) WithLogAttrs(attrs map[string]any) *GitDownloader {
+	d.logger = d.logger.WithFields(attrs)
+	return d
+}
+
+// WithPath sets the directory in the repository to build bundles from. By
+// default, bundles are built from the root of the repository.
+func (d *GitDownloader) WithPath(path string) *GitDownloader {
+	d.path = path
+	return d
+}
+
+// WithBundleParserOpts specifies the parser options to use when building bundles.
+func (d *GitDownloader) WithBundleParserOpts(opts ast.ParserOptions) *GitDownloader {
+	d.bundleParserOpts = opts
+	return d
+}
+
+// WithCapabilities sets the capabilities to build bundles with.
+func (d *GitDownloader) WithCapabilities(c *ast.Capabilities) *GitDownloader {
+	d.capabilities = c
+	return d
+}
+
+// ClearCache is deprecated. Use SetCache instead.
+func (*GitDownloader) ClearCache() {
+}
+
+// SetCache sets the etag value to the commit SHA of the loaded bundle.
+func (d *GitDownloader) SetCache(etag string) {
+	d.etag = etag
+}
+
+// Trigger can be used to control when the downloader attempts to download
+// a new bundle in manual triggering mode.
+func (d *GitDownloader) Trigger(ctx context.Context) error {
+	d.stateMtx.Lock()
+	if d.stopped {
+		d.stateMtx.Unlock()
+		return errors.New("downloader stopped")
+	}
+	d.triggerWG.Add(1)
+	d.stateMtx.Unlock()
+
+	done := make(chan error)
+
+	go func() {
+		defer d.triggerWG.Done()
+
+		err := d.oneShot(ctx)
+		if err != nil {
+			d.logger.Error("Git - Bundle download failed: %v.", err)
+			if ctx.Err() == nil {
+				done <- err
+			}
+		}
+		close(done)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start tells the Downloader to begin downloading bundles.
+func (d *GitDownloader) Start(ctx context.Context) {
+	if *d.config.Trigger == plugins.TriggerPeriodic {
+		go d.doStart(ctx)
+	}
+}
+
+// Stop tells the Downloader to stop downloading bundles.
+func (d *GitDownloader) Stop(context.Context) {
+	d.stopOnce.Do(func() {
+		d.stateMtx.Lock()
+		d.stopped = true
+		d.stateMtx.Unlock()
+
+		if *d.config.Trigger == plugins.TriggerPeriodic {
+			done := make(chan struct{})
+			d.stop <- done
+			<-done
+		}
+
+		d.triggerWG.Wait()
+		d.cleanupLocalRepo()
+	})
+}
+
+func (d *GitDownloader) cleanupLocalRepo() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.localRepoPath == "" {
+		return
+	}
+	if err := os.RemoveAll(d.localRepoPath); err != nil {
+		d.logger.Error("Git - Failed to remove local repository %q: %v.", d.localRepoPath, err)
+	}
+	d.localRepoPath = ""
+}
+
+func (d *GitDownloader) doStart(context.Context) {
+	// We'll revisit context passing/usage later.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d.wg.Add(1)
+	go d.loop(ctx)
+
+	done := <-d.stop // blocks until there's something to read
+	cancel()
+	d.wg.Wait()
+	close(done)
+}
+
+func (d *GitDownloader) loop(ctx context.Context) {
+	defer d.wg.Done()
+
+	var retry int
+
+	for {
+
+		var delay time.Duration
+
+		err := d.oneShot(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			delay = util.DefaultBackoff(float64(minRetryDelay), float64(*d.config.Polling.parsedMaxDelaySeconds), retry)
+		} else {
+			min := float64(*d.config.Polling.parsedMinDelaySeconds)
+			max := float64(*d.config.Polling.parsedMaxDelaySeconds)
+			delay = time.Duration(((max - min) * rand.Float64()) + min)
+		}
+
+		d.logger.Debug("Git - Waiting %v before next download/retry.", delay)
+
+		timer, timerCancel := util.TimerWithCancel(delay)
+		select {
+		case <-timer.C:
+			if err != nil {
+				retry++
+			} else {
+				retry = 0
+			}
+		case <-ctx.Done():
+			timerCancel() // explicitly cancel the timer.
+			return
+		}
+	}
+}
+
+func (d *GitDownloader) oneShot(ctx context.Context) error {
+	m := metrics.New()
+	resp, err := d.download(ctx, m)
+	if err != nil {
+		if d.f != nil {
+			err = errors.Join(err, d.f(ctx, Update{Error: err, Metrics: m}))
+		}
+		return err
+	}
+	d.SetCache(resp.etag)
+
+	if d.f != nil {
+		if err := d.f(ctx, Update{ETag: resp.etag, Bundle: resp.b, Metrics: m, Raw: resp.raw, Size: resp.size}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *GitDownloader) download(ctx context.Context, m metrics.Metrics) (*downloaderResponse, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.logger.Debug("Git - Download starting.")
+
+	m.Timer(metrics.BundleRequest).Start()
+	defer m.Timer(metrics.BundleRequest).Stop()
+
+	if d.localRepoPath == "" {
+		dir, err := os.MkdirTemp("", "opa-git-*")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := runGit(ctx, dir, "init", "--bare", "--quiet"); err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, err
+		}
+		d.localRepoPath = dir
+	}
+
+	if _, err := runGit(ctx, d.localRepoPath, "fetch", "--quiet", "--depth=1", "--no-tags", "--", d.repository, d.ref); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from %s: %w", d.ref, d.repository, err)
+	}
+
+	sha, err := runGit(ctx, d.localRepoPath, "rev-parse", "--verify", "--quiet", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", d.ref, err)
+	}
+
+	if sha == d.etag {
+		d.logger.Debug("Git - Bundle not modified, commit %v already built.", sha)
+		return &downloaderResponse{etag: sha}, nil
+	}
+
+	b, raw, err := d.build(ctx, sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bundle from commit %s: %w", sha, err)
+	}
+
+	d.logger.Debug("Git - Successfully built bundle from commit %v.", sha)
+
+	return &downloaderResponse{
+		b:    b,
+		raw:  bytes.NewReader(raw),
+		etag: sha,
+		size: len(raw),
+	}, nil
+}
+
+// build checks out the commit into a temporary worktree and builds the bundle
+// from it.
+func (d *GitDownloader) build(ctx context.Context, sha string) (*bundle.Bundle, []byte, error) {
+	worktree, err := os.MkdirTemp("", "opa-git-worktree-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(worktree)
+
+	if _, err := runGit(ctx, d.localRepoPath, "worktree", "add", "--quiet", "--force", "--detach", worktree, sha); err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if _, err := runGit(context.WithoutCancel(ctx), d.localRepoPath, "worktree", "remove", "--force", worktree); err != nil {
+			d.logger.Warn("Git - Failed to remove worktree: %v.", err)
+		}
+	}()
+
+	// The path is validated with the configuration, but a bundle must never
+	// be built from outside of the checked out commit.
+	dir := filepath.Join(worktree, filepath.FromSlash(d.path))
+	if rel, err := filepath.Rel(worktree, dir); err != nil || !filepath.IsLocal(rel) {
+		return nil, nil, fmt.Errorf("path %q is outside of the repository", d.path)
+	}
+
+	regoVersion := d.bundleParserOpts.RegoVersion
+	if regoVersion == ast.RegoUndefined {
+		regoVersion = ast.DefaultRegoVersion
+	}
+
+	var buf bytes.Buffer
+	compiler := compile.New().
+		WithCapabilities(d.capabilities).
+		WithAsBundle(true).
+		WithOutput(&buf).
+		WithPaths(dir).
+		WithFilter(loader.GlobExcludeName(".git", 1)).
+		WithRegoVersion(regoVersion).
+		WithRevision(sha)
+
+	if err := compiler.Build(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	raw := buf.Bytes()
+	b, err := bundle.NewReader(bytes.NewReader(raw)).
+		WithBundleEtag(sha).
+		WithRegoVersion(regoVersion).
+		WithProcessAnnotations(d.bundleParserOpts.ProcessAnnotation).
+		Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &b, raw, nil
+}
+
+// runGit runs git with args in dir and returns its trimmed standard output.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Repositories can be configured through discovery, so transports that
+	// run commands, like ssh and ext::, aren't allowed.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ALLOW_PROTOCOL=file:http:https")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
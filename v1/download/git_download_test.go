@@ -0,0 +1,245 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+//go:build slow
+// +build slow
+
+package download
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/plugins"
+)
+
+type testGitRepo struct {
+	t      *testing.T
+	remote string // bare repository the downloader fetches from
+	work   string // working copy commits are pushed from
+}
+
+func newTestGitRepo(t *testing.T) *testGitRepo {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	r := &testGitRepo{t: t, remote: filepath.Join(dir, "remote.git"), work: filepath.Join(dir, "work")}
+	r.git(dir, "init", "--quiet", "--bare", r.remote)
+	r.git(dir, "init", "--quiet", "--initial-branch=main", r.work)
+	return r
+}
+
+func (r *testGitRepo) git(dir string, args ...string) string {
+	r.t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)
+	out, err := runGit(context.Background(), dir, args...)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return out
+}
+
+// commit writes files to the working copy, commits and pushes them, and
+// returns the commit SHA.
+func (r *testGitRepo) commit(files map[string]string) string {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git(r.work, "add", "--all")
+	r.git(r.work, "commit", "--quiet", "--message=update")
+	r.git(r.work, "push", "--quiet", r.remote, "main")
+	return r.git(r.work, "rev-parse", "HEAD")
+}
+
+func newTestGitDownloader(t *testing.T, repository, ref string) (*GitDownloader, *[]Update) {
+	t.Helper()
+
+	config := Config{}
+	manual := plugins.TriggerManual
+	config.Trigger = &manual
+	if err := config.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []Update
+	d := NewGit(config, repository, ref).WithLogger(logging.NewNoOpLogger()).WithCallback(func(_ context.Context, u Update) error {
+		updates = append(updates, u)
+		return nil
+	})
+	t.Cleanup(func() { d.Stop(context.Background()) })
+	return d, &updates
+}
+
+func TestGitDownloader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestGitRepo(t)
+
+	sha1 := repo.commit(map[string]string{
+		"authz/policy.rego": "package authz\n\nallow if input.user == data.authz.admin\n",
+		"authz/data.json":   `{"admin": "alice"}`,
+		".github/ci.yaml":   "on: push",
+		"README.md":         "# policies",
+	})
+	repo.git(repo.work, "tag", "v1")
+	repo.git(repo.work, "push", "--quiet", repo.remote, "v1")
+
+	d, updates := newTestGitDownloader(t, repo.remote, "main")
+
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	u := (*updates)[0]
+	if u.Bundle == nil || u.ETag != sha1 || u.Bundle.Manifest.Revision != sha1 || u.Bundle.Etag != sha1 {
+		t.Fatalf("expected bundle with revision and etag %v, got %+v", sha1, u)
+	}
+	if len(u.Bundle.Modules) != 1 || u.Bundle.Modules[0].Parsed.Package.Path.String() != "data.authz" {
+		t.Fatalf("expected authz module, got %v", u.Bundle.Modules)
+	}
+	if admin := u.Bundle.Data["authz"].(map[string]any)["admin"]; admin != "alice" {
+		t.Fatalf("expected data from repository, got %v", u.Bundle.Data)
+	}
+	if u.Raw == nil || u.Size == 0 {
+		t.Fatal("expected raw bundle")
+	}
+
+	// unchanged commits aren't built again
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := (*updates)[1]; u.Bundle != nil || u.ETag != sha1 {
+		t.Fatalf("expected no bundle for unchanged commit, got %+v", u)
+	}
+
+	sha2 := repo.commit(map[string]string{"authz/data.json": `{"admin": "bob"}`})
+
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	u = (*updates)[2]
+	if u.Bundle == nil || u.Bundle.Manifest.Revision != sha2 {
+		t.Fatalf("expected bundle with revision %v, got %+v", sha2, u)
+	}
+	if admin := u.Bundle.Data["authz"].(map[string]any)["admin"]; admin != "bob" {
+		t.Fatalf("expected updated data, got %v", u.Bundle.Data)
+	}
+
+	// tags resolve to the commit they point to
+	tagged, updates := newTestGitDownloader(t, repo.remote, "v1")
+	if err := tagged.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := (*updates)[0]; u.Bundle == nil || u.Bundle.Manifest.Revision != sha1 {
+		t.Fatalf("expected bundle with revision %v, got %+v", sha1, u)
+	}
+}
+
+func TestGitDownloaderPath(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestGitRepo(t)
+	sha := repo.commit(map[string]string{
+		"bundles/authz/.manifest":   `{"roots": ["authz"]}`,
+		"bundles/authz/policy.rego": "package authz\n\nallow := true\n",
+		"bundles/other/policy.rego": "package other\n\nallow := true\n",
+	})
+
+	d, updates := newTestGitDownloader(t, "file://"+filepath.ToSlash(repo.remote), "main")
+	d = d.WithPath("bundles/authz")
+
+	if err := d.Trigger(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	u := (*updates)[0]
+	if u.Bundle == nil || u.Bundle.Manifest.Revision != sha {
+		t.Fatalf("expected bundle with revision %v, got %+v", sha, u)
+	}
+	if roots := *u.Bundle.Manifest.Roots; len(roots) != 1 || roots[0] != "authz" {
+		t.Fatalf("expected roots from manifest, got %v", roots)
+	}
+	if len(u.Bundle.Modules) != 1 {
+		t.Fatalf("expected one module, got %v", u.Bundle.Modules)
+	}
+}
+
+func TestGitDownloaderErrors(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestGitRepo(t)
+	repo.commit(map[string]string{"policy.rego": "package a\n\nallow if {"})
+
+	tests := []struct {
+		note       string
+		repository string
+		ref        string
+		path       string
+		err        string
+	}{
+		{
+			note:       "unknown ref",
+			repository: repo.remote,
+			ref:        "missing",
+			err:        "failed to fetch missing",
+		},
+		{
+			note:       "unknown repository",
+			repository: filepath.Join(t.TempDir(), "missing.git"),
+			ref:        "main",
+			err:        "failed to fetch main",
+		},
+		{
+			note:       "disallowed transport",
+			repository: "ssh://git@127.0.0.1:1/policies.git",
+			ref:        "main",
+			err:        "transport 'ssh' not allowed",
+		},
+		{
+			note:       "invalid policy",
+			repository: repo.remote,
+			ref:        "main",
+			err:        "failed to build bundle from commit",
+		},
+		{
+			note:       "path outside of repository",
+			repository: repo.remote,
+			ref:        "main",
+			path:       "../",
+			err:        `path "../" is outside of the repository`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			d, updates := newTestGitDownloader(t, tc.repository, tc.ref)
+			d = d.WithPath(tc.path)
+
+			err := d.Trigger(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+			if len(*updates) != 1 || (*updates)[0].Error == nil {
+				t.Fatalf("expected error update, got %+v", *updates)
+			}
+		})
+	}
+}
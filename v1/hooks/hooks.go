@@ -79,7 +79,8 @@ type ConfigDiscoveryHook interface {
 
 // InterQueryCacheHook allows access to the server's inter-query cache instance.
 // It's useful for out-of-tree handlers that also need to evaluate something.
-// Using this hook, they can share the caches with the rest of OPA.
+// Using this hook, they can share the caches with the rest of OPA, or plug in
+// a custom shared backend if the cache implements topdown_cache.SharedInterQueryCache.
 type InterQueryCacheHook interface {
 	OnInterQueryCache(context.Context, topdown_cache.InterQueryCache) error
 }
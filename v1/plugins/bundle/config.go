@@ -5,9 +5,11 @@
 package bundle
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"path"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -35,7 +37,7 @@ func ParseConfig(config []byte, services []string) (*Config, error) {
 		return nil, err
 	}
 
-	if err := parsedConfig.validateAndInjectDefaults(services, nil, nil); err != nil {
+	if err := parsedConfig.validateAndInjectDefaults(services, nil, nil, nil); err != nil {
 		return nil, err
 	}
 
@@ -80,6 +82,13 @@ func (b *ConfigBuilder) WithServices(services []string) *ConfigBuilder {
 	return b
 }
 
+// WithServiceTypes sets the types of the services, e.g. "oci", by service name.
+// Mirrors are rejected for bundles downloaded from OCI services.
+func (b *ConfigBuilder) WithServiceTypes(types map[string]string) *ConfigBuilder {
+	b.serviceTypes = types
+	return b
+}
+
 // WithKeyConfigs sets the public keys to verify a signed bundle
 func (b *ConfigBuilder) WithKeyConfigs(keys map[string]*keys.Config) *ConfigBuilder {
 	b.keys = keys
@@ -112,7 +121,7 @@ func (b *ConfigBuilder) Parse() (*Config, error) {
 		}
 	}
 
-	err := c.validateAndInjectDefaults(b.services, b.keys, b.trigger)
+	err := c.validateAndInjectDefaults(b.services, b.serviceTypes, b.keys, b.trigger)
 	if err != nil {
 		return nil, err
 	}
@@ -122,10 +131,11 @@ func (b *ConfigBuilder) Parse() (*Config, error) {
 
 // ConfigBuilder assists in the construction of the plugin configuration.
 type ConfigBuilder struct {
-	raw      []byte
-	services []string
-	keys     map[string]*keys.Config
-	trigger  *plugins.TriggerMode
+	raw          []byte
+	services     []string
+	serviceTypes map[string]string
+	keys         map[string]*keys.Config
+	trigger      *plugins.TriggerMode
 }
 
 // Config represents the configuration of the plugin.
@@ -152,6 +162,23 @@ type Source struct {
 	Signing        *bundle.VerificationConfig `json:"signing"`
 	Persist        bool                       `json:"persist"`
 	SizeLimitBytes int64                      `json:"size_limit_bytes"`
+	Mirrors        []Mirror                   `json:"mirrors,omitempty"`
+	Git            *GitSource                 `json:"git,omitempty"`
+}
+
+// GitSource is a git repository to build the bundle from instead of
+// downloading it from a service.
+type GitSource struct {
+	Repository string `json:"repository"`
+	Ref        string `json:"ref,omitempty"`  // branch or tag, defaults to the repository's default branch
+	Path       string `json:"path,omitempty"` // directory in the repository to build the bundle from
+}
+
+// Mirror is a service to download the bundle from if the source's service,
+// or the mirrors before it, fail to serve it.
+type Mirror struct {
+	Service  string `json:"service"`
+	Resource string `json:"resource,omitempty"` // defaults to the source's resource
 }
 
 // IsMultiBundle returns whether or not the config is the newer multi-bundle
@@ -162,7 +189,7 @@ func (c *Config) IsMultiBundle() bool {
 	return c.Name == ""
 }
 
-func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*keys.Config, trigger *plugins.TriggerMode) error {
+func (c *Config) validateAndInjectDefaults(services []string, serviceTypes map[string]string, keys map[string]*keys.Config, trigger *plugins.TriggerMode) error {
 	if c.Bundles == nil {
 		return c.validateAndInjectDefaultsLegacy(services)
 	}
@@ -172,7 +199,11 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 			source.Resource = path.Join(defaultBundlePathPrefix, name)
 		}
 
-		if source.Signing != nil {
+		if source.Git != nil {
+			if err := source.validateAndInjectDefaultsGit(); err != nil {
+				return fmt.Errorf("invalid configuration for bundle %q: %w", name, err)
+			}
+		} else if source.Signing != nil {
 			err := source.Signing.ValidateAndInjectDefaults(keys)
 			if err != nil {
 				return fmt.Errorf("invalid configuration for bundle %q: %s", name, err.Error())
@@ -181,11 +212,14 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 			source.Signing = bundle.NewVerificationConfig(keys, "", "", nil)
 		}
 
-		if strings.HasPrefix(source.Resource, "file://") {
+		switch {
+		case source.Git != nil:
+			// bundles are built from the repository, no service is used
+		case strings.HasPrefix(source.Resource, "file://"):
 			if _, err := url.Parse(source.Resource); err != nil {
 				return fmt.Errorf("invalid URL for bundle %q: %v", name, err)
 			}
-		} else {
+		default:
 			svc, err := c.getServiceFromList(source.Service, services)
 			if err != nil {
 				return fmt.Errorf("invalid configuration for bundle %q: %s", name, err.Error())
@@ -193,6 +227,10 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 			source.Service = svc
 		}
 
+		if err := source.validateAndInjectDefaultsMirrors(services, serviceTypes); err != nil {
+			return fmt.Errorf("invalid configuration for bundle %q: %w", name, err)
+		}
+
 		t, err := plugins.ValidateAndInjectDefaultsForTriggerMode(trigger, source.Trigger)
 		if err != nil {
 			return fmt.Errorf("invalid configuration for bundle %q: %w", name, err)
@@ -212,6 +250,63 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 	return nil
 }
 
+func (s *Source) validateAndInjectDefaultsGit() error {
+	if s.Git.Repository == "" {
+		return errors.New("missing git repository")
+	}
+	if s.Git.Ref == "" {
+		s.Git.Ref = "HEAD"
+	}
+	// The bundle is built from the directory at path, which must not be
+	// outside of the repository.
+	if p := s.Git.Path; p != "" && (path.IsAbs(p) || !filepath.IsLocal(filepath.FromSlash(path.Clean(p)))) {
+		return fmt.Errorf("git path %q must be a relative path inside the repository", p)
+	}
+	if s.Signing != nil {
+		return errors.New("signing isn't supported for git sources")
+	}
+	if len(s.Mirrors) > 0 {
+		return errors.New("mirrors aren't supported for git sources")
+	}
+	return nil
+}
+
+func (s *Source) validateAndInjectDefaultsMirrors(services []string, serviceTypes map[string]string) error {
+	if len(s.Mirrors) == 0 {
+		return nil
+	}
+
+	if strings.HasPrefix(s.Resource, "file://") {
+		return errors.New("mirrors aren't supported for file resources")
+	}
+
+	if isOCIService(serviceTypes, s.Service) {
+		return errors.New("mirrors aren't supported for OCI services")
+	}
+
+	for i := range s.Mirrors {
+		m := &s.Mirrors[i]
+		if !slices.Contains(services, m.Service) {
+			return fmt.Errorf("mirror service name %q not found", m.Service)
+		}
+		if m.Resource == "" {
+			m.Resource = s.Resource
+		}
+		if strings.HasPrefix(m.Resource, "file://") {
+			return errors.New("mirrors aren't supported for file resources")
+		}
+		if isOCIService(serviceTypes, m.Service) {
+			return fmt.Errorf("mirror service %q: mirrors aren't supported for OCI services", m.Service)
+		}
+	}
+
+	return nil
+}
+
+func isOCIService(serviceTypes map[string]string, name string) bool {
+	return strings.ToLower(serviceTypes[name]) == "oci"
+}
+
 func (c *Config) validateAndInjectDefaultsLegacy(services []string) error {
 	if c.Name == "" {
 		return fmt.Errorf("invalid bundle name %q", c.Name)
@@ -7,6 +7,7 @@ package bundle
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"testing"
 
@@ -366,6 +367,140 @@ b4:
 	}
 }
 
+func TestParseBundlesConfigMirrors(t *testing.T) {
+	conf := []byte(`
+b1:
+  service: s1
+  resource: /b1/bundle.tar.gz
+  mirrors:
+  - service: s2
+  - service: s3
+    resource: /mirror/b1.tar.gz
+`)
+	services := []string{"s1", "s2", "s3"}
+	parsedConfig, err := ParseBundlesConfig(conf, services)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []Mirror{
+		{Service: "s2", Resource: "/b1/bundle.tar.gz"},
+		{Service: "s3", Resource: "/mirror/b1.tar.gz"},
+	}
+	if actual := parsedConfig.Bundles["b1"].Mirrors; !slices.Equal(expected, actual) {
+		t.Fatalf("Expected mirrors %v, found %v", expected, actual)
+	}
+
+	tests := map[string]struct {
+		conf string
+		err  string
+	}{
+		"unknown service": {
+			conf: `{"b1": {"service": "s1", "mirrors": [{"service": "s4"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirror service name "s4" not found`,
+		},
+		"missing service": {
+			conf: `{"b1": {"service": "s1", "mirrors": [{"resource": "/b1"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirror service name "" not found`,
+		},
+		"file resource": {
+			conf: `{"b1": {"resource": "file:///b1", "mirrors": [{"service": "s2"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirrors aren't supported for file resources`,
+		},
+		"file mirror resource": {
+			conf: `{"b1": {"service": "s1", "mirrors": [{"service": "s2", "resource": "file:///b1"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirrors aren't supported for file resources`,
+		},
+		"oci service": {
+			conf: `{"b1": {"service": "s3", "mirrors": [{"service": "s1"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirrors aren't supported for OCI services`,
+		},
+		"oci mirror service": {
+			conf: `{"b1": {"service": "s1", "mirrors": [{"service": "s3"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirror service "s3": mirrors aren't supported for OCI services`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfigBuilder().WithBytes([]byte(tc.conf)).WithServices(services).
+				WithServiceTypes(map[string]string{"s1": "rest", "s3": "oci"}).Parse()
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestParseBundlesConfigGit(t *testing.T) {
+	conf := []byte(`
+b1:
+  git:
+    repository: https://git.example.com/policies.git
+    path: bundles/b1
+b2:
+  git:
+    repository: /srv/git/policies.git
+    ref: v1.0.0
+`)
+	parsedConfig, err := ParseBundlesConfig(conf, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]GitSource{
+		"b1": {Repository: "https://git.example.com/policies.git", Ref: "HEAD", Path: "bundles/b1"},
+		"b2": {Repository: "/srv/git/policies.git", Ref: "v1.0.0"},
+	}
+	for name, exp := range expected {
+		if actual := *parsedConfig.Bundles[name].Git; actual != exp {
+			t.Fatalf("Expected git source %v for %q, found %v", exp, name, actual)
+		}
+		if parsedConfig.Bundles[name].Service != "" {
+			t.Fatalf("Expected no service for %q", name)
+		}
+	}
+
+	tests := map[string]struct {
+		conf string
+		err  string
+	}{
+		"missing repository": {
+			conf: `{"b1": {"git": {"ref": "main"}}}`,
+			err:  `invalid configuration for bundle "b1": missing git repository`,
+		},
+		"signing": {
+			conf: `{"b1": {"git": {"repository": "/repo"}, "signing": {"keyid": "foo"}}}`,
+			err:  `invalid configuration for bundle "b1": signing isn't supported for git sources`,
+		},
+		"mirrors": {
+			conf: `{"b1": {"git": {"repository": "/repo"}, "mirrors": [{"service": "s1"}]}}`,
+			err:  `invalid configuration for bundle "b1": mirrors aren't supported for git sources`,
+		},
+		"path outside of repository": {
+			conf: `{"b1": {"git": {"repository": "/repo", "path": "../"}}}`,
+			err:  `invalid configuration for bundle "b1": git path "../" must be a relative path inside the repository`,
+		},
+		"path escaping repository": {
+			conf: `{"b1": {"git": {"repository": "/repo", "path": "bundles/../../etc"}}}`,
+			err:  `invalid configuration for bundle "b1": git path "bundles/../../etc" must be a relative path inside the repository`,
+		},
+		"absolute path": {
+			conf: `{"b1": {"git": {"repository": "/repo", "path": "/etc"}}}`,
+			err:  `invalid configuration for bundle "b1": git path "/etc" must be a relative path inside the repository`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBundlesConfig([]byte(tc.conf), []string{"s1"})
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
 func TestParseBundlesConfigSimpleFileURL(t *testing.T) {
 
 	config := []byte(`{"test": {"resource": "file:///b.tar.gz"}}`)
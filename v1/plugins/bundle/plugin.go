@@ -447,12 +447,20 @@ func (p *Plugin) newDownloader(name string, source *Source, bundles map[string]*
 	}
 
 	conf := source.Config
-	client := p.manager.Client(source.Service)
-	path := source.Resource
 	callback := func(ctx context.Context, u download.Update) error {
 		// wrap the callback to include the name of the bundle that was updated
 		return p.oneShot(ctx, name, u)
 	}
+	if source.Git != nil {
+		return download.NewGit(conf, source.Git.Repository, source.Git.Ref).
+			WithPath(source.Git.Path).
+			WithCallback(callback).
+			WithLogger(p.log(name)).
+			WithBundleParserOpts(p.manager.ParserOptions())
+	}
+
+	client := p.manager.Client(source.Service)
+	path := source.Resource
 	if strings.ToLower(client.Config().Type) == "oci" {
 		ociStorePath := ""
 		if cfg := p.manager.GetConfig(); cfg.PersistenceDirectory != nil {
@@ -466,6 +474,7 @@ func (p *Plugin) newDownloader(name string, source *Source, bundles map[string]*
 			WithBundleParserOpts(p.manager.ParserOptions())
 	}
 	return download.New(conf, client, path).
+		WithMirrors(p.mirrors(source)...).
 		WithCallback(callback).
 		WithBundleVerificationConfig(source.Signing).
 		WithSizeLimitBytes(source.SizeLimitBytes).
@@ -475,6 +484,32 @@ func (p *Plugin) newDownloader(name string, source *Source, bundles map[string]*
 		WithBundleParserOpts(p.manager.ParserOptions())
 }
 
+// mirrors returns the download mirrors of source, or nil if it has none.
+func (p *Plugin) mirrors(source *Source) []download.Mirror {
+	if len(source.Mirrors) == 0 {
+		return nil
+	}
+
+	mirrors := make([]download.Mirror, 0, len(source.Mirrors))
+	for _, m := range source.Mirrors {
+		mirrors = append(mirrors, download.Mirror{Client: p.manager.Client(m.Service), Path: m.Resource})
+	}
+	return mirrors
+}
+
+// mirrorStatus returns the mirror the update was downloaded from, if the
+// bundle has mirrors.
+func (p *Plugin) mirrorStatus(name string, u download.Update) *Mirror {
+	p.cfgMtx.RLock()
+	source, ok := p.config.Bundles[name]
+	p.cfgMtx.RUnlock()
+
+	if !ok || len(source.Mirrors) == 0 || u.Service == "" {
+		return nil
+	}
+	return &Mirror{Service: u.Service, Resource: u.Path}
+}
+
 func (p *Plugin) oneShot(ctx context.Context, name string, u download.Update) error {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -521,6 +556,7 @@ func (p *Plugin) process(ctx context.Context, name string, u download.Update) er
 	}
 
 	p.status[name].LastSuccessfulRequest = p.status[name].LastRequest
+	p.status[name].Mirror = p.mirrorStatus(name, u)
 
 	if u.Bundle != nil {
 		p.status[name].Type = u.Bundle.Type()
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"reflect"
@@ -6989,6 +6990,175 @@ func TestPluginManualTrigger(t *testing.T) {
 	}
 }
 
+func TestPluginManualTriggerMirrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	mockBundle := bundle.Bundle{
+		Data:    map[string]any{"p": "x1"},
+		Modules: []bundle.ModuleFile{},
+	}
+
+	s1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s1.Close()
+
+	s2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mirror/test.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		err := bundle.NewWriter(w).Write(mockBundle)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer s2.Close()
+
+	manager := getTestManagerWithOpts(fmt.Appendf(nil, `{
+		"services": {
+			"primary": {
+				"url": %q
+			},
+			"mirror": {
+				"url": %q
+			}
+		}
+	}`, s1.URL, s2.URL))
+	defer manager.Stop(ctx)
+
+	var mode plugins.TriggerMode = "manual"
+
+	plugin := New(&Config{
+		Bundles: map[string]*Source{
+			"test": {
+				Service:        "primary",
+				Resource:       "/bundles/test.tar.gz",
+				SizeLimitBytes: int64(bundle.DefaultSizeLimitBytes),
+				Config:         download.Config{Trigger: &mode},
+				Mirrors:        []Mirror{{Service: "mirror", Resource: "/mirror/test.tar.gz"}},
+			},
+		},
+	}, manager)
+
+	statusCh := make(chan map[string]*Status)
+
+	plugin.RegisterBulkListener("test-case", func(st map[string]*Status) {
+		statusCh <- st
+	})
+
+	err := plugin.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plugin.Stop(ctx)
+
+	go func() {
+		_ = plugin.Loaders()["test"].Trigger(ctx)
+	}()
+
+	status := (<-statusCh)["test"]
+	if status.Code != "" {
+		t.Fatalf("expected no error but got %v", status.Message)
+	}
+
+	expMirror := &Mirror{Service: "mirror", Resource: "/mirror/test.tar.gz"}
+	if !reflect.DeepEqual(status.Mirror, expMirror) {
+		t.Fatalf("expected mirror %v but got %v", expMirror, status.Mirror)
+	}
+
+	result, err := storage.ReadOne(ctx, manager.Store, storage.Path{"p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result, mockBundle.Data["p"]) {
+		t.Fatalf("expected data to be %v but got %v", mockBundle.Data, result)
+	}
+}
+
+func TestPluginManualTriggerGit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	ctx := t.Context()
+
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	git("init", "--quiet", "--initial-branch=main", repo)
+	if err := os.WriteFile(filepath.Join(repo, "policy.rego"), []byte("package authz\n\nallow := true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("-C", repo, "add", "--all")
+	git("-C", repo, "commit", "--quiet", "--message=init")
+	sha := git("-C", repo, "rev-parse", "HEAD")
+
+	manager := getTestManager()
+	defer manager.Stop(ctx)
+
+	var mode plugins.TriggerMode = "manual"
+
+	plugin := New(&Config{
+		Bundles: map[string]*Source{
+			"test": {
+				Git:            &GitSource{Repository: repo, Ref: "main"},
+				SizeLimitBytes: int64(bundle.DefaultSizeLimitBytes),
+				Config:         download.Config{Trigger: &mode},
+			},
+		},
+	}, manager)
+
+	statusCh := make(chan map[string]*Status)
+
+	plugin.RegisterBulkListener("test-case", func(st map[string]*Status) {
+		statusCh <- st
+	})
+
+	err := plugin.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plugin.Stop(ctx)
+
+	go func() {
+		_ = plugin.Loaders()["test"].Trigger(ctx)
+	}()
+
+	status := (<-statusCh)["test"]
+	if status.Code != "" {
+		t.Fatalf("expected no error but got %v", status.Message)
+	}
+	if status.ActiveRevision != sha {
+		t.Fatalf("expected revision %v but got %v", sha, status.ActiveRevision)
+	}
+
+	txn := storage.NewTransactionOrDie(ctx, manager.Store)
+	defer manager.Store.Abort(ctx, txn)
+
+	ids, err := manager.Store.ListPolicies(ctx, txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("expected one policy but got %v", ids)
+	}
+}
+
 func TestPluginManualTriggerMultipleDiskStorage(t *testing.T) {
 	t.Parallel()
 
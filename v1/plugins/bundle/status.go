@@ -36,6 +36,7 @@ type Status struct {
 	Errors                   []error         `json:"errors,omitempty"`
 	Metrics                  metrics.Metrics `json:"metrics,omitempty"`
 	HTTPCode                 json.Number     `json:"http_code,omitempty"`
+	Mirror                   *Mirror         `json:"mirror,omitempty"` // the mirror of the last successful request, if the bundle has mirrors
 }
 
 // SetActivateSuccess updates the status object to reflect a successful
@@ -112,7 +113,8 @@ func (s *Status) Equal(other *Status) bool {
 		s.LastSuccessfulActivation.Equal(other.LastSuccessfulActivation) &&
 		s.LastSuccessfulDownload.Equal(other.LastSuccessfulDownload) &&
 		s.LastSuccessfulRequest.Equal(other.LastSuccessfulRequest) &&
-		s.LastRequest.Equal(other.LastRequest)
+		s.LastRequest.Equal(other.LastRequest) &&
+		reflect.DeepEqual(s.Mirror, other.Mirror)
 
 	if !equal {
 		return false
@@ -650,8 +650,12 @@ func getPluginSet(
 		return nil, err
 	}
 	if bundleConfig == nil {
+		serviceTypes := make(map[string]string, len(serviceNames))
+		for _, name := range serviceNames {
+			serviceTypes[name] = manager.Client(name).Config().Type
+		}
 		bundleConfig, err = bundle.NewConfigBuilder().WithBytes(config.Bundles).WithServices(serviceNames).
-			WithKeyConfigs(manager.PublicKeys()).WithTriggerMode(trigger).Parse()
+			WithServiceTypes(serviceTypes).WithKeyConfigs(manager.PublicKeys()).WithTriggerMode(trigger).Parse()
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,629 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	lstat "github.com/open-policy-agent/opa/v1/plugins/logs/status"
+	"github.com/open-policy-agent/opa/v1/plugins/rest"
+)
+
+const (
+	diskSegmentExt          = ".log"
+	diskRecordHeaderSize    = 8              // record length and CRC-32 checksum, both uint32
+	maxDiskSegmentSizeBytes = int64(4 << 20) // 4MB
+)
+
+// diskBuffer stores events in segment files of a directory, so that they
+// survive restarts of OPA. Each event is appended to the active segment as a
+// length-prefixed, checksummed JSON record. On upload the active segment is
+// sealed, and the sealed segments are chunked and uploaded oldest first. A
+// segment is only removed once all of its events have been uploaded.
+//
+// Records are written without buffering, they survive crashes of OPA as soon
+// as Push returns. Segments are synced to stable storage when sealed.
+type diskBuffer struct {
+	dir         string
+	limit       int64 // max bytes stored, 0 for unlimited
+	uploadLimit int64
+	segmentSize int64
+
+	mtx      sync.Mutex
+	segments []*diskSegment // oldest first, the last one is active if active is set
+	active   *os.File
+	seq      uint64
+	bytes    int64
+	events   int64
+	pending  int64 // bytes pushed since the last upload, used in immediate mode
+
+	uploadMtx sync.Mutex
+	enc       *chunkEncoder // keeps the adaptive uncompressed limit across uploads
+
+	limiter  *rate.Limiter
+	metrics  metrics.Metrics
+	logger   logging.Logger
+	uploader uploader
+
+	mode  plugins.TriggerMode
+	ready chan struct{}
+	stop  chan chan struct{}
+}
+
+type diskSegment struct {
+	seq    uint64
+	size   int64
+	events int64
+}
+
+func newDiskBuffer(dir string, bufferSizeLimitBytes int64, uploadSizeLimitBytes int64, client rest.Client, uploadPath string, mode plugins.TriggerMode) (*diskBuffer, error) {
+	b := &diskBuffer{
+		dir:         dir,
+		limit:       bufferSizeLimitBytes,
+		uploadLimit: uploadSizeLimitBytes,
+		segmentSize: maxDiskSegmentSizeBytes,
+		enc:         newChunkEncoder(uploadSizeLimitBytes),
+		uploader:    serviceUploader{client: client, path: uploadPath},
+		mode:        mode,
+	}
+
+	// keep several segments within the limit, so that dropping the oldest
+	// one when the limit is reached doesn't drop most of the events
+	if b.limit > 0 && b.limit/4 < b.segmentSize {
+		b.segmentSize = max(b.limit/4, 1)
+	}
+
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+
+	if b.mode == plugins.TriggerImmediate {
+		b.ready = make(chan struct{}, 1)
+		b.stop = make(chan chan struct{})
+		go b.read()
+	}
+
+	return b, nil
+}
+
+// load reads the segments left in the directory by previous runs.
+func (b *diskBuffer) load() error {
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return fmt.Errorf("create decision log buffer directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("read decision log buffer directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, diskSegmentExt+".tmp") {
+			// left over by an interrupted rewrite, the segment itself is intact
+			_ = os.Remove(filepath.Join(b.dir, name))
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, diskSegmentExt), 10, 64)
+		if err != nil || !strings.HasSuffix(name, diskSegmentExt) {
+			continue
+		}
+
+		raws, size, err := b.readSegment(seq)
+		if err != nil {
+			return err
+		}
+		b.segments = append(b.segments, &diskSegment{seq: seq, size: size, events: int64(len(raws))})
+		b.bytes += size
+		b.events += int64(len(raws))
+		b.seq = max(b.seq, seq)
+	}
+
+	slices.SortFunc(b.segments, func(a, b *diskSegment) int {
+		switch {
+		case a.seq < b.seq:
+			return -1
+		case a.seq > b.seq:
+			return 1
+		}
+		return 0
+	})
+
+	return nil
+}
+
+func (b *diskBuffer) WithLimiter(maxDecisionsPerSecond *float64) *diskBuffer {
+	if maxDecisionsPerSecond != nil {
+		b.limiter = rate.NewLimiter(rate.Limit(*maxDecisionsPerSecond), int(math.Max(1, *maxDecisionsPerSecond)))
+	}
+	return b
+}
+
+// WithUploader replaces the upload of chunks to a service.
+func (b *diskBuffer) WithUploader(u uploader) *diskBuffer {
+	b.uploader = u
+	return b
+}
+
+func (b *diskBuffer) WithMetrics(m metrics.Metrics) {
+	b.metrics = m
+	b.enc = b.enc.WithMetrics(m)
+}
+
+func (b *diskBuffer) WithLogger(l logging.Logger) *diskBuffer {
+	b.logger = l
+	b.enc = b.enc.WithLogger(l)
+	return b
+}
+
+func (*diskBuffer) Name() string {
+	return diskBufferType
+}
+
+func (b *diskBuffer) incrMetric(name string) {
+	if b.metrics != nil {
+		b.metrics.Counter(name).Incr()
+	}
+}
+
+// backlog returns the events stored that haven't been uploaded yet.
+func (b *diskBuffer) backlog() *lstat.BufferStatus {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return &lstat.BufferStatus{Type: diskBufferType, Events: b.events, Bytes: b.bytes}
+}
+
+// Push appends the event to the active segment. This can be called
+// concurrently.
+func (b *diskBuffer) Push(event *EventV1) {
+	if b.limiter != nil && !b.limiter.Allow() {
+		b.incrMetric(logRateLimitExDropCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log dropped as rate limit exceeded. Reduce reporting interval or increase rate limit.")
+		}
+		return
+	}
+
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		b.incrMetric(logEncodingFailureCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log dropped due to error serializing event to JSON with decision ID %v", event.DecisionID)
+		}
+		return
+	}
+
+	record := make([]byte, diskRecordHeaderSize, diskRecordHeaderSize+len(eventBytes))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(eventBytes)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(eventBytes))
+	record = append(record, eventBytes...)
+	size := int64(len(record))
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.limit > 0 && size > b.limit {
+		b.incrMetric(logBufferSizeLimitExDropCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log with decision ID %v dropped as it exceeds the buffer size limit.", event.DecisionID)
+		}
+		return
+	}
+
+	if err := b.write(record); err != nil {
+		b.incrMetric(logBufferWriteFailureCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log with decision ID %v dropped due to a buffer write failure: %v", event.DecisionID, err)
+		}
+		return
+	}
+
+	b.dropOldest()
+
+	if b.mode == plugins.TriggerImmediate {
+		b.pending += size
+		if b.pending >= b.uploadLimit {
+			select {
+			case b.ready <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// write appends the record to the active segment, starting a new one if
+// needed. It must be called with mtx held.
+func (b *diskBuffer) write(record []byte) error {
+	size := int64(len(record))
+
+	if b.active != nil {
+		if seg := b.segments[len(b.segments)-1]; seg.size > 0 && seg.size+size > b.segmentSize {
+			b.seal()
+		}
+	}
+
+	if b.active == nil {
+		f, err := os.OpenFile(b.segmentPath(b.seq+1), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		b.seq++
+		b.active = f
+		b.segments = append(b.segments, &diskSegment{seq: b.seq})
+	}
+
+	n, err := b.active.Write(record)
+	seg := b.segments[len(b.segments)-1]
+	seg.size += int64(n)
+	b.bytes += int64(n)
+	if err != nil {
+		// the segment may end with a partial record now: records appended
+		// after it would be unreadable, so start a new segment
+		b.seal()
+		return err
+	}
+
+	seg.events++
+	b.events++
+	return nil
+}
+
+// seal closes the active segment, it isn't appended to anymore. It must be
+// called with mtx held.
+func (b *diskBuffer) seal() {
+	if b.active == nil {
+		return
+	}
+	if err := b.active.Sync(); err != nil && b.logger != nil {
+		b.logger.Error("Failed to sync decision log buffer segment: %v", err)
+	}
+	_ = b.active.Close()
+	b.active = nil
+}
+
+// dropOldest removes the oldest segments while the buffer exceeds its limit.
+// It must be called with mtx held.
+func (b *diskBuffer) dropOldest() {
+	if b.limit <= 0 {
+		return
+	}
+
+	var dropped int64
+	for b.bytes > b.limit && len(b.segments) > 0 {
+		if len(b.segments) == 1 {
+			b.seal()
+		}
+		seg := b.segments[0]
+		if err := os.Remove(b.segmentPath(seg.seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			if b.logger != nil {
+				b.logger.Error("Failed to remove decision log buffer segment: %v", err)
+			}
+			return
+		}
+		b.untrack(0)
+		dropped += seg.events
+	}
+
+	if dropped > 0 {
+		if b.metrics != nil {
+			b.metrics.Counter(logBufferSizeLimitExDropCounterName).Add(uint64(dropped))
+		}
+		if b.logger != nil {
+			b.logger.Error("Dropped %v events from buffer. Reduce reporting interval or increase buffer size.", dropped)
+		}
+	}
+}
+
+// untrack forgets the segment at index i. It must be called with mtx held.
+func (b *diskBuffer) untrack(i int) {
+	seg := b.segments[i]
+	b.bytes -= seg.size
+	b.events -= seg.events
+	b.segments = slices.Delete(b.segments, i, i+1)
+}
+
+// index returns the index of the segment, or -1 if it has been dropped. It
+// must be called with mtx held.
+func (b *diskBuffer) index(seq uint64) int {
+	return slices.IndexFunc(b.segments, func(seg *diskSegment) bool { return seg.seq == seq })
+}
+
+func (b *diskBuffer) segmentPath(seq uint64) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%020d%s", seq, diskSegmentExt))
+}
+
+// readSegment returns the records of the segment, and the size of its file. A
+// truncated or corrupted record ends the segment: it can only be the result of
+// a crash while it was written.
+func (b *diskBuffer) readSegment(seq uint64) ([][]byte, int64, error) {
+	bs, err := os.ReadFile(b.segmentPath(seq))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var raws [][]byte
+	for rest := bs; len(rest) > 0; {
+		if len(rest) < diskRecordHeaderSize {
+			b.warnCorrupted(seq)
+			break
+		}
+		n := int(binary.BigEndian.Uint32(rest[0:4]))
+		sum := binary.BigEndian.Uint32(rest[4:8])
+		rest = rest[diskRecordHeaderSize:]
+		if n > len(rest) || crc32.ChecksumIEEE(rest[:n]) != sum {
+			b.warnCorrupted(seq)
+			break
+		}
+		raws = append(raws, rest[:n])
+		rest = rest[n:]
+	}
+
+	return raws, int64(len(bs)), nil
+}
+
+func (b *diskBuffer) warnCorrupted(seq uint64) {
+	if b.logger != nil {
+		b.logger.Warn("Ignoring the corrupted end of decision log buffer segment %v.", b.segmentPath(seq))
+	}
+}
+
+// readEvents returns the events of the segment.
+func (b *diskBuffer) readEvents(seq uint64) ([]EventV1, [][]byte, error) {
+	raws, _, err := b.readSegment(seq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make([]EventV1, 0, len(raws))
+	valid := raws[:0]
+	for _, raw := range raws {
+		var event EventV1
+		if err := json.Unmarshal(raw, &event); err != nil {
+			b.incrMetric(logEncodingFailureCounterName)
+			if b.logger != nil {
+				b.logger.Error("Dropping event due to decoding failure: %v", err)
+			}
+			continue
+		}
+		events = append(events, event)
+		valid = append(valid, raw)
+	}
+	return events, valid, nil
+}
+
+// Upload seals the active segment, and uploads the events of all segments,
+// oldest first. If an upload fails, the segment being uploaded is rewritten
+// with the events not uploaded yet, and they're retried on the next upload.
+func (b *diskBuffer) Upload(ctx context.Context) error {
+	b.uploadMtx.Lock()
+	defer b.uploadMtx.Unlock()
+
+	b.mtx.Lock()
+	b.seal()
+	b.pending = 0
+	segments := make([]uint64, 0, len(b.segments))
+	for _, seg := range b.segments {
+		segments = append(segments, seg.seq)
+	}
+	b.mtx.Unlock()
+
+	if len(segments) == 0 {
+		return &bufferEmpty{}
+	}
+
+	for _, seq := range segments {
+		events, raws, err := b.readEvents(seq)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // dropped as the buffer size limit was exceeded
+		} else if err != nil {
+			return err
+		}
+
+		chunks, err := b.encode(events, raws)
+		if err != nil {
+			return err
+		}
+
+		for i, chunk := range chunks {
+			if err := b.uploader.Upload(ctx, chunk); err != nil {
+				if rerr := b.rewrite(seq, chunks[i:]); rerr != nil && b.logger != nil {
+					b.logger.Error("Failed to rewrite decision log buffer segment, events will be uploaded again: %v", rerr)
+				}
+				return err
+			}
+		}
+
+		b.remove(seq)
+	}
+
+	return nil
+}
+
+// encode returns the events as chunks fit for upload.
+func (b *diskBuffer) encode(events []EventV1, raws [][]byte) ([][]byte, error) {
+	enc := newChunkEncoder(b.uploadLimit).WithMetrics(b.metrics).WithLogger(b.logger).
+		WithUncompressedLimit(b.enc.uncompressedLimit, b.enc.uncompressedLimitScaleDownExponent, b.enc.uncompressedLimitScaleUpExponent)
+
+	var result [][]byte
+	for i := range events {
+		chunks, err := enc.Encode(events[i], raws[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, chunks...)
+	}
+
+	chunks, err := enc.Flush()
+	if err != nil {
+		return nil, err
+	}
+	b.enc = enc
+	return append(result, chunks...), nil
+}
+
+// rewrite replaces the events of the segment with the events of the chunks.
+func (b *diskBuffer) rewrite(seq uint64, chunks [][]byte) error {
+	var records []byte
+	var count int64
+	for _, chunk := range chunks {
+		events, err := newChunkDecoder(chunk).decode()
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			bs, err := json.Marshal(&events[i])
+			if err != nil {
+				return err
+			}
+			records = binary.BigEndian.AppendUint32(records, uint32(len(bs)))
+			records = binary.BigEndian.AppendUint32(records, crc32.ChecksumIEEE(bs))
+			records = append(records, bs...)
+			count++
+		}
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	i := b.index(seq)
+	if i < 0 {
+		return nil // dropped in the meantime
+	}
+
+	path := b.segmentPath(seq)
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, records); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	seg := b.segments[i]
+	b.bytes += int64(len(records)) - seg.size
+	b.events += count - seg.events
+	seg.size, seg.events = int64(len(records)), count
+	return nil
+}
+
+func writeFileSync(path string, bs []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// remove removes the uploaded segment.
+func (b *diskBuffer) remove(seq uint64) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	i := b.index(seq)
+	if i < 0 {
+		return
+	}
+	if err := os.Remove(b.segmentPath(seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		// keep tracking it, its events will be uploaded again
+		if b.logger != nil {
+			b.logger.Error("Failed to remove uploaded decision log buffer segment: %v", err)
+		}
+		return
+	}
+	b.untrack(i)
+}
+
+// Flush removes and returns all events, for them to be moved to another
+// buffer.
+func (b *diskBuffer) Flush() []*EventV1 {
+	b.uploadMtx.Lock()
+	defer b.uploadMtx.Unlock()
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.seal()
+
+	var result []*EventV1
+	for len(b.segments) > 0 {
+		seq := b.segments[0].seq
+		events, _, err := b.readEvents(seq)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			if b.logger != nil {
+				b.logger.Error("Dropping decision log buffer segment due to read failure: %v", err)
+			}
+		}
+		for i := range events {
+			result = append(result, &events[i])
+		}
+		_ = os.Remove(b.segmentPath(seq))
+		b.untrack(0)
+	}
+
+	return result
+}
+
+// Stop stops uploading in immediate mode, and seals the active segment.
+func (b *diskBuffer) Stop(ctx context.Context) {
+	if b.mode == plugins.TriggerImmediate {
+		done := make(chan struct{})
+		select {
+		case b.stop <- done:
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+		case <-ctx.Done():
+		}
+	}
+
+	b.mtx.Lock()
+	b.seal()
+	b.mtx.Unlock()
+}
+
+// read uploads events in immediate mode, as soon as enough have been pushed
+// to fill a chunk.
+func (b *diskBuffer) read() {
+	ctx := context.Background()
+	for {
+		select {
+		case <-b.ready:
+			if err := b.Upload(ctx); err != nil && !errors.Is(err, &bufferEmpty{}) && b.logger != nil {
+				b.logger.Error("Failed to upload decision logs, events have been buffered and will be retried. Error: %v", err)
+			}
+		case done := <-b.stop:
+			done <- struct{}{}
+			return
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/plugins"
+	lstat "github.com/open-policy-agent/opa/v1/plugins/logs/status"
+	"github.com/open-policy-agent/opa/v1/plugins/rest"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+type testUploader struct {
+	mtx    sync.Mutex
+	events []EventV1
+	fail   func(n int) bool // fails the nth upload if it returns true
+	n      int
+}
+
+func (u *testUploader) Upload(_ context.Context, chunk []byte) error {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	u.n++
+	if u.fail != nil && u.fail(u.n) {
+		return errors.New("upload failed")
+	}
+	events, err := newChunkDecoder(chunk).decode()
+	if err != nil {
+		return err
+	}
+	u.events = append(u.events, events...)
+	return nil
+}
+
+func (u *testUploader) decisionIDs() []string {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	ids := make([]string, 0, len(u.events))
+	for _, e := range u.events {
+		ids = append(ids, e.DecisionID)
+	}
+	return ids
+}
+
+func newTestDiskBuffer(t *testing.T, dir string, limit int64, u uploader) *diskBuffer {
+	t.Helper()
+
+	b, err := newDiskBuffer(dir, limit, 400, rest.Client{}, "", plugins.TriggerPeriodic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.WithUploader(u)
+}
+
+func pushTestEvents(b *diskBuffer, from, to int) []string {
+	var ids []string
+	for i := from; i < to; i++ {
+		id := fmt.Sprint(i)
+		b.Push(&EventV1{
+			DecisionID: id,
+			Path:       "data.authz.allow",
+			Labels:     map[string]string{"id": "test-instance-id"},
+			Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func assertBacklog(t *testing.T, b *diskBuffer, events int64) {
+	t.Helper()
+
+	backlog := b.backlog()
+	if backlog.Events != events {
+		t.Fatalf("expected %d events in backlog, got %d", events, backlog.Events)
+	}
+	if events == 0 && backlog.Bytes != 0 {
+		t.Fatalf("expected no bytes in backlog, got %d", backlog.Bytes)
+	}
+}
+
+func TestDiskBufferUpload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	u := &testUploader{}
+	b := newTestDiskBuffer(t, dir, 0, u)
+
+	if err := b.Upload(t.Context()); !errors.Is(err, &bufferEmpty{}) {
+		t.Fatalf("expected buffer empty error, got %v", err)
+	}
+
+	exp := pushTestEvents(b, 0, 20)
+	assertBacklog(t, b, 20)
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act := u.decisionIDs(); !slices.Equal(exp, act) {
+		t.Fatalf("expected events %v, got %v", exp, act)
+	}
+	if u.n < 2 {
+		t.Fatalf("expected several chunks to be uploaded, got %d", u.n)
+	}
+	assertBacklog(t, b, 0)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected uploaded segments to be removed, got %v", entries)
+	}
+}
+
+func TestDiskBufferRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	b := newTestDiskBuffer(t, dir, 0, &testUploader{})
+	exp := pushTestEvents(b, 0, 5)
+	b.Stop(t.Context())
+
+	// simulate a crash while writing the next record
+	segments, err := filepath.Glob(filepath.Join(dir, "*"+diskSegmentExt))
+	if err != nil || len(segments) != 1 {
+		t.Fatalf("expected one segment, got %v (err: %v)", segments, err)
+	}
+	f, err := os.OpenFile(segments[0], os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{0, 0, 1, 0, 1, 2, 3, 4, '{'}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	u := &testUploader{}
+	b = newTestDiskBuffer(t, dir, 0, u)
+	assertBacklog(t, b, 5)
+
+	exp = append(exp, pushTestEvents(b, 5, 8)...)
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act := u.decisionIDs(); !slices.Equal(exp, act) {
+		t.Fatalf("expected events %v, got %v", exp, act)
+	}
+	assertBacklog(t, b, 0)
+}
+
+func TestDiskBufferRequeue(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	u := &testUploader{fail: func(n int) bool { return n == 2 }}
+	b := newTestDiskBuffer(t, dir, 0, u)
+
+	exp := pushTestEvents(b, 0, 20)
+	if err := b.Upload(t.Context()); err == nil {
+		t.Fatal("expected error")
+	}
+	uploaded := len(u.decisionIDs())
+	if uploaded == 0 || uploaded == len(exp) {
+		t.Fatalf("expected some events to be uploaded, got %d", uploaded)
+	}
+	assertBacklog(t, b, int64(len(exp)-uploaded))
+
+	// the events not uploaded survive a restart
+	b.Stop(t.Context())
+	b = newTestDiskBuffer(t, dir, 0, u)
+	assertBacklog(t, b, int64(len(exp)-uploaded))
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act := u.decisionIDs(); !slices.Equal(exp, act) {
+		t.Fatalf("expected events %v, got %v", exp, act)
+	}
+	assertBacklog(t, b, 0)
+}
+
+func TestDiskBufferSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	u := &testUploader{}
+	b := newTestDiskBuffer(t, t.TempDir(), 2000, u)
+
+	exp := pushTestEvents(b, 0, 100)
+	backlog := b.backlog()
+	if backlog.Bytes > 2000 {
+		t.Fatalf("expected at most 2000 bytes in backlog, got %d", backlog.Bytes)
+	}
+	if backlog.Events == 0 || backlog.Events >= 100 {
+		t.Fatalf("expected the oldest events to be dropped, got %d events", backlog.Events)
+	}
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	// the newest events are kept
+	if act := u.decisionIDs(); !slices.Equal(exp[100-len(act):], act) {
+		t.Fatalf("expected events %v, got %v", exp[100-len(act):], act)
+	}
+}
+
+func TestDiskBufferFlush(t *testing.T) {
+	t.Parallel()
+
+	b := newTestDiskBuffer(t, t.TempDir(), 0, &testUploader{})
+	exp := pushTestEvents(b, 0, 10)
+
+	events := b.Flush()
+	act := make([]string, 0, len(events))
+	for _, e := range events {
+		act = append(act, e.DecisionID)
+	}
+	if !slices.Equal(exp, act) {
+		t.Fatalf("expected events %v, got %v", exp, act)
+	}
+	assertBacklog(t, b, 0)
+}
+
+func TestPluginDiskBufferStatus(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manager, err := plugins.New(nil, "test-instance-id", inmem.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ParseConfig(fmt.Appendf(nil, `{
+		"console": true,
+		"reporting": {"buffer_type": "disk", "buffer_path": %q}
+	}`, dir), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Reporting.BufferSizeLimitBytes != defaultDiskBufferSizeLimitBytes {
+		t.Fatalf("expected default disk buffer size limit, got %d", *config.Reporting.BufferSizeLimitBytes)
+	}
+
+	p := New(config, manager)
+	u := &testUploader{fail: func(int) bool { return true }}
+	p.b.(*diskBuffer).WithUploader(u)
+
+	pushTestEvents(p.b.(*diskBuffer), 0, 3)
+	if err := p.doOneShot(t.Context()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	p.statusMtx.Lock()
+	act := p.status.Buffer
+	p.statusMtx.Unlock()
+	if act == nil || act.Type != diskBufferType || act.Events != 3 || act.Bytes == 0 {
+		t.Fatalf("expected backlog of 3 events in status, got %+v", act)
+	}
+
+	u.mtx.Lock()
+	u.fail = nil
+	u.mtx.Unlock()
+	if err := p.doOneShot(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	p.statusMtx.Lock()
+	act = p.status.Buffer
+	p.statusMtx.Unlock()
+	if exp := (&lstat.BufferStatus{Type: diskBufferType}); *act != *exp {
+		t.Fatalf("expected empty backlog in status, got %+v", act)
+	}
+}
+
+func TestParseConfigDiskBuffer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note   string
+		config string
+		err    string
+	}{
+		{
+			note:   "buffer path with size buffer",
+			config: `{"reporting": {"buffer_path": "/tmp/logs"}}`,
+			err:    "'buffer_path' isn't supported for the size buffer type",
+		},
+		{
+			note:   "event limit with disk buffer",
+			config: `{"reporting": {"buffer_type": "disk", "buffer_size_limit_events": 10}}`,
+			err:    "'buffer_size_limit_events' isn't supported for the disk buffer type",
+		},
+		{
+			note:   "invalid buffer type",
+			config: `{"reporting": {"buffer_type": "tape"}}`,
+			err:    `invalid buffer type "tape", expected "event", "size" or "disk"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tc.config), []string{"s0"}, nil)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -31,8 +31,7 @@ type eventBuffer struct {
 	limiter    *rate.Limiter
 	metrics    metrics.Metrics
 	logger     logging.Logger
-	client     rest.Client
-	uploadPath string
+	uploader   uploader
 	// Enables the read loop in immediate mode to constantly read from the event buffer
 	mode         plugins.TriggerMode
 	stop         chan chan struct{}
@@ -41,11 +40,10 @@ type eventBuffer struct {
 
 func newEventBuffer(bufferSizeLimitEvents int64, uploadSizeLimitBytes int64, client rest.Client, uploadPath string, mode plugins.TriggerMode) *eventBuffer {
 	b := &eventBuffer{
-		buffer:     make(chan *bufferItem, bufferSizeLimitEvents),
-		enc:        newChunkEncoder(uploadSizeLimitBytes),
-		mode:       mode,
-		client:     client,
-		uploadPath: uploadPath,
+		buffer:   make(chan *bufferItem, bufferSizeLimitEvents),
+		enc:      newChunkEncoder(uploadSizeLimitBytes),
+		mode:     mode,
+		uploader: serviceUploader{client: client, path: uploadPath},
 	}
 
 	if b.mode == plugins.TriggerImmediate {
@@ -63,6 +61,12 @@ func (b *eventBuffer) WithLimiter(maxDecisionsPerSecond *float64) *eventBuffer {
 	return b
 }
 
+// WithUploader replaces the upload of chunks to a service.
+func (b *eventBuffer) WithUploader(u uploader) *eventBuffer {
+	b.uploader = u
+	return b
+}
+
 func (b *eventBuffer) WithMetrics(m metrics.Metrics) {
 	b.metrics = m
 	b.enc = b.enc.WithMetrics(m)
@@ -207,7 +211,7 @@ func (b *eventBuffer) immediateRead(ctx context.Context, item *bufferItem) {
 		return
 	}
 
-	if err := b.uploadChunks(ctx, result, b.client, b.uploadPath); err != nil {
+	if err := b.uploadChunks(ctx, result); err != nil {
 		if b.logger != nil {
 			b.logger.Error("Failed to upload decision logs, events have been buffered an will be retried. Error: %v", err)
 		}
@@ -256,7 +260,7 @@ func (b *eventBuffer) Upload(ctx context.Context) error {
 
 		result := b.processBufferItem(item)
 		if result != nil {
-			if err := b.uploadChunks(ctx, result, b.client, b.uploadPath); err != nil {
+			if err := b.uploadChunks(ctx, result); err != nil {
 				if b.logger != nil {
 					b.logger.Error("Failed to upload decision logs, events have been buffered an will be retried. Error: %v", err)
 				}
@@ -278,19 +282,19 @@ func (b *eventBuffer) Upload(ctx context.Context) error {
 		return nil
 	}
 
-	if err := b.uploadChunks(ctx, result, b.client, b.uploadPath); err != nil {
+	if err := b.uploadChunks(ctx, result); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// uploadChunks attempts to upload multiple chunks to the configured client.
+// uploadChunks attempts to upload multiple chunks with the configured uploader.
 // In case of failure all the events are added back to the buffer.
-func (b *eventBuffer) uploadChunks(ctx context.Context, result [][]byte, client rest.Client, uploadPath string) error {
+func (b *eventBuffer) uploadChunks(ctx context.Context, result [][]byte) error {
 	var finalErr error
 	for _, chunk := range result {
-		err := uploadChunk(ctx, client, uploadPath, chunk)
+		err := b.uploader.Upload(ctx, chunk)
 
 		// if an upload failed, requeue the chunk
 		if err != nil {
@@ -0,0 +1,262 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/kafka"
+	"github.com/open-policy-agent/opa/internal/tlsutil"
+	"github.com/open-policy-agent/opa/v1/logging"
+)
+
+const (
+	defaultKafkaPartitionKey = "decision_id"
+	kafkaAcksAll             = "all"
+	kafkaAcksLeader          = "leader"
+	kafkaAcksNone            = "none"
+	kafkaCompressionNone     = "none"
+	kafkaCompressionGzip     = "gzip"
+)
+
+// KafkaConfig represents the configuration for publishing decision logs to a
+// topic of a Kafka-compatible broker.
+type KafkaConfig struct {
+	Brokers        []string        `json:"brokers"`
+	Topic          string          `json:"topic"`
+	PartitionKey   *string         `json:"partition_key,omitempty"`
+	ClientID       string          `json:"client_id,omitempty"`
+	RequiredAcks   string          `json:"required_acks,omitempty"`
+	Compression    string          `json:"compression,omitempty"`
+	TimeoutSeconds *int64          `json:"timeout_seconds,omitempty"`
+	TLS            *KafkaTLSConfig `json:"tls,omitempty"`
+
+	tls *tls.Config // loaded when the configuration is validated
+}
+
+// KafkaTLSConfig represents the TLS configuration used to connect to the
+// brokers.
+type KafkaTLSConfig struct {
+	CACert             string `json:"ca_cert,omitempty"`
+	Cert               string `json:"cert,omitempty"`
+	PrivateKey         string `json:"private_key,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+func (c *KafkaConfig) validateAndInjectDefaults() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("invalid decision_log config, kafka 'brokers' must be set")
+	}
+	if c.Topic == "" {
+		return errors.New("invalid decision_log config, kafka 'topic' must be set")
+	}
+
+	if c.PartitionKey == nil {
+		key := defaultKafkaPartitionKey
+		c.PartitionKey = &key
+	}
+
+	switch c.RequiredAcks {
+	case "":
+		c.RequiredAcks = kafkaAcksAll
+	case kafkaAcksAll, kafkaAcksLeader, kafkaAcksNone:
+	default:
+		return fmt.Errorf("invalid decision_log config, kafka 'required_acks' must be one of %q, %q or %q, got %q",
+			kafkaAcksAll, kafkaAcksLeader, kafkaAcksNone, c.RequiredAcks)
+	}
+
+	switch c.Compression {
+	case "":
+		c.Compression = kafkaCompressionNone
+	case kafkaCompressionNone, kafkaCompressionGzip:
+	default:
+		return fmt.Errorf("invalid decision_log config, kafka 'compression' must be one of %q or %q, got %q",
+			kafkaCompressionNone, kafkaCompressionGzip, c.Compression)
+	}
+
+	if c.TimeoutSeconds != nil && *c.TimeoutSeconds <= 0 {
+		return errors.New("invalid decision_log config, kafka 'timeout_seconds' must be > 0")
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("invalid decision_log config, kafka 'tls': %w", err)
+	}
+	c.tls = tlsConfig
+
+	return nil
+}
+
+// producerConfig returns the configuration of the producer. It assumes the
+// configuration has been validated.
+func (c *KafkaConfig) producerConfig() kafka.Config {
+	config := kafka.Config{
+		Brokers:  c.Brokers,
+		ClientID: c.ClientID,
+		TLS:      c.tls,
+	}
+
+	switch c.RequiredAcks {
+	case kafkaAcksLeader:
+		config.Acks = kafka.AcksLeader
+	case kafkaAcksNone:
+		config.Acks = kafka.AcksNone
+	}
+
+	if c.Compression == kafkaCompressionGzip {
+		config.Compression = kafka.CompressionGzip
+	}
+
+	if c.TimeoutSeconds != nil {
+		config.Timeout = time.Duration(*c.TimeoutSeconds) * time.Second
+	}
+
+	return config
+}
+
+func (c *KafkaConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLS == nil {
+		return nil, nil
+	}
+
+	cert, err := tlsutil.LoadCertificate(c.TLS.Cert, c.TLS.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := tlsutil.LoadCertPool(c.TLS.CACert)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+
+	return config, nil
+}
+
+// kafkaUploader publishes the events of each chunk to a topic, one record per
+// event. A chunk is only considered uploaded if all of its events have been
+// published: if some fail, the buffers retry the whole chunk, and events may
+// be published more than once.
+type kafkaUploader struct {
+	producer *kafka.Producer
+	topic    string
+	key      []string
+	logger   logging.Logger
+}
+
+// newKafkaUploader returns an uploader publishing to the topic of config,
+// which must have been validated.
+func newKafkaUploader(config *KafkaConfig, logger logging.Logger) *kafkaUploader {
+	var key []string
+	if k := strings.Trim(*config.PartitionKey, "/"); k != "" {
+		key = strings.Split(k, "/")
+	}
+
+	return &kafkaUploader{
+		producer: kafka.NewProducer(config.producerConfig()),
+		topic:    config.Topic,
+		key:      key,
+		logger:   logger,
+	}
+}
+
+func (u *kafkaUploader) Upload(ctx context.Context, chunk []byte) error {
+	events, err := newChunkDecoder(chunk).decode()
+	if err != nil {
+		// the chunk can't be retried successfully, drop it
+		u.logger.Error("Failed to decode decision log chunk, dropping it: %v", err)
+		return nil
+	}
+
+	records := make([]kafka.Record, 0, len(events))
+	for i := range events {
+		value, err := json.Marshal(events[i])
+		if err != nil {
+			u.logger.Error("Failed to encode decision log event, dropping it: %v", err)
+			continue
+		}
+
+		var ts time.Time
+		if events[i].Timestamp.IsZero() {
+			ts = time.Now()
+		} else {
+			ts = events[i].Timestamp
+		}
+
+		records = append(records, kafka.Record{
+			Key:       u.partitionKey(&events[i], value),
+			Value:     value,
+			Timestamp: ts,
+		})
+	}
+
+	if err := u.producer.Produce(ctx, u.topic, records); err != nil {
+		return fmt.Errorf("log upload failed: %w", err)
+	}
+
+	return nil
+}
+
+// partitionKey returns the value at the key path of the event, for records of
+// the same key to be published to the same partition. Strings are used as-is,
+// other values are JSON encoded. If the event has no value at the path, the
+// record has no key.
+func (u *kafkaUploader) partitionKey(event *EventV1, value []byte) []byte {
+	switch {
+	case len(u.key) == 0:
+		return nil
+	case len(u.key) == 1 && u.key[0] == defaultKafkaPartitionKey:
+		if event.DecisionID == "" {
+			return nil
+		}
+		return []byte(event.DecisionID)
+	}
+
+	var x any
+	if err := json.Unmarshal(value, &x); err != nil {
+		return nil
+	}
+	for _, k := range u.key {
+		obj, ok := x.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if x, ok = obj[k]; !ok {
+			return nil
+		}
+	}
+
+	switch x := x.(type) {
+	case nil:
+		return nil
+	case string:
+		return []byte(x)
+	default:
+		bs, err := json.Marshal(x)
+		if err != nil {
+			return nil
+		}
+		return bs
+	}
+}
+
+// Close closes the connections to the brokers.
+func (u *kafkaUploader) Close() {
+	_ = u.producer.Close()
+}
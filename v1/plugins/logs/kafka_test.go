@@ -0,0 +1,320 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/kafka"
+	kafkatest "github.com/open-policy-agent/opa/internal/kafka/test"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+func newKafkaTestPlugin(t *testing.T, broker *kafkatest.Broker, bufferType string, partitionKey string) *Plugin {
+	t.Helper()
+
+	config := fmt.Sprintf(`{
+		"kafka": {"brokers": [%q], "topic": "decisions", "partition_key": %q},
+		"reporting": {"buffer_type": %q}
+	}`, broker.Addr(), partitionKey, bufferType)
+
+	manager, err := plugins.New(nil, "test-instance-id", inmem.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseConfig([]byte(config), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(c, manager)
+	t.Cleanup(func() { p.kafka.Close() })
+	return p
+}
+
+func TestPluginKafka(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note         string
+		bufferType   string
+		partitionKey string
+		expKeys      []string
+	}{
+		{
+			note:         "decision ID keys, size buffer",
+			bufferType:   sizeBufferType,
+			partitionKey: "decision_id",
+			expKeys:      []string{"0", "1", "2"},
+		},
+		{
+			note:         "decision ID keys, event buffer",
+			bufferType:   eventBufferType,
+			partitionKey: "decision_id",
+			expKeys:      []string{"0", "1", "2"},
+		},
+		{
+			note:         "path keys",
+			bufferType:   sizeBufferType,
+			partitionKey: "input/tenant",
+			expKeys:      []string{"acme", "globex", ""},
+		},
+		{
+			note:         "non-string keys",
+			bufferType:   sizeBufferType,
+			partitionKey: "/input/",
+			expKeys:      []string{`{"tenant":"acme"}`, `{"tenant":"globex"}`, ""},
+		},
+		{
+			note:         "no keys",
+			bufferType:   sizeBufferType,
+			partitionKey: "",
+			expKeys:      []string{"", "", ""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+
+			broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 3))
+			t.Cleanup(broker.Stop)
+
+			ctx := context.Background()
+			p := newKafkaTestPlugin(t, broker, tc.bufferType, tc.partitionKey)
+
+			inputs := []any{
+				map[string]any{"tenant": "acme"},
+				map[string]any{"tenant": "globex"},
+				nil,
+			}
+			for i, input := range inputs {
+				var result any = true
+				if err := p.Log(ctx, &server.Info{
+					DecisionID: fmt.Sprint(i),
+					Path:       "data.authz.allow",
+					Input:      &input,
+					Results:    &result,
+					Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := p.b.Upload(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			records := broker.Records("decisions")
+			if len(records) != len(inputs) {
+				t.Fatalf("expected %d records, got %d", len(inputs), len(records))
+			}
+
+			keys := map[string]string{}
+			for _, r := range records {
+				var event EventV1
+				if err := json.Unmarshal(r.Value, &event); err != nil {
+					t.Fatal(err)
+				}
+				if !r.Timestamp.Equal(event.Timestamp) {
+					t.Errorf("expected record timestamp %v, got %v", event.Timestamp, r.Timestamp)
+				}
+				keys[event.DecisionID] = string(r.Key)
+			}
+			for i, exp := range tc.expKeys {
+				if act := keys[fmt.Sprint(i)]; act != exp {
+					t.Errorf("decision %d: expected key %q, got %q", i, exp, act)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginKafkaRequeue(t *testing.T) {
+	t.Parallel()
+
+	for _, bufferType := range []string{sizeBufferType, eventBufferType} {
+		t.Run(bufferType, func(t *testing.T) {
+			t.Parallel()
+
+			broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 1))
+			t.Cleanup(broker.Stop)
+
+			ctx := context.Background()
+			p := newKafkaTestPlugin(t, broker, bufferType, "decision_id")
+
+			var input any = map[string]any{"method": "GET"}
+			var result any = false
+			if err := p.Log(ctx, &server.Info{
+				DecisionID: "abc",
+				Path:       "data.foo.bar",
+				Input:      &input,
+				Results:    &result,
+				Timestamp:  time.Now().UTC(),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			broker.FailProduce(kafka.ErrNotEnoughReplicas)
+			err := p.b.Upload(ctx)
+			var kerr kafka.Error
+			if !errors.As(err, &kerr) || kerr.Code != kafka.ErrNotEnoughReplicas {
+				t.Fatalf("expected not enough replicas error, got %v", err)
+			}
+			if n := len(broker.Records("decisions")); n != 0 {
+				t.Fatalf("expected no records, got %d", n)
+			}
+
+			if err := p.b.Upload(ctx); err != nil {
+				t.Fatal(err)
+			}
+			records := broker.Records("decisions")
+			if len(records) != 1 || string(records[0].Key) != "abc" {
+				t.Fatalf("expected the requeued event to be published, got %v", records)
+			}
+
+			err = p.b.Upload(ctx)
+			if err != nil && !errors.Is(err, &bufferEmpty{}) {
+				t.Fatalf("unexpected error or upload, err: %v", err)
+			}
+		})
+	}
+}
+
+func TestPluginKafkaUploadSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	broker := kafkatest.MustNewBroker(kafkatest.Topic("decisions", 1))
+	t.Cleanup(broker.Stop)
+
+	manager, err := plugins.New(nil, "test-instance-id", inmem.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseConfig(fmt.Appendf(nil, `{
+		"kafka": {"brokers": [%q], "topic": "decisions"},
+		"reporting": {"upload_size_limit_bytes": 400}
+	}`, broker.Addr()), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(c, manager)
+	t.Cleanup(func() { p.kafka.Close() })
+
+	ctx := context.Background()
+	for i := range 20 {
+		var input any = map[string]any{"value": strings.Repeat("x", i)}
+		var result any = true
+		if err := p.Log(ctx, &server.Info{
+			DecisionID: fmt.Sprint(i),
+			Input:      &input,
+			Results:    &result,
+			Timestamp:  time.Now().UTC(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := p.b.Upload(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(broker.Records("decisions")); n != 20 {
+		t.Fatalf("expected 20 records, got %d", n)
+	}
+	if n := broker.Requests(kafka.APIKeyProduce); n < 2 {
+		t.Fatalf("expected events to be published in several batches, got %d produce requests", n)
+	}
+}
+
+func TestParseConfigKafka(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note     string
+		config   string
+		services []string
+		err      string
+	}{
+		{
+			note:   "defaults",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions"}}`,
+		},
+		{
+			note:     "not defaulting to the first service",
+			config:   `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions"}}`,
+			services: []string{"s0"},
+		},
+		{
+			note:   "missing brokers",
+			config: `{"kafka": {"topic": "decisions"}}`,
+			err:    "kafka 'brokers' must be set",
+		},
+		{
+			note:   "missing topic",
+			config: `{"kafka": {"brokers": ["localhost:9092"]}}`,
+			err:    "kafka 'topic' must be set",
+		},
+		{
+			note:     "service and kafka",
+			config:   `{"service": "s0", "kafka": {"brokers": ["localhost:9092"], "topic": "decisions"}}`,
+			services: []string{"s0"},
+			err:      "specify either 'service' or 'kafka'",
+		},
+		{
+			note:   "invalid acks",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions", "required_acks": "some"}}`,
+			err:    "kafka 'required_acks' must be one of",
+		},
+		{
+			note:   "invalid compression",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions", "compression": "zstd"}}`,
+			err:    "kafka 'compression' must be one of",
+		},
+		{
+			note:   "invalid timeout",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions", "timeout_seconds": 0}}`,
+			err:    "kafka 'timeout_seconds' must be > 0",
+		},
+		{
+			note:   "missing private key",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions", "tls": {"cert": "cert.pem"}}}`,
+			err:    "must be specified together",
+		},
+		{
+			note:   "missing CA certificate",
+			config: `{"kafka": {"brokers": ["localhost:9092"], "topic": "decisions", "tls": {"ca_cert": "does-not-exist.pem"}}}`,
+			err:    "kafka 'tls'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tc.config), tc.services, nil)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Service != "" {
+				t.Errorf("expected no service, got %q", config.Service)
+			}
+			if *config.Kafka.PartitionKey != "decision_id" || config.Kafka.RequiredAcks != "all" || config.Kafka.Compression != "none" {
+				t.Errorf("unexpected defaults: %+v", config.Kafka)
+			}
+		})
+	}
+}
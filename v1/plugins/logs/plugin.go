@@ -13,6 +13,7 @@ import (
 	"log/slog"
 	"math/rand"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
@@ -269,15 +270,19 @@ const (
 	minUploadSizeLimitBytes             = int64(90)         // A single event with a decision ID (69 bytes) + empty gzip file (21 bytes)
 	maxUploadSizeLimitBytes             = int64(4294967296) // about 4GB
 	defaultBufferSizeLimitBytes         = int64(0)          // unlimited
+	defaultDiskBufferSizeLimitBytes     = int64(104857600)  // 100MB
 	defaultMaskDecisionPath             = "/system/log/mask"
 	defaultDropDecisionPath             = "/system/log/drop"
 	logRateLimitExDropCounterName       = "decision_logs_dropped_rate_limit_exceeded"
 	logBufferEventDropCounterName       = "decision_logs_dropped_buffer_size_limit_exceeded"
 	logBufferSizeLimitExDropCounterName = "decision_logs_dropped_buffer_size_limit_bytes_exceeded"
 	logEncodingFailureCounterName       = "decision_logs_encoding_failure"
+	logBufferWriteFailureCounterName    = "decision_logs_dropped_buffer_write_failure"
 	defaultResourcePath                 = "/logs"
 	sizeBufferType                      = "size"
 	eventBufferType                     = "event"
+	diskBufferType                      = "disk"
+	diskBufferDirectory                 = "decision_logs"
 )
 
 // ReportingConfig represents configuration for the plugin's reporting behaviour.
@@ -285,6 +290,7 @@ type ReportingConfig struct {
 	BufferType            string               `json:"buffer_type,omitempty"`              // toggles how the buffer stores events, defaults to using bytes
 	BufferSizeLimitBytes  *int64               `json:"buffer_size_limit_bytes,omitempty"`  // max size of in-memory size buffer
 	BufferSizeLimitEvents *int64               `json:"buffer_size_limit_events,omitempty"` // max size of in-memory event channel buffer
+	BufferPath            string               `json:"buffer_path,omitempty"`              // directory of the disk buffer, defaults to a directory in the persistence directory
 	UploadSizeLimitBytes  *int64               `json:"upload_size_limit_bytes,omitempty"`  // max size of upload payload
 	MinDelaySeconds       *int64               `json:"min_delay_seconds,omitempty"`        // min amount of time to wait between successful poll attempts
 	MaxDelaySeconds       *int64               `json:"max_delay_seconds,omitempty"`        // max amount of time to wait between poll attempts
@@ -312,6 +318,7 @@ type Config struct {
 	ConsoleLogs     bool                 `json:"console"`
 	Resource        *string              `json:"resource"`
 	NDBuiltinCache  bool                 `json:"nd_builtin_cache,omitempty"`
+	Kafka           *KafkaConfig         `json:"kafka,omitempty"`
 	maskDecisionRef ast.Ref
 	dropDecisionRef ast.Ref
 }
@@ -326,6 +333,13 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		if !found {
 			return fmt.Errorf("invalid plugin name %q in decision_logs", *c.Plugin)
 		}
+	} else if c.Kafka != nil {
+		if c.Service != "" {
+			return errors.New("invalid decision_log config, specify either 'service' or 'kafka'")
+		}
+		if err := c.Kafka.validateAndInjectDefaults(); err != nil {
+			return err
+		}
 	} else if c.Service == "" && len(services) != 0 && !c.ConsoleLogs {
 		// For backwards compatibility allow defaulting to the first
 		// service listed, but only if console logging is disabled. If enabled
@@ -394,15 +408,18 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 
 	if c.Reporting.BufferType == "" {
 		c.Reporting.BufferType = sizeBufferType
-	} else if c.Reporting.BufferType != eventBufferType && c.Reporting.BufferType != sizeBufferType {
-		return fmt.Errorf("invalid buffer type %q, expected %q or %q", c.Reporting.BufferType, eventBufferType, sizeBufferType)
+	} else if c.Reporting.BufferType != eventBufferType && c.Reporting.BufferType != sizeBufferType && c.Reporting.BufferType != diskBufferType {
+		return fmt.Errorf("invalid buffer type %q, expected %q, %q or %q", c.Reporting.BufferType, eventBufferType, sizeBufferType, diskBufferType)
 	}
 
 	if c.Reporting.BufferType == eventBufferType && c.Reporting.BufferSizeLimitBytes != nil {
 		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_bytes' isn't supported for the %v buffer type", eventBufferType)
 	}
-	if c.Reporting.BufferType == sizeBufferType && c.Reporting.BufferSizeLimitEvents != nil {
-		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_events' isn't supported for the %v buffer type", sizeBufferType)
+	if c.Reporting.BufferType != eventBufferType && c.Reporting.BufferSizeLimitEvents != nil {
+		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_events' isn't supported for the %v buffer type", c.Reporting.BufferType)
+	}
+	if c.Reporting.BufferType != diskBufferType && c.Reporting.BufferPath != "" {
+		return fmt.Errorf("invalid decision_log config, 'buffer_path' isn't supported for the %v buffer type", c.Reporting.BufferType)
 	}
 
 	if c.Reporting.BufferSizeLimitBytes != nil && c.Reporting.MaxDecisionsPerSecond != nil {
@@ -411,6 +428,9 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 
 	// default the buffer size limit
 	sizeBufferLimit := defaultBufferSizeLimitBytes
+	if c.Reporting.BufferType == diskBufferType {
+		sizeBufferLimit = defaultDiskBufferSizeLimitBytes
+	}
 	if c.Reporting.BufferSizeLimitBytes != nil {
 		if *c.Reporting.BufferSizeLimitBytes <= int64(0) {
 			return errors.New("invalid decision_log config, 'buffer_size_limit_bytes' must be higher than 0")
@@ -463,6 +483,12 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 	return nil
 }
 
+// uploads returns true if events are uploaded, to a service or to a Kafka
+// topic.
+func (c *Config) uploads() bool {
+	return c.Service != "" || c.Kafka != nil
+}
+
 type buffer interface {
 	Name() string
 	Push(*EventV1)
@@ -478,6 +504,7 @@ type Plugin struct {
 	config        Config
 	reconfigMtx   sync.RWMutex // reconfigMtx blocks reads/writes on buffer reconfiguration
 	b             buffer
+	kafka         *kafkaUploader // set if events are published to Kafka
 	statusMtx     sync.Mutex
 	stop          chan chan struct{}
 	reconfig      chan reconfigure
@@ -584,7 +611,7 @@ func (b *ConfigBuilder) Parse() (*Config, error) {
 		return nil, err
 	}
 
-	if parsedConfig.Plugin == nil && parsedConfig.Service == "" && len(b.services) == 0 && !parsedConfig.ConsoleLogs {
+	if parsedConfig.Plugin == nil && parsedConfig.Service == "" && parsedConfig.Kafka == nil && len(b.services) == 0 && !parsedConfig.ConsoleLogs {
 		// Nothing to validate or inject
 		return nil, nil
 	}
@@ -609,24 +636,7 @@ func New(parsedConfig *Config, manager *plugins.Manager) *Plugin {
 		preparedMask: *newPrepareOnce(),
 	}
 
-	switch parsedConfig.Reporting.BufferType {
-	case eventBufferType:
-		plugin.b = newEventBuffer(
-			*parsedConfig.Reporting.BufferSizeLimitEvents,
-			*parsedConfig.Reporting.UploadSizeLimitBytes,
-			plugin.manager.Client(plugin.config.Service),
-			*parsedConfig.Resource,
-			*parsedConfig.Reporting.Trigger,
-		).WithLogger(plugin.logger).WithLimiter(parsedConfig.Reporting.MaxDecisionsPerSecond)
-	case sizeBufferType:
-		plugin.b = newSizeBuffer(
-			*parsedConfig.Reporting.BufferSizeLimitBytes,
-			*parsedConfig.Reporting.UploadSizeLimitBytes,
-			plugin.manager.Client(plugin.config.Service),
-			*parsedConfig.Resource,
-			*parsedConfig.Reporting.Trigger,
-		).WithLogger(plugin.logger).WithLimiter(parsedConfig.Reporting.MaxDecisionsPerSecond)
-	}
+	plugin.b = plugin.newBuffer()
 
 	manager.RegisterCompilerTrigger(plugin.compilerUpdated)
 
@@ -667,11 +677,15 @@ func (p *Plugin) Stop(ctx context.Context) {
 	p.b.Stop(ctx)
 
 	if *p.config.Reporting.Trigger == plugins.TriggerPeriodic || *p.config.Reporting.Trigger == plugins.TriggerImmediate {
-		if _, ok := ctx.Deadline(); ok && p.config.Service != "" {
+		if _, ok := ctx.Deadline(); ok && p.config.uploads() {
 			p.flushDecisions(ctx)
 		}
 	}
 
+	if p.kafka != nil {
+		p.kafka.Close()
+	}
+
 	done := make(chan struct{})
 	p.stop <- done
 	<-done
@@ -793,7 +807,7 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		}
 	}
 
-	if p.config.Service != "" {
+	if p.config.uploads() {
 		p.push(event)
 	}
 
@@ -872,7 +886,7 @@ func (p *Plugin) Trigger(ctx context.Context) error {
 	done := make(chan error)
 
 	go func() {
-		if p.config.Service != "" {
+		if p.config.uploads() {
 			err := p.doOneShot(ctx)
 			if err != nil {
 				if ctx.Err() == nil {
@@ -907,7 +921,7 @@ func (p *Plugin) loop() {
 	for {
 		var waitC chan struct{}
 
-		if (*p.config.Reporting.Trigger == plugins.TriggerPeriodic || *p.config.Reporting.Trigger == plugins.TriggerImmediate) && p.config.Service != "" {
+		if (*p.config.Reporting.Trigger == plugins.TriggerPeriodic || *p.config.Reporting.Trigger == plugins.TriggerImmediate) && p.config.uploads() {
 			p.reconfigMtx.RLock()
 
 			err := p.doOneShot(ctx)
@@ -991,10 +1005,23 @@ func (p *Plugin) doOneShot(ctx context.Context) error {
 	return err
 }
 
+// equal reports whether the configurations are the same, ignoring the TLS
+// configuration loaded for the Kafka sink, which is loaded anew each time the
+// configuration is validated.
+func (c *Config) equal(other *Config) bool {
+	a, b := *c, *other
+	if a.Kafka != nil && b.Kafka != nil {
+		ka, kb := *a.Kafka, *b.Kafka
+		ka.tls, kb.tls = nil, nil
+		a.Kafka, b.Kafka = &ka, &kb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 func (p *Plugin) reconfigure(ctx context.Context, config any) {
 	newConfig := config.(*Config)
 
-	if reflect.DeepEqual(p.config, *newConfig) {
+	if p.config.equal(newConfig) {
 		p.logger.Debug("Decision log uploader configuration unchanged.")
 		return
 	}
@@ -1010,31 +1037,92 @@ func (p *Plugin) reconfigure(ctx context.Context, config any) {
 		p.setStatus(err)
 	}
 	p.b.Stop(ctx)
-	events := p.b.Flush()
 
-	switch newConfig.Reporting.BufferType {
+	// events stay on disk if the new buffer uses the same directory, the new
+	// buffer picks them up
+	var events []*EventV1
+	if db, ok := p.b.(*diskBuffer); !ok || p.config.Reporting.BufferType != diskBufferType || !p.sameDiskBufferPath(db.dir) {
+		events = p.b.Flush()
+	}
+
+	if p.kafka != nil {
+		p.kafka.Close()
+		p.kafka = nil
+	}
+	p.b = p.newBuffer()
+	p.b.WithMetrics(p.metrics)
+
+	for _, event := range events {
+		p.b.Push(event)
+	}
+}
+
+// newBuffer returns a new buffer of the configured type, uploading events to
+// the configured service or Kafka topic.
+func (p *Plugin) newBuffer() buffer {
+	var u uploader = serviceUploader{client: p.manager.Client(p.config.Service), path: *p.config.Resource}
+	if p.config.Kafka != nil {
+		p.kafka = newKafkaUploader(p.config.Kafka, p.logger)
+		u = p.kafka
+	}
+
+	switch p.config.Reporting.BufferType {
 	case eventBufferType:
-		p.b = newEventBuffer(
+		return newEventBuffer(
 			*p.config.Reporting.BufferSizeLimitEvents,
 			*p.config.Reporting.UploadSizeLimitBytes,
 			p.manager.Client(p.config.Service),
 			*p.config.Resource,
 			*p.config.Reporting.Trigger,
-		).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
-	case sizeBufferType:
-		p.b = newSizeBuffer(
-			*p.config.Reporting.BufferSizeLimitBytes,
-			*p.config.Reporting.UploadSizeLimitBytes,
-			p.manager.Client(p.config.Service),
-			*p.config.Resource,
-			*p.config.Reporting.Trigger,
-		).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+		).WithUploader(u).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+	case diskBufferType:
+		b, err := p.newDiskBuffer()
+		if err == nil {
+			return b.WithUploader(u).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+		}
+		// keep buffering events in memory rather than dropping them
+		p.logger.Error("Failed to open the disk buffer, falling back to the %v buffer type: %v", sizeBufferType, err)
 	}
-	p.b.WithMetrics(p.metrics)
 
-	for _, event := range events {
-		p.b.Push(event)
+	return newSizeBuffer(
+		*p.config.Reporting.BufferSizeLimitBytes,
+		*p.config.Reporting.UploadSizeLimitBytes,
+		p.manager.Client(p.config.Service),
+		*p.config.Resource,
+		*p.config.Reporting.Trigger,
+	).WithUploader(u).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+}
+
+func (p *Plugin) newDiskBuffer() (*diskBuffer, error) {
+	path, err := p.diskBufferPath()
+	if err != nil {
+		return nil, err
+	}
+	return newDiskBuffer(
+		path,
+		*p.config.Reporting.BufferSizeLimitBytes,
+		*p.config.Reporting.UploadSizeLimitBytes,
+		p.manager.Client(p.config.Service),
+		*p.config.Resource,
+		*p.config.Reporting.Trigger,
+	)
+}
+
+func (p *Plugin) sameDiskBufferPath(dir string) bool {
+	path, err := p.diskBufferPath()
+	return err == nil && filepath.Clean(path) == filepath.Clean(dir)
+}
+
+func (p *Plugin) diskBufferPath() (string, error) {
+	if p.config.Reporting.BufferPath != "" {
+		return p.config.Reporting.BufferPath, nil
+	}
+
+	persistDir, err := p.manager.GetConfig().GetPersistenceDirectory()
+	if err != nil {
+		return "", err
 	}
+	return filepath.Join(persistDir, diskBufferDirectory), nil
 }
 
 func (p *Plugin) push(event EventV1) {
@@ -1140,6 +1228,23 @@ func (p *Plugin) dropEvent(ctx context.Context, txn storage.Transaction, input a
 	return rs.Allowed(), nil
 }
 
+// uploader uploads chunks of events: gzip compressed JSON arrays of EventV1
+// entries, limited by the upload size limit. If an upload fails, the buffers
+// keep the chunk for the next upload attempt.
+type uploader interface {
+	Upload(ctx context.Context, chunk []byte) error
+}
+
+// serviceUploader uploads chunks to a service's decision log resource.
+type serviceUploader struct {
+	client rest.Client
+	path   string
+}
+
+func (u serviceUploader) Upload(ctx context.Context, chunk []byte) error {
+	return uploadChunk(ctx, u.client, u.path, chunk)
+}
+
 func uploadChunk(ctx context.Context, client rest.Client, uploadPath string, data []byte) error {
 
 	resp, err := client.
@@ -1362,9 +1467,20 @@ func eventToFields(event EventV1) map[string]any {
 	return fields
 }
 
+// backlogReporter is implemented by the buffers reporting their backlog in the
+// decision log status.
+type backlogReporter interface {
+	backlog() *lstat.BufferStatus
+}
+
 func (p *Plugin) setStatus(err error) {
 	p.statusMtx.Lock()
 	p.status.SetError(err)
+	if r, ok := p.b.(backlogReporter); ok {
+		p.status.Buffer = r.backlog()
+	} else {
+		p.status.Buffer = nil
+	}
 	oldStatus := p.status
 	p.statusMtx.Unlock()
 
@@ -14,25 +14,23 @@ import (
 )
 
 type sizeBuffer struct {
-	mtx        sync.Mutex
-	uploadMtx  sync.Mutex // used only in immediate upload mode
-	buffer     *logBuffer
-	enc        *chunkEncoder // encoder appends events into the gzip compressed JSON array
-	limiter    *rate.Limiter
-	metrics    metrics.Metrics
-	logger     logging.Logger
-	client     rest.Client
-	uploadPath string
-	mode       plugins.TriggerMode
+	mtx       sync.Mutex
+	uploadMtx sync.Mutex // used only in immediate upload mode
+	buffer    *logBuffer
+	enc       *chunkEncoder // encoder appends events into the gzip compressed JSON array
+	limiter   *rate.Limiter
+	metrics   metrics.Metrics
+	logger    logging.Logger
+	uploader  uploader
+	mode      plugins.TriggerMode
 }
 
 func newSizeBuffer(bufferSizeLimitBytes int64, uploadSizeLimitBytes int64, client rest.Client, uploadPath string, mode plugins.TriggerMode) *sizeBuffer {
 	return &sizeBuffer{
-		enc:        newChunkEncoder(uploadSizeLimitBytes),
-		buffer:     newLogBuffer(bufferSizeLimitBytes),
-		client:     client,
-		uploadPath: uploadPath,
-		mode:       mode,
+		enc:      newChunkEncoder(uploadSizeLimitBytes),
+		buffer:   newLogBuffer(bufferSizeLimitBytes),
+		uploader: serviceUploader{client: client, path: uploadPath},
+		mode:     mode,
 	}
 }
 
@@ -43,6 +41,12 @@ func (b *sizeBuffer) WithLimiter(maxDecisionsPerSecond *float64) *sizeBuffer {
 	return b
 }
 
+// WithUploader replaces the upload of chunks to a service.
+func (b *sizeBuffer) WithUploader(u uploader) *sizeBuffer {
+	b.uploader = u
+	return b
+}
+
 func (b *sizeBuffer) WithMetrics(m metrics.Metrics) {
 	b.metrics = m
 	b.enc.metrics = m
@@ -149,7 +153,7 @@ func (b *sizeBuffer) Push(event *EventV1) {
 
 			var uploadErr error
 			for _, chunk := range result {
-				uploadErr = uploadChunk(ctx, b.client, b.uploadPath, chunk)
+				uploadErr = b.uploader.Upload(ctx, chunk)
 				if uploadErr != nil {
 					b.mtx.Lock()
 					b.bufferChunk(b.buffer, chunk)
@@ -199,7 +203,7 @@ func (b *sizeBuffer) Upload(ctx context.Context) error {
 
 	for bs := oldBuffer.Pop(); bs != nil; bs = oldBuffer.Pop() {
 		if err == nil {
-			err = uploadChunk(ctx, b.client, b.uploadPath, bs)
+			err = b.uploader.Upload(ctx, bs)
 		}
 		if err != nil {
 			if b.limiter != nil {
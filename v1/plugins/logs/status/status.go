@@ -25,6 +25,15 @@ type Status struct {
 	Message  string          `json:"message,omitempty"`
 	HTTPCode json.Number     `json:"http_code,omitempty"`
 	Metrics  metrics.Metrics `json:"metrics,omitempty"`
+	Buffer   *BufferStatus   `json:"buffer,omitempty"`
+}
+
+// BufferStatus represents the backlog of a persistent decision log buffer:
+// the events stored that haven't been uploaded yet.
+type BufferStatus struct {
+	Type   string `json:"type"`
+	Events int64  `json:"events"`
+	Bytes  int64  `json:"bytes"`
 }
 
 // SetError updates the status object to reflect a failure to upload or
@@ -227,6 +227,7 @@ type Manager struct {
 	tracerProvider               *trace.TracerProvider
 	distributedTacingOpts        tracing.Options
 	registeredNDCacheTriggers    []func(bool)
+	registeredServerTriggers     []func(*config.ServerConfig)
 	bootstrapConfigLabels        map[string]string
 	hooks                        hooks.Hooks
 	enableVersionCheck           bool
@@ -1028,6 +1029,10 @@ func (m *Manager) Reconfigure(newCfg *config.Config) error {
 		trigger(config.NDBuiltinCache)
 	}
 
+	for _, trigger := range m.registeredServerTriggers {
+		trigger(config.Server)
+	}
+
 	return nil
 }
 
@@ -1352,6 +1357,15 @@ func (m *Manager) RegisterNDCacheTrigger(trigger func(bool)) {
 	m.registeredNDCacheTriggers = append(m.registeredNDCacheTriggers, trigger)
 }
 
+// RegisterServerConfigTrigger accepts a func that receives the new server config
+// generated by a reconfigure of the plugin manager, so that settings that can be
+// changed at runtime are applied by the server.
+func (m *Manager) RegisterServerConfigTrigger(trigger func(*config.ServerConfig)) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.registeredServerTriggers = append(m.registeredServerTriggers, trigger)
+}
+
 func (m *Manager) sendOPAUpdateLoop(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * defaultUploadIntervalSec))
 	mr.New(mr.NewSource(time.Now().UnixNano()))
@@ -0,0 +1,99 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package authentication implements the configuration of the server's
+// authentication schemes that need more than a command-line flag. Currently
+// this is the JWT scheme (--authentication=jwt), which verifies bearer tokens
+// against a set of trusted issuers before the request reaches system.authz.
+package authentication
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/configpolicy"
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+//go:embed validate.rego
+var validationModule string
+
+var validationPolicy = configpolicy.New(
+	"opa/config/server/authentication/validate.rego",
+	validationModule,
+	"data.opa.config.server.authentication = x",
+)
+
+func init() {
+	config.RegisterConfigSpec(config.SpecsFromStruct[Config]("server", "authentication")...)
+}
+
+// Config represents the configuration for the Server.Authentication settings
+type Config struct {
+	JWT *JWT `json:"jwt,omitempty"`
+}
+
+// JWT represents the configuration for the Server.Authentication.JWT settings
+type JWT struct {
+	Issuers            []Issuer `json:"issuers"`
+	LeewaySeconds      *int64   `json:"leeway_seconds,omitempty"`       // clock skew tolerated when checking exp, nbf and iat
+	JWKSRefreshSeconds *int64   `json:"jwks_refresh_seconds,omitempty"` // how long fetched key sets are cached
+	RequireExp         *bool    `json:"require_exp,omitempty"`          // reject tokens without exp claim
+}
+
+// Issuer is a trusted token issuer and the key set its tokens are verified
+// with. Exactly one of JWKSURL and JWKSFile is set.
+type Issuer struct {
+	Issuer    string   `json:"issuer"`              // expected iss claim
+	Audiences []string `json:"audiences,omitempty"` // accepted aud claims, any audience if empty
+	JWKSURL   string   `json:"jwks_url,omitempty"`
+	JWKSFile  string   `json:"jwks_file,omitempty"`
+}
+
+// Leeway returns the clock skew tolerated when validating time claims.
+func (c *JWT) Leeway() time.Duration {
+	return time.Duration(*c.LeewaySeconds) * time.Second
+}
+
+// JWKSRefresh returns how long fetched key sets are cached.
+func (c *JWT) JWKSRefresh() time.Duration {
+	return time.Duration(*c.JWKSRefreshSeconds) * time.Second
+}
+
+// RequiresExp returns true if tokens without exp claim are rejected.
+func (c *JWT) RequiresExp() bool {
+	return c.RequireExp == nil || *c.RequireExp
+}
+
+// ConfigBuilder assists in the construction of the plugin configuration.
+type ConfigBuilder struct {
+	raw []byte
+}
+
+// NewConfigBuilder returns a new ConfigBuilder to build and parse the server config
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{}
+}
+
+// WithBytes sets the raw server config
+func (b *ConfigBuilder) WithBytes(config []byte) *ConfigBuilder {
+	b.raw = config
+	return b
+}
+
+// Parse returns a valid Config object with defaults injected.
+func (b *ConfigBuilder) Parse() (*Config, error) {
+	return b.ParseWithContext(context.Background())
+}
+
+// ParseWithContext returns a valid Config object with defaults injected, using
+// ctx to evaluate the validation policy.
+func (b *ConfigBuilder) ParseWithContext(ctx context.Context) (*Config, error) {
+	var result Config
+	if _, err := configpolicy.EvalConfigInto(ctx, validationPolicy, b.raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
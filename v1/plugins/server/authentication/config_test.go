@@ -0,0 +1,111 @@
+package authentication
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+func TestConfigValue(t *testing.T) {
+	conf, err := NewConfigBuilder().WithBytes([]byte(`{
+		"jwt": {
+			"issuers": [
+				{"issuer": "https://idp.example.com", "audiences": ["opa"], "jwks_url": "https://idp.example.com/jwks.json"},
+				{"issuer": "internal", "jwks_file": "/etc/opa/jwks.json"}
+			],
+			"leeway_seconds": 30
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Issuer{
+		{Issuer: "https://idp.example.com", Audiences: []string{"opa"}, JWKSURL: "https://idp.example.com/jwks.json"},
+		{Issuer: "internal", JWKSFile: "/etc/opa/jwks.json"},
+	}
+	if !slices.EqualFunc(exp, conf.JWT.Issuers, func(a, b Issuer) bool {
+		return a.Issuer == b.Issuer && slices.Equal(a.Audiences, b.Audiences) && a.JWKSURL == b.JWKSURL && a.JWKSFile == b.JWKSFile
+	}) {
+		t.Fatalf("expected issuers %v, got %v", exp, conf.JWT.Issuers)
+	}
+	if exp, act := 30*time.Second, conf.JWT.Leeway(); exp != act {
+		t.Fatalf("expected leeway %v, got %v", exp, act)
+	}
+	if exp, act := 5*time.Minute, conf.JWT.JWKSRefresh(); exp != act {
+		t.Fatalf("expected JWKS refresh %v, got %v", exp, act)
+	}
+	if !conf.JWT.RequiresExp() {
+		t.Fatal("expected exp claim to be required")
+	}
+}
+
+func TestConfigWithoutJWT(t *testing.T) {
+	for _, raw := range []string{"", `{}`} {
+		conf, err := NewConfigBuilder().WithBytes([]byte(raw)).Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.JWT != nil {
+			t.Fatalf("expected no jwt config for %q, got %+v", raw, conf.JWT)
+		}
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{
+			input:   `{"jwt": true}`,
+			wantErr: "invalid value for server.authentication.jwt field, should be an object",
+		},
+		{
+			input:   `{"jwt": {}}`,
+			wantErr: "invalid value for server.authentication.jwt.issuers field, should be a non-empty array",
+		},
+		{
+			input:   `{"jwt": {"issuers": [{"issuer": "a"}]}}`,
+			wantErr: "invalid value for server.authentication.jwt.issuers[0] field, exactly one of jwks_url or jwks_file must be set",
+		},
+		{
+			input:   `{"jwt": {"issuers": [{"issuer": "a", "jwks_file": "f"}], "jwks_refresh_seconds": -5}}`,
+			wantErr: "invalid value for server.authentication.jwt.jwks_refresh_seconds field, should be a positive number",
+		},
+		{
+			input:   `[1, 2, 3]`,
+			wantErr: "config must be an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := NewConfigBuilder().WithBytes([]byte(test.input)).Parse()
+			if err == nil {
+				t.Fatalf("expected error containing %q, got none", test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", test.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestConfigWarnsOnUnknownAuthenticationOption(t *testing.T) {
+	conf, err := config.ParseConfig([]byte(`{"server": {"authentication": {"jwt": {
+		"issuers": [{"issuer": "a", "jwks_file": "f", "audience": "opa"}],
+		"leeway_seconds": 5
+	}}}}`), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{`unknown configuration option "server.authentication.jwt.issuers.0.audience" encountered`}
+	if !slices.Equal(exp, conf.Warnings) {
+		t.Fatalf("expected warnings %v, got %v", exp, conf.Warnings)
+	}
+}
@@ -0,0 +1,117 @@
+# METADATA
+# description: |
+#   Injects defaults and validates the server.authentication configuration (JWT
+#   bearer-token verification). Evaluated by the authentication config builder.
+#   Defaults are only injected when the jwt section is configured, so its absence
+#   remains visible to the server, which refuses --authentication=jwt without it.
+#
+#   Input: {"config": <raw server.authentication config>}
+#   Rules read by the Go layer: processed (config + defaults), errors (fatal).
+package opa.config.server.authentication
+
+import data.opa.config.util
+
+# Defaults mirror authentication/config.go.
+_default_leeway_seconds := 0
+
+_default_jwks_refresh_seconds := 300
+
+_default_require_exp := true
+
+# METADATA
+# description: the config with JWT defaults injected for absent options.
+processed := object.union_n(array.concat([input.config], [patch | some patch in _patches]))
+
+_patches contains {"jwt": {"leeway_seconds": _default_leeway_seconds}} if {
+	is_object(util.value(["jwt"]))
+	util.absent(["jwt", "leeway_seconds"])
+}
+
+_patches contains {"jwt": {"jwks_refresh_seconds": _default_jwks_refresh_seconds}} if {
+	is_object(util.value(["jwt"]))
+	util.absent(["jwt", "jwks_refresh_seconds"])
+}
+
+_patches contains {"jwt": {"require_exp": _default_require_exp}} if {
+	is_object(util.value(["jwt"]))
+	util.absent(["jwt", "require_exp"])
+}
+
+errors contains "invalid value for server.authentication.jwt field, should be an object" if {
+	util.not_object(["jwt"])
+}
+
+errors contains "invalid value for server.authentication.jwt.issuers field, should be a non-empty array" if {
+	is_object(util.value(["jwt"]))
+	not _non_empty_array(util.value(["jwt", "issuers"]))
+}
+
+errors contains sprintf("invalid value for server.authentication.jwt.issuers[%d] field, should be an object", [i]) if {
+	some i, issuer in _issuers
+	not is_object(issuer)
+}
+
+errors contains sprintf("invalid value for server.authentication.jwt.issuers[%d].issuer field, should be a non-empty string", [i]) if {
+	some i, issuer in _issuers
+	is_object(issuer)
+	not _non_empty_string(object.get(issuer, "issuer", null))
+}
+
+errors contains sprintf("invalid value for server.authentication.jwt.issuers[%d] field, exactly one of jwks_url or jwks_file must be set", [i]) if {
+	some i, issuer in _issuers
+	is_object(issuer)
+	count([key | some key in ["jwks_url", "jwks_file"]; _non_empty_string(object.get(issuer, key, null))]) != 1
+}
+
+errors contains sprintf("invalid value for server.authentication.jwt.issuers[%d].audiences field, should be an array of strings", [i]) if {
+	some i, issuer in _issuers
+	is_object(issuer)
+	audiences := object.get(issuer, "audiences", null)
+	audiences != null
+	not _string_array(audiences)
+}
+
+errors contains "invalid value for server.authentication.jwt.leeway_seconds field, should be a non-negative number" if {
+	value := util.value(["jwt", "leeway_seconds"])
+	value != null
+	not _non_negative_number(value)
+}
+
+errors contains "invalid value for server.authentication.jwt.jwks_refresh_seconds field, should be a positive number" if {
+	util.not_positive_number(["jwt", "jwks_refresh_seconds"])
+}
+
+errors contains "invalid value for server.authentication.jwt.require_exp field, should be a boolean" if {
+	value := util.value(["jwt", "require_exp"])
+	value != null
+	not is_boolean(value)
+}
+
+default _issuers := []
+
+_issuers := issuers if {
+	issuers := util.value(["jwt", "issuers"])
+	is_array(issuers)
+}
+
+_non_empty_array(v) if {
+	is_array(v)
+	count(v) > 0
+}
+
+_non_empty_string(v) if {
+	is_string(v)
+	v != ""
+}
+
+_string_array(v) if {
+	is_array(v)
+	every s in v {
+		is_string(s)
+	}
+}
+
+_non_negative_number(v) if {
+	is_number(v)
+	v >= 0
+}
@@ -0,0 +1,121 @@
+package opa.config.server.authentication_test
+
+import data.opa.config.server.authentication
+
+_issuer := {"issuer": "https://idp.example.com", "jwks_url": "https://idp.example.com/jwks.json"}
+
+test_injects_defaults if {
+	result := authentication.processed with input as {"config": {"jwt": {"issuers": [_issuer]}}}
+	result.jwt.leeway_seconds == 0
+	result.jwt.jwks_refresh_seconds == 300
+	result.jwt.require_exp == true
+}
+
+test_preserves_configured_values if {
+	raw := {"jwt": {"issuers": [_issuer], "leeway_seconds": 30, "jwks_refresh_seconds": 60, "require_exp": false}}
+	result := authentication.processed with input as {"config": raw}
+	result.jwt.leeway_seconds == 30
+	result.jwt.jwks_refresh_seconds == 60
+	result.jwt.require_exp == false
+}
+
+# The server relies on jwt staying absent to refuse --authentication=jwt
+# without any trusted issuers.
+test_no_defaults_without_jwt if {
+	result := authentication.processed with input as {"config": {}}
+	result == {}
+}
+
+test_rejects_non_object_jwt[tc.note] if {
+	some tc in [
+		{"note": "array", "config": {"jwt": [1, 2, 3]}},
+		{"note": "string", "config": {"jwt": "nope"}},
+	]
+
+	result := authentication.errors with input as {"config": tc.config}
+	result == {"invalid value for server.authentication.jwt field, should be an object"}
+}
+
+test_rejects_missing_issuers[tc.note] if {
+	some tc in [
+		{"note": "absent", "config": {"jwt": {}}},
+		{"note": "empty", "config": {"jwt": {"issuers": []}}},
+		{"note": "object", "config": {"jwt": {"issuers": _issuer}}},
+	]
+
+	result := authentication.errors with input as {"config": tc.config}
+	"invalid value for server.authentication.jwt.issuers field, should be a non-empty array" in result
+}
+
+test_rejects_invalid_issuers[tc.note] if {
+	some tc in [
+		{
+			"note": "not an object",
+			"issuer": "https://idp.example.com",
+			"want": "invalid value for server.authentication.jwt.issuers[0] field, should be an object",
+		},
+		{
+			"note": "missing issuer",
+			"issuer": object.remove(_issuer, ["issuer"]),
+			"want": "invalid value for server.authentication.jwt.issuers[0].issuer field, should be a non-empty string",
+		},
+		{
+			"note": "no key set",
+			"issuer": object.remove(_issuer, ["jwks_url"]),
+			"want": "invalid value for server.authentication.jwt.issuers[0] field, exactly one of jwks_url or jwks_file must be set",
+		},
+		{
+			"note": "two key sets",
+			"issuer": object.union(_issuer, {"jwks_file": "/etc/opa/jwks.json"}),
+			"want": "invalid value for server.authentication.jwt.issuers[0] field, exactly one of jwks_url or jwks_file must be set",
+		},
+		{
+			"note": "audience string",
+			"issuer": object.union(_issuer, {"audiences": "opa"}),
+			"want": "invalid value for server.authentication.jwt.issuers[0].audiences field, should be an array of strings",
+		},
+	]
+
+	result := authentication.errors with input as {"config": {"jwt": {"issuers": [tc.issuer]}}}
+	result == {tc.want}
+}
+
+test_rejects_invalid_durations[tc.note] if {
+	some tc in [
+		{
+			"note": "negative leeway",
+			"jwt": {"leeway_seconds": -1},
+			"want": "invalid value for server.authentication.jwt.leeway_seconds field, should be a non-negative number",
+		},
+		{
+			"note": "zero refresh",
+			"jwt": {"jwks_refresh_seconds": 0},
+			"want": "invalid value for server.authentication.jwt.jwks_refresh_seconds field, should be a positive number",
+		},
+	]
+
+	result := authentication.errors with input as {"config": {"jwt": object.union({"issuers": [_issuer]}, tc.jwt)}}
+	result == {tc.want}
+}
+
+test_rejects_non_boolean_require_exp if {
+	result := authentication.errors with input as {"config": {"jwt": {"issuers": [_issuer], "require_exp": "yes"}}}
+	result == {"invalid value for server.authentication.jwt.require_exp field, should be a boolean"}
+}
+
+test_valid_config_has_no_errors if {
+	raw := {"jwt": {
+		"issuers": [
+			object.union(_issuer, {"audiences": ["opa"]}),
+			{"issuer": "internal", "jwks_file": "/etc/opa/jwks.json"},
+		],
+		"leeway_seconds": 0,
+	}}
+	result := authentication.errors with input as {"config": raw}
+	count(result) == 0
+}
+
+test_empty_config_has_no_errors if {
+	result := authentication.errors with input as {"config": {}}
+	count(result) == 0
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package extauthz implements the configuration of the server's Envoy
+// external authorization service. When enabled, the gRPC API also serves the
+// envoy.service.auth.v3.Authorization service, answering Envoy's CheckRequests
+// with the policy decision at Path.
+package extauthz
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/open-policy-agent/opa/internal/configpolicy"
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+//go:embed validate.rego
+var validationModule string
+
+var validationPolicy = configpolicy.New(
+	"opa/config/server/envoy_ext_authz/validate.rego",
+	validationModule,
+	"data.opa.config.server.envoy_ext_authz = x",
+)
+
+func init() {
+	config.RegisterConfigSpec(config.SpecsFromStruct[Config]("server", "envoy_ext_authz")...)
+}
+
+// Config represents the configuration for the Server.EnvoyExtAuthz settings
+type Config struct {
+	Enabled              bool   `json:"enabled,omitempty"`
+	Path                 string `json:"path,omitempty"`    // slash-separated path of the decision under data
+	DryRun               bool   `json:"dry_run,omitempty"` // allow all requests, but still evaluate and log decisions
+	SkipRequestBodyParse bool   `json:"skip_request_body_parse,omitempty"`
+}
+
+// ConfigBuilder assists in the construction of the plugin configuration.
+type ConfigBuilder struct {
+	raw []byte
+}
+
+// NewConfigBuilder returns a new ConfigBuilder to build and parse the server config
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{}
+}
+
+// WithBytes sets the raw server config
+func (b *ConfigBuilder) WithBytes(config []byte) *ConfigBuilder {
+	b.raw = config
+	return b
+}
+
+// Parse returns a valid Config object with defaults injected.
+func (b *ConfigBuilder) Parse() (*Config, error) {
+	return b.ParseWithContext(context.Background())
+}
+
+// ParseWithContext returns a valid Config object with defaults injected, using
+// ctx to evaluate the validation policy.
+func (b *ConfigBuilder) ParseWithContext(ctx context.Context) (*Config, error) {
+	var result Config
+	if _, err := configpolicy.EvalConfigInto(ctx, validationPolicy, b.raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
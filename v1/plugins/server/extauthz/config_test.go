@@ -0,0 +1,80 @@
+package extauthz
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+func TestConfigValue(t *testing.T) {
+	conf, err := NewConfigBuilder().WithBytes([]byte(`{"enabled": true, "dry_run": true}`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Config{Enabled: true, Path: "envoy/authz/allow", DryRun: true}
+	if *conf != exp {
+		t.Fatalf("expected %+v, got %+v", exp, *conf)
+	}
+}
+
+func TestConfigDefault(t *testing.T) {
+	for _, raw := range []string{"", `{}`} {
+		conf, err := NewConfigBuilder().WithBytes([]byte(raw)).Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.Enabled || conf.Path != "envoy/authz/allow" {
+			t.Fatalf("unexpected config for %q: %+v", raw, *conf)
+		}
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{
+			input:   `{"path": "data.envoy.authz.allow"}`,
+			wantErr: "invalid value for server.envoy_ext_authz.path field, should be a non-empty slash-separated path, e.g. envoy/authz/allow",
+		},
+		{
+			input:   `{"enabled": "yes"}`,
+			wantErr: "invalid value for server.envoy_ext_authz.enabled field, should be a boolean",
+		},
+		{
+			input:   `[1, 2, 3]`,
+			wantErr: "config must be an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := NewConfigBuilder().WithBytes([]byte(test.input)).Parse()
+			if err == nil {
+				t.Fatalf("expected error containing %q, got none", test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", test.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestConfigWarnsOnUnknownExtAuthzOption(t *testing.T) {
+	conf, err := config.ParseConfig([]byte(`{"server": {"envoy_ext_authz": {
+		"enabled": true,
+		"dry-run": true
+	}}}`), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{`unknown configuration option "server.envoy_ext_authz.dry-run" encountered`}
+	if !slices.Equal(exp, conf.Warnings) {
+		t.Fatalf("expected warnings %v, got %v", exp, conf.Warnings)
+	}
+}
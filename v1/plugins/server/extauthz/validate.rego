@@ -0,0 +1,41 @@
+# METADATA
+# description: |
+#   Injects defaults and validates the server.envoy_ext_authz configuration
+#   (the Envoy external authorization service of the gRPC API). Evaluated by
+#   the ext_authz config builder, at startup and whenever the configuration is
+#   reloaded.
+#
+#   Input: {"config": <raw server.envoy_ext_authz config>}
+#   Rules read by the Go layer: processed (config + defaults), errors (fatal).
+package opa.config.server.envoy_ext_authz
+
+import data.opa.config.util
+
+# Defaults mirror extauthz/config.go.
+_default_path := "envoy/authz/allow"
+
+# METADATA
+# description: the config with the decision path default injected when absent.
+processed := object.union_n(array.concat([input.config], [patch | some patch in _patches]))
+
+_patches contains {"path": _default_path} if util.absent(["path"])
+
+# The path is given without the data prefix, like the path of the Data API.
+errors contains "invalid value for server.envoy_ext_authz.path field, should be a non-empty slash-separated path, e.g. envoy/authz/allow" if {
+	value := util.value(["path"])
+	value != null
+	not _valid_path(value)
+}
+
+errors contains sprintf("invalid value for server.envoy_ext_authz.%s field, should be a boolean", [key]) if {
+	some key in ["enabled", "dry_run", "skip_request_body_parse"]
+	value := util.value([key])
+	value != null
+	not is_boolean(value)
+}
+
+_valid_path(v) if {
+	is_string(v)
+	trim(v, "/") != ""
+	not contains(v, ".")
+}
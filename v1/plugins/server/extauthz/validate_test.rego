@@ -0,0 +1,38 @@
+package opa.config.server.envoy_ext_authz_test
+
+import data.opa.config.server.envoy_ext_authz
+
+test_injects_defaults if {
+	result := envoy_ext_authz.processed with input as {"config": {"enabled": true}}
+	result == {"enabled": true, "path": "envoy/authz/allow"}
+}
+
+test_preserves_configured_values if {
+	raw := {"enabled": true, "path": "istio/authz/allow", "dry_run": true, "skip_request_body_parse": true}
+	result := envoy_ext_authz.processed with input as {"config": raw}
+	result == raw
+}
+
+test_accepts_valid_config if {
+	result := envoy_ext_authz.errors with input as {"config": {"enabled": false, "path": "/envoy/authz/result"}}
+	count(result) == 0
+}
+
+test_rejects_invalid_path[tc.note] if {
+	some tc in [
+		{"note": "empty", "path": ""},
+		{"note": "slashes only", "path": "/"},
+		{"note": "dotted ref", "path": "data.envoy.authz.allow"},
+		{"note": "not a string", "path": ["envoy", "authz"]},
+	]
+
+	result := envoy_ext_authz.errors with input as {"config": {"path": tc.path}}
+	result == {"invalid value for server.envoy_ext_authz.path field, should be a non-empty slash-separated path, e.g. envoy/authz/allow"}
+}
+
+test_rejects_non_boolean_flags[key] if {
+	some key in ["enabled", "dry_run", "skip_request_body_parse"]
+
+	result := envoy_ext_authz.errors with input as {"config": {key: "true"}}
+	result == {sprintf("invalid value for server.envoy_ext_authz.%s field, should be a boolean", [key])}
+}
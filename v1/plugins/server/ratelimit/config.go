@@ -0,0 +1,104 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package ratelimit implements the configuration of the server's per-client
+// rate limits. Limits are applied to every client separately: a request rate
+// (a token bucket of Burst requests, refilled at RequestsPerSecond) and a
+// maximum number of requests in flight. Client-wide limits apply to all API
+// endpoints except the health checks, and endpoint budgets apply on top of
+// them to a single endpoint.
+package ratelimit
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/open-policy-agent/opa/internal/configpolicy"
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+// Client keys, determining how clients are told apart.
+const (
+	ClientKeyIdentity   = "identity"    // the authenticated identity, or the remote address without one
+	ClientKeyHeader     = "header"      // the value of ClientHeader
+	ClientKeyRemoteAddr = "remote_addr" // the remote IP address
+)
+
+//go:embed validate.rego
+var validationModule string
+
+var validationPolicy = configpolicy.New(
+	"opa/config/server/rate_limits/validate.rego",
+	validationModule,
+	"data.opa.config.server.rate_limits = x",
+)
+
+func init() {
+	config.RegisterConfigSpec(config.SpecsFromStruct[Config]("server", "rate_limits")...)
+}
+
+// Config represents the configuration for the Server.RateLimits settings
+type Config struct {
+	Limits
+	ClientKey    string             `json:"client_key,omitempty"`
+	ClientHeader string             `json:"client_header,omitempty"`
+	Endpoints    map[string]*Limits `json:"endpoints,omitempty"` // keyed by the handler label of the endpoint's metrics, e.g. v1/query
+}
+
+// Limits are the limits applied to each client. Unset limits are not
+// enforced.
+type Limits struct {
+	RequestsPerSecond *float64 `json:"requests_per_second,omitempty"`
+	Burst             *int     `json:"burst,omitempty"` // defaults to requests_per_second, rounded up
+	MaxInFlight       *int     `json:"max_in_flight,omitempty"`
+}
+
+// Enabled returns true if any client-wide or endpoint limit is set.
+func (c *Config) Enabled() bool {
+	if c.Limits.Enabled() {
+		return true
+	}
+	for _, l := range c.Endpoints {
+		if l.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns true if a request rate or in-flight limit is set.
+func (l *Limits) Enabled() bool {
+	return l != nil && (l.RequestsPerSecond != nil || l.MaxInFlight != nil)
+}
+
+// ConfigBuilder assists in the construction of the plugin configuration.
+type ConfigBuilder struct {
+	raw []byte
+}
+
+// NewConfigBuilder returns a new ConfigBuilder to build and parse the server config
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{}
+}
+
+// WithBytes sets the raw server config
+func (b *ConfigBuilder) WithBytes(config []byte) *ConfigBuilder {
+	b.raw = config
+	return b
+}
+
+// Parse returns a valid Config object with defaults injected.
+func (b *ConfigBuilder) Parse() (*Config, error) {
+	return b.ParseWithContext(context.Background())
+}
+
+// ParseWithContext returns a valid Config object with defaults injected, using
+// ctx to evaluate the validation policy.
+func (b *ConfigBuilder) ParseWithContext(ctx context.Context) (*Config, error) {
+	var result Config
+	if _, err := configpolicy.EvalConfigInto(ctx, validationPolicy, b.raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
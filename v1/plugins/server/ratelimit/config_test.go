@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/config"
+)
+
+func TestConfigValue(t *testing.T) {
+	conf, err := NewConfigBuilder().WithBytes([]byte(`{
+		"requests_per_second": 1.5,
+		"max_in_flight": 4,
+		"endpoints": {"v1/compile": {"requests_per_second": 0.5}}
+	}`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.ClientKey != ClientKeyIdentity {
+		t.Fatalf("expected client key %q, got %q", ClientKeyIdentity, conf.ClientKey)
+	}
+	if *conf.RequestsPerSecond != 1.5 || *conf.Burst != 2 || *conf.MaxInFlight != 4 {
+		t.Fatalf("unexpected client limits: %+v", conf.Limits)
+	}
+	compile := conf.Endpoints["v1/compile"]
+	if *compile.RequestsPerSecond != 0.5 || *compile.Burst != 1 || compile.MaxInFlight != nil {
+		t.Fatalf("unexpected endpoint limits: %+v", compile)
+	}
+	if !conf.Enabled() {
+		t.Fatal("expected limits to be enabled")
+	}
+}
+
+func TestConfigDisabled(t *testing.T) {
+	for _, raw := range []string{"", `{}`, `{"client_key": "remote_addr", "endpoints": {}}`} {
+		conf, err := NewConfigBuilder().WithBytes([]byte(raw)).Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.Enabled() {
+			t.Fatalf("expected limits to be disabled for %q", raw)
+		}
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{
+			input:   `{"client_key": "cookie"}`,
+			wantErr: "invalid value for server.rate_limits.client_key field, accepted values are identity, header or remote_addr",
+		},
+		{
+			input:   `{"requests_per_second": -1}`,
+			wantErr: "invalid value for server.rate_limits.requests_per_second field, should be a positive number",
+		},
+		{
+			input:   `{"endpoints": {"v1/query": {"burst": 0.5}}}`,
+			wantErr: "invalid value for server.rate_limits.endpoints.v1/query.burst field, should be a positive integer",
+		},
+		{
+			input:   `[1, 2, 3]`,
+			wantErr: "config must be an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := NewConfigBuilder().WithBytes([]byte(test.input)).Parse()
+			if err == nil {
+				t.Fatalf("expected error containing %q, got none", test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", test.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestConfigWarnsOnUnknownRateLimitOption(t *testing.T) {
+	conf, err := config.ParseConfig([]byte(`{"server": {"rate_limits": {
+		"requests_per_second": 10,
+		"endpoints": {"v1/query": {"max_in_flight": 1, "max_inflight": 1}}
+	}}}`), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{`unknown configuration option "server.rate_limits.endpoints.v1/query.max_inflight" encountered`}
+	if !slices.Equal(exp, conf.Warnings) {
+		t.Fatalf("expected warnings %v, got %v", exp, conf.Warnings)
+	}
+}
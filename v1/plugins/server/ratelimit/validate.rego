@@ -0,0 +1,104 @@
+# METADATA
+# description: |
+#   Injects defaults and validates the server.rate_limits configuration
+#   (per-client request rates and in-flight limits). Evaluated by the rate limit
+#   config builder, at startup and whenever the configuration is reloaded.
+#
+#   Input: {"config": <raw server.rate_limits config>}
+#   Rules read by the Go layer: processed (config + defaults), errors (fatal).
+package opa.config.server.rate_limits
+
+import data.opa.config.util
+
+# Defaults mirror ratelimit/config.go.
+_default_client_key := "identity"
+
+_client_keys := {"identity", "header", "remote_addr"}
+
+# METADATA
+# description: |
+#   the config with the client key and burst defaults injected for absent
+#   options. The burst of a rate defaults to the rate per second, rounded up.
+processed := object.union_n(array.concat([input.config], [patch | some patch in _patches]))
+
+_patches contains {"client_key": _default_client_key} if util.absent(["client_key"])
+
+_patches contains {"burst": _default_burst(input.config)} if {
+	util.absent(["burst"])
+	_positive_number(util.value(["requests_per_second"]))
+}
+
+_patches contains {"endpoints": {name: {"burst": _default_burst(limits)}}} if {
+	some name, limits in _endpoints
+	is_object(limits)
+	object.get(limits, "burst", null) == null
+	_positive_number(object.get(limits, "requests_per_second", null))
+}
+
+_default_burst(limits) := max([1, ceil(limits.requests_per_second)])
+
+default _endpoints := {}
+
+_endpoints := endpoints if {
+	endpoints := util.value(["endpoints"])
+	is_object(endpoints)
+}
+
+errors contains "invalid value for server.rate_limits.client_key field, accepted values are identity, header or remote_addr" if {
+	value := util.value(["client_key"])
+	value != null
+	not value in _client_keys
+}
+
+errors contains "invalid value for server.rate_limits.client_header field, should be set when client_key is header" if {
+	util.value(["client_key"]) == "header"
+	not _non_empty_string(util.value(["client_header"]))
+}
+
+errors contains "invalid value for server.rate_limits.endpoints field, should be an object" if {
+	util.not_object(["endpoints"])
+}
+
+errors contains sprintf("invalid value for server.rate_limits.endpoints.%s field, should be an object", [name]) if {
+	some name, limits in _endpoints
+	not is_object(limits)
+}
+
+errors contains msg if {
+	some msg in _limit_errors("server.rate_limits", input.config)
+}
+
+errors contains msg if {
+	some name, limits in _endpoints
+	is_object(limits)
+	some msg in _limit_errors(sprintf("server.rate_limits.endpoints.%s", [name]), limits)
+}
+
+_limit_errors(prefix, limits) := {sprintf("invalid value for %s.%s field, %s", [prefix, key, msg]) |
+	some key, msg in {
+		"requests_per_second": "should be a positive number",
+		"burst": "should be a positive integer",
+		"max_in_flight": "should be a positive integer",
+	}
+	value := object.get(limits, key, null)
+	value != null
+	not _valid_limit(key, value)
+}
+
+_valid_limit("requests_per_second", value) if _positive_number(value)
+
+_valid_limit(key, value) if {
+	key != "requests_per_second"
+	_positive_number(value)
+	round(value) == value
+}
+
+_positive_number(v) if {
+	is_number(v)
+	v > 0
+}
+
+_non_empty_string(v) if {
+	is_string(v)
+	v != ""
+}
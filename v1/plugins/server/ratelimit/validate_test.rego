@@ -0,0 +1,102 @@
+package opa.config.server.rate_limits_test
+
+import data.opa.config.server.rate_limits
+
+test_injects_defaults if {
+	raw := {
+		"requests_per_second": 2.5,
+		"endpoints": {
+			"v1/compile": {"requests_per_second": 0.2},
+			"v1/query": {"max_in_flight": 1},
+		},
+	}
+	result := rate_limits.processed with input as {"config": raw}
+	result.client_key == "identity"
+	result.burst == 3
+	result.endpoints["v1/compile"].burst == 1
+	not "burst" in object.keys(result.endpoints["v1/query"])
+}
+
+test_no_burst_without_rate if {
+	result := rate_limits.processed with input as {"config": {"max_in_flight": 4}}
+	result == {"client_key": "identity", "max_in_flight": 4}
+}
+
+test_preserves_configured_values if {
+	raw := {"client_key": "header", "client_header": "X-Client-ID", "requests_per_second": 10, "burst": 50}
+	result := rate_limits.processed with input as {"config": raw}
+	result == raw
+}
+
+test_rejects_invalid_client_key[tc.note] if {
+	some tc in [
+		{
+			"note": "unknown key",
+			"config": {"client_key": "cookie"},
+			"want": "invalid value for server.rate_limits.client_key field, accepted values are identity, header or remote_addr",
+		},
+		{
+			"note": "header without name",
+			"config": {"client_key": "header"},
+			"want": "invalid value for server.rate_limits.client_header field, should be set when client_key is header",
+		},
+	]
+
+	result := rate_limits.errors with input as {"config": tc.config}
+	result == {tc.want}
+}
+
+test_rejects_invalid_limits[tc.note] if {
+	some tc in [
+		{
+			"note": "zero rate",
+			"config": {"requests_per_second": 0},
+			"want": "invalid value for server.rate_limits.requests_per_second field, should be a positive number",
+		},
+		{
+			"note": "fractional burst",
+			"config": {"requests_per_second": 1, "burst": 1.5},
+			"want": "invalid value for server.rate_limits.burst field, should be a positive integer",
+		},
+		{
+			"note": "string max_in_flight",
+			"config": {"max_in_flight": "10"},
+			"want": "invalid value for server.rate_limits.max_in_flight field, should be a positive integer",
+		},
+		{
+			"note": "endpoint limit",
+			"config": {"endpoints": {"v1/query": {"max_in_flight": -1}}},
+			"want": "invalid value for server.rate_limits.endpoints.v1/query.max_in_flight field, should be a positive integer",
+		},
+		{
+			"note": "endpoint not an object",
+			"config": {"endpoints": {"v1/query": 5}},
+			"want": "invalid value for server.rate_limits.endpoints.v1/query field, should be an object",
+		},
+		{
+			"note": "endpoints not an object",
+			"config": {"endpoints": ["v1/query"]},
+			"want": "invalid value for server.rate_limits.endpoints field, should be an object",
+		},
+	]
+
+	result := rate_limits.errors with input as {"config": tc.config}
+	result == {tc.want}
+}
+
+test_valid_config_has_no_errors if {
+	raw := {
+		"client_key": "remote_addr",
+		"requests_per_second": 100,
+		"burst": 200,
+		"max_in_flight": 10,
+		"endpoints": {"v1/compile": {"requests_per_second": 0.5, "max_in_flight": 1}},
+	}
+	result := rate_limits.errors with input as {"config": raw}
+	count(result) == 0
+}
+
+test_empty_config_has_no_errors if {
+	result := rate_limits.errors with input as {"config": {}}
+	count(result) == 0
+}
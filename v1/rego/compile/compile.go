@@ -3,8 +3,9 @@
 // masking rule), and partial eval, equipped with the correct settings for some
 // options; and paired with post-checks that determine if the result of partial
 // evaluation can be translated into filter queries for certain targets/dialects.
-// On success, the PE results are translated into queries, i.e. SQL WHERE clauses
-// or UCAST expressions.
+// On success, the PE results are translated into queries, i.e. SQL WHERE clauses,
+// UCAST expressions, MongoDB filter documents or Elasticsearch queries, or by
+// the Translator of a custom target registered with RegisterTarget.
 package compile
 
 import (
@@ -12,6 +13,7 @@ import (
 	"fmt"
 
 	"github.com/open-policy-agent/opa/internal/compile"
+	"github.com/open-policy-agent/opa/internal/ref"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/metrics"
 	"github.com/open-policy-agent/opa/v1/rego"
@@ -28,9 +30,12 @@ type Compile struct {
 	maskRule ast.Ref
 	unknowns []*ast.Term // ast.Ref would be slightly more on-the-spot, but we follow what is done in v1/rego to minimise surprises.
 	query    ast.Body
+	path     string
 	mappings map[string]any
 	metrics  metrics.Metrics
 
+	annotations *ast.Compiler
+
 	regoOpts []func(*rego.Rego)
 }
 
@@ -90,6 +95,9 @@ func MaskRule(rule ast.Ref) CompileOption {
 //	      }
 //	   }
 //	}
+//
+// For the mongo and elasticsearch targets, `$self` maps to the name of the
+// collection or index, and the other keys to (dot-separated) field names.
 func Mappings(m map[string]any) CompileOption {
 	return func(c *Compile) {
 		c.mappings = m
@@ -118,6 +126,28 @@ func ParsedQuery(q ast.Body) CompileOption {
 	}
 }
 
+// Path lets you pass in the main entrypoint of this filter compilation as the
+// slash-separated path of a rule, e.g. "filters/include" for the rule
+// `data.filters.include`. It's an alternative to ParsedQuery.
+func Path(path string) CompileOption {
+	return func(c *Compile) {
+		c.path = path
+	}
+}
+
+// Annotations lets you pass the compiler holding the `compile` annotations of
+// the rule to compile: if no unknowns or no mask rule are provided, they're
+// taken from these annotations, like the Compile API does. If no compiler is
+// passed, the annotations of the modules passed via Rego() are used: note that
+// these are only available for modules parsed with annotation processing, like
+// those of rego.Load, rego.LoadBundle, or rego.ParsedModule with a module parsed
+// using ast.ParserOptions{ProcessAnnotation: true}.
+func Annotations(comp *ast.Compiler) CompileOption {
+	return func(c *Compile) {
+		c.annotations = comp
+	}
+}
+
 // New creates a new `*compile.Compile` struct.
 func New(opts ...CompileOption) *Compile {
 	c := &Compile{}
@@ -139,6 +169,10 @@ func New(opts ...CompileOption) *Compile {
 type Prepared struct {
 	compile *Compile // carry along
 
+	query    ast.Body
+	unknowns []*ast.Term
+	maskRule ast.Ref
+
 	constraintSet        *compile.ConstraintSet
 	shorts               compile.Set[string]
 	regoPrepareOptions   []rego.PrepareOption
@@ -190,7 +224,7 @@ func (c *Compile) Prepare(ctx context.Context, po ...PrepareOption) (*Prepared,
 	constrs := make([]*compile.Constraint, len(p.compile.targets))
 	for i := range p.compile.targets {
 		var err error
-		constrs[i], err = compile.NewConstraints(p.compile.targets[i], p.compile.dialects[i]) // NewConstraints validates the tuples
+		constrs[i], err = constraints(p.compile.targets[i], p.compile.dialects[i])
 		if err != nil {
 			return nil, err
 		}
@@ -198,29 +232,96 @@ func (c *Compile) Prepare(ctx context.Context, po ...PrepareOption) (*Prepared,
 	p.constraintSet = compile.NewConstraintSet(constrs...)
 	p.shorts = compile.ShortsFromMappings(p.compile.mappings)
 
-	// mask prep
-	if c.maskRule != nil {
-		rp, err := rego.New(append(c.regoOpts,
-			rego.ParsedQuery(ast.NewBody(ast.NewExpr(ast.NewTerm(c.maskRule)))),
-		)...).PrepareForEval(ctx, p.regoPrepareOptions...)
+	p.query, p.unknowns, p.maskRule = c.query, c.unknowns, c.maskRule
+	if c.path != "" {
+		r, err := ref.ParseDataPath(c.path)
 		if err != nil {
-			return nil, fmt.Errorf("prepare for mask eval: %w", err)
+			return nil, fmt.Errorf("invalid path: %w", err)
 		}
-		p.preparedMaskQuery = &rp
+		p.query = ast.NewBody(ast.NewExpr(ast.NewTerm(r)))
 	}
 
 	// PE prep
 	rp, err := rego.New(append(c.regoOpts,
-		rego.ParsedQuery(c.query),
+		rego.ParsedQuery(p.query),
 	)...).PrepareForPartial(ctx, p.regoPrepareOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("prepare for partial: %w", err)
 	}
 
+	if len(p.unknowns) == 0 || p.maskRule == nil {
+		if errs := p.fromAnnotations(rp.Modules()); errs != nil {
+			return nil, errs
+		}
+	}
+
+	// mask prep
+	if p.maskRule != nil {
+		rp, err := rego.New(append(c.regoOpts,
+			rego.ParsedQuery(ast.NewBody(ast.NewExpr(ast.NewTerm(p.maskRule)))),
+		)...).PrepareForEval(ctx, p.regoPrepareOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("prepare for mask eval: %w", err)
+		}
+		p.preparedMaskQuery = &rp
+	}
+
 	p.preparedPartialQuery = &rp
 	return p, nil
 }
 
+// fromAnnotations fills in the unknowns and mask rule that haven't been
+// provided from the `compile` annotations of the rule that's queried, if the
+// query is a plain reference to a rule.
+func (p *Prepared) fromAnnotations(modules map[string]*ast.Module) ast.Errors {
+	if len(p.query) != 1 {
+		return nil
+	}
+	t, ok := p.query[0].Terms.(*ast.Term)
+	if !ok {
+		return nil
+	}
+	queryRef, ok := t.Value.(ast.Ref)
+	if !ok || !queryRef.HasPrefix(ast.DefaultRootRef) {
+		return nil
+	}
+
+	var unknowns []ast.Ref
+	var maskRule ast.Ref
+	if comp := p.compile.annotations; comp != nil {
+		var errs []*ast.Error
+		unknowns, errs = compile.ExtractUnknownsFromAnnotations(comp, queryRef)
+		if errs != nil {
+			return errs
+		}
+		var err *ast.Error
+		maskRule, err = compile.ExtractMaskRuleRefFromAnnotations(comp, queryRef)
+		if err != nil {
+			return ast.Errors{err}
+		}
+	} else {
+		mods := make([]*ast.Module, 0, len(modules))
+		for _, name := range util.KeysSorted(modules) {
+			mods = append(mods, modules[name])
+		}
+		var errs []*ast.Error
+		unknowns, maskRule, errs = compile.ExtractFromModuleAnnotations(mods, queryRef)
+		if errs != nil {
+			return errs
+		}
+	}
+
+	if len(p.unknowns) == 0 {
+		for i := range unknowns {
+			p.unknowns = append(p.unknowns, ast.NewTerm(unknowns[i]))
+		}
+	}
+	if p.maskRule == nil {
+		p.maskRule = maskRule
+	}
+	return nil
+}
+
 // Filter represents the result of a policy-to-filter compilation for one
 // specific target/dialect
 type Filter struct {
@@ -293,7 +394,7 @@ func (p *Prepared) Compile(ctx context.Context, eo ...rego.EvalOption) (*Filters
 
 	// PE for conversion
 	opts := append(maskOpts,
-		rego.EvalParsedUnknowns(p.compile.unknowns),
+		rego.EvalParsedUnknowns(p.unknowns),
 	)
 	pq, err := p.preparedPartialQuery.Partial(ctx, opts...)
 	if err != nil {
@@ -328,6 +429,28 @@ func (p *Prepared) Compile(ctx context.Context, eo ...rego.EvalOption) (*Filters
 				return nil, fmt.Errorf("convert to queries: %w", err)
 			}
 			ret.push(target, dialect, sql, maskResult)
+		case "mongo":
+			query, err := compile.QueriesToMongo(pq.Queries, mappings)
+			if err != nil {
+				return nil, fmt.Errorf("convert to queries: %w", err)
+			}
+			ret.push(target, dialect, query, maskResult)
+		case "elasticsearch":
+			query, err := compile.QueriesToElasticsearch(pq.Queries, mappings)
+			if err != nil {
+				return nil, fmt.Errorf("convert to queries: %w", err)
+			}
+			ret.push(target, dialect, query, maskResult)
+		default:
+			t, ok := customTarget(target)
+			if !ok {
+				return nil, fmt.Errorf("unknown target %s", target)
+			}
+			query, err := t.Translator.Translate(&Queries{Dialect: dialect, Queries: pq.Queries, Mappings: mappings})
+			if err != nil {
+				return nil, fmt.Errorf("translate to %s: %w", target, err)
+			}
+			ret.push(target, dialect, query, maskResult)
 		}
 	}
 
@@ -1,6 +1,8 @@
 package compile_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -88,6 +90,75 @@ include if input.fruit.name in input.names
 		}
 	})
 
+	t.Run("document targets/mappings", func(t *testing.T) {
+		unknowns := []*ast.Term{ast.MustParseTerm("input.fruit")}
+		query := ast.MustParseBody("data.filters.include")
+
+		module := `package filters
+include if {
+	input.fruit.name in input.names
+	input.fruit.price < 10
+}
+`
+
+		r := compile.New(
+			compile.Target("mongo", ""),
+			compile.Target("elasticsearch", ""),
+			compile.ParsedUnknowns(unknowns...),
+			compile.ParsedQuery(query),
+			compile.Mappings(map[string]any{
+				"mongo": map[string]any{"fruit": map[string]any{
+					"$self": "fruits",
+					"price": "pricing.amount",
+				}},
+				"elasticsearch": map[string]any{"fruit": map[string]any{
+					"$self": "fruit-index",
+					"name":  "name.keyword",
+				}},
+			}),
+			compile.Rego(
+				rego.Module("filters.rego", module),
+				rego.Input(map[string]any{"names": []string{"apple", "banana"}}),
+			),
+		)
+
+		prep, err := r.Prepare(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		filters, err := prep.Compile(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("mongo", func(t *testing.T) {
+			exp := map[string]any{
+				"collection": "fruits",
+				"filter": map[string]any{"$and": []any{
+					map[string]any{"name": map[string]any{"$in": []any{"apple", "banana"}}},
+					map[string]any{"pricing.amount": map[string]any{"$lt": int64(10)}},
+				}},
+			}
+			if diff := cmp.Diff(exp, filters.For("mongo", "").Query); diff != "" {
+				t.Error("unexpected query: (-want, +got)", diff)
+			}
+		})
+
+		t.Run("elasticsearch", func(t *testing.T) {
+			exp := map[string]any{
+				"index": "fruit-index",
+				"query": map[string]any{"bool": map[string]any{"filter": []any{
+					map[string]any{"terms": map[string]any{"name.keyword": []any{"apple", "banana"}}},
+					map[string]any{"range": map[string]any{"price": map[string]any{"lt": int64(10)}}},
+				}}},
+			}
+			if diff := cmp.Diff(exp, filters.For("elasticsearch", "").Query); diff != "" {
+				t.Error("unexpected query: (-want, +got)", diff)
+			}
+		})
+	})
+
 	t.Run("multiple target+dialect", func(t *testing.T) {
 		unknowns := []*ast.Term{ast.MustParseTerm("input.fruit")}
 		query := ast.MustParseBody("data.filters.include")
@@ -166,3 +237,123 @@ mask.fruit.owner := {"replace": {"value": "***"}}
 		})
 	})
 }
+
+func TestCompileFiltersCustomTarget(t *testing.T) {
+	t.Cleanup(compile.ResetTargets)
+
+	compile.RegisterTarget("kv", compile.CustomTarget{
+		Dialects: []string{"strict", "loose"},
+		Builtins: []string{"eq", "internal.member_2"},
+		Translator: compile.TranslatorFunc(func(q *compile.Queries) (any, error) {
+			node := q.UCAST()
+			if node == nil {
+				return "*", nil
+			}
+			return fmt.Sprintf("%s:%s %s %v", q.Dialect, node.Field, node.Op, node.Value), nil
+		}),
+	})
+
+	unknowns := []*ast.Term{ast.MustParseTerm("input.fruit")}
+	query := ast.MustParseBody("data.filters.include")
+
+	compileWith := func(module string, target, dialect string) (*compile.Filters, error) {
+		prep, err := compile.New(
+			compile.Target(target, dialect),
+			compile.ParsedUnknowns(unknowns...),
+			compile.ParsedQuery(query),
+			compile.Mappings(map[string]any{"kv": map[string]any{"fruit": map[string]any{"name": "title"}}}),
+			compile.Rego(
+				rego.Module("filters.rego", module),
+				rego.Input(map[string]any{"names": []string{"apple", "banana"}}),
+			),
+		).Prepare(t.Context())
+		if err != nil {
+			return nil, err
+		}
+		return prep.Compile(t.Context())
+	}
+
+	t.Run("translated", func(t *testing.T) {
+		filters, err := compileWith(`package filters
+include if input.fruit.name in input.names
+`, "kv", "strict")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := "strict:fruit.title in [apple banana]", filters.For("kv", "strict").Query; exp != act {
+			t.Errorf("query: expected %q, got %q", exp, act)
+		}
+	})
+
+	t.Run("unconditional yes", func(t *testing.T) {
+		filters, err := compileWith(`package filters
+include := true
+`, "kv", "loose")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := "*", filters.One().Query; exp != act {
+			t.Errorf("query: expected %q, got %q", exp, act)
+		}
+	})
+
+	t.Run("unsupported builtin", func(t *testing.T) {
+		_, err := compileWith(`package filters
+include if startswith(input.fruit.name, "app")
+`, "kv", "strict")
+		if err == nil || !strings.Contains(err.Error(), "invalid builtin `startswith`: unsupported for KV (strict)") {
+			t.Fatalf("expected constraint violation, got %v", err)
+		}
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		_, err := compileWith(`package filters
+include := true
+`, "kv", "lax")
+		if err == nil || err.Error() != "unsupported variant for kv: lax" {
+			t.Fatalf("expected unsupported variant error, got %v", err)
+		}
+	})
+}
+
+func TestCompileFiltersPathAndAnnotations(t *testing.T) {
+	module := `package filters
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruit]
+#   mask_rule: mask
+include if input.fruit.name in input.names
+
+mask.fruit.owner := {"replace": {"value": "***"}}
+`
+	// annotations are only available on modules parsed with ProcessAnnotation
+	parsed, err := ast.ParseModuleWithOpts("filters.rego", module, ast.ParserOptions{ProcessAnnotation: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prep, err := compile.New(
+		compile.Target("sql", "mysql"),
+		compile.Path("filters/include"),
+		compile.Rego(
+			rego.ParsedModule(parsed),
+			rego.Input(map[string]any{"names": []string{"apple"}}),
+		),
+	).Prepare(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := prep.Compile(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "WHERE fruit.name IN ('apple')", filters.One().Query; exp != act {
+		t.Errorf("query: expected %q, got %q", exp, act)
+	}
+	exp := map[string]any{"fruit": map[string]any{"owner": map[string]any{"replace": map[string]any{"value": "***"}}}}
+	if diff := cmp.Diff(exp, filters.One().Masks); diff != "" {
+		t.Error("unexpected masks (-want, +got):", diff)
+	}
+}
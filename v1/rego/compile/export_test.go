@@ -0,0 +1,7 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package compile
+
+var ResetTargets = resetTargets
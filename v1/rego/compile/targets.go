@@ -0,0 +1,150 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/internal/compile"
+	"github.com/open-policy-agent/opa/internal/ucast"
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// UCASTNode is a node of the UCAST tree that partially evaluated queries are
+// translated into. Field nodes compare a field with a value, compound nodes
+// ("and", "or", "not") combine the nodes in their value.
+type UCASTNode = ucast.UCASTNode
+
+// UCASTFieldRef is used as the value of a UCASTNode when a field is compared
+// to another field.
+type UCASTFieldRef = ucast.FieldRef
+
+// UCASTNull is used as the value of a UCASTNode when a field is compared to
+// null.
+type UCASTNull = ucast.Null
+
+// Translator translates the result of partial evaluation into a filter for a
+// custom target, e.g. a query in another query language.
+type Translator interface {
+	Translate(*Queries) (any, error)
+}
+
+// TranslatorFunc lets you use a function as a Translator.
+type TranslatorFunc func(*Queries) (any, error)
+
+func (f TranslatorFunc) Translate(q *Queries) (any, error) {
+	return f(q)
+}
+
+// Queries is what a Translator is given to translate: the queries resulting
+// from partial evaluation, which have passed the checks for the target's
+// constraints.
+type Queries struct {
+	// Dialect is the dialect the filter is requested for.
+	Dialect string
+
+	// Queries are the partially evaluated queries. The filter should select
+	// everything that satisfies any of them. A single empty query means
+	// everything is selected.
+	Queries []ast.Body
+
+	// Mappings are the mappings applicable for the target and dialect, see
+	// Mappings.
+	Mappings map[string]any
+}
+
+// UCAST returns the UCAST representation of the queries, with the mappings
+// applied to its field names. It returns nil if everything is selected.
+func (q *Queries) UCAST() *UCASTNode {
+	return compile.BodiesToUCAST(q.Queries, &compile.Opts{Translations: q.Mappings})
+}
+
+// CustomTarget describes a filter target that isn't built into OPA.
+type CustomTarget struct {
+	// Dialects lists the dialects supported by the target. If empty, any
+	// dialect is accepted.
+	Dialects []string
+
+	// Builtins lists the built-in functions that the partially evaluated
+	// queries can call, e.g. "eq", "startswith", or "internal.member_2" for
+	// `in`. Queries calling any other built-in function are rejected.
+	// Note that the UCAST representation only covers the comparisons, `in`,
+	// startswith, endswith, and contains.
+	Builtins []string
+
+	// Features lists the language features that the partially evaluated
+	// queries can use: "not" for negated expressions, "field-ref" for
+	// comparisons of two unknowns, and "existence-ref" for unknowns that are
+	// checked for existence.
+	Features []string
+
+	// Translator translates the partially evaluated queries into filters.
+	Translator Translator
+}
+
+var (
+	customTargets   = map[string]CustomTarget{}
+	customTargetMtx sync.RWMutex
+)
+
+// RegisterTarget makes a custom target available for filter compilation. It
+// panics if a target with the same name has been registered before, or the
+// name is that of a built-in target.
+func RegisterTarget(name string, t CustomTarget) {
+	if t.Translator == nil {
+		panic("missing translator for target " + name)
+	}
+	customTargetMtx.Lock()
+	defer customTargetMtx.Unlock()
+	if _, ok := customTargets[name]; ok || isBuiltinTarget(name) {
+		panic("target already registered " + name)
+	}
+	customTargets[name] = t
+}
+
+// resetTargets removes all registered custom targets.
+func resetTargets() {
+	customTargetMtx.Lock()
+	defer customTargetMtx.Unlock()
+	customTargets = map[string]CustomTarget{}
+}
+
+func isBuiltinTarget(name string) bool {
+	switch name {
+	case "ucast", "sql", "mongo", "elasticsearch":
+		return true
+	}
+	return false
+}
+
+func customTarget(name string) (CustomTarget, bool) {
+	customTargetMtx.RLock()
+	defer customTargetMtx.RUnlock()
+	t, ok := customTargets[name]
+	return t, ok
+}
+
+func (t CustomTarget) constraints(name, dialect string) (*compile.Constraint, error) {
+	if len(t.Dialects) > 0 && !slices.Contains(t.Dialects, dialect) {
+		return nil, fmt.Errorf("unsupported variant for %s: %s", name, dialect)
+	}
+	return &compile.Constraint{
+		Target:   strings.ToUpper(name),
+		Variant:  dialect,
+		Builtins: compile.NewSet(t.Builtins...),
+		Features: compile.NewSet(t.Features...),
+	}, nil
+}
+
+// constraints returns the constraints of a target/dialect combination, be it
+// a built-in or a custom one.
+func constraints(target, dialect string) (*compile.Constraint, error) {
+	if t, ok := customTarget(target); ok {
+		return t.constraints(target, dialect)
+	}
+	return compile.NewConstraints(target, dialect) // NewConstraints validates the tuples
+}
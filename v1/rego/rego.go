@@ -18,6 +18,7 @@ import (
 	bundleUtils "github.com/open-policy-agent/opa/internal/bundle"
 	"github.com/open-policy-agent/opa/internal/compiler/wasm"
 	"github.com/open-policy-agent/opa/internal/future"
+	"github.com/open-policy-agent/opa/internal/ireval"
 	"github.com/open-policy-agent/opa/internal/planner"
 	"github.com/open-policy-agent/opa/internal/rego/opa"
 	"github.com/open-policy-agent/opa/internal/wasm/encoding"
@@ -45,6 +46,7 @@ const (
 
 	targetWasm = "wasm"
 	targetRego = "rego"
+	targetPlan = "plan"
 )
 
 // CompileResult represents the result of compiling a Rego query, zero or more
@@ -710,6 +712,7 @@ type Rego struct {
 	schemaSet                   *ast.SchemaSet
 	target                      string // target type (wasm, rego, etc.)
 	opa                         opa.EvalEngine
+	plan                        *ireval.Interpreter
 	generateJSON                func(*ast.Term, *EvalContext) (any, error)
 	printHook                   print.Hook
 	enablePrintStatements       bool
@@ -1455,7 +1458,7 @@ func New(options ...func(r *Rego)) *Rego {
 
 		// topdown could be target "" or "rego", but both could be overridden by
 		// a target plugin (checked below)
-		if r.target == targetWasm {
+		if r.target == targetWasm || r.target == targetPlan {
 			r.compiler = r.compiler.WithEvalMode(ast.EvalModeIR)
 		}
 
@@ -1885,6 +1888,20 @@ func (r *Rego) PrepareForEval(ctx context.Context, opts ...PrepareOption) (Prepa
 		}
 		r.opa = o
 
+	case targetPlan:
+		queries := []ast.Body{r.compiledQueries[evalQueryType].query}
+		pol, err := r.planQuery(queries, evalQueryType)
+		if err != nil {
+			_ = txnClose(ctx, err) // Ignore error
+			return PreparedEvalQuery{}, err
+		}
+
+		r.plan, err = ireval.New(pol, r.builtinFuncs)
+		if err != nil {
+			_ = txnClose(ctx, err) // Ignore error
+			return PreparedEvalQuery{}, err
+		}
+
 	case targetRego: // do nothing, don't lookup default plugin
 	default: // either a specific plugin target, or one that is default
 		if tgt := r.targetPlugin(r.target); tgt != nil {
@@ -2323,6 +2340,8 @@ func (r *Rego) eval(ctx context.Context, ectx *EvalContext) (ResultSet, error) {
 		return r.valueToQueryResult(s, ectx)
 	case r.target == targetWasm:
 		return r.evalWasm(ctx, ectx)
+	case r.target == targetPlan:
+		return r.evalPlan(ctx, ectx)
 	case r.target == targetRego: // continue
 	}
 
@@ -2442,6 +2461,62 @@ func (r *Rego) evalWasm(ctx context.Context, ectx *EvalContext) (ResultSet, erro
 	return r.valueToQueryResult(parsed.Value, ectx)
 }
 
+func (r *Rego) evalPlan(ctx context.Context, ectx *EvalContext) (ResultSet, error) {
+	data, err := r.store.Read(ctx, ectx.txn, storage.RootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like topdown, only convert the documents the plan reads.
+	var dataValue ast.Value
+	switch data := data.(type) {
+	case ast.Value:
+		dataValue = data
+	case map[string]any:
+		dataValue = ast.LazyObject(data)
+	default:
+		dataValue, err = ast.InterfaceToValue(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := ireval.EvalOpts{
+		Input:                       ectx.parsedInput,
+		Data:                        dataValue,
+		Metrics:                     ectx.metrics,
+		Time:                        ectx.time,
+		Seed:                        ectx.seed,
+		Runtime:                     r.runtime,
+		Cancel:                      ectx.externalCancel,
+		InterQueryBuiltinCache:      ectx.interQueryBuiltinCache,
+		InterQueryBuiltinValueCache: ectx.interQueryBuiltinValueCache,
+		NDBuiltinCache:              ectx.ndBuiltinCache,
+		PrintHook:                   ectx.printHook,
+		Capabilities:                ectx.capabilities,
+		StrictBuiltinErrors:         ectx.strictBuiltinErrors,
+		BuiltinErrorList:            ectx.builtinErrorList,
+	}
+
+	// Cancel evaluation if context is cancelled or deadline is reached.
+	if opts.Cancel == nil {
+		c := topdown.NewCancel()
+		opts.Cancel = c
+		exit := make(chan struct{})
+		defer close(exit)
+		go waitForDone(ctx, exit, func() {
+			c.Cancel()
+		})
+	}
+
+	result, err := r.plan.Eval(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.valueToQueryResult(result, ectx)
+}
+
 func (r *Rego) valueToQueryResult(res ast.Value, ectx *EvalContext) (ResultSet, error) {
 	resultSet, ok := res.(ast.Set)
 	if !ok {
@@ -2839,7 +2914,7 @@ func (r *Rego) generateTermVar() *ast.Term {
 	prefix := ast.WildcardPrefix
 	if p := r.targetPlugin(r.target); p != nil {
 		prefix = wasmVarPrefix
-	} else if r.target == targetWasm {
+	} else if r.target == targetWasm || r.target == targetPlan {
 		prefix = wasmVarPrefix
 	}
 	return ast.VarTerm(fmt.Sprintf("%sterm%v", prefix, r.termVarID))
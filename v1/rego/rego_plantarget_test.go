@@ -0,0 +1,118 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package rego
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/types"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func TestPrepareAndEvalWithPlanTarget(t *testing.T) {
+	t.Parallel()
+
+	mod := `package test
+
+default allow := false
+
+allow if {
+	input.user in data.admins
+	print("admin", input.user)
+}
+
+greeting := greet(input.user)
+`
+
+	ctx := t.Context()
+	store := inmem.NewFromObject(map[string]any{"admins": []any{"alice"}})
+
+	pq, err := New(
+		Query("data.test.allow = x; y = data.test.greeting"),
+		Target("plan"),
+		Module("test.rego", mod),
+		Store(store),
+		EnablePrintStatements(true),
+		Function1(&Function{
+			Name: "greet",
+			Decl: types.NewFunction(types.Args(types.S), types.S),
+		}, func(_ BuiltinContext, a *ast.Term) (*ast.Term, error) {
+			return ast.StringTerm("hello " + string(a.Value.(ast.String))), nil
+		}),
+	).PrepareForEval(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	assertPreparedEvalQueryEval(t, pq, []EvalOption{
+		EvalInput(map[string]any{"user": "alice"}),
+		EvalPrintHook(topdown.NewPrintHook(&buf)),
+	}, `[[true, true]]`)
+
+	if exp, act := "admin alice\n", buf.String(); exp != act {
+		t.Fatalf("expected print output %q, got %q", exp, act)
+	}
+
+	rs, err := pq.Eval(ctx, EvalInput(map[string]any{"user": "bob"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"x":false,"y":"hello bob"}`, string(util.MustMarshalJSON(rs[0].Bindings)); exp != act {
+		t.Fatalf("expected bindings %v, got %v", exp, act)
+	}
+
+	// data is read on every evaluation
+	if err := storage.WriteOne(ctx, store, storage.AddOp, storage.MustParsePath("/admins/-"), "bob"); err != nil {
+		t.Fatal(err)
+	}
+	assertPreparedEvalQueryEval(t, pq, []EvalOption{
+		EvalInput(map[string]any{"user": "bob"}),
+	}, `[[true, true]]`)
+}
+
+func TestPlanTargetErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	_, err := New(
+		Query("data.test.p"),
+		Target("plan"),
+		Module("test.rego", "package test\n\np := input.x\n\np := input.y\n"),
+		Input(map[string]any{"x": 1, "y": 2}),
+	).Eval(ctx)
+
+	var tdErr *topdown.Error
+	if !errors.As(err, &tdErr) || tdErr.Code != topdown.ConflictErr {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if tdErr.Location == nil || tdErr.Location.File != "test.rego" {
+		t.Fatalf("expected location in test.rego, got %v", tdErr.Location)
+	}
+
+	rs, err := New(
+		Query(`x := to_number("foo")`),
+		Target("plan"),
+	).Eval(ctx)
+	if err != nil || len(rs) != 0 {
+		t.Fatalf("expected undefined result, got %v (err: %v)", rs, err)
+	}
+
+	_, err = New(
+		Query(`x := to_number("foo")`),
+		Target("plan"),
+		StrictBuiltinErrors(true),
+	).Eval(ctx)
+	if !errors.As(err, &tdErr) || tdErr.Code != topdown.BuiltinErr {
+		t.Fatalf("expected builtin error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/open-policy-agent/opa/internal/dap"
+	"github.com/open-policy-agent/opa/v1/debug"
+)
+
+// validateDebugAddr ensures the attach token isn't sent in cleartext over the
+// network: the debug adapter is served over TLS with the server's certificate,
+// and only loopback addresses can be used without one.
+func validateDebugAddr(addr string, useTLS bool) error {
+	if useTLS {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid debug address: %w", err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("debug address %q must be a loopback address unless a TLS certificate is configured", addr)
+}
+
+// startDebugAdapter serves the Debug Adapter Protocol on the configured debug
+// address, so that authenticated clients can attach to decisions served by the
+// server. The listener is closed when ctx is cancelled.
+func (rt *Runtime) startDebugAdapter(ctx context.Context) error {
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", rt.Params.DebugAddr)
+	if err != nil {
+		return err
+	}
+
+	if cfg := rt.server.DebugTLSConfig(); cfg != nil {
+		l = tls.NewListener(l, cfg)
+	}
+
+	go func() {
+		if err := dap.Serve(ctx, l, rt.logger, dap.WithAttacher(rt.attachDebugSession)); err != nil {
+			rt.logger.WithFields(map[string]any{"err": err}).Error("Debug adapter failed.")
+		}
+	}()
+
+	return nil
+}
+
+func (rt *Runtime) attachDebugSession(ctx context.Context, d debug.Debugger, args dap.AttachArguments) (debug.Session, error) {
+	if subtle.ConstantTimeCompare([]byte(args.Token), []byte(rt.Params.DebugToken)) != 1 {
+		rt.logger.Warn("Rejected debug client: invalid token.")
+		return nil, errors.New("unauthorized: invalid token")
+	}
+
+	s, err := d.Attach(ctx, debug.AttachProperties{
+		StopOnEntry:  args.StopOnEntry,
+		StopOnResult: args.StopOnResult,
+		StopOnFail:   args.StopOnFail,
+		Store:        rt.Store,
+		Timeout:      rt.Params.DebugTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rt.logger.Info("Debug client attached.")
+
+	return &serverDebugSession{
+		AttachedSession: s,
+		detach:          rt.server.AttachDebugSession(s),
+	}, nil
+}
+
+// serverDebugSession stops offering decisions to the session once it's terminated.
+type serverDebugSession struct {
+	debug.AttachedSession
+	detach func()
+}
+
+func (s *serverDebugSession) Terminate() error {
+	s.detach()
+	return s.AttachedSession.Terminate()
+}
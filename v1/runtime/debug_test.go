@@ -0,0 +1,212 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/dap"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/util/test"
+)
+
+type debugTestClient struct {
+	t     *testing.T
+	codec *dap.Codec
+	r     *bufio.Reader
+	seq   int
+}
+
+func (c *debugTestClient) request(command string, args any) map[string]any {
+	c.t.Helper()
+
+	c.seq++
+	req := map[string]any{"seq": c.seq, "type": "request", "command": command}
+	if args != nil {
+		req["arguments"] = args
+	}
+	if err := c.codec.WriteMessage(req); err != nil {
+		c.t.Fatal(err)
+	}
+
+	return c.next(func(msg map[string]any) bool {
+		return msg["type"] == dap.TypeResponse && msg["request_seq"] == float64(c.seq)
+	})
+}
+
+func (c *debugTestClient) next(match func(map[string]any) bool) map[string]any {
+	c.t.Helper()
+
+	for {
+		header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		n, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		bs := make([]byte, n)
+		if _, err := io.ReadFull(c.r, bs); err != nil {
+			c.t.Fatal(err)
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(bs, &msg); err != nil {
+			c.t.Fatal(err)
+		}
+		if match(msg) {
+			return msg
+		}
+	}
+}
+
+func TestRuntimeDebugAdapter(t *testing.T) {
+	ctx := t.Context()
+
+	policyPath := filepath.Join(t.TempDir(), "policy.rego")
+	policy := `package test
+
+allow if {
+	input.user == "alice"
+}
+`
+	if err := os.WriteFile(policyPath, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reserve a free port for the debug adapter.
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	debugAddr := l.Addr().String()
+	_ = l.Close()
+
+	params := NewParams()
+	params.Addrs = &[]string{"localhost:0"}
+	params.Paths = []string{policyPath}
+	params.Logger = logging.NewNoOpLogger()
+	params.DebugAddr = debugAddr
+	params.DebugToken = "secret"
+
+	rt, err := NewRuntime(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go rt.StartServer(ctx)
+
+	if !test.Eventually(t, 5*time.Second, func() bool {
+		return rt.ServerStatus() == ServerInitialized && len(rt.Addrs()) > 0
+	}) {
+		t.Fatal("Timed out waiting for server to start")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", debugAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	c := &debugTestClient{t: t, codec: dap.NewCodec(conn, conn), r: bufio.NewReader(conn)}
+
+	c.request("initialize", nil)
+
+	if resp := c.request("attach", map[string]any{"token": "wrong"}); resp["success"] != false {
+		t.Fatalf("expected attach with invalid token to fail, got %v", resp)
+	}
+
+	if resp := c.request("attach", map[string]any{"token": "secret"}); resp["success"] != true {
+		t.Fatalf("expected attach to succeed, got %v", resp)
+	}
+
+	resp := c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": policyPath},
+		"breakpoints": []map[string]any{{"line": 4, "condition": `input.user == "alice"`}},
+	})
+	bps := resp["body"].(map[string]any)["breakpoints"].([]any)
+	if len(bps) != 1 || bps[0].(map[string]any)["verified"] != true {
+		t.Fatalf("unexpected breakpoints: %v", bps)
+	}
+
+	c.request("configurationDone", nil)
+
+	decision := make(chan int, 1)
+	go func() {
+		url := "http://" + rt.Addrs()[0] + "/v1/data/test/allow"
+		resp, err := http.Post(url, "application/json", strings.NewReader(`{"input": {"user": "alice"}}`))
+		if err != nil {
+			decision <- 0
+			return
+		}
+		resp.Body.Close()
+		decision <- resp.StatusCode
+	}()
+
+	stopped := c.next(func(msg map[string]any) bool {
+		return msg["type"] == dap.TypeEvent && msg["event"] == "stopped"
+	})
+	body := stopped["body"].(map[string]any)
+	if body["reason"] != "breakpoint" {
+		t.Fatalf("expected breakpoint stop, got %v", body)
+	}
+
+	select {
+	case <-decision:
+		t.Fatal("expected decision to be paused")
+	default:
+	}
+
+	// Disconnecting releases the paused decision.
+	c.request("disconnect", nil)
+
+	select {
+	case code := <-decision:
+		if code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("decision did not complete after disconnecting")
+	}
+}
+
+func TestValidateDebugAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		tls     bool
+		wantErr bool
+	}{
+		{addr: "localhost:4712"},
+		{addr: "127.0.0.1:4712"},
+		{addr: "[::1]:4712"},
+		{addr: ":4712", wantErr: true},
+		{addr: "0.0.0.0:4712", wantErr: true},
+		{addr: "example.com:4712", wantErr: true},
+		{addr: "0.0.0.0:4712", tls: true},
+		{addr: "localhost", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.addr, func(t *testing.T) {
+			err := validateDebugAddr(tc.addr, tc.tls)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -117,6 +117,10 @@ type Params struct {
 	// for read-only diagnostic API's (/health, /metrics, etc)
 	DiagnosticAddrs *[]string
 
+	// GRPCAddrs are the listening addresses that the OPA server will bind to
+	// for the gRPC API.
+	GRPCAddrs *[]string
+
 	// H2CEnabled flag controls whether OPA will allow H2C (HTTP/2 cleartext) on
 	// HTTP listeners.
 	H2CEnabled bool
@@ -294,6 +298,17 @@ type Params struct {
 	// NDBCacheEnabled allows enabling the non-deterministic builtin cache globally.
 	NDBCacheEnabled bool
 
+	// DebugAddr, if set, is the TCP address on which the runtime serves the Debug Adapter Protocol in server mode.
+	// Debug clients can attach to the server, and debug decisions served by the v1 data API as they are made.
+	DebugAddr string
+
+	// DebugToken is the token debug clients must present in their attach request. It's required if DebugAddr is set.
+	DebugToken string
+
+	// DebugTimeout is the maximum amount of time a decision can be held by a debug client. Once exceeded, the
+	// decision is released and completes as usual. If not set, debug.DefaultAttachTimeout is used.
+	DebugTimeout time.Duration
+
 	Brand string
 }
 
@@ -645,12 +660,25 @@ func (rt *Runtime) Serve(ctx context.Context) (err error) {
 		rt.Params.DiagnosticAddrs = &[]string{}
 	}
 
-	rt.logger.WithFields(map[string]any{
+	fields := map[string]any{
 		"addrs":            *rt.Params.Addrs,
 		"diagnostic-addrs": *rt.Params.DiagnosticAddrs,
-	}).Info("%s", serverInitializingMessage)
+	}
+	if rt.Params.GRPCAddrs != nil && len(*rt.Params.GRPCAddrs) > 0 {
+		fields["grpc-addrs"] = *rt.Params.GRPCAddrs
+	}
+	rt.logger.WithFields(fields).Info("%s", serverInitializingMessage)
+
+	if rt.Params.DebugAddr != "" && rt.Params.DebugToken == "" {
+		return errors.New("a debug token must be configured to serve the debug adapter")
+	}
+	if rt.Params.DebugAddr != "" {
+		if err := validateDebugAddr(rt.Params.DebugAddr, rt.Params.Certificate != nil); err != nil {
+			return err
+		}
+	}
 
-	if rt.Params.Authorization == server.AuthorizationOff && rt.Params.Authentication == server.AuthenticationToken {
+	if rt.Params.Authorization == server.AuthorizationOff && (rt.Params.Authentication == server.AuthenticationToken || rt.Params.Authentication == server.AuthenticationJWT) {
 		rt.logger.Error("Token authentication enabled without authorization. Authentication will be ineffective. See https://www.openpolicyagent.org/docs/latest/security/#authentication-and-authorization for more information.")
 	}
 
@@ -709,6 +737,10 @@ func (rt *Runtime) Serve(ctx context.Context) (err error) {
 		rt.server = rt.server.WithDiagnosticAddresses(*rt.Params.DiagnosticAddrs)
 	}
 
+	if rt.Params.GRPCAddrs != nil {
+		rt.server = rt.server.WithGRPCAddresses(*rt.Params.GRPCAddrs)
+	}
+
 	if rt.Params.UnixSocketPerm != nil {
 		rt.server = rt.server.WithUnixSocketPermission(rt.Params.UnixSocketPerm)
 	}
@@ -773,6 +805,14 @@ func (rt *Runtime) Serve(ctx context.Context) (err error) {
 		return err
 	}
 
+	if rt.Params.DebugAddr != "" {
+		if err := rt.startDebugAdapter(ctx); err != nil {
+			rt.logger.WithFields(map[string]any{"err": err}).Error("Unable to start debug adapter.")
+			return err
+		}
+		rt.logger.Warn("Debug adapter enabled on %s. Debug clients can pause and inspect decisions.", rt.Params.DebugAddr)
+	}
+
 	errc := make(chan error)
 	for _, loop := range loops {
 		go func(serverLoop func() error) {
@@ -834,6 +874,17 @@ func (rt *Runtime) DiagnosticAddrs() []string {
 	return rt.server.DiagnosticAddrs()
 }
 
+// GRPCAddrs returns a list of gRPC API addresses that the runtime is listening
+// on (when in server mode). Returns an empty list if it hasn't started
+// listening.
+func (rt *Runtime) GRPCAddrs() []string {
+	if rt.server == nil {
+		return nil
+	}
+
+	return rt.server.GRPCAddrs()
+}
+
 // StartREPL starts the runtime in REPL mode. This function will block the calling goroutine.
 func (rt *Runtime) StartREPL(ctx context.Context) error {
 	if err := rt.Manager.Start(ctx); err != nil {
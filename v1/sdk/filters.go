@@ -0,0 +1,198 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/compile"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/rego"
+	rego_compile "github.com/open-policy-agent/opa/v1/rego/compile"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/topdown/print"
+	"github.com/open-policy-agent/opa/v1/version"
+)
+
+// filtersDecisionLogType is injected under custom.type in the decision log
+// entry, like the Compile API does.
+const filtersDecisionLogType = "open-policy-agent/compile"
+
+// FiltersOptions contains parameters for compiling a policy into data filters.
+type FiltersOptions struct {
+	Now                 time.Time           // specifies wallclock time used for time.now_ns(), decision log timestamp, etc.
+	Path                string              // specifies name of the filter rule to compile (e.g., filters/include)
+	Input               any                 // specifies value of the input document to evaluate policy with
+	Unknowns            []string            // specifies the unknowns; if not set, they're taken from the rule's `compile` annotations
+	Target              string              // specifies the target of the filter: ucast, sql, mongo, elasticsearch, or a custom target
+	Dialect             string              // specifies the dialect of the target, e.g. postgresql for sql, or prisma for ucast
+	Mappings            map[string]any      // specifies the mappings of tables and columns, like `targetSQLTableMappings` of the Compile API
+	MaskRule            string              // specifies the rule to evaluate for column masks; if not set, it's taken from the rule's `compile` annotations
+	DisableInlining     []string            // specifies the rules to not inline during partial evaluation
+	StrictBuiltinErrors bool                // treat built-in function errors as fatal
+	Tracer              topdown.QueryTracer // specifies the tracer to use for evaluation, optional
+	Metrics             metrics.Metrics     // specifies the metrics to use for preparing and evaluation, optional
+	Instrument          bool                // if true, instrumentation will be enabled
+	DecisionID          string              // the identifier for this decision; if not set, a globally unique identifier will be generated
+}
+
+// FiltersResult contains the filter compiled from a policy.
+type FiltersResult struct {
+	ID         string             // decision ID
+	Query      any                // the filter query, e.g. a SQL WHERE clause; nil if nothing is allowed
+	Masks      map[string]any     // the column masks, if a mask rule is used
+	Provenance types.ProvenanceV1 // wraps the bundle build/version information
+}
+
+// Filters compiles the filter rule at options.Path into a filter for the
+// requested target and dialect, the same way the Compile API does for
+// requests to /v1/compile/{path}. This function is threadsafe.
+//
+// If the policy can't be translated into a filter for the target, the returned
+// error is an ast.Errors, with one error per construct that can't be
+// translated.
+func (opa *OPA) Filters(ctx context.Context, options FiltersOptions) (*FiltersResult, error) {
+	if options.Path == "" {
+		return nil, errors.New("missing filter rule path")
+	}
+
+	record := server.Info{
+		Timestamp:  options.Now,
+		Path:       options.Path,
+		Input:      &options.Input,
+		Metrics:    options.Metrics,
+		DecisionID: options.DecisionID,
+		Custom: map[string]any{
+			"type":    filtersDecisionLogType,
+			"target":  options.Target,
+			"dialect": options.Dialect,
+		},
+	}
+
+	var filter rego_compile.Filter
+	var provenance types.ProvenanceV1
+
+	decision, err := opa.executeTransaction(
+		ctx,
+		&record,
+		func(s state, _ *DecisionResult) {
+			filter, provenance, record.InputAST, record.Bundles, record.Error = filters(ctx, filtersArgs{
+				runtime:             s.manager.Info,
+				printHook:           s.manager.PrintHook(),
+				compiler:            s.manager.GetCompiler(),
+				store:               s.manager.Store,
+				txn:                 record.Txn,
+				now:                 record.Timestamp,
+				options:             options,
+				m:                   record.Metrics,
+				strictBuiltinErrors: options.StrictBuiltinErrors,
+			})
+			if record.Error == nil && filter.Query != nil {
+				var result any = map[string]any{"query": filter.Query, "masks": filter.Masks}
+				record.Results = &result
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FiltersResult{
+		ID:         decision.ID,
+		Query:      filter.Query,
+		Masks:      filter.Masks,
+		Provenance: provenance,
+	}, record.Error
+}
+
+type filtersArgs struct {
+	runtime             *ast.Term
+	compiler            *ast.Compiler
+	printHook           print.Hook
+	store               storage.Store
+	txn                 storage.Transaction
+	now                 time.Time
+	options             FiltersOptions
+	m                   metrics.Metrics
+	strictBuiltinErrors bool
+}
+
+func filters(ctx context.Context, args filtersArgs) (rego_compile.Filter, types.ProvenanceV1, ast.Value, map[string]server.BundleInfo, error) {
+	provenance := types.ProvenanceV1{
+		Version: version.Version,
+		Bundles: make(map[string]types.ProvenanceBundleV1),
+	}
+
+	bundles, err := bundles(ctx, args.store, args.txn)
+	if err != nil {
+		return rego_compile.Filter{}, provenance, nil, nil, err
+	}
+	for b, info := range bundles {
+		provenance.Bundles[b] = types.ProvenanceBundleV1{
+			Revision: info.Revision,
+		}
+	}
+
+	inputAST, err := ast.InterfaceToValue(args.options.Input)
+	if err != nil {
+		return rego_compile.Filter{}, provenance, nil, bundles, err
+	}
+
+	unknowns := make([]*ast.Term, len(args.options.Unknowns))
+	for i := range args.options.Unknowns {
+		unknowns[i], err = ast.ParseTerm(args.options.Unknowns[i])
+		if err != nil {
+			return rego_compile.Filter{}, provenance, inputAST, bundles, fmt.Errorf("invalid unknown: %w", err)
+		}
+	}
+
+	maskRule, err := compile.ParseMaskRule(args.options.Path, args.options.MaskRule)
+	if err != nil {
+		return rego_compile.Filter{}, provenance, inputAST, bundles, err
+	}
+
+	prepared, err := rego_compile.New(
+		rego_compile.Target(args.options.Target, args.options.Dialect),
+		rego_compile.Path(args.options.Path),
+		rego_compile.ParsedUnknowns(unknowns...),
+		rego_compile.MaskRule(maskRule),
+		rego_compile.Mappings(args.options.Mappings),
+		rego_compile.Metrics(args.m),
+		rego_compile.Annotations(args.compiler),
+		rego_compile.Rego(
+			rego.Time(args.now),
+			rego.Store(args.store),
+			rego.Compiler(args.compiler),
+			rego.Transaction(args.txn),
+			rego.Runtime(args.runtime),
+			rego.PrintHook(args.printHook),
+			rego.DisableInlining(args.options.DisableInlining),
+			rego.StrictBuiltinErrors(args.strictBuiltinErrors),
+			rego.QueryTracer(args.options.Tracer),
+			rego.Instrument(args.options.Instrument),
+		),
+	).Prepare(ctx)
+	if err != nil {
+		return rego_compile.Filter{}, provenance, inputAST, bundles, err
+	}
+
+	fs, err := prepared.Compile(ctx,
+		rego.EvalTime(args.now),
+		rego.EvalParsedInput(inputAST),
+		rego.EvalTransaction(args.txn),
+	)
+	if err != nil {
+		return rego_compile.Filter{}, provenance, inputAST, bundles, err
+	}
+
+	return fs.For(args.options.Target, args.options.Dialect), provenance, inputAST, bundles, nil
+}
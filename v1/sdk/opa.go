@@ -12,9 +12,12 @@ import (
 	"crypto/rand"
 	"fmt"
 	"maps"
+	"runtime"
 	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/open-policy-agent/opa/internal/ref"
 	"github.com/open-policy-agent/opa/internal/uuid"
 	"github.com/open-policy-agent/opa/v1/ast"
@@ -300,13 +303,73 @@ func (opa *OPA) Stop(ctx context.Context) {
 
 // Decision returns a named decision. This function is threadsafe.
 func (opa *OPA) Decision(ctx context.Context, options DecisionOptions) (*DecisionResult, error) {
+	opa.mtx.Lock()
+	s := *opa.state
+	opa.mtx.Unlock()
+
+	return opa.decision(ctx, s, nil, "", options)
+}
+
+// DecisionBatch evaluates several named decisions. All decisions are evaluated
+// against the same storage transaction and share prepared queries, and up to
+// options.Concurrency of them are evaluated at the same time. Each decision is
+// logged individually, with the batch ID as its batch decision ID. This
+// function is threadsafe.
+//
+// The returned error is only non-nil if the batch could not be evaluated at
+// all; errors for individual decisions are reported in the result.
+func (opa *OPA) DecisionBatch(ctx context.Context, options DecisionBatchOptions) (*DecisionBatchResult, error) {
+	batchID := options.BatchDecisionID
+	if batchID == "" {
+		id, err := uuid.New(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		batchID = id
+	}
+
+	opa.mtx.Lock()
+	s := *opa.state
+	opa.mtx.Unlock()
+
+	txn, err := s.manager.Store.NewTransaction(ctx, storage.TransactionParams{})
+	if err != nil {
+		return nil, err
+	}
+	defer s.manager.Store.Abort(ctx, txn)
+
+	result := &DecisionBatchResult{
+		ID:      batchID,
+		Results: make([]DecisionBatchItem, len(options.Decisions)),
+	}
+
+	var g errgroup.Group
+	g.SetLimit(cmp.Or(options.Concurrency, runtime.GOMAXPROCS(0)))
+
+	for i := range options.Decisions {
+		g.Go(func() error {
+			r, err := opa.decision(ctx, s, txn, batchID, options.Decisions[i])
+			result.Results[i] = DecisionBatchItem{Result: r, Error: err}
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return result, nil
+}
+
+// decision evaluates a single decision using the given state. If txn is nil,
+// a new transaction is opened for the evaluation.
+func (opa *OPA) decision(ctx context.Context, s state, txn storage.Transaction, batchID string, options DecisionOptions) (*DecisionResult, error) {
 	record := server.Info{
-		Timestamp:      options.Now,
-		Path:           options.Path,
-		Input:          &options.Input,
-		NDBuiltinCache: &options.NDBCache,
-		Metrics:        options.Metrics,
-		DecisionID:     options.DecisionID,
+		Timestamp:       options.Now,
+		Path:            options.Path,
+		Input:           &options.Input,
+		NDBuiltinCache:  &options.NDBCache,
+		Metrics:         options.Metrics,
+		DecisionID:      options.DecisionID,
+		BatchDecisionID: batchID,
 	}
 
 	// Only use non-deterministic builtins cache if it's available.
@@ -320,8 +383,10 @@ func (opa *OPA) Decision(ctx context.Context, options DecisionOptions) (*Decisio
 	// TODO: make extractor configurable via SDK options
 	tracker := &topdown.EvaluatedRuleTracker{}
 
-	result, err := opa.executeTransaction(
+	result, err := opa.execute(
 		ctx,
+		s,
+		txn,
 		&record,
 		func(s state, result *DecisionResult) {
 			result.Result, result.Provenance, record.InputAST, record.Bundles, record.Error = evaluate(ctx, evalArgs{
@@ -380,6 +445,28 @@ type DecisionOptions struct {
 	HTTPRoundTripper topdown.CustomizeRoundTripper
 }
 
+// DecisionBatchOptions contains parameters for evaluating a batch of
+// decisions.
+type DecisionBatchOptions struct {
+	Decisions       []DecisionOptions // specifies the decisions to evaluate
+	Concurrency     int               // maximum number of decisions evaluated at the same time; defaults to GOMAXPROCS
+	BatchDecisionID string            // the identifier for this batch; if not set, a globally unique identifier will be generated
+}
+
+// DecisionBatchResult contains the output of a batch of decisions.
+type DecisionBatchResult struct {
+	ID      string              // provides the identifier for this batch (which is included in the decision logs.)
+	Results []DecisionBatchItem // provides the outcome of each decision, in the order of DecisionBatchOptions.Decisions
+}
+
+// DecisionBatchItem contains the outcome of a single decision in a batch. The
+// Result and Error fields are set the same way as the return values of
+// Decision.
+type DecisionBatchItem struct {
+	Result *DecisionResult
+	Error  error
+}
+
 // DecisionResult contains the output of query evaluation.
 type DecisionResult struct {
 	ID         string             // provides the identifier for this decision (which is included in the decision log.)
@@ -388,6 +475,16 @@ type DecisionResult struct {
 }
 
 func (opa *OPA) executeTransaction(ctx context.Context, record *server.Info, work func(state, *DecisionResult)) (*DecisionResult, error) {
+	opa.mtx.Lock()
+	s := *opa.state
+	opa.mtx.Unlock()
+
+	return opa.execute(ctx, s, nil, record, work)
+}
+
+// execute runs work using the given state and logs the resulting decision. If
+// txn is nil, a new transaction is opened for work and closed afterwards.
+func (opa *OPA) execute(ctx context.Context, s state, txn storage.Transaction, record *server.Info, work func(state, *DecisionResult)) (*DecisionResult, error) {
 	record.Metrics = util.Or(record.Metrics, metrics.New)
 	record.Metrics.Timer(metrics.SDKDecisionEval).Start()
 
@@ -401,10 +498,6 @@ func (opa *OPA) executeTransaction(ctx context.Context, record *server.Info, wor
 
 	result := &DecisionResult{ID: record.DecisionID}
 
-	opa.mtx.Lock()
-	s := *opa.state
-	opa.mtx.Unlock()
-
 	if record.Timestamp.IsZero() {
 		record.Timestamp = time.Now().UTC()
 	}
@@ -413,11 +506,16 @@ func (opa *OPA) executeTransaction(ctx context.Context, record *server.Info, wor
 		record.Path = *s.manager.GetConfig().DefaultDecision
 	}
 
-	record.Txn, record.Error = s.manager.Store.NewTransaction(ctx, storage.TransactionParams{})
-
-	if record.Error == nil {
-		defer s.manager.Store.Abort(ctx, record.Txn)
+	if txn != nil {
+		record.Txn = txn
 		work(s, result)
+	} else {
+		record.Txn, record.Error = s.manager.Store.NewTransaction(ctx, storage.TransactionParams{})
+
+		if record.Error == nil {
+			defer s.manager.Store.Abort(ctx, record.Txn)
+			work(s, result)
+		}
 	}
 
 	record.Metrics.Timer(metrics.SDKDecisionEval).Stop()
@@ -620,9 +718,6 @@ func evaluate(ctx context.Context, args evalArgs) (any, types.ProvenanceV1, ast.
 			rego.Instrument(args.instrument),
 			rego.Runtime(args.runtime),
 		}
-		if args.evaluatedRules != nil {
-			opts = append(opts, rego.EvaluatedRuleTracker(args.evaluatedRules))
-		}
 		pq, err := rego.New(opts...).PrepareForEval(ctx)
 		if err != nil {
 			return nil, err
@@ -651,6 +746,9 @@ func evaluate(ctx context.Context, args evalArgs) (any, types.ProvenanceV1, ast.
 		rego.EvalQueryTracer(args.profiler),
 		rego.EvalInstrument(args.instrument),
 		rego.EvalHTTPRoundTripper(args.httpRoundTripper),
+		// The tracker is set per evaluation because the prepared query is
+		// cached and shared by concurrent decisions.
+		rego.EvalEvaluatedRuleTracker(args.evaluatedRules),
 	)
 	if err != nil {
 		return nil, provenance, inputAST, bundles, err
@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -400,6 +401,121 @@ loopback = input
 	}
 }
 
+func TestDecisionBatch(t *testing.T) {
+
+	ctx := t.Context()
+
+	server := sdktest.MustNewServer(
+		sdktest.MockBundle("/bundles/bundle.tar.gz", map[string]string{
+			"main.rego": `
+package system
+
+main = true
+
+loopback = input
+
+conflict = input.x
+conflict = input.y
+`,
+		}),
+	)
+
+	defer server.Stop()
+
+	config := fmt.Sprintf(`{
+		"services": {
+			"test": {
+				"url": %q
+			}
+		},
+		"bundles": {
+			"test": {
+				"resource": "/bundles/bundle.tar.gz"
+			}
+		},
+		"decision_logs": {
+			"console": true
+		}
+	}`, server.URL())
+
+	testLogger := loggingtest.New()
+	opa, err := sdk.New(ctx, sdk.Options{
+		Config:        strings.NewReader(config),
+		ConsoleLogger: testLogger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer opa.Stop(ctx)
+
+	options := sdk.DecisionBatchOptions{
+		BatchDecisionID: "batch-1",
+		Concurrency:     2,
+	}
+	for i := range 10 {
+		options.Decisions = append(options.Decisions, sdk.DecisionOptions{
+			Path:       "/system/loopback",
+			Input:      map[string]any{"i": i},
+			DecisionID: fmt.Sprintf("decision-%d", i),
+		})
+	}
+	options.Decisions = append(options.Decisions,
+		sdk.DecisionOptions{Path: "/system/main"},
+		sdk.DecisionOptions{Path: "/system/undefined"},
+		sdk.DecisionOptions{Path: "/system/conflict", Input: map[string]any{"x": 1, "y": 2}},
+	)
+
+	result, err := opa.DecisionBatch(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ID != "batch-1" {
+		t.Fatalf("expected batch ID batch-1 but got %q", result.ID)
+	}
+
+	if len(result.Results) != len(options.Decisions) {
+		t.Fatalf("expected %d results but got %d", len(options.Decisions), len(result.Results))
+	}
+
+	for i := range 10 {
+		item := result.Results[i]
+		if item.Error != nil {
+			t.Fatalf("unexpected error for decision %d: %v", i, item.Error)
+		}
+		if exp := fmt.Sprintf("decision-%d", i); item.Result.ID != exp {
+			t.Errorf("expected decision ID %q but got %q", exp, item.Result.ID)
+		}
+		if exp := map[string]any{"i": json.Number(strconv.Itoa(i))}; !reflect.DeepEqual(item.Result.Result, exp) {
+			t.Errorf("expected %v but got %v", exp, item.Result.Result)
+		}
+	}
+
+	if item := result.Results[10]; item.Error != nil || item.Result.Result != true {
+		t.Errorf("expected true but got: %v (err: %v)", item.Result, item.Error)
+	}
+
+	if item := result.Results[11]; !sdk.IsUndefinedErr(item.Error) {
+		t.Errorf("expected undefined error but got: %v", item.Error)
+	}
+
+	if item := result.Results[12]; item.Error == nil || !strings.Contains(item.Error.Error(), "eval_conflict_error") {
+		t.Errorf("expected conflict error but got: %v", item.Error)
+	}
+
+	entries := testLogger.Entries()
+	if len(entries) != len(options.Decisions) {
+		t.Fatalf("expected %d decision log entries but got %d", len(options.Decisions), len(entries))
+	}
+
+	for _, e := range entries {
+		if e.Fields["batch_decision_id"] != "batch-1" {
+			t.Errorf("expected batch_decision_id batch-1 but got %v", e.Fields["batch_decision_id"])
+		}
+	}
+}
+
 func TestDecisionWithStrictBuiltinErrors(t *testing.T) {
 
 	ctx := t.Context()
@@ -970,6 +1086,106 @@ allow if {
 
 }
 
+func TestFilters(t *testing.T) {
+	ctx := t.Context()
+
+	server := sdktest.MustNewServer(
+		sdktest.MockBundle("/bundles/bundle.tar.gz", map[string]string{
+			"filters.rego": `
+package filters
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+#   mask_rule: masks
+include if input.fruits.name in input.names
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+expensive if input.fruits.price > input.budget * 2
+expensive if is_number(input.fruits.price)
+
+masks.fruits.supplier.replace.value := "***"
+`,
+		}),
+	)
+
+	defer server.Stop()
+
+	config := fmt.Sprintf(`{
+		"services": {
+			"test": {
+				"url": %q
+			}
+		},
+		"bundles": {
+			"test": {
+				"resource": "/bundles/bundle.tar.gz"
+			}
+		},
+		"decision_logs": {
+			"console": true
+		}
+	}`, server.URL())
+
+	testLogger := loggingtest.New()
+	opa, err := sdk.New(ctx, sdk.Options{
+		Config:        strings.NewReader(config),
+		ConsoleLogger: testLogger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer opa.Stop(ctx)
+
+	result, err := opa.Filters(ctx, sdk.FiltersOptions{
+		Path:     "filters/include",
+		Input:    map[string]any{"names": []string{"apple", "banana"}},
+		Target:   "sql",
+		Dialect:  "postgresql",
+		Mappings: map[string]any{"fruits": map[string]any{"$self": "fruit_table"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "WHERE fruit_table.name IN (E'apple', E'banana')", result.Query; exp != act {
+		t.Errorf("expected query %q, got %q", exp, act)
+	}
+	expMasks := map[string]any{"fruits": map[string]any{"supplier": map[string]any{"replace": map[string]any{"value": "***"}}}}
+	if diff := cmp.Diff(expMasks, result.Masks); diff != "" {
+		t.Errorf("unexpected masks (-want, +got):\n%s", diff)
+	}
+
+	entries := testLogger.Entries()
+	if l := len(entries); l != 1 {
+		t.Fatalf("expected %v but got %v", 1, l)
+	}
+	if entries[0].Fields["decision_id"] != result.ID {
+		t.Errorf("expected decision ID %v, got %v", result.ID, entries[0].Fields["decision_id"])
+	}
+	if custom, ok := entries[0].Fields["custom"].(map[string]any); !ok || custom["type"] != "open-policy-agent/compile" {
+		t.Errorf("expected custom type in decision log, got %v", entries[0].Fields["custom"])
+	}
+
+	_, err = opa.Filters(ctx, sdk.FiltersOptions{
+		Path:    "filters/expensive",
+		Input:   map[string]any{"budget": 10},
+		Target:  "ucast",
+		Dialect: "linq",
+	})
+	var errs ast.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ast.Errors, got %v", err)
+	}
+	if exp, act := "invalid builtin `is_number`", errs[0].Message; exp != act {
+		t.Errorf("expected error %q, got %q", exp, act)
+	}
+}
+
 func TestPartialWithStrictBuiltinErrors(t *testing.T) {
 
 	ctx := t.Context()
@@ -91,8 +91,15 @@ func URLPathExpectsBodyFunc(urlPathExpectsBodyFunc []func(string, []any) bool) f
 
 // NewBasic returns a new Basic object.
 func NewBasic(inner http.Handler, compiler func() *ast.Compiler, store storage.Store, opts ...func(*Basic)) http.Handler {
+	b := New(compiler, store, opts...)
+	b.inner = inner
+	return b
+}
+
+// New returns a new Basic object that authorizes requests with Authorize, for
+// transports other than HTTP.
+func New(compiler func() *ast.Compiler, store storage.Store, opts ...func(*Basic)) *Basic {
 	b := &Basic{
-		inner:    inner,
 		compiler: compiler,
 		store:    store,
 	}
@@ -104,6 +111,40 @@ func NewBasic(inner http.Handler, compiler func() *ast.Compiler, store storage.S
 	return b
 }
 
+// Request is a request to authorize with Authorize. It describes the HTTP
+// request equivalent to the request, which is what the authorization policy
+// gets as input.
+type Request struct {
+	Method string
+	Path   string // escaped URL path
+	Query  url.Values
+	Header http.Header
+	Body   any // parsed body, only passed to the policy if the path expects one
+}
+
+// Authorize evaluates the authorization decision for req, made by the caller
+// associated with ctx. If the request isn't allowed, it returns the status and
+// error response the request is rejected with.
+func (b *Basic) Authorize(ctx context.Context, req *Request) (int, *types.ErrorV1) {
+	path, err := parsePath(req.Path)
+	if err != nil {
+		return http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, "%s", err.Error())
+	}
+
+	query := req.Query
+	if len(query) == 0 {
+		query = emptyQuery
+	}
+
+	method := strings.ToUpper(req.Method)
+	input := newInput(ctx, method, path, query, req.Header)
+	if req.Body != nil && (expectBody(method, path) || checkExtraExpectedReqBodyPaths(b.urlPathExpectsBodyFunc, method, path)) {
+		input["body"] = req.Body
+	}
+
+	return b.decide(ctx, input)
+}
+
 func (b *Basic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// TODO(tsandall): Pass AST value as input instead of Go value to avoid unnecessary
 	// conversions.
@@ -113,6 +154,17 @@ func (b *Basic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if status, e := b.decide(r.Context(), input); e != nil {
+		writer.Error(w, status, e)
+		return
+	}
+
+	b.inner.ServeHTTP(w, r)
+}
+
+// decide evaluates the authorization decision for input, returning the status
+// and error response of requests that aren't allowed.
+func (b *Basic) decide(ctx context.Context, input any) (int, *types.ErrorV1) {
 	rego := rego.New(
 		rego.Query(b.decision().String()),
 		rego.Compiler(b.compiler()),
@@ -125,43 +177,37 @@ func (b *Basic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		rego.InterQueryBuiltinValueCache(b.interQueryValueCache),
 	)
 
-	rs, err := rego.Eval(r.Context())
+	rs, err := rego.Eval(ctx)
 	if err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return writer.AutoStatus(err)
 	}
 
 	if len(rs) == 0 {
 		// Authorizer was configured but no policy defined. This indicates an internal error or misconfiguration.
-		writer.Error(w, http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, types.MsgUnauthorizedUndefinedError))
-		return
+		return http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, types.MsgUnauthorizedUndefinedError)
 	}
 
 	switch allowed := rs[0].Expressions[0].Value.(type) {
 	case bool:
 		if allowed {
-			b.inner.ServeHTTP(w, r)
-			return
+			return http.StatusOK, nil
 		}
 	case map[string]any:
 		if decision, ok := allowed["allowed"]; ok {
 			if allow, ok := decision.(bool); ok && allow {
-				b.inner.ServeHTTP(w, r)
-				return
+				return http.StatusOK, nil
 			}
 			if reason, ok := allowed["reason"]; ok {
 				message, ok := reason.(string)
 				if ok {
-					writer.Error(w, http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, "%s", message))
-					return
+					return http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, "%s", message)
 				}
 			}
 		} else {
-			writer.Error(w, http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, types.MsgUndefinedError))
-			return
+			return http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, types.MsgUndefinedError)
 		}
 	}
-	writer.Error(w, http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, types.MsgUnauthorizedError))
+	return http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, types.MsgUnauthorizedError)
 }
 
 var emptyQuery = url.Values{}
@@ -189,12 +235,7 @@ func makeInput(r *http.Request, extraPaths []func(string, []any) bool) (*http.Re
 		}
 	}
 
-	input := map[string]any{
-		"path":    path,
-		"method":  method,
-		"params":  query,
-		"headers": r.Header,
-	}
+	input := newInput(r.Context(), method, path, query, r.Header)
 
 	if len(rawBody) > 0 {
 		var body any
@@ -213,17 +254,36 @@ func makeInput(r *http.Request, extraPaths []func(string, []any) bool) (*http.Re
 		r = r.WithContext(ctx)
 	}
 
-	identity, ok := identifier.Identity(r)
+	return r, input, nil
+}
+
+// newInput returns the input document of a request without its body, with the
+// identity of the caller associated with ctx.
+func newInput(ctx context.Context, method string, path []any, query url.Values, header http.Header) map[string]any {
+	input := map[string]any{
+		"path":    path,
+		"method":  method,
+		"params":  query,
+		"headers": header,
+	}
+
+	identity, ok := identifier.IdentityFromContext(ctx)
 	if ok {
 		input["identity"] = identity
 	}
 
-	clientCertificates, ok := identifier.ClientCertificates(r)
+	// With JWT authentication, the identity is the set of verified claims.
+	claims, ok := identifier.ClaimsFromContext(ctx)
+	if ok {
+		input["identity"] = claims
+	}
+
+	clientCertificates, ok := identifier.ClientCertificatesFromContext(ctx)
 	if ok {
 		input["client_certificates"] = clientCertificates
 	}
 
-	return r, input, nil
+	return input
 }
 
 var dataAPIVersions = map[string]bool{
@@ -239,6 +299,9 @@ func expectBody(method string, path []any) bool {
 		} else if len(path) >= 2 {
 			s1 := path[0].(string)
 			s2 := path[1].(string)
+			if s1 == "v1" && s2 == "batch" {
+				return len(path) >= 3 && path[2].(string) == "data"
+			}
 			return dataAPIVersions[s1] && s2 == "data"
 		}
 	}
@@ -332,6 +332,75 @@ func TestMakeInput(t *testing.T) {
 	}
 }
 
+func TestMakeInputWithClaims(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://localhost:8181/v1/data", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = identifier.SetClaims(req, map[string]any{"iss": "https://idp.example.com", "sub": "bob"})
+
+	_, result, err := makeInput(req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{"iss":"https://idp.example.com","sub":"bob"}`
+	if act := string(util.MustMarshalJSON(result.(map[string]any)["identity"])); exp != act {
+		t.Fatalf("Expected identity %v but got %v", exp, act)
+	}
+}
+
+func TestBasicAuthorize(t *testing.T) {
+	ctx := t.Context()
+	compiler := func() *ast.Compiler {
+		return ast.MustCompileModules(map[string]string{"authz.rego": `package system.authz
+
+default allow := false
+
+allow if {
+	input.method == "POST"
+	input.path == ["v1", "data", "a b"]
+	input.params.pretty == ["true"]
+	input.headers["X-Tenant"] == ["acme"]
+	input.identity == "bob"
+	input.body.input.x == 1
+}
+`})
+	}
+	authz := New(compiler, inmem.New(), Decision(func() ast.Ref {
+		return ast.MustParseRef("data.system.authz.allow")
+	}))
+
+	req := func() *Request {
+		header := http.Header{}
+		header.Set("X-Tenant", "acme")
+		return &Request{
+			Method: "post",
+			Path:   "/v1/data/a%20b",
+			Query:  map[string][]string{"pretty": {"true"}},
+			Header: header,
+			Body:   map[string]any{"input": map[string]any{"x": json.Number("1")}},
+		}
+	}
+
+	if status, err := authz.Authorize(identifier.WithIdentity(ctx, "bob"), req()); err != nil {
+		t.Fatalf("Expected request to be allowed but got %d: %v", status, err)
+	}
+
+	status, err := authz.Authorize(identifier.WithIdentity(ctx, "alice"), req())
+	if status != http.StatusUnauthorized || err == nil || err.Code != types.CodeUnauthorized {
+		t.Fatalf("Expected request to be rejected but got %d: %v", status, err)
+	}
+
+	// The body is only passed to the policy if the path expects one.
+	r := req()
+	r.Method = http.MethodGet
+	if _, err := authz.Authorize(identifier.WithIdentity(ctx, "bob"), r); err == nil {
+		t.Fatal("Expected request without body to be rejected")
+	}
+}
+
 func TestMakeInputWithBody(t *testing.T) {
 	reqs := []struct {
 		method                 string
@@ -368,6 +437,18 @@ func TestMakeInputWithBody(t *testing.T) {
 			body:             `{"foo": "bar"}`,
 			assertBodyExists: true,
 		},
+		{
+			method:           "POST",
+			path:             "/v1/batch/data/foo",
+			body:             `{"inputs": {"a": {"foo": "bar"}}}`,
+			assertBodyExists: true,
+		},
+		{
+			method:                 "POST",
+			path:                   "/v1/batch",
+			body:                   `{"inputs": {}}`,
+			assertBodyDoesNotExist: true,
+		},
 		{
 			method:                 "PUT",
 			path:                   "/v1/data",
@@ -0,0 +1,343 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/server/authorizer"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/writer"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/lineage"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// batchInput is a single named input of a batch Data API request.
+type batchInput struct {
+	id      string
+	value   ast.Value
+	goInput *any
+}
+
+// v1BatchDataPost evaluates the same policy for every input in the request
+// body.
+func (s *Server) v1BatchDataPost(w http.ResponseWriter, r *http.Request) {
+	opts := getDataOptions(r.URL)
+	m := s.newMetrics(opts.metrics, opts.instrument)
+	m.Timer(metrics.ServerHandler).Start()
+
+	m.Timer(metrics.RegoInputParse).Start()
+
+	inputs, err := readInputBatchPostV1(r)
+	if err != nil {
+		writer.ErrorString(w, http.StatusBadRequest, types.CodeInvalidParameter, err)
+		return
+	}
+
+	m.Timer(metrics.RegoInputParse).Stop()
+
+	result, status, err := s.evalBatchData(r.Context(), m, escapedPathValue(r, "path"), inputs, opts)
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+
+	writeBatchResponse(w, status, *result, opts.pretty)
+}
+
+// evalBatchData evaluates the decision at the escaped path urlPath for every
+// input. All evaluations share one prepared query and one read transaction,
+// and run concurrently. Every evaluation gets its own decision ID and
+// decision log entry, linked by the batch decision ID. The returned status is
+// http.StatusMultiStatus if any evaluation failed. The ServerHandler timer of
+// m must have been started.
+func (s *Server) evalBatchData(ctx context.Context, m metrics.Metrics, urlPath string, inputs []batchInput, opts dataOptions) (*types.BatchDataResponseV1, int, error) {
+	batchDecisionID := s.generateDecisionID()
+	ctx = logging.WithBatchDecisionID(ctx, batchDecisionID)
+	annotateSpan(ctx, batchDecisionID)
+
+	txn, err := s.store.NewTransaction(ctx, storage.TransactionParams{Context: storage.NewContext().WithMetrics(m)})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer s.store.Abort(ctx, txn)
+
+	var br bundleRevisions
+
+	if s.logger != nil || opts.provenance {
+		br, err = getRevisions(ctx, s.store, txn)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	pqID := "v1BatchDataPost::"
+	if opts.strictBuiltinErrors {
+		pqID = "v1BatchDataPost::strict-builtin-errors::"
+	}
+	pqID += urlPath
+	preparedQuery, ok := s.getCachedPreparedEvalQuery(pqID, m)
+	if !ok {
+		regoOpts := []func(*rego.Rego){
+			rego.Compiler(s.getCompiler()),
+			rego.Store(s.store),
+		}
+
+		for _, r := range s.manager.GetWasmResolvers() {
+			for _, entrypoint := range r.Entrypoints() {
+				regoOpts = append(regoOpts, rego.Resolver(entrypoint, r))
+			}
+		}
+
+		rego, err := s.makeRego(ctx, opts.strictBuiltinErrors, txn, nil, urlPath, m, opts.instrument, nil, regoOpts)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		pq, err := rego.PrepareForEval(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		preparedQuery = &pq
+		s.preparedEvalQueries.Insert(pqID, preparedQuery)
+	}
+
+	// Decision IDs are generated up front so that the factory is never
+	// called concurrently.
+	decisionIDs := make([]string, len(inputs))
+	for i := range inputs {
+		decisionIDs[i] = s.generateDecisionID()
+	}
+
+	items := make([]types.BatchDataResponseItemV1, len(inputs))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+
+	for i, in := range inputs {
+		g.Go(func() error {
+			itemCtx := logging.WithDecisionID(gctx, decisionIDs[i])
+
+			var logger decisionLogger
+			if s.logger != nil {
+				itemCtx, logger = s.getDecisionLogger(itemCtx, br)
+			}
+
+			item, err := s.evalBatchItem(itemCtx, txn, preparedQuery, logger, br, in, urlPath, opts)
+			if err != nil {
+				// Decision logging failures are fatal for the whole batch, just
+				// like they are for single decisions.
+				return err
+			}
+			item.DecisionID = decisionIDs[i]
+			items[i] = item
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	m.Timer(metrics.ServerHandler).Stop()
+
+	result := &types.BatchDataResponseV1{
+		BatchDecisionID: batchDecisionID,
+		Responses:       make(map[string]types.BatchDataResponseItemV1, len(inputs)),
+	}
+
+	if opts.metrics || opts.instrument {
+		result.Metrics = m.All()
+	}
+
+	status := http.StatusOK
+	for i, in := range inputs {
+		if items[i].Error != nil {
+			status = http.StatusMultiStatus
+		}
+		result.Responses[in.id] = items[i]
+	}
+
+	return result, status, nil
+}
+
+// evalBatchItem evaluates a single input of a batch. Evaluation errors are
+// reported in the returned item; the returned error is only non-nil if the
+// decision could not be logged.
+func (s *Server) evalBatchItem(
+	ctx context.Context,
+	txn storage.Transaction,
+	preparedQuery *rego.PreparedEvalQuery,
+	logger decisionLogger,
+	br bundleRevisions,
+	in batchInput,
+	urlPath string,
+	opts dataOptions,
+) (types.BatchDataResponseItemV1, error) {
+	m := s.newMetrics(opts.metrics, opts.instrument)
+	m.Timer(metrics.ServerHandler).Start()
+
+	var buf *topdown.BufferTracer
+	if opts.explain != types.ExplainOffV1 {
+		buf = topdown.NewBufferTracer()
+	}
+
+	var ndbCache builtins.NDBCache
+	if s.ndbCacheEnabled {
+		ndbCache = builtins.NDBCache{}
+	}
+
+	respMetadata := map[string]any{}
+	customLog := func() map[string]any {
+		if len(respMetadata) == 0 {
+			return nil
+		}
+		return map[string]any{"response_metadata": respMetadata}
+	}
+
+	tracker := newEvaluatedRuleTracker()
+	evalOpts := []rego.EvalOption{
+		rego.EvalTransaction(txn),
+		rego.EvalParsedInput(in.value),
+		rego.EvalMetrics(m),
+		rego.EvalQueryTracer(buf),
+		rego.EvalInterQueryBuiltinCache(s.interQueryBuiltinCache),
+		rego.EvalInterQueryBuiltinValueCache(s.interQueryBuiltinValueCache),
+		rego.EvalInstrument(opts.instrument),
+		rego.EvalNDBuiltinCache(ndbCache),
+		rego.EvalResponseMetadata(respMetadata),
+		rego.EvalEvaluatedRuleTracker(tracker),
+	}
+
+	rs, err := preparedQuery.Eval(ctx, evalOpts...)
+
+	m.Timer(metrics.ServerHandler).Stop()
+
+	if err != nil {
+		if logErr := logger.Log(ctx, txn, urlPath, "", in.goInput, in.value, nil, ndbCache, err, m, nil, customLog()); logErr != nil {
+			return types.BatchDataResponseItemV1{}, logErr
+		}
+		status, e := writer.AutoStatus(err)
+		return types.BatchDataResponseItemV1{HTTPStatusCode: status, Error: e}, nil
+	}
+
+	item := types.BatchDataResponseItemV1{HTTPStatusCode: http.StatusOK}
+
+	if in.value == nil {
+		item.Warning = types.NewWarning(types.CodeAPIUsageWarn, types.MsgInputKeyMissing)
+	}
+
+	if opts.metrics || opts.instrument {
+		item.Metrics = m.All()
+	}
+
+	if opts.provenance {
+		item.Provenance = s.getProvenance(br)
+	}
+
+	if len(rs) == 0 {
+		if opts.explain == types.ExplainFullV1 {
+			if item.Explanation, err = types.NewTraceV1(lineage.Full(*buf), opts.pretty); err != nil {
+				status, e := writer.AutoStatus(err)
+				return types.BatchDataResponseItemV1{HTTPStatusCode: status, Error: e}, nil
+			}
+		}
+		return item, logger.Log(ctx, txn, urlPath, "", in.goInput, in.value, nil, ndbCache, nil, m, nil, customLog())
+	}
+
+	item.Result = &rs[0].Expressions[0].Value
+
+	if opts.explain != types.ExplainOffV1 {
+		item.Explanation = s.getExplainResponse(opts.explain, *buf, opts.pretty)
+	}
+
+	return item, logger.Log(ctx, txn, urlPath, "", in.goInput, in.value, item.Result, ndbCache, nil, m, evaluatedRuleLabels(tracker), customLog())
+}
+
+func readInputBatchPostV1(r *http.Request) ([]batchInput, error) {
+	var request types.BatchDataRequestV1
+
+	if parsed, ok := authorizer.GetBodyOnContext(r.Context()); ok {
+		bs, err := json.Marshal(parsed)
+		if err != nil {
+			return nil, err
+		}
+		if err := util.UnmarshalJSON(bs, &request); err != nil {
+			return nil, fmt.Errorf("body contains malformed batch request: %w", err)
+		}
+	} else {
+		bodyBytes, err := util.ReadMaybeCompressedBody(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress the body: %w", err)
+		}
+
+		if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+			if len(bodyBytes) > 0 {
+				if err := util.Unmarshal(bodyBytes, &request); err != nil {
+					return nil, fmt.Errorf("body contains malformed batch request: %w", err)
+				}
+			}
+		} else {
+			dec := util.NewJSONDecoder(bytes.NewBuffer(bodyBytes))
+			if err := dec.Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("body contains malformed batch request: %w", err)
+			}
+		}
+	}
+
+	if request.Inputs == nil {
+		return nil, errors.New("missing required 'inputs' value")
+	}
+
+	inputs := make([]batchInput, 0, len(request.Inputs))
+	for _, id := range util.KeysSorted(request.Inputs) {
+		in := batchInput{id: id, goInput: request.Inputs[id]}
+		if in.goInput != nil {
+			v, err := ast.InterfaceToValue(*in.goInput)
+			if err != nil {
+				return nil, fmt.Errorf("body contains malformed input document for %q: %w", id, err)
+			}
+			in.value = v
+		}
+		inputs = append(inputs, in)
+	}
+
+	return inputs, nil
+}
+
+func writeBatchResponse(w http.ResponseWriter, status int, result types.BatchDataResponseV1, pretty bool) {
+	var bs []byte
+	var err error
+	if pretty {
+		bs, err = json.MarshalIndent(result, "", "  ")
+	} else {
+		bs, err = json.Marshal(result)
+	}
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(bs, '\n'))
+}
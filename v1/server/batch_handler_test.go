@@ -0,0 +1,192 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const batchTestPolicy = `package test
+
+allow if input.user == "alice"
+
+deny contains msg if {
+	input.user == "mallory"
+	msg := "go away"
+}
+`
+
+func TestV1BatchDataPost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note string
+		path string
+		body string
+		code int
+		resp string
+	}{
+		{
+			note: "all inputs evaluated",
+			path: "/batch/data/test/allow",
+			body: `{"inputs": {"a": {"user": "alice"}, "b": {"user": "bob"}}}`,
+			code: http.StatusOK,
+			resp: `{"responses": {
+				"a": {"http_status_code": 200, "result": true},
+				"b": {"http_status_code": 200}
+			}}`,
+		},
+		{
+			note: "empty inputs",
+			path: "/batch/data/test/allow",
+			body: `{"inputs": {}}`,
+			code: http.StatusOK,
+			resp: `{"responses": {}}`,
+		},
+		{
+			note: "null input",
+			path: "/batch/data/test/deny",
+			body: `{"inputs": {"a": null}}`,
+			code: http.StatusOK,
+			resp: `{"responses": {
+				"a": {"http_status_code": 200, "result": [], "warning": {"code": "api_usage_warning", "message": "'input' key missing from the request"}}
+			}}`,
+		},
+		{
+			note: "missing inputs",
+			path: "/batch/data/test/allow",
+			body: `{}`,
+			code: http.StatusBadRequest,
+			resp: `{"code": "invalid_parameter", "message": "missing required 'inputs' value"}`,
+		},
+		{
+			note: "malformed body",
+			path: "/batch/data/test/allow",
+			body: `{"inputs": [1]}`,
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+
+			f := newFixture(t)
+			if err := f.v1(http.MethodPut, "/policies/test", batchTestPolicy, 200, ""); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.v1(http.MethodPost, tc.path, tc.body, tc.code, tc.resp); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestV1BatchDataPostPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	policy := `package test
+
+p := x if {
+	x := input.x
+	x > 0
+}
+
+p := y if {
+	y := input.y
+	y > 0
+}
+`
+	if err := f.v1(http.MethodPut, "/policies/test", policy, 200, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"inputs": {"ok": {"x": 1}, "conflict": {"x": 1, "y": 2}}}`
+	resp := `{"responses": {
+		"ok": {"http_status_code": 200, "result": 1},
+		"conflict": {
+			"http_status_code": 500,
+			"error": {
+				"code": "internal_error",
+				"message": "error(s) occurred while evaluating query",
+				"errors": [{"code": "eval_conflict_error", "message": "complete rules must not produce multiple outputs", "location": {"file": "test", "row": 3, "col": 1}}]
+			}
+		}
+	}}`
+	if err := f.v1(http.MethodPost, "/batch/data/test/p", body, http.StatusMultiStatus, resp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestV1BatchDataPostDecisionLogging(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	var mu sync.Mutex
+	var decisions []*Info
+	var nextID int
+
+	f.server = f.server.WithDecisionIDFactory(func() string {
+		nextID++
+		return strconv.Itoa(nextID)
+	}).WithDecisionLoggerWithErr(func(_ context.Context, info *Info) error {
+		mu.Lock()
+		defer mu.Unlock()
+		decisions = append(decisions, info)
+		return nil
+	})
+
+	if err := f.v1(http.MethodPut, "/policies/test", batchTestPolicy, 200, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"inputs": {"a": {"user": "alice"}, "b": {"user": "bob"}, "c": {"user": "carol"}}}`
+	resp := `{
+		"batch_decision_id": "1",
+		"responses": {
+			"a": {"http_status_code": 200, "decision_id": "2", "result": true},
+			"b": {"http_status_code": 200, "decision_id": "3"},
+			"c": {"http_status_code": 200, "decision_id": "4"}
+		}
+	}`
+	if err := f.v1(http.MethodPost, "/batch/data/test/allow", body, http.StatusOK, resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 3 {
+		t.Fatalf("expected 3 decisions, got %d", len(decisions))
+	}
+
+	ids := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		if d.BatchDecisionID != "1" {
+			t.Errorf("expected batch decision ID 1, got %q", d.BatchDecisionID)
+		}
+		if d.Path != "test/allow" {
+			t.Errorf("expected path test/allow, got %q", d.Path)
+		}
+		ids = append(ids, d.DecisionID)
+	}
+
+	slices.Sort(ids)
+	if exp := []string{"2", "3", "4"}; !slices.Equal(ids, exp) {
+		t.Errorf("expected decision IDs %v, got %v", exp, ids)
+	}
+}
+
+func TestV1BatchDataMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	if err := f.v1(http.MethodGet, "/batch/data/test", "", http.StatusMethodNotAllowed, ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,9 +9,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/open-policy-agent/opa/internal/compile"
 	"github.com/open-policy-agent/opa/v1/ast"
@@ -41,22 +43,30 @@ const (
 	unknownsCacheSize    = 500
 	maskingRuleCacheSize = 500
 
-	// These need to be kept up to date with `CompileApiKnownHeaders()` below
-	multiTargetJSON  = "application/vnd.opa.multitarget+json"
-	ucastAllJSON     = "application/vnd.opa.ucast.all+json"
-	ucastMinimalJSON = "application/vnd.opa.ucast.minimal+json"
-	ucastPrismaJSON  = "application/vnd.opa.ucast.prisma+json"
-	ucastLINQJSON    = "application/vnd.opa.ucast.linq+json"
-	sqlPostgresJSON  = "application/vnd.opa.sql.postgresql+json"
-	sqlMySQLJSON     = "application/vnd.opa.sql.mysql+json"
-	sqlSQLServerJSON = "application/vnd.opa.sql.sqlserver+json"
-	sqliteJSON       = "application/vnd.opa.sql.sqlite+json"
+	// These need to be kept up to date with `builtinCompileAPIHeaders()` below
+	multiTargetJSON   = "application/vnd.opa.multitarget+json"
+	ucastAllJSON      = "application/vnd.opa.ucast.all+json"
+	ucastMinimalJSON  = "application/vnd.opa.ucast.minimal+json"
+	ucastPrismaJSON   = "application/vnd.opa.ucast.prisma+json"
+	ucastLINQJSON     = "application/vnd.opa.ucast.linq+json"
+	sqlPostgresJSON   = "application/vnd.opa.sql.postgresql+json"
+	sqlMySQLJSON      = "application/vnd.opa.sql.mysql+json"
+	sqlSQLServerJSON  = "application/vnd.opa.sql.sqlserver+json"
+	sqliteJSON        = "application/vnd.opa.sql.sqlite+json"
+	mongoJSON         = "application/vnd.opa.mongo+json"
+	elasticsearchJSON = "application/vnd.opa.elasticsearch+json"
 
 	// back-compat
 	applicationJSON = "application/json"
 )
 
+// CompileAPIKnownHeaders returns the media types accepted by the Compile API,
+// including those registered with RegisterCompileTarget.
 func CompileAPIKnownHeaders() []string {
+	return append(builtinCompileAPIHeaders(), registeredCompileTargetHeaders()...)
+}
+
+func builtinCompileAPIHeaders() []string {
 	return []string{
 		multiTargetJSON,
 		ucastAllJSON,
@@ -67,10 +77,43 @@ func CompileAPIKnownHeaders() []string {
 		sqlMySQLJSON,
 		sqlSQLServerJSON,
 		sqliteJSON,
+		mongoJSON,
+		elasticsearchJSON,
 	}
 }
 
-var allKnownHeaders = append(CompileAPIKnownHeaders(), applicationJSON)
+var allKnownHeaders = append(builtinCompileAPIHeaders(), applicationJSON)
+
+var (
+	compileTargets   = map[string][2]string{} // media type -> target, dialect
+	compileTargetMtx sync.RWMutex
+)
+
+// RegisterCompileTarget makes the Compile API serve filters for a custom target
+// to requests with the given media type in their Accept header. The target is
+// to be registered with compile.RegisterTarget (package v1/rego/compile).
+// It panics if the media type is already known.
+func RegisterCompileTarget(mediaType, target, dialect string) {
+	compileTargetMtx.Lock()
+	defer compileTargetMtx.Unlock()
+	if _, ok := compileTargets[mediaType]; ok || slices.Contains(allKnownHeaders, mediaType) {
+		panic("compile target media type already registered " + mediaType)
+	}
+	compileTargets[mediaType] = [2]string{target, dialect}
+}
+
+func registeredCompileTargetHeaders() []string {
+	compileTargetMtx.RLock()
+	defer compileTargetMtx.RUnlock()
+	return slices.Sorted(maps.Keys(compileTargets))
+}
+
+func registeredCompileTarget(mediaType string) (string, string, bool) {
+	compileTargetMtx.RLock()
+	defer compileTargetMtx.RUnlock()
+	td, ok := compileTargets[mediaType]
+	return td[0], td[1], ok
+}
 
 type CompileResult struct {
 	Query any            `json:"query"`
@@ -140,9 +183,7 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	explainMode := getExplain(r.URL, types.ExplainOffV1)
-	includeInstrumentation := getBoolParam(r.URL, types.ParamInstrumentV1, true)
+	opts := getCompileOptions(r.URL)
 
 	m := metrics.New()
 	m.Timer(metrics.ServerHandler).Start()
@@ -154,6 +195,20 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result, contentType, err := s.compileFilters(r.Context(), m, urlPath, body, r.Header.Get("Accept"), opts)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	fin(w, *result, contentType, opts.pretty)
+}
+
+// compileFilters compiles the filters of the decision at urlPath for the
+// target of the accept header from the request body, and logs the decision.
+// It returns the content type of the response. The ServerHandler and
+// RegoQueryParse timers of m must have been started.
+func (s *Server) compileFilters(ctx context.Context, m metrics.Metrics, urlPath string, body []byte, accept string, opts compileOptions) (*CompileResponseV1, string, error) {
 	comp := s.getCompiler() // used for fuzzy rule name hints, and rego evals further down
 
 	// NOTE(sr): We keep some fields twice: from the unparsed and from the transformed
@@ -161,21 +216,19 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 	// transform the values back for including them in the decision logs.
 	orig, request, reqErr := readInputCompileFiltersV1(comp, body, urlPath, s.manager.ParserOptions())
 	if reqErr != nil {
-		writer.Error(w, http.StatusBadRequest, reqErr)
-		return
+		return nil, "", newAPIError(http.StatusBadRequest, reqErr)
 	}
 	m.Timer(metrics.RegoQueryParse).Stop()
 
 	c := storage.NewContext().WithMetrics(m)
 	txn, err := s.store.NewTransaction(ctx, storage.TransactionParams{Context: c})
 	if err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return nil, "", err
 	}
 
 	defer s.store.Abort(ctx, txn)
 	var buf *topdown.BufferTracer
-	if explainMode != types.ExplainOffV1 {
+	if opts.explain != types.ExplainOffV1 {
 		buf = topdown.NewBufferTracer()
 	}
 
@@ -189,10 +242,9 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		var errs ast.Errors
 		annotationsCompiler, errs = prepareAnnotations(ctx, comp, s.store, txn, s.manager.ParserOptions())
 		if len(errs) > 0 {
-			writer.Error(w, http.StatusBadRequest,
+			return nil, "", newAPIError(http.StatusBadRequest,
 				types.NewErrorV1(types.CodeEvaluation, types.MsgEvaluationError).
 					WithASTErrors(errs))
-			return
 		}
 	}
 
@@ -200,10 +252,9 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		var errs []*ast.Error
 		unknowns, errs = s.compileFiltersUnknowns(m, annotationsCompiler, urlPath, request.Query)
 		if errs != nil {
-			writer.Error(w, http.StatusBadRequest,
+			return nil, "", newAPIError(http.StatusBadRequest,
 				types.NewErrorV1(types.CodeEvaluation, types.MsgEvaluationError).
 					WithASTErrors(errs))
-			return
 		}
 	}
 
@@ -211,10 +262,9 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		var errs []*ast.Error
 		maskingRule, errs = s.compileFiltersMaskRule(m, annotationsCompiler, urlPath, request.Query)
 		if errs != nil {
-			writer.Error(w, http.StatusBadRequest,
+			return nil, "", newAPIError(http.StatusBadRequest,
 				types.NewErrorV1(types.CodeEvaluation, types.MsgEvaluationError).
 					WithASTErrors(errs))
-			return
 		}
 	}
 
@@ -223,10 +273,9 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		ndbCache = builtins.NDBCache{}
 	}
 
-	contentType, err := sanitizeHeader(r.Header.Get("Accept"))
+	contentType, err := sanitizeHeader(accept)
 	if err != nil {
-		writer.Error(w, http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, "Accept header: %s", err.Error()))
-		return
+		return nil, "", newAPIError(http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, "Accept header: %s", err.Error()))
 	}
 
 	target, dialect := targetDialect(contentType)
@@ -237,8 +286,7 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 	switch target {
 	case "multi":
 		for i, targetTuple := range orig.Options.TargetDialects {
-			s := strings.Split(targetTuple, "+")
-			target, dialect := s[0], s[1]
+			target, dialect, _ := strings.Cut(targetTuple, "+")
 			multi[i] = [2]string{target, dialect}
 			targetOption = append(targetOption, rego_compile.Target(target, dialect))
 		}
@@ -265,7 +313,7 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 				rego.Transaction(txn),
 				rego.DisableInlining(orig.Options.DisableInlining),
 				rego.QueryTracer(buf),
-				rego.Instrument(includeInstrumentation),
+				rego.Instrument(opts.instrument),
 				rego.NDBuiltinCache(ndbCache),
 				rego.Runtime(s.runtime),
 				rego.UnsafeBuiltins(unsafeBuiltinsMap),
@@ -279,12 +327,10 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch err := err.(type) {
 		case ast.Errors:
-			writer.Error(w, http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, types.MsgCompileModuleError).WithASTErrors(err))
+			return nil, "", newAPIError(http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, types.MsgCompileModuleError).WithASTErrors(err))
 		default:
-			writer.ErrorAuto(w, err)
+			return nil, "", err
 		}
-		return
-
 	}
 	m.Timer(timerPrepPartial).Stop()
 
@@ -309,51 +355,39 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch err := err.(type) {
 		case ast.Errors:
-			writer.Error(w, http.StatusBadRequest, types.NewErrorV1(types.CodeEvaluation, types.MsgEvaluationError).WithASTErrors(err))
+			return nil, "", newAPIError(http.StatusBadRequest, types.NewErrorV1(types.CodeEvaluation, types.MsgEvaluationError).WithASTErrors(err))
 		default:
-			writer.ErrorAuto(w, err)
+			return nil, "", err
 		}
-		return
 	}
 
-	result := CompileResponseV1{
+	result := &CompileResponseV1{
 		Hints: qt.Hints(unknowns),
 	}
 
 	switch target {
 	case "multi":
-		targets := struct {
-			UCAST    *CompileResult `json:"ucast,omitempty"`
-			Postgres *CompileResult `json:"postgresql,omitempty"`
-			MySQL    *CompileResult `json:"mysql,omitempty"`
-			MSSQL    *CompileResult `json:"sqlserver,omitempty"`
-			SQLite   *CompileResult `json:"sqlite,omitempty"`
-		}{}
-
+		targets := map[string]*CompileResult{}
 		for _, targetTuple := range multi {
 			target, dialect := targetTuple[0], targetTuple[1]
 			f := filters.For(target, dialect)
-			var cr *CompileResult
-			if f.Query != nil {
-				cr = &CompileResult{Query: f.Query, Masks: f.Masks}
+			if f.Query == nil {
+				continue
 			}
+			cr := &CompileResult{Query: f.Query, Masks: f.Masks}
 			switch target {
 			case "ucast":
-				if targets.UCAST != nil {
+				if _, ok := targets["ucast"]; ok {
 					continue // there's only one UCAST representation, don't translate that twice
 				}
-				targets.UCAST = cr
+				targets["ucast"] = cr
 			case "sql":
 				switch dialect {
-				case "postgresql":
-					targets.Postgres = cr
-				case "mysql":
-					targets.MySQL = cr
-				case "sqlserver":
-					targets.MSSQL = cr
-				case "sqlite":
-					targets.SQLite = cr
+				case "postgresql", "mysql", "sqlserver", "sqlite":
+					targets[dialect] = cr
 				}
+			default: // other targets are keyed by their name
+				targets[target] = cr
 			}
 		}
 
@@ -374,7 +408,9 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 		result.Metadata = respMetadata
 	}
 
-	fin(w, result, contentType, m, includeMetrics(r), includeInstrumentation, pretty(r))
+	if opts.metrics || opts.instrument {
+		result.Metrics = m.All()
+	}
 
 	unk := make([]string, len(unknowns))
 	for i := range unknowns {
@@ -397,9 +433,10 @@ func (s *Server) v1CompileFilters(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := logger.Log(ctx, txn, urlPath, orig.Query, orig.Input, request.Input, result.Result, ndbCache, nil, m, nil, custom); err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return nil, "", err
 	}
+
+	return result, contentType, nil
 }
 
 func (s *Server) compileFiltersUnknowns(m metrics.Metrics, comp *ast.Compiler, path string, query ast.Body) ([]ast.Ref, []*ast.Error) {
@@ -533,16 +570,7 @@ func readInputCompileFiltersV1(comp *ast.Compiler, reqBytes []byte, urlPath stri
 	}, nil
 }
 
-func fin(w http.ResponseWriter,
-	result CompileResponseV1,
-	contentType string,
-	metrics metrics.Metrics,
-	includeMetrics, includeInstrumentation, pretty bool,
-) {
-	if includeMetrics || includeInstrumentation {
-		result.Metrics = metrics.All()
-	}
-
+func fin(w http.ResponseWriter, result CompileResponseV1, contentType string, pretty bool) {
 	enc := json.NewEncoder(w)
 	if pretty {
 		enc.SetIndent("", "  ")
@@ -567,7 +595,9 @@ func sanitizeHeader(accept string) (string, error) {
 	}
 
 	if !slices.Contains(allKnownHeaders, accept) {
-		return "", fmt.Errorf("unsupported header: %s", accept)
+		if _, _, ok := registeredCompileTarget(accept); !ok {
+			return "", fmt.Errorf("unsupported header: %s", accept)
+		}
 	}
 
 	return accept, nil
@@ -595,6 +625,14 @@ func targetDialect(accept string) (string, string) {
 		return "sql", "sqlserver"
 	case sqliteJSON:
 		return "sql", "sqlite"
+	case mongoJSON:
+		return "mongo", ""
+	case elasticsearchJSON:
+		return "elasticsearch", ""
+	}
+
+	if target, dialect, ok := registeredCompileTarget(accept); ok {
+		return target, dialect
 	}
 
 	panic("unreachable")
@@ -10,12 +10,15 @@ import (
 	"fmt"
 	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/open-policy-agent/opa/v1/ast"
+	rego_compile "github.com/open-policy-agent/opa/v1/rego/compile"
 	"github.com/open-policy-agent/opa/v1/storage"
 	"github.com/open-policy-agent/opa/v1/storage/inmem"
 )
@@ -280,6 +283,169 @@ include if input.fruit.cost < input.max
 	}
 }
 
+func TestCompileHandlerDocumentTargets(t *testing.T) {
+	t.Parallel()
+
+	f := setup(t, `package filters
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.tickets]
+include if {
+	input.tickets.tenant == input.tenant
+	not input.tickets.resolved == true
+}
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.tickets]
+overdue if input.tickets.resolved_at > input.tickets.due
+`, nil)
+
+	payload := map[string]any{
+		"input": map[string]any{"tenant": "acmecorp"},
+		"options": map[string]any{
+			"targetSQLTableMappings": map[string]any{
+				"mongo":         map[string]any{"tickets": map[string]any{"$self": "support_tickets"}},
+				"elasticsearch": map[string]any{"tickets": map[string]any{"$self": "tickets-v2", "tenant": "tenant.keyword"}},
+			},
+		},
+	}
+
+	t.Run("mongo", func(t *testing.T) {
+		t.Parallel()
+		req := evalReq(t, "filters/include", payload, "application/vnd.opa.mongo+json")
+		expBody := `{"result": {"query": {
+			"collection": "support_tickets",
+			"filter": {"$and": [
+				{"tenant": {"$eq": "acmecorp"}},
+				{"$nor": [{"resolved": {"$eq": true}}]}
+			]}
+		}}}`
+		if err := f.executeRequest(req, http.StatusOK, expBody, ignoreMetrics); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("elasticsearch", func(t *testing.T) {
+		t.Parallel()
+		req := evalReq(t, "filters/include", payload, "application/vnd.opa.elasticsearch+json")
+		expBody := `{"result": {"query": {
+			"index": "tickets-v2",
+			"query": {"bool": {"filter": [
+				{"term": {"tenant.keyword": "acmecorp"}},
+				{"bool": {"must_not": [{"term": {"resolved": true}}]}}
+			]}}
+		}}}`
+		if err := f.executeRequest(req, http.StatusOK, expBody, ignoreMetrics); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("mongo field reference", func(t *testing.T) {
+		t.Parallel()
+		req := evalReq(t, "filters/overdue", payload, "application/vnd.opa.mongo+json")
+		expBody := `{"result": {"query": {
+			"collection": "support_tickets",
+			"filter": {"$expr": {"$gt": ["$resolved_at", "$due"]}}
+		}}}`
+		if err := f.executeRequest(req, http.StatusOK, expBody, ignoreMetrics); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("elasticsearch field reference", func(t *testing.T) {
+		t.Parallel()
+		req := evalReq(t, "filters/overdue", payload, "application/vnd.opa.elasticsearch+json")
+		if err := f.executeRequest(req, http.StatusBadRequest, ""); err != nil {
+			t.Error(err)
+		}
+		if exp, act := `unsupported feature \"field-ref\" for ELASTICSEARCH`, f.recorder.Body.String(); !strings.Contains(act, exp) {
+			t.Errorf("expected error %q, got %s", exp, act)
+		}
+	})
+}
+
+var registerKVTarget sync.Once
+
+func TestCompileHandlerCustomTarget(t *testing.T) {
+	const mediaType = "application/vnd.example.kv+json"
+	// Targets can't be unregistered, so the target is registered once for all runs of the test.
+	registerKVTarget.Do(func() {
+		rego_compile.RegisterTarget("kv", rego_compile.CustomTarget{
+			Builtins: []string{"eq"},
+			Translator: rego_compile.TranslatorFunc(func(q *rego_compile.Queries) (any, error) {
+				node := q.UCAST()
+				return map[string]any{"key": node.Field, "value": node.Value}, nil
+			}),
+		})
+	})
+	RegisterCompileTarget(mediaType, "kv", "")
+	t.Cleanup(func() {
+		compileTargetMtx.Lock()
+		delete(compileTargets, mediaType)
+		compileTargetMtx.Unlock()
+	})
+
+	if !slices.Contains(CompileAPIKnownHeaders(), mediaType) {
+		t.Errorf("expected %s in known headers", mediaType)
+	}
+
+	f := setup(t, `package filters
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+include if input.fruits.name == input.name
+
+# METADATA
+# scope: document
+# compile:
+#   unknowns: [input.fruits]
+exclude if input.fruits.name != input.name
+`, nil)
+
+	t.Run("single target", func(t *testing.T) {
+		req := evalReq(t, "filters/include", map[string]any{"input": map[string]any{"name": "apple"}}, mediaType)
+		expBody := `{"result": {"query": {"key": "fruits.name", "value": "apple"}}}`
+		if err := f.executeRequest(req, http.StatusOK, expBody, ignoreMetrics); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("multi target", func(t *testing.T) {
+		req := evalReq(t, "filters/include", map[string]any{
+			"input":   map[string]any{"name": "apple"},
+			"options": map[string]any{"targetDialects": []string{"kv", "sql+postgresql"}},
+		}, multiTargetJSON)
+		expBody := `{"result": {
+			"kv": {"query": {"key": "fruits.name", "value": "apple"}},
+			"postgresql": {"query": "WHERE fruits.name = E'apple'"}
+		}}`
+		if err := f.executeRequest(req, http.StatusOK, expBody, ignoreMetrics); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("constraint violation", func(t *testing.T) {
+		req := evalReq(t, "filters/exclude", map[string]any{"input": map[string]any{"name": "apple"}}, mediaType)
+		if err := f.executeRequest(req, http.StatusBadRequest, ""); err != nil {
+			t.Error(err)
+		}
+		if exp, act := "invalid builtin `neq`: unsupported for KV", f.recorder.Body.String(); !strings.Contains(act, exp) {
+			t.Errorf("expected error %q, got %s", exp, act)
+		}
+	})
+
+	t.Run("unknown media type", func(t *testing.T) {
+		req := evalReq(t, "filters/include", map[string]any{"input": map[string]any{"name": "apple"}}, "application/vnd.example.unknown+json")
+		if err := f.executeRequest(req, http.StatusBadRequest, ""); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
 func TestCompileHandlerMaskingRules(t *testing.T) {
 	t.Parallel()
 	var roles map[string]any
@@ -0,0 +1,66 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"slices"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/debug"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/topdown"
+)
+
+// AttachDebugSession offers decisions served by the v1 data API to sess, which
+// debugs those that any of its breakpoints apply to. Decisions are offered to
+// the session until the returned function is called.
+func (s *Server) AttachDebugSession(sess debug.AttachedSession) func() {
+	s.debugMtx.Lock()
+	defer s.debugMtx.Unlock()
+
+	s.debugSessions = append(s.debugSessions, sess)
+
+	return func() {
+		s.debugMtx.Lock()
+		defer s.debugMtx.Unlock()
+
+		s.debugSessions = slices.DeleteFunc(slices.Clone(s.debugSessions), func(other debug.AttachedSession) bool {
+			return other == sess
+		})
+	}
+}
+
+// debugTracer offers the decision of the request described by name to the
+// attached debug sessions, and returns the tracer of the first session
+// debugging it, if any. The returned function must be called with the result
+// set once the decision has been evaluated.
+func (s *Server) debugTracer(ctx context.Context, name, decisionID string, input ast.Value) (topdown.QueryTracer, func(rego.ResultSet)) {
+	s.debugMtx.RLock()
+	sessions := s.debugSessions
+	s.debugMtx.RUnlock()
+
+	if len(sessions) > 0 {
+		title := fmt.Sprintf("%s (decision %s)", name, decisionID)
+		for _, sess := range sessions {
+			if tracer, done := sess.Trace(ctx, title, input); tracer != nil {
+				return tracer, done
+			}
+		}
+	}
+
+	return nil, func(rego.ResultSet) {}
+}
+
+// DebugTLSConfig returns the TLS configuration of the debug adapter's listener,
+// which serves the server's certificate. It returns nil if the server isn't
+// configured with a certificate.
+func (s *Server) DebugTLSConfig() *tls.Config {
+	if s.cert == nil {
+		return nil
+	}
+	return s.tlsConfig()
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 The OPA Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast/location"
+	"github.com/open-policy-agent/opa/v1/debug"
+)
+
+func TestAttachDebugSession(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	if err := f.v1(http.MethodPut, "/policies/test", batchTestPolicy, 200, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan debug.Event, 100)
+	d := debug.NewDebugger(debug.SetEventHandler(func(e debug.Event) {
+		events <- e
+	}))
+
+	sess, err := d.Attach(t.Context(), debug.AttachProperties{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sess.Terminate() }()
+
+	if _, err := sess.AddConditionalBreakpoint(location.Location{File: "test", Row: 3}, `input.user == "alice"`); err != nil {
+		t.Fatal(err)
+	}
+
+	detach := f.server.AttachDebugSession(sess)
+
+	// Decisions the breakpoint doesn't apply to aren't debugged.
+	if err := f.v1(http.MethodPost, "/data/test/allow", `{"input": {"user": "bob"}}`, 200, `{}`); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func() <-chan *httptest.ResponseRecorder {
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			f.server.Handler.ServeHTTP(rec, newReqV1(http.MethodPost, "/data/test/allow", `{"input": {"user": "alice"}}`))
+			done <- rec
+		}()
+		return done
+	}
+
+	done := serve()
+
+	var stopped debug.Event
+	timeout := time.After(5 * time.Second)
+	for stopped.Type != debug.StoppedEventType {
+		select {
+		case stopped = <-events:
+		case <-timeout:
+			t.Fatal("decision was not stopped at breakpoint")
+		}
+	}
+
+	select {
+	case <-done:
+		t.Fatal("expected decision to be paused")
+	default:
+	}
+
+	// The breakpoint is hit for every evaluation step on its line. Threads are
+	// removed once they exit, so only stopped threads are resumed.
+	for completed := false; !completed; {
+		if err := sess.Resume(stopped.Thread); err != nil {
+			t.Fatal(err)
+		}
+
+		for waiting := true; waiting; {
+			select {
+			case rec := <-done:
+				if rec.Code != http.StatusOK {
+					t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+				}
+				completed, waiting = true, false
+			case e := <-events:
+				if e.Type == debug.StoppedEventType {
+					stopped, waiting = e, false
+				}
+			case <-timeout:
+				t.Fatal("decision did not complete after resuming")
+			}
+		}
+	}
+
+	// Once detached, the session isn't offered decisions anymore.
+	detach()
+
+	select {
+	case rec := <-serve():
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("decision did not complete after detaching")
+	}
+}
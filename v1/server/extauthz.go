@@ -0,0 +1,386 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/config"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	serverExtAuthzPlugin "github.com/open-policy-agent/opa/v1/plugins/server/extauthz"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// The Envoy external authorization service answers Envoy's CheckRequests with
+// a policy decision. Like the rest of the gRPC API, every CheckRequest is
+// handled like the equivalent Data API request: the decision at the
+// configured path is evaluated with the conventional input document of
+// OPA-Envoy integrations, and its result is translated into a CheckResponse.
+
+// extAuthzPartialBodyHeader is set by Envoy when the request body sent in a
+// CheckRequest was truncated.
+const extAuthzPartialBodyHeader = "x-envoy-auth-partial-body"
+
+// extAuthzVersion identifies the encoding of the input document.
+var extAuthzVersion = map[string]any{"encoding": "protojson", "ext_authz": "v3"}
+
+// extAuthz holds the server.envoy_ext_authz config, which can be replaced at
+// runtime.
+type extAuthz struct {
+	cfg atomic.Pointer[serverExtAuthzPlugin.Config]
+	raw atomic.Pointer[[]byte]
+}
+
+func (a *extAuthz) update(ctx context.Context, raw []byte) error {
+	if prev := a.raw.Load(); prev != nil && bytes.Equal(*prev, raw) {
+		return nil
+	}
+
+	cfg, err := serverExtAuthzPlugin.NewConfigBuilder().WithBytes(raw).ParseWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.cfg.Store(cfg)
+	a.raw.Store(&raw)
+	return nil
+}
+
+func (s *Server) initExtAuthz(ctx context.Context) error {
+	if err := s.extAuthz.update(ctx, extAuthzRawConfig(s.manager.GetConfig().Server)); err != nil {
+		return err
+	}
+
+	logger := s.manager.Logger()
+	s.manager.RegisterServerConfigTrigger(func(cfg *config.ServerConfig) {
+		if err := s.extAuthz.update(context.Background(), extAuthzRawConfig(cfg)); err != nil {
+			logger.Error("Failed to update Envoy ext_authz config, keeping the previous config: %v", err)
+		}
+	})
+	return nil
+}
+
+func extAuthzRawConfig(cfg *config.ServerConfig) []byte {
+	if cfg == nil {
+		return nil
+	}
+	return cfg.EnvoyExtAuthz
+}
+
+// grpcExtAuthzService implements envoy.service.auth.v3.Authorization.
+type grpcExtAuthzService struct {
+	authv3.UnimplementedAuthorizationServer
+	s *Server
+}
+
+func (a *grpcExtAuthzService) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	cfg := a.s.extAuthz.cfg.Load()
+	if cfg == nil || !cfg.Enabled {
+		return nil, status.Error(codes.Unimplemented, "envoy ext_authz is not enabled")
+	}
+
+	input, err := extAuthzInput(req, cfg.SkipRequestBodyParse)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	value, err := ast.InterfaceToValue(input)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Decision logs get the headers of the checked request, e.g. for the
+	// decision_logs.request_context.http.headers option.
+	header := http.Header{}
+	for key, value := range req.GetAttributes().GetRequest().GetHttp().GetHeaders() {
+		header.Add(key, value)
+	}
+	ctx = logging.WithHTTPRequestContext(ctx, &logging.HTTPRequestContext{Header: header})
+
+	path := grpcPath(cfg.Path)
+	call := &grpcCall{
+		method:   http.MethodPost,
+		path:     grpcURLPath("/v1/data", path),
+		endpoint: PromHandlerV1Data,
+		body:     map[string]any{"input": input},
+	}
+
+	var decision *types.DataResponseV1
+	err = a.s.handleGRPC(ctx, call, func(ctx context.Context) error {
+		m := a.s.newMetrics(false, false)
+		m.Timer(metrics.ServerHandler).Start()
+
+		var goInput any = input
+		var err error
+		decision, err = a.s.evalData(ctx, m, &dataRequest{
+			name:  call.method + " " + call.path,
+			path:  path,
+			input: &parsedInput{Value: value, GoInput: &goInput},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+
+	var result *authv3.CheckResponse
+	if cfg.DryRun {
+		result = &authv3.CheckResponse{
+			Status:       &rpcstatus.Status{Code: int32(codes.OK)},
+			HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{}},
+		}
+	} else if result, err = extAuthzResponse(decision.Result); err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid decision at %v: %v", cfg.Path, err)
+	}
+
+	if decision.DecisionID != "" {
+		if result.DynamicMetadata == nil {
+			result.DynamicMetadata = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		}
+		if _, ok := result.DynamicMetadata.Fields["decision_id"]; !ok {
+			result.DynamicMetadata.Fields["decision_id"] = structpb.NewStringValue(decision.DecisionID)
+		}
+	}
+
+	return result, nil
+}
+
+// extAuthzInput returns the input document of a CheckRequest: the request
+// attributes in their protojson encoding, along with the parsed path, query
+// and body of the HTTP request.
+func extAuthzInput(req *authv3.CheckRequest, skipBodyParse bool) (map[string]any, error) {
+	bs, err := protojson.Marshal(req.GetAttributes())
+	if err != nil {
+		return nil, err
+	}
+	var attributes any
+	if err := util.UnmarshalJSON(bs, &attributes); err != nil {
+		return nil, err
+	}
+
+	input := map[string]any{
+		"attributes": attributes,
+		"version":    extAuthzVersion,
+	}
+
+	httpReq := req.GetAttributes().GetRequest().GetHttp()
+
+	u, err := url.Parse(httpReq.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("invalid request path: %w", err)
+	}
+	var parsedPath []any
+	for segment := range strings.SplitSeq(strings.TrimLeft(u.Path, "/"), "/") {
+		parsedPath = append(parsedPath, segment)
+	}
+	input["parsed_path"] = parsedPath
+	input["parsed_query"] = extAuthzValues(u.Query())
+
+	truncated := httpReq.GetHeaders()[extAuthzPartialBodyHeader] == "true"
+	input["truncated_body"] = truncated
+
+	var parsedBody any
+	body := httpReq.GetBody()
+	if body == "" {
+		body = string(httpReq.GetRawBody())
+	}
+	if body != "" && !skipBodyParse && !truncated {
+		mediaType, _, _ := mime.ParseMediaType(httpReq.GetHeaders()["content-type"])
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			if err := util.UnmarshalJSON([]byte(body), &parsedBody); err != nil {
+				return nil, fmt.Errorf("invalid request body: %w", err)
+			}
+		case mediaType == "application/x-www-form-urlencoded":
+			form, err := url.ParseQuery(body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid request body: %w", err)
+			}
+			parsedBody = extAuthzValues(form)
+		}
+	}
+	input["parsed_body"] = parsedBody
+
+	return input, nil
+}
+
+func extAuthzValues(values url.Values) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, vs := range values {
+		list := make([]any, len(vs))
+		for i, v := range vs {
+			list[i] = v
+		}
+		result[key] = list
+	}
+	return result
+}
+
+// extAuthzResponse returns the CheckResponse for a decision. The decision is
+// either a boolean, or an object with a boolean "allowed" field and optional
+// "headers", "response_headers_to_add", "request_headers_to_remove", "body",
+// "http_status" and "dynamic_metadata" fields. Requests are denied if the
+// decision is undefined.
+func extAuthzResponse(result *any) (*authv3.CheckResponse, error) {
+	allowed := false
+	decision := map[string]any{}
+	if result != nil {
+		switch v := (*result).(type) {
+		case bool:
+			allowed = v
+		case map[string]any:
+			decision = v
+			b, ok := v["allowed"].(bool)
+			if !ok {
+				return nil, errors.New("allowed field must be a boolean")
+			}
+			allowed = b
+		default:
+			return nil, fmt.Errorf("decision must be a boolean or an object, got %s", util.MustMarshalJSON(v))
+		}
+	}
+
+	headers, err := extAuthzHeaders(decision, "headers")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &authv3.CheckResponse{}
+
+	if allowed {
+		responseHeaders, err := extAuthzHeaders(decision, "response_headers_to_add")
+		if err != nil {
+			return nil, err
+		}
+		var toRemove []string
+		if v, ok := decision["request_headers_to_remove"]; ok {
+			list, ok := v.([]any)
+			if !ok {
+				return nil, errors.New("request_headers_to_remove field must be an array of strings")
+			}
+			for _, name := range list {
+				s, ok := name.(string)
+				if !ok {
+					return nil, errors.New("request_headers_to_remove field must be an array of strings")
+				}
+				toRemove = append(toRemove, s)
+			}
+		}
+
+		resp.Status = &rpcstatus.Status{Code: int32(codes.OK)}
+		resp.HttpResponse = &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{
+			Headers:              headers,
+			HeadersToRemove:      toRemove,
+			ResponseHeadersToAdd: responseHeaders,
+		}}
+	} else {
+		code := int64(http.StatusForbidden)
+		if v, ok := decision["http_status"]; ok {
+			n, ok := v.(json.Number)
+			if !ok {
+				return nil, errors.New("http_status field must be an integer")
+			}
+			if code, err = n.Int64(); err != nil || code < 100 || code > 599 {
+				return nil, fmt.Errorf("http_status field must be an HTTP status code, got %v", n)
+			}
+		}
+		var body string
+		if v, ok := decision["body"]; ok {
+			if body, ok = v.(string); !ok {
+				return nil, errors.New("body field must be a string")
+			}
+		}
+
+		resp.Status = &rpcstatus.Status{Code: int32(codes.PermissionDenied)}
+		resp.HttpResponse = &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+			Status:  &typev3.HttpStatus{Code: typev3.StatusCode(code)},
+			Headers: headers,
+			Body:    body,
+		}}
+	}
+
+	if v, ok := decision["dynamic_metadata"]; ok {
+		if _, ok := v.(map[string]any); !ok {
+			return nil, errors.New("dynamic_metadata field must be an object")
+		}
+		resp.DynamicMetadata = &structpb.Struct{}
+		if err := protojson.Unmarshal(util.MustMarshalJSON(v), resp.DynamicMetadata); err != nil {
+			return nil, fmt.Errorf("dynamic_metadata field: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// extAuthzHeaders returns the headers set by a decision field, an object of
+// header names to a string or an array of strings. The first value of a header
+// replaces any existing values, and further values are appended.
+func extAuthzHeaders(decision map[string]any, field string) ([]*corev3.HeaderValueOption, error) {
+	v, ok := decision[field]
+	if !ok {
+		return nil, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s field must be an object", field)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var result []*corev3.HeaderValueOption
+	for _, key := range keys {
+		var values []string
+		switch v := obj[key].(type) {
+		case string:
+			values = []string{v}
+		case []any:
+			for _, x := range v {
+				s, ok := x.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s field: values of header %q must be strings", field, key)
+				}
+				values = append(values, s)
+			}
+		default:
+			return nil, fmt.Errorf("%s field: value of header %q must be a string or an array of strings", field, key)
+		}
+		for i, value := range values {
+			action := corev3.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD
+			if i == 0 {
+				action = corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD
+			}
+			result = append(result, &corev3.HeaderValueOption{
+				Header:       &corev3.HeaderValue{Key: key, Value: value},
+				AppendAction: action,
+			})
+		}
+	}
+	return result, nil
+}
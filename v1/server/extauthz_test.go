@@ -0,0 +1,379 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-policy-agent/opa/v1/config"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func newCheckRequest(method, path string, headers map[string]string, body string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{
+		Source: &authv3.AttributeContext_Peer{Address: &corev3.Address{Address: &corev3.Address_SocketAddress{
+			SocketAddress: &corev3.SocketAddress{Address: "10.0.0.1", PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: 4321}},
+		}}},
+		Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+			Method:  method,
+			Path:    path,
+			Headers: headers,
+			Body:    body,
+		}},
+	}}
+}
+
+func TestExtAuthzInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note          string
+		headers       map[string]string
+		body          string
+		skipBodyParse bool
+		exp           string
+	}{
+		{
+			note: "no body",
+			exp:  `{"parsed_body": null, "truncated_body": false}`,
+		},
+		{
+			note:    "json body",
+			headers: map[string]string{"content-type": "application/json; charset=utf-8"},
+			body:    `{"amount": 10}`,
+			exp:     `{"parsed_body": {"amount": 10}, "truncated_body": false}`,
+		},
+		{
+			note:    "form body",
+			headers: map[string]string{"content-type": "application/x-www-form-urlencoded"},
+			body:    `a=1&a=2&b=3`,
+			exp:     `{"parsed_body": {"a": ["1", "2"], "b": ["3"]}, "truncated_body": false}`,
+		},
+		{
+			note:    "truncated body",
+			headers: map[string]string{"content-type": "application/json", "x-envoy-auth-partial-body": "true"},
+			body:    `{"amount": 1`,
+			exp:     `{"parsed_body": null, "truncated_body": true}`,
+		},
+		{
+			note:          "skipped body",
+			headers:       map[string]string{"content-type": "application/json"},
+			body:          `{"amount": 10}`,
+			skipBodyParse: true,
+			exp:           `{"parsed_body": null, "truncated_body": false}`,
+		},
+		{
+			note:    "other media type",
+			headers: map[string]string{"content-type": "text/plain"},
+			body:    `hello`,
+			exp:     `{"parsed_body": null, "truncated_body": false}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			input, err := extAuthzInput(newCheckRequest(http.MethodPost, "/api/v1/items?limit=5&tag=a&tag=b", tc.headers, tc.body), tc.skipBodyParse)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var exp map[string]any
+			if err := util.UnmarshalJSON([]byte(tc.exp), &exp); err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range exp {
+				if !jsonEqual(t, string(util.MustMarshalJSON(input[key])), string(util.MustMarshalJSON(value))) {
+					t.Fatalf("Expected %v %v but got %v", key, value, input[key])
+				}
+			}
+
+			for key, exp := range map[string]string{
+				"parsed_path":  `["api", "v1", "items"]`,
+				"parsed_query": `{"limit": ["5"], "tag": ["a", "b"]}`,
+				"version":      `{"encoding": "protojson", "ext_authz": "v3"}`,
+			} {
+				if act := string(util.MustMarshalJSON(input[key])); !jsonEqual(t, act, exp) {
+					t.Fatalf("Expected %v %v but got %v", key, exp, act)
+				}
+			}
+
+			attrs := input["attributes"].(map[string]any)
+			source := attrs["source"].(map[string]any)["address"].(map[string]any)["socketAddress"].(map[string]any)
+			if source["address"] != "10.0.0.1" {
+				t.Fatalf("Expected protojson encoded attributes but got %v", attrs)
+			}
+		})
+	}
+
+	if _, err := extAuthzInput(newCheckRequest(http.MethodPost, "/", map[string]string{"content-type": "application/json"}, "{"), false); err == nil {
+		t.Fatal("Expected error for invalid JSON body")
+	}
+}
+
+func TestExtAuthzResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note    string
+		result  string
+		exp     string
+		wantErr string
+	}{
+		{
+			note:   "undefined",
+			exp:    `{"status": {"code": 7}, "deniedResponse": {"status": {"code": "Forbidden"}}}`,
+			result: ``,
+		},
+		{
+			note:   "allowed",
+			result: `true`,
+			exp:    `{"status": {}, "okResponse": {}}`,
+		},
+		{
+			note:   "denied",
+			result: `false`,
+			exp:    `{"status": {"code": 7}, "deniedResponse": {"status": {"code": "Forbidden"}}}`,
+		},
+		{
+			note: "allowed object",
+			result: `{
+				"allowed": true,
+				"headers": {"x-user": "alice", "x-roles": ["a", "b"]},
+				"response_headers_to_add": {"x-checked": "yes"},
+				"request_headers_to_remove": ["authorization"],
+				"dynamic_metadata": {"user": "alice"}
+			}`,
+			exp: `{
+				"status": {},
+				"okResponse": {
+					"headers": [
+						{"header": {"key": "x-roles", "value": "a"}, "appendAction": "OVERWRITE_IF_EXISTS_OR_ADD"},
+						{"header": {"key": "x-roles", "value": "b"}},
+						{"header": {"key": "x-user", "value": "alice"}, "appendAction": "OVERWRITE_IF_EXISTS_OR_ADD"}
+					],
+					"headersToRemove": ["authorization"],
+					"responseHeadersToAdd": [
+						{"header": {"key": "x-checked", "value": "yes"}, "appendAction": "OVERWRITE_IF_EXISTS_OR_ADD"}
+					]
+				},
+				"dynamicMetadata": {"user": "alice"}
+			}`,
+		},
+		{
+			note:   "denied object",
+			result: `{"allowed": false, "http_status": 401, "body": "login first", "headers": {"www-authenticate": "Bearer"}}`,
+			exp: `{
+				"status": {"code": 7},
+				"deniedResponse": {
+					"status": {"code": "Unauthorized"},
+					"headers": [
+						{"header": {"key": "www-authenticate", "value": "Bearer"}, "appendAction": "OVERWRITE_IF_EXISTS_OR_ADD"}
+					],
+					"body": "login first"
+				}
+			}`,
+		},
+		{
+			note:    "missing allowed",
+			result:  `{"headers": {}}`,
+			wantErr: "allowed field must be a boolean",
+		},
+		{
+			note:    "invalid type",
+			result:  `"yes"`,
+			wantErr: `decision must be a boolean or an object, got "yes"`,
+		},
+		{
+			note:    "invalid status",
+			result:  `{"allowed": false, "http_status": 1000}`,
+			wantErr: "http_status field must be an HTTP status code, got 1000",
+		},
+		{
+			note:    "invalid header",
+			result:  `{"allowed": true, "headers": {"x-n": 1}}`,
+			wantErr: `headers field: value of header "x-n" must be a string or an array of strings`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			var result *any
+			if tc.result != "" {
+				var x any
+				if err := util.UnmarshalJSON([]byte(tc.result), &x); err != nil {
+					t.Fatal(err)
+				}
+				result = &x
+			}
+
+			resp, err := extAuthzResponse(result)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("Expected error %q but got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertProtoJSON(t, resp, tc.exp)
+		})
+	}
+}
+
+const extAuthzTestPolicy = `package envoy.authz
+
+default allow := false
+
+allow if input.parsed_path == ["public"]
+
+allow := {"allowed": true, "headers": {"x-user": input.attributes.request.http.headers["x-user"]}} if {
+	input.parsed_path == ["items"]
+	input.parsed_body.amount < 100
+}
+
+allow := {"allowed": false, "http_status": 402, "body": "over budget"} if {
+	input.parsed_path == ["items"]
+	input.parsed_body.amount >= 100
+}
+`
+
+func TestExtAuthzCheck(t *testing.T) {
+	t.Parallel()
+
+	var mtx sync.Mutex
+	var infos []*Info
+	f := newFixtureWithConfig(t, `{
+		"server": {"envoy_ext_authz": {"enabled": true}}
+	}`, func(s *Server) {
+		s.WithDecisionLoggerWithErr(func(_ context.Context, info *Info) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			infos = append(infos, info)
+			return nil
+		}).WithDecisionIDFactory(func() string {
+			return "42"
+		})
+	})
+	if err := f.v1(http.MethodPut, "/policies/envoy", extAuthzTestPolicy, http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	client := authv3.NewAuthorizationClient(newGRPCClient(t, f))
+	ctx := t.Context()
+	headers := map[string]string{"content-type": "application/json", "x-user": "alice"}
+
+	resp, err := client.Check(ctx, newCheckRequest(http.MethodPost, "/items", headers, `{"amount": 10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProtoJSON(t, resp, `{
+		"status": {},
+		"okResponse": {
+			"headers": [{"header": {"key": "x-user", "value": "alice"}, "appendAction": "OVERWRITE_IF_EXISTS_OR_ADD"}]
+		},
+		"dynamicMetadata": {"decision_id": "42"}
+	}`)
+
+	mtx.Lock()
+	if len(infos) != 1 || infos[0].Path != "envoy/authz/allow" || infos[0].DecisionID != "42" {
+		t.Fatalf("Expected decision to be logged but got %+v", infos)
+	}
+	if act := infos[0].HTTPRequestContext.Header.Get("X-User"); act != "alice" {
+		t.Fatalf("Expected headers of the checked request in the request context but got %v", infos[0].HTTPRequestContext.Header)
+	}
+	mtx.Unlock()
+
+	resp, err = client.Check(ctx, newCheckRequest(http.MethodPost, "/items", headers, `{"amount": 500}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus().GetCode() != int32(codes.PermissionDenied) ||
+		resp.GetDeniedResponse().GetStatus().GetCode() != typev3.StatusCode_PaymentRequired ||
+		resp.GetDeniedResponse().GetBody() != "over budget" {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	resp, err = client.Check(ctx, newCheckRequest(http.MethodGet, "/private", nil, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus().GetCode() != int32(codes.PermissionDenied) || resp.GetDeniedResponse().GetStatus().GetCode() != typev3.StatusCode_Forbidden {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	_, err = client.Check(ctx, newCheckRequest(http.MethodPost, "/items", headers, `{"amount":`))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected code InvalidArgument but got %v", err)
+	}
+}
+
+func TestExtAuthzReconfigure(t *testing.T) {
+	t.Parallel()
+
+	f := newFixtureWithConfig(t, `{}`)
+	if err := f.v1(http.MethodPut, "/policies/envoy", extAuthzTestPolicy, http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+	client := authv3.NewAuthorizationClient(newGRPCClient(t, f))
+	req := newCheckRequest(http.MethodGet, "/private", nil, "")
+
+	reconfigure := func(extAuthz string) {
+		t.Helper()
+		cfg, err := config.ParseConfig([]byte(`{"server": {"envoy_ext_authz": `+extAuthz+`}}`), "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.server.manager.Reconfigure(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Disabled by default.
+	if _, err := client.Check(t.Context(), req); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("Expected code Unimplemented but got %v", err)
+	}
+
+	reconfigure(`{"enabled": true, "dry_run": true}`)
+
+	resp, err := client.Check(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(resp, &authv3.CheckResponse{
+		Status:       resp.GetStatus(),
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{}},
+	}) || resp.GetStatus().GetCode() != int32(codes.OK) {
+		t.Fatalf("Expected dry run to allow the request but got %v", protojson.Format(resp))
+	}
+
+	// An invalid update keeps the previous config.
+	reconfigure(`{"enabled": true, "path": ""}`)
+
+	if _, err := client.Check(t.Context(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	reconfigure(`{"enabled": true, "path": "envoy/authz/missing"}`)
+
+	resp, err = client.Check(t.Context(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetDeniedResponse().GetStatus().GetCode() != typev3.StatusCode_Forbidden {
+		t.Fatalf("Expected undefined decision to deny the request but got %v", resp)
+	}
+}
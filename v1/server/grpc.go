@@ -0,0 +1,754 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/server/authorizer"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/v1pb"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// The gRPC API is served by the same functions as the REST API. Every call is
+// authenticated, rate limited, authorized and recorded in the metrics like the
+// equivalent REST API request, which is also what the authorization policy
+// and the decision logs see. Middlewares of the REST API, e.g. those added by
+// plugins, don't apply to gRPC calls.
+
+// grpcDroppedMetadata are gRPC metadata keys that are specific to the gRPC
+// protocol, and aren't passed on as headers of the equivalent request.
+var grpcDroppedMetadata = map[string]struct{}{
+	"content-type":    {},
+	"content-length":  {},
+	"accept-encoding": {},
+	"te":              {},
+}
+
+var grpcUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// requestObserver is implemented by Metrics that can record requests which
+// aren't served over HTTP.
+type requestObserver interface {
+	ObserveRequest(ctx context.Context, label, method string, code int, duration time.Duration)
+}
+
+// grpcCall describes the REST API request equivalent to a gRPC call.
+type grpcCall struct {
+	method   string
+	path     string // escaped URL path
+	endpoint string // label of the endpoint in rate limits and metrics
+	query    url.Values
+	header   http.Header // headers set in addition to the call's metadata
+	body     any
+}
+
+// handleGRPC authenticates, rate limits and authorizes a call before it's
+// handled by handle, and records it in the metrics.
+func (s *Server) handleGRPC(ctx context.Context, call *grpcCall, handle func(context.Context) error) error {
+	start := time.Now()
+	err := s.serveGRPC(ctx, call, handle)
+	s.observeGRPC(ctx, call.endpoint, call.method, err, time.Since(start))
+	return err
+}
+
+func (s *Server) serveGRPC(ctx context.Context, call *grpcCall, handle func(context.Context) error) (err error) {
+	header := http.Header{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			if _, drop := grpcDroppedMetadata[key]; drop || strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") || strings.HasSuffix(key, "-bin") {
+				continue
+			}
+			for _, v := range values {
+				header.Add(key, v)
+			}
+		}
+	}
+	for key, values := range call.header {
+		header[key] = values
+	}
+	if call.body != nil {
+		header.Set("Content-Type", "application/json")
+	}
+
+	var remoteAddr string
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+
+	if s.identify != nil {
+		identified, err := s.identify(ctx, header, state)
+		if err != nil {
+			return newAPIError(http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, types.MsgUnauthenticatedError))
+		}
+		ctx = identified
+	}
+
+	if s.rateLimiter != nil {
+		release, retryAfter, ok := s.rateLimiter.admit(ctx, header, remoteAddr, call.endpoint)
+		if !ok {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+			return newAPIError(http.StatusTooManyRequests, types.NewErrorV1(types.CodeTooManyRequests, types.MsgRateLimitError))
+		}
+		defer release()
+	}
+
+	if s.authorizer != nil {
+		start := time.Now()
+		defer func() {
+			s.observeGRPC(ctx, PromHandlerAPIAuthz, call.method, err, time.Since(start))
+		}()
+
+		status, e := s.authorizer.Authorize(ctx, &authorizer.Request{
+			Method: call.method,
+			Path:   call.path,
+			Query:  call.query,
+			Header: header,
+			Body:   call.body,
+		})
+		if e != nil {
+			return newAPIError(status, e)
+		}
+	}
+
+	// The call doesn't go through the runtime's HTTP logging handler, which
+	// sets the request context of decision logs.
+	if _, ok := logging.HTTPRequestContextFromContext(ctx); !ok {
+		ctx = logging.WithHTTPRequestContext(ctx, &logging.HTTPRequestContext{Header: header})
+	}
+	if _, ok := logging.FromContext(ctx); !ok {
+		ctx = logging.NewContext(ctx, &logging.RequestContext{
+			ClientAddr: remoteAddr,
+			ReqMethod:  call.method,
+			ReqPath:    call.path,
+		})
+	}
+
+	return handle(ctx)
+}
+
+// observeGRPC records a call in the metrics, with the status of the
+// equivalent REST API response.
+func (s *Server) observeGRPC(ctx context.Context, label, method string, err error, duration time.Duration) {
+	o, ok := s.metrics.(requestObserver)
+	if !ok {
+		return
+	}
+	code := http.StatusOK
+	if err != nil {
+		code = apiErrorFor(err).status
+	}
+	o.ObserveRequest(ctx, label, method, code, duration)
+}
+
+// grpcCode returns the gRPC status code equivalent to the HTTP status of e.
+func (e *apiError) grpcCode() codes.Code {
+	switch e.status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		// Requests rejected by the authorizer are answered with 401 too, but
+		// only failed authentication asks the client to authenticate.
+		if e.resp.Code == types.CodeUnauthorized && e.resp.Message != types.MsgUnauthenticatedError {
+			return codes.PermissionDenied
+		}
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusInternalServerError:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// proto returns e as an Error message, for errors reported in responses.
+func (e *apiError) proto() *v1pb.Error {
+	result := &v1pb.Error{Code: proto.String(e.resp.Code), Message: proto.String(e.resp.Message)}
+	for _, detail := range e.resp.Errors {
+		bs, err := json.Marshal(detail)
+		if err != nil {
+			continue
+		}
+		var s structpb.Struct
+		if grpcUnmarshalOptions.Unmarshal(bs, &s) == nil {
+			result.Errors = append(result.Errors, &s)
+		}
+	}
+	return result
+}
+
+// grpcStatus returns the error of a failed call.
+func grpcStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	e := apiErrorFor(err)
+	return status.Error(e.grpcCode(), e.resp.Message)
+}
+
+func grpcInvalidArgument(err error) error {
+	return newAPIError(http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, "%s", err.Error()))
+}
+
+// grpcPath returns the escaped decision path of a call, without leading and
+// trailing slashes.
+func grpcPath(path string) string {
+	return (&url.URL{Path: strings.Trim(path, "/")}).EscapedPath()
+}
+
+// grpcURLPath returns the URL path of the endpoint at prefix for the escaped
+// decision path.
+func grpcURLPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	return prefix + "/" + path
+}
+
+func grpcQuery(provenance bool, explain string, metrics, instrument, strictBuiltinErrors bool) url.Values {
+	q := url.Values{}
+	for name, set := range map[string]bool{
+		types.ParamProvenanceV1:        provenance,
+		types.ParamMetricsV1:           metrics,
+		types.ParamInstrumentV1:        instrument,
+		types.ParamStrictBuiltinErrors: strictBuiltinErrors,
+	} {
+		if set {
+			q.Set(name, "true")
+		}
+	}
+	if explain != "" {
+		q.Set(types.ParamExplainV1, explain)
+	}
+	return q
+}
+
+// grpcURL returns the URL of the REST API request equivalent to call, which
+// its options are parsed from.
+func grpcURL(call *grpcCall) *url.URL {
+	return &url.URL{Path: call.path, RawQuery: call.query.Encode()}
+}
+
+// grpcJSON returns the JSON encoding of a well-known type, nil if it's unset.
+func grpcJSON(m proto.Message) (json.RawMessage, error) {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return nil, nil
+	}
+	return protojson.Marshal(m)
+}
+
+// grpcValue returns the value of a well-known type, nil if it's unset.
+func grpcValue(m proto.Message) (*any, error) {
+	bs, err := grpcJSON(m)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	var v any
+	if err := util.UnmarshalJSON(bs, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// grpcMessage converts a REST API response into the equivalent gRPC response.
+func grpcMessage(v any, m proto.Message) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return grpcUnmarshalOptions.Unmarshal(bs, m)
+}
+
+type grpcDataService struct {
+	v1pb.UnimplementedDataServiceServer
+	s *Server
+}
+
+func (d *grpcDataService) GetData(ctx context.Context, req *v1pb.DataRequest) (*v1pb.DataResponse, error) {
+	resp, err := d.s.grpcGetData(ctx, req)
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return resp, nil
+}
+
+func (d *grpcDataService) StreamData(stream grpc.BidiStreamingServer[v1pb.DataRequest, v1pb.DataResponse]) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err := d.s.grpcGetData(ctx, req)
+		if err != nil {
+			resp = &v1pb.DataResponse{Error: apiErrorFor(err).proto()}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *grpcDataService) BatchGetData(ctx context.Context, req *v1pb.BatchDataRequest) (*v1pb.BatchDataResponse, error) {
+	inputs := make([]batchInput, 0, len(req.GetInputs()))
+	body := make(map[string]any, len(req.GetInputs()))
+	for _, id := range util.KeysSorted(req.GetInputs()) {
+		in := batchInput{id: id}
+		goInput, err := grpcValue(req.GetInputs()[id])
+		if err != nil {
+			return nil, grpcStatus(grpcInvalidArgument(err))
+		}
+		if goInput != nil {
+			if in.value, err = ast.InterfaceToValue(*goInput); err != nil {
+				return nil, grpcStatus(grpcInvalidArgument(err))
+			}
+			in.goInput = goInput
+			body[id] = *goInput
+		} else {
+			body[id] = nil
+		}
+		inputs = append(inputs, in)
+	}
+
+	path := grpcPath(req.GetPath())
+	call := &grpcCall{
+		method:   http.MethodPost,
+		path:     grpcURLPath("/v1/batch/data", path),
+		endpoint: PromHandlerV1Batch,
+		query:    grpcQuery(req.GetProvenance(), req.GetExplain(), req.GetMetrics(), req.GetInstrument(), req.GetStrictBuiltinErrors()),
+		body:     map[string]any{"inputs": body},
+	}
+
+	var result v1pb.BatchDataResponse
+	err := d.s.handleGRPC(ctx, call, func(ctx context.Context) error {
+		opts := getDataOptions(grpcURL(call))
+		m := d.s.newMetrics(opts.metrics, opts.instrument)
+		m.Timer(metrics.ServerHandler).Start()
+
+		resp, _, err := d.s.evalBatchData(ctx, m, path, inputs, opts)
+		if err != nil {
+			return err
+		}
+		return grpcMessage(resp, &result)
+	})
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &result, nil
+}
+
+func (s *Server) grpcGetData(ctx context.Context, req *v1pb.DataRequest) (*v1pb.DataResponse, error) {
+	input := &parsedInput{}
+	body := map[string]any{}
+
+	if md := req.GetMetadata(); md != nil {
+		v, err := grpcValue(md)
+		if err != nil {
+			return nil, grpcInvalidArgument(err)
+		}
+		if fields, ok := (*v).(map[string]any); ok && len(fields) > 0 {
+			input.Metadata = fields
+			for key, value := range fields {
+				body[key] = value
+			}
+		}
+	}
+
+	goInput, err := grpcValue(req.GetInput())
+	if err != nil {
+		return nil, grpcInvalidArgument(err)
+	}
+	if goInput != nil {
+		if input.Value, err = ast.InterfaceToValue(*goInput); err != nil {
+			return nil, grpcInvalidArgument(err)
+		}
+		input.GoInput = goInput
+		body["input"] = *goInput
+	}
+
+	path := grpcPath(req.GetPath())
+	call := &grpcCall{
+		method:   http.MethodPost,
+		path:     grpcURLPath("/v1/data", path),
+		endpoint: PromHandlerV1Data,
+		query:    grpcQuery(req.GetProvenance(), req.GetExplain(), req.GetMetrics(), req.GetInstrument(), req.GetStrictBuiltinErrors()),
+		body:     body,
+	}
+
+	var result v1pb.DataResponse
+	err = s.handleGRPC(ctx, call, func(ctx context.Context) error {
+		return s.grpcEvalData(ctx, call, path, input, &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// grpcEvalData evaluates the decision of a DataService call into result.
+func (s *Server) grpcEvalData(ctx context.Context, call *grpcCall, path string, input *parsedInput, result *v1pb.DataResponse) error {
+	opts := getDataOptions(grpcURL(call))
+	m := s.newMetrics(opts.metrics, opts.instrument)
+	m.Timer(metrics.ServerHandler).Start()
+
+	resp, err := s.evalData(ctx, m, &dataRequest{
+		name:        call.method + " " + call.path,
+		path:        path,
+		input:       input,
+		dataOptions: opts,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := grpcMessage(resp, result); err != nil {
+		return err
+	}
+
+	// Response metadata is returned in the metadata field.
+	if len(resp.Metadata) > 0 {
+		bs, err := json.Marshal(resp.Metadata)
+		if err != nil {
+			return err
+		}
+		result.Metadata = &structpb.Struct{}
+		return grpcUnmarshalOptions.Unmarshal(bs, result.Metadata)
+	}
+	return nil
+}
+
+type grpcCompileService struct {
+	v1pb.UnimplementedCompileServiceServer
+	s *Server
+}
+
+func (c *grpcCompileService) Compile(ctx context.Context, req *v1pb.CompileRequest) (*v1pb.CompileResponse, error) {
+	body := map[string]any{}
+	if req.Query != nil {
+		body["query"] = req.GetQuery()
+	}
+	if len(req.GetUnknowns()) > 0 {
+		body["unknowns"] = req.GetUnknowns()
+	}
+	input, err := grpcValue(req.GetInput())
+	if err != nil {
+		return nil, grpcStatus(grpcInvalidArgument(err))
+	}
+	if input != nil {
+		body["input"] = *input
+	}
+	options, err := grpcValue(req.GetOptions())
+	if err != nil {
+		return nil, grpcStatus(grpcInvalidArgument(err))
+	}
+	if options != nil {
+		body["options"] = *options
+	}
+
+	// The request body is parsed like the body of the equivalent request.
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, grpcStatus(grpcInvalidArgument(err))
+	}
+
+	path := strings.Trim(req.GetPath(), "/")
+	call := &grpcCall{
+		method:   http.MethodPost,
+		path:     grpcURLPath("/v1/compile", grpcPath(path)),
+		endpoint: PromHandlerV1Compile,
+		query:    grpcQuery(false, req.GetExplain(), req.GetMetrics(), req.GetInstrument(), false),
+		header:   http.Header{},
+		body:     body,
+	}
+	if req.GetTarget() != "" {
+		call.header.Set("Accept", req.GetTarget())
+	}
+
+	var result v1pb.CompileResponse
+	err = c.s.handleGRPC(ctx, call, func(ctx context.Context) error {
+		opts := getCompileOptions(grpcURL(call))
+
+		m := metrics.New()
+		m.Timer(metrics.ServerHandler).Start()
+		m.Timer(metrics.RegoQueryParse).Start()
+
+		if path != "" {
+			resp, _, err := c.s.compileFilters(ctx, m, path, bs, req.GetTarget(), opts)
+			if err != nil {
+				return err
+			}
+			return grpcMessage(resp, &result)
+		}
+
+		request, reqErr := readInputCompilePostV1(bs, c.s.manager.ParserOptions())
+		if reqErr != nil {
+			return newAPIError(http.StatusBadRequest, reqErr)
+		}
+
+		m.Timer(metrics.RegoQueryParse).Stop()
+
+		resp, err := c.s.compile(ctx, m, request, opts)
+		if err != nil {
+			return err
+		}
+		return grpcMessage(resp, &result)
+	})
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &result, nil
+}
+
+type grpcHealthService struct {
+	v1pb.UnimplementedHealthServiceServer
+	s *Server
+}
+
+func (h *grpcHealthService) Check(ctx context.Context, req *v1pb.HealthRequest) (*v1pb.HealthResponse, error) {
+	if err := h.s.grpcHealth(ctx, req.GetBundles(), req.GetPlugins(), req.GetExcludePlugin()); err != nil {
+		return nil, err
+	}
+	return &v1pb.HealthResponse{}, nil
+}
+
+// grpcHealth checks the health of OPA like GET /health, failing with code
+// UNAVAILABLE if OPA isn't healthy.
+func (s *Server) grpcHealth(ctx context.Context, bundles, plugins bool, excludePlugin []string) error {
+	query := url.Values{}
+	if bundles {
+		query.Set(types.ParamBundlesActivationV1, "true")
+	}
+	if plugins {
+		query.Set(types.ParamPluginsV1, "true")
+	}
+	for _, name := range excludePlugin {
+		query.Add(types.ParamExcludePluginV1, name)
+	}
+
+	call := &grpcCall{
+		method:   http.MethodGet,
+		path:     "/health",
+		endpoint: PromHandlerHealth,
+		query:    query,
+	}
+
+	var unhealthy error
+	err := s.handleGRPC(ctx, call, func(ctx context.Context) error {
+		unhealthy = s.checkHealth(ctx, bundles, plugins, excludePlugin)
+		if unhealthy != nil {
+			// Recorded in the metrics like the response to GET /health.
+			return newAPIError(http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, "%s", unhealthy.Error()))
+		}
+		return nil
+	})
+	if unhealthy != nil {
+		return status.Error(codes.Unavailable, unhealthy.Error())
+	}
+	if err != nil {
+		return grpcStatus(err)
+	}
+	return nil
+}
+
+// grpcStandardHealthService implements the standard gRPC health checking
+// protocol, e.g. for gRPC liveness and readiness probes. Only the overall
+// health of the server can be checked.
+type grpcStandardHealthService struct {
+	healthpb.UnimplementedHealthServer
+	s *Server
+}
+
+func (h *grpcStandardHealthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() != "" {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	err := h.s.grpcHealth(ctx, false, false, nil)
+	if status.Code(err) == codes.Unavailable {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+type grpcStatusService struct {
+	v1pb.UnimplementedStatusServiceServer
+	s *Server
+}
+
+func (st *grpcStatusService) GetStatus(ctx context.Context, _ *v1pb.StatusRequest) (*v1pb.StatusResponse, error) {
+	call := &grpcCall{
+		method:   http.MethodGet,
+		path:     "/v1/status",
+		endpoint: PromHandlerV1Status,
+	}
+
+	var result v1pb.StatusResponse
+	err := st.s.handleGRPC(ctx, call, func(context.Context) error {
+		resp, err := st.s.getStatus()
+		if err != nil {
+			return err
+		}
+		return grpcMessage(resp, &result)
+	})
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &result, nil
+}
+
+// newGRPCServer returns a gRPC server with all services of the gRPC API.
+func (s *Server) newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	if s.decodingMaxLength > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(s.decodingMaxLength)))
+	}
+	srv := grpc.NewServer(opts...)
+	v1pb.RegisterDataServiceServer(srv, &grpcDataService{s: s})
+	v1pb.RegisterCompileServiceServer(srv, &grpcCompileService{s: s})
+	v1pb.RegisterHealthServiceServer(srv, &grpcHealthService{s: s})
+	v1pb.RegisterStatusServiceServer(srv, &grpcStatusService{s: s})
+	healthpb.RegisterHealthServer(srv, &grpcStandardHealthService{s: s})
+	authv3.RegisterAuthorizationServer(srv, &grpcExtAuthzService{s: s})
+	return srv
+}
+
+func (s *Server) getListenerForGRPC(addr string) ([]Loop, httpListener, error) {
+	parsedURL, err := parseURL(addr, s.cert != nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l := &grpcListener{network: "tcp", address: parsedURL.Host}
+	loops := []Loop{l.ListenAndServe}
+
+	switch parsedURL.Scheme {
+	case "unix":
+		if l.listener, err = s.listenUnixSocket(parsedURL); err != nil {
+			return nil, nil, err
+		}
+		l.s = s.newGRPCServer()
+	case "http":
+		l.s = s.newGRPCServer()
+	case "https":
+		if s.cert == nil {
+			return nil, nil, errors.New("TLS certificate required but not supplied")
+		}
+		l.s = s.newGRPCServer(grpc.Creds(credentials.NewTLS(s.tlsConfig("h2"))))
+		loops = append(loops, s.certLoops()...)
+	default:
+		return nil, nil, fmt.Errorf("invalid url scheme %q", parsedURL.Scheme)
+	}
+
+	return loops, l, nil
+}
+
+// grpcListener serves the gRPC API.
+type grpcListener struct {
+	s        *grpc.Server
+	network  string
+	address  string
+	listener net.Listener // set if already listening, e.g. on a unix socket
+	addr     string
+	addrMtx  sync.RWMutex
+}
+
+var _ httpListener = (*grpcListener)(nil)
+
+func (g *grpcListener) ListenAndServe() error {
+	l := g.listener
+	if l == nil {
+		var err error
+		if l, err = net.Listen(g.network, g.address); err != nil {
+			return err
+		}
+	}
+
+	g.addrMtx.Lock()
+	g.addr = l.Addr().String()
+	g.addrMtx.Unlock()
+
+	return g.s.Serve(l)
+}
+
+// ListenAndServeTLS is the same as ListenAndServe, as the TLS configuration of
+// a gRPC server is set when it's created.
+func (g *grpcListener) ListenAndServeTLS(string, string) error {
+	return g.ListenAndServe()
+}
+
+func (g *grpcListener) Addr() string {
+	g.addrMtx.RLock()
+	defer g.addrMtx.RUnlock()
+	return g.addr
+}
+
+// Shutdown stops the server gracefully, waiting for pending calls to finish,
+// unless ctx is done first.
+func (g *grpcListener) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.s.Stop()
+		return ctx.Err()
+	}
+}
+
+func (*grpcListener) Type() httpListenerType {
+	return grpcListenerType
+}
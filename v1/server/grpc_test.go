@@ -0,0 +1,484 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/open-policy-agent/opa/internal/prometheus"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/v1pb"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+// newGRPCClient serves the gRPC API of f's server on a local port, and returns
+// a client connection to it.
+func newGRPCClient(t *testing.T, f *fixture) *grpc.ClientConn {
+	t.Helper()
+
+	loops, listener, err := f.server.getListenerForGRPC("localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, loop := range loops {
+		go func() { _ = loop() }()
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = listener.Shutdown(ctx)
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for listener.Addr() == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("gRPC listener didn't start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	conn, err := grpc.NewClient(listener.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func mustValue(t *testing.T, js string) *structpb.Value {
+	t.Helper()
+	var v structpb.Value
+	if err := protojson.Unmarshal([]byte(js), &v); err != nil {
+		t.Fatal(err)
+	}
+	return &v
+}
+
+func assertProtoJSON(t *testing.T, m proto.Message, exp string) {
+	t.Helper()
+	bs, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, string(bs), exp) {
+		t.Fatalf("Expected %s but got %s", exp, bs)
+	}
+}
+
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	var x, y structpb.Value
+	if err := protojson.Unmarshal([]byte(a), &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := protojson.Unmarshal([]byte(b), &y); err != nil {
+		t.Fatal(err)
+	}
+	return proto.Equal(&x, &y)
+}
+
+const grpcTestPolicy = `package test
+
+allow if input.user == "alice"
+
+deny contains "bob" if input.user == "bob"
+
+fail if 1 / 0
+
+filter if input.fruits.name == "apple"
+`
+
+func TestGRPCData(t *testing.T) {
+	t.Parallel()
+
+	var mtx sync.Mutex
+	var infos []*Info
+	f := newFixture(t, func(s *Server) {
+		s.WithDecisionLoggerWithErr(func(_ context.Context, info *Info) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			infos = append(infos, info)
+			return nil
+		}).WithDecisionIDFactory(func() string {
+			return "42"
+		})
+	})
+	if err := f.v1(http.MethodPut, "/policies/test", grpcTestPolicy, http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	client := v1pb.NewDataServiceClient(newGRPCClient(t, f))
+	ctx := t.Context()
+
+	resp, err := client.GetData(ctx, &v1pb.DataRequest{
+		Path:     proto.String("test/allow"),
+		Input:    mustValue(t, `{"user": "alice"}`),
+		Metadata: &structpb.Struct{Fields: map[string]*structpb.Value{"tenant": structpb.NewStringValue("acme")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetDecisionId() == "" || !resp.GetResult().GetBoolValue() {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	mtx.Lock()
+	if len(infos) != 1 || infos[0].DecisionID != resp.GetDecisionId() || infos[0].Path != "test/allow" {
+		t.Fatalf("Expected decision %q to be logged but got %+v", resp.GetDecisionId(), infos)
+	}
+	if input, ok := (*infos[0].Input).(map[string]any); !ok || input["user"] != "alice" {
+		t.Fatalf("Expected input to be logged but got %v", *infos[0].Input)
+	}
+	mtx.Unlock()
+
+	// Undefined decisions have no result, and a warning if the input is missing.
+	resp, err = client.GetData(ctx, &v1pb.DataRequest{Path: proto.String("test/missing")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result != nil || resp.GetWarning().GetCode() != types.CodeAPIUsageWarn {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	_, err = client.GetData(ctx, &v1pb.DataRequest{Path: proto.String("test/fail"), StrictBuiltinErrors: proto.Bool(true)})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected code Internal but got %v", err)
+	}
+
+	batch, err := client.BatchGetData(ctx, &v1pb.BatchDataRequest{
+		Path: proto.String("test/deny"),
+		Inputs: map[string]*structpb.Value{
+			"a": mustValue(t, `{"user": "alice"}`),
+			"b": mustValue(t, `{"user": "bob"}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.GetBatchDecisionId() == "" || len(batch.GetResponses()) != 2 {
+		t.Fatalf("Unexpected response: %v", batch)
+	}
+	for id, exp := range map[string]string{"a": `[]`, "b": `["bob"]`} {
+		item := batch.GetResponses()[id]
+		if item.GetHttpStatusCode() != http.StatusOK || item.GetDecisionId() == "" {
+			t.Fatalf("Unexpected response %q: %v", id, item)
+		}
+		bs, err := protojson.Marshal(item.GetResult())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, string(bs), exp) {
+			t.Fatalf("Expected result %s for %q but got %s", exp, id, bs)
+		}
+	}
+}
+
+func TestGRPCStreamData(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	if err := f.v1(http.MethodPut, "/policies/test", grpcTestPolicy, http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := v1pb.NewDataServiceClient(newGRPCClient(t, f)).StreamData(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := []*v1pb.DataRequest{
+		{Path: proto.String("test/allow"), Input: mustValue(t, `{"user": "alice"}`)},
+		{Path: proto.String("test/fail"), Input: mustValue(t, `{}`), StrictBuiltinErrors: proto.Bool(true)},
+		{Path: proto.String("test/allow"), Input: mustValue(t, `{"user": "bob"}`)},
+	}
+	for _, req := range reqs {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var resps []*v1pb.DataResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		resps = append(resps, resp)
+	}
+
+	if len(resps) != 3 {
+		t.Fatalf("Expected 3 responses but got %d", len(resps))
+	}
+	if !resps[0].GetResult().GetBoolValue() || resps[0].Error != nil {
+		t.Fatalf("Unexpected first response: %v", resps[0])
+	}
+	if resps[1].GetError().GetCode() != types.CodeInternal || resps[1].GetDecisionId() != "" {
+		t.Fatalf("Expected second response to fail but got: %v", resps[1])
+	}
+	if resps[2].Result != nil || resps[2].Error != nil {
+		t.Fatalf("Expected third decision to be undefined but got: %v", resps[2])
+	}
+}
+
+func TestGRPCCompile(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	if err := f.v1(http.MethodPut, "/policies/test", grpcTestPolicy, http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	client := v1pb.NewCompileServiceClient(newGRPCClient(t, f))
+
+	resp, err := client.Compile(t.Context(), &v1pb.CompileRequest{
+		Query:    proto.String("data.test.allow == true"),
+		Unknowns: []string{"input"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetResult().GetStructValue().GetFields()["queries"].GetListValue().GetValues()) != 1 {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	resp, err = client.Compile(t.Context(), &v1pb.CompileRequest{
+		Path:     proto.String("test/filter"),
+		Unknowns: []string{"input.fruits"},
+		Target:   proto.String("application/vnd.opa.ucast.prisma+json"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProtoJSON(t, resp.GetResult(), `{"query": {"field": "fruits.name", "operator": "eq", "type": "field", "value": "apple"}}`)
+
+	_, err = client.Compile(t.Context(), &v1pb.CompileRequest{Query: proto.String("data.test.allow ==")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected code InvalidArgument but got %v", err)
+	}
+}
+
+func TestGRPCHealthAndStatus(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	conn := newGRPCClient(t, f)
+
+	if _, err := v1pb.NewHealthServiceClient(conn).Check(t.Context(), &v1pb.HealthRequest{Bundles: proto.Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Expected SERVING but got %v", resp.GetStatus())
+	}
+
+	_, err = healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{Service: "other"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected code NotFound but got %v", err)
+	}
+
+	// The status plugin isn't registered.
+	_, err = v1pb.NewStatusServiceClient(conn).GetStatus(t.Context(), &v1pb.StatusRequest{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected code Internal but got %v", err)
+	}
+}
+
+func TestGRPCAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.New()
+	txn := storage.NewTransactionOrDie(ctx, store, storage.WriteParams)
+	if err := store.UpsertPolicy(ctx, txn, "authz.rego", []byte(`package system.authz
+
+default allow := false
+
+allow if input.path == ["metrics"]
+
+allow if {
+	input.path == ["v1", "data", "test", "allow"]
+	input.headers["X-Tenant"] == ["acme"]
+}
+`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertPolicy(ctx, txn, "test.rego", []byte(grpcTestPolicy)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	prom := prometheus.New(metrics.New(), nil, []float64{1})
+	f := newFixtureWithStore(t, store, func(s *Server) {
+		s.WithAuthorization(AuthorizationBasic).WithMetrics(prom)
+	})
+	client := v1pb.NewDataServiceClient(newGRPCClient(t, f))
+
+	req := &v1pb.DataRequest{Path: proto.String("test/allow"), Input: mustValue(t, `{"user": "alice"}`)}
+
+	_, err := client.GetData(ctx, req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected code PermissionDenied but got %v", err)
+	}
+
+	resp, err := client.GetData(metadata.AppendToOutgoingContext(ctx, "x-tenant", "acme"), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.GetResult().GetBoolValue() {
+		t.Fatalf("Unexpected response: %v", resp)
+	}
+
+	f.reset()
+	f.server.DiagnosticHandler.ServeHTTP(f.recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(f.recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`http_request_duration_seconds_count{code="200",handler="v1/data",method="post"} 1`,
+		`http_request_duration_seconds_count{code="401",handler="v1/data",method="post"} 1`,
+		`http_request_duration_seconds_count{code="200",handler="authz",method="post"} 1`,
+		`http_request_duration_seconds_count{code="401",handler="authz",method="post"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metric %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.New()
+	txn := storage.NewTransactionOrDie(ctx, store, storage.WriteParams)
+	if err := store.UpsertPolicy(ctx, txn, "authz.rego", []byte(`package system.authz
+
+default allow := false
+
+allow if input.identity == "secret"
+`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFixtureWithStore(t, store, func(s *Server) {
+		s.WithAuthentication(AuthenticationToken).WithAuthorization(AuthorizationBasic)
+	})
+	client := v1pb.NewDataServiceClient(newGRPCClient(t, f))
+
+	if _, err := client.GetData(ctx, &v1pb.DataRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected code PermissionDenied but got %v", err)
+	}
+
+	if _, err := client.GetData(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret"), &v1pb.DataRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGRPCRateLimits(t *testing.T) {
+	t.Parallel()
+
+	f := newFixtureWithConfig(t, `{"server": {"rate_limits": {
+		"client_key": "header",
+		"client_header": "X-Tenant",
+		"endpoints": {"v1/data": {"requests_per_second": 1}}
+	}}}`)
+	client := v1pb.NewDataServiceClient(newGRPCClient(t, f))
+
+	tenant := func(name string) context.Context {
+		return metadata.AppendToOutgoingContext(t.Context(), "x-tenant", name)
+	}
+
+	if _, err := client.GetData(tenant("a"), &v1pb.DataRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var header metadata.MD
+	_, err := client.GetData(tenant("a"), &v1pb.DataRequest{}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected code ResourceExhausted but got %v", err)
+	}
+	if act := header.Get("retry-after"); len(act) != 1 || act[0] != "1" {
+		t.Fatalf("Expected retry-after 1 but got %v", act)
+	}
+
+	// The limits are shared with the REST API.
+	req := httptest.NewRequest(http.MethodPost, "/v1/data", nil)
+	req.Header.Set("X-Tenant", "a")
+	if err := f.executeRequest(req, http.StatusTooManyRequests, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetData(tenant("b"), &v1pb.DataRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGRPCUnixSocketPermission(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket permissions are not supported on Windows")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "grpc.sock")
+	perm := "600"
+	f := newFixture(t, func(s *Server) {
+		s.WithUnixSocketPermission(&perm)
+	})
+
+	_, listener, err := f.server.getListenerForGRPC("unix://" + socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Shutdown(t.Context())
+	})
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Fatalf("Expected socket permissions 0600 but got %o", mode)
+	}
+}
@@ -29,7 +29,7 @@ func CompressHandler(handler http.Handler, gzipMinLength int, gzipCompressionLev
 	initGzipPool(gzipCompressionLevel)
 
 	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
-		enabledForEndpoint := isDataEndpoint(request) || isCompileEndpoint(request)
+		enabledForEndpoint := (isDataEndpoint(request) && !isWatchRequest(request)) || isCompileEndpoint(request)
 		if !enabledForEndpoint {
 			handler.ServeHTTP(responseWriter, request)
 			return
@@ -199,6 +199,17 @@ func isDataEndpoint(req *http.Request) bool {
 	return isPostOrGetMethod && isV1rV0
 }
 
+// isWatchRequest returns true for data watch requests, whose responses are
+// streamed and must not be buffered.
+func isWatchRequest(req *http.Request) bool {
+	for _, v := range req.URL.Query()["watch"] {
+		if v == "" || strings.EqualFold(v, "true") {
+			return true
+		}
+	}
+	return false
+}
+
 func isCompileEndpoint(req *http.Request) bool {
 	return isPostMethod(req) && strings.HasPrefix(req.URL.Path, "/v1/compile")
 }
@@ -12,8 +12,12 @@ const clientCertificates = clientCertificatesKey("org.openpolicyagent/client-cer
 
 // ClientCertificates returns the ClientCertificates of the caller associated with ctx.
 func ClientCertificates(r *http.Request) ([]*x509.Certificate, bool) {
-	ctx := r.Context()
+	return ClientCertificatesFromContext(r.Context())
+}
 
+// ClientCertificatesFromContext returns the ClientCertificates of the caller
+// associated with ctx.
+func ClientCertificatesFromContext(ctx context.Context) ([]*x509.Certificate, bool) {
 	certs, ok := ctx.Value(clientCertificates).([]*x509.Certificate)
 
 	return certs, ok
@@ -21,5 +25,11 @@ func ClientCertificates(r *http.Request) ([]*x509.Certificate, bool) {
 
 // SetClientCertificates returns a new http.Request with the ClientCertificates set to v.
 func SetClientCertificates(r *http.Request, v []*x509.Certificate) *http.Request {
-	return r.WithContext(context.WithValue(r.Context(), clientCertificates, v))
+	return r.WithContext(WithClientCertificates(r.Context(), v))
+}
+
+// WithClientCertificates returns a copy of ctx with the ClientCertificates set
+// to v.
+func WithClientCertificates(ctx context.Context, v []*x509.Certificate) context.Context {
+	return context.WithValue(ctx, clientCertificates, v)
 }
@@ -7,16 +7,27 @@ package identifier
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 )
 
+// IdentifyFunc identifies the caller of a request by its headers and the state
+// of its TLS connection, like the handlers of this package do, and returns ctx
+// with the identity of the caller. It lets transports other than HTTP, e.g.
+// the gRPC API, authenticate their requests.
+type IdentifyFunc func(ctx context.Context, header http.Header, state *tls.ConnectionState) (context.Context, error)
+
 type identityKey string
 
 const identity = identityKey("org.openpolicyagent/identity")
 
 // Identity returns the identity of the caller associated with ctx.
 func Identity(r *http.Request) (string, bool) {
-	ctx := r.Context()
+	return IdentityFromContext(r.Context())
+}
+
+// IdentityFromContext returns the identity of the caller associated with ctx.
+func IdentityFromContext(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(identity).(string)
 	if ok {
 		return v, true
@@ -26,5 +37,36 @@ func Identity(r *http.Request) (string, bool) {
 
 // SetIdentity returns a new http.Request with the identity set to v.
 func SetIdentity(r *http.Request, v string) *http.Request {
-	return r.WithContext(context.WithValue(r.Context(), identity, v))
+	return r.WithContext(WithIdentity(r.Context(), v))
+}
+
+// WithIdentity returns a copy of ctx with the identity set to v.
+func WithIdentity(ctx context.Context, v string) context.Context {
+	return context.WithValue(ctx, identity, v)
+}
+
+type claimsKey string
+
+const claims = claimsKey("org.openpolicyagent/claims")
+
+// Claims returns the verified token claims of the caller associated with ctx.
+func Claims(r *http.Request) (map[string]any, bool) {
+	return ClaimsFromContext(r.Context())
+}
+
+// ClaimsFromContext returns the verified token claims of the caller associated
+// with ctx.
+func ClaimsFromContext(ctx context.Context) (map[string]any, bool) {
+	v, ok := ctx.Value(claims).(map[string]any)
+	return v, ok
+}
+
+// SetClaims returns a new http.Request with the verified token claims set to v.
+func SetClaims(r *http.Request, v map[string]any) *http.Request {
+	return r.WithContext(WithClaims(r.Context(), v))
+}
+
+// WithClaims returns a copy of ctx with the verified token claims set to v.
+func WithClaims(ctx context.Context, v map[string]any) context.Context {
+	return context.WithValue(ctx, claims, v)
 }
@@ -0,0 +1,315 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package identifier
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+
+	"github.com/open-policy-agent/opa/v1/plugins/server/authentication"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/writer"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	// jwksMinRefreshInterval limits how often a key set is fetched again when
+	// a token is signed with a key that isn't in the cached set, e.g. after
+	// the issuer rotated its keys.
+	jwksMinRefreshInterval = 10 * time.Second
+
+	jwksFetchTimeout = 10 * time.Second
+	jwksMaxSize      = 1 << 20
+)
+
+// JWTBased verifies JWT bearer tokens and associates their claims with the
+// request. Requests carrying a bearer token that can't be verified are
+// rejected; requests without an Authorization header are passed on without
+// identity.
+type JWTBased struct {
+	inner    http.Handler
+	verifier *JWTVerifier
+}
+
+// NewJWTBased returns a new JWTBased object. The verifier can be shared by
+// several handlers.
+func NewJWTBased(inner http.Handler, verifier *JWTVerifier) *JWTBased {
+	return &JWTBased{
+		inner:    inner,
+		verifier: verifier,
+	}
+}
+
+func (h *JWTBased) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, err := h.verifier.Identify(r.Context(), r.Header, r.TLS)
+	if err != nil {
+		unauthenticated(w)
+		return
+	}
+
+	h.inner.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func unauthenticated(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	writer.Error(w, http.StatusUnauthorized, types.NewErrorV1(types.CodeUnauthorized, types.MsgUnauthenticatedError))
+}
+
+// JWTVerifier verifies tokens against a set of trusted issuers. Key sets are
+// cached, and fetched again when they expire or when a token is signed with
+// an unknown key. A JWTVerifier can be used concurrently.
+type JWTVerifier struct {
+	issuers    map[string]*jwtIssuer
+	leeway     time.Duration
+	requireExp bool
+}
+
+type jwtIssuer struct {
+	audiences []string
+	keys      *keySetCache
+}
+
+// NewJWTVerifier returns a verifier for the issuers in config. Key sets read
+// from files are loaded immediately, while key sets served over HTTP are
+// fetched on first use.
+func NewJWTVerifier(config *authentication.JWT) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		issuers:    make(map[string]*jwtIssuer, len(config.Issuers)),
+		leeway:     config.Leeway(),
+		requireExp: config.RequiresExp(),
+	}
+
+	client := &http.Client{Timeout: jwksFetchTimeout}
+
+	for _, iss := range config.Issuers {
+		if _, ok := v.issuers[iss.Issuer]; ok {
+			return nil, fmt.Errorf("duplicate jwt issuer %q", iss.Issuer)
+		}
+
+		var load func(context.Context) (jwk.Set, error)
+		if iss.JWKSFile != "" {
+			file := iss.JWKSFile
+			load = func(context.Context) (jwk.Set, error) { return loadJWKSFile(file) }
+		} else {
+			url := iss.JWKSURL
+			load = func(ctx context.Context) (jwk.Set, error) { return fetchJWKS(ctx, client, url) }
+		}
+
+		keys := &keySetCache{load: load, ttl: config.JWKSRefresh()}
+		if iss.JWKSFile != "" {
+			if _, err := keys.get(context.Background(), false); err != nil {
+				return nil, fmt.Errorf("jwt issuer %q: %w", iss.Issuer, err)
+			}
+		}
+
+		v.issuers[iss.Issuer] = &jwtIssuer{audiences: iss.Audiences, keys: keys}
+	}
+
+	return v, nil
+}
+
+// Identify is the IdentifyFunc of JWTBased: the identity is the set of claims
+// of the bearer token of the Authorization header, if any. It fails if there's
+// a token that can't be verified.
+func (v *JWTVerifier) Identify(ctx context.Context, header http.Header, _ *tls.ConnectionState) (context.Context, error) {
+	value := header.Get("Authorization")
+	if len(value) == 0 {
+		return ctx, nil
+	}
+
+	match := bearerTokenRegexp.FindStringSubmatch(value)
+	if len(match) == 0 {
+		return nil, errors.New("invalid authorization header")
+	}
+
+	claims, err := v.Verify(ctx, match[1])
+	if err != nil {
+		return nil, err
+	}
+	return WithClaims(ctx, claims), nil
+}
+
+// Verify checks the signature and the registered claims of token, and
+// returns the claims of the token.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (map[string]any, error) {
+	// The issuer determines the key set the token is verified with, so it's
+	// read before the signature is checked.
+	unverified, err := jwt.ParseInsecure([]byte(token))
+	if err != nil {
+		return nil, err
+	}
+	name, _ := unverified.Issuer()
+	iss, ok := v.issuers[name]
+	if !ok {
+		return nil, fmt.Errorf("untrusted issuer %q", name)
+	}
+
+	set, err := iss.keys.get(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := v.parse(token, name, set)
+	if err != nil && !errors.Is(err, jwt.ValidateError()) {
+		// The signing key may have been rotated since the key set was fetched.
+		if refreshed, rerr := iss.keys.get(ctx, true); rerr == nil && refreshed != set {
+			tok, err = v.parse(token, name, refreshed)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iss.audiences) > 0 {
+		aud, _ := tok.Audience()
+		if !slices.ContainsFunc(aud, func(a string) bool { return slices.Contains(iss.audiences, a) }) {
+			return nil, errors.New("token audience not accepted")
+		}
+	}
+
+	return decodeClaims(token)
+}
+
+func (v *JWTVerifier) parse(token, issuer string, set jwk.Set) (jwt.Token, error) {
+	opts := []jwt.ParseOption{
+		jwt.WithKeySet(set, jws.WithInferAlgorithmFromKey(true), jws.WithUseDefault(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(issuer),
+		jwt.WithAcceptableSkew(v.leeway),
+	}
+	// Tokens without expiry would stay valid forever once leaked.
+	if v.requireExp {
+		opts = append(opts, jwt.WithRequiredClaim(jwt.ExpirationKey))
+	}
+	return jwt.Parse([]byte(token), opts...)
+}
+
+// decodeClaims returns the payload of a verified token as-is, rather than the
+// claims as parsed by the jwt package, so that numbers keep their precision.
+func decodeClaims(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid token")
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]any
+	if err := util.UnmarshalJSON(bs, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// keySetCache holds the key set of an issuer. If a key set can't be fetched
+// again, the previous one stays in use.
+type keySetCache struct {
+	load func(context.Context) (jwk.Set, error)
+	ttl  time.Duration
+
+	mtx       sync.Mutex
+	set       jwk.Set
+	fetched   time.Time
+	refreshed time.Time // last refresh forced by an unknown key
+	attempted time.Time
+	err       error
+}
+
+// get returns the cached key set, fetching it when it's expired. If refresh
+// is set, the key set is fetched unless that was done recently.
+func (c *keySetCache) get(ctx context.Context, refresh bool) (jwk.Set, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	if c.set != nil {
+		if !refresh && now.Sub(c.fetched) < c.ttl {
+			return c.set, nil
+		}
+		if refresh && now.Sub(c.refreshed) < jwksMinRefreshInterval {
+			return c.set, nil
+		}
+	}
+
+	// Back off after a failed fetch.
+	if c.err != nil && now.Sub(c.attempted) < jwksMinRefreshInterval {
+		if c.set != nil {
+			return c.set, nil
+		}
+		return nil, c.err
+	}
+
+	if refresh {
+		c.refreshed = now
+	}
+	c.attempted = now
+
+	set, err := c.load(ctx)
+	if err != nil {
+		c.err = err
+		if c.set != nil {
+			return c.set, nil
+		}
+		return nil, err
+	}
+
+	c.set, c.fetched, c.err = set, now, nil
+	return set, nil
+}
+
+func loadJWKSFile(file string) (jwk.Set, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	set, err := jwk.Parse(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file %s: %w", file, err)
+	}
+	return set, nil
+}
+
+func fetchJWKS(ctx context.Context, client *http.Client, url string) (jwk.Set, error) {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS from %s: %s", url, resp.Status)
+	}
+
+	bs, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+	if err != nil {
+		return nil, err
+	}
+	set, err := jwk.Parse(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS from %s: %w", url, err)
+	}
+	return set, nil
+}
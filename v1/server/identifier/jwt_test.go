@@ -0,0 +1,345 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package identifier_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+
+	"github.com/open-policy-agent/opa/v1/plugins/server/authentication"
+	"github.com/open-policy-agent/opa/v1/server/identifier"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type testKey struct {
+	private jwk.Key
+	public  jwk.Key
+	alg     jwa.SignatureAlgorithm
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newTestKey(t, raw, kid, jwa.RS256())
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	raw, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newTestKey(t, raw, kid, jwa.ES256())
+}
+
+func newTestKey(t *testing.T, raw any, kid string, alg jwa.SignatureAlgorithm) testKey {
+	t.Helper()
+	private, err := jwk.Import(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid != "" {
+		if err := private.Set(jwk.KeyIDKey, kid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	public, err := jwk.PublicKeyOf(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{private: private, public: public, alg: alg}
+}
+
+func (k testKey) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	tok := jwt.New()
+	for name, value := range claims {
+		if err := tok.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := jwt.Sign(tok, jwt.WithKey(k.alg, k.private))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bs)
+}
+
+func jwks(t *testing.T, keys ...testKey) []byte {
+	t.Helper()
+	set := jwk.NewSet()
+	for _, k := range keys {
+		if err := set.AddKey(k.public); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func newJWTConfig(t *testing.T, raw string) *authentication.JWT {
+	t.Helper()
+	conf, err := authentication.NewConfigBuilder().WithBytes([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conf.JWT
+}
+
+func TestJWTBased(t *testing.T) {
+	idpKey := newRSAKey(t, "idp-1")
+	internalKey := newECKey(t, "")
+	otherKey := newRSAKey(t, "idp-1")
+
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(jwks(t, idpKey))
+	}))
+	defer idp.Close()
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, jwks(t, internalKey), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := identifier.NewJWTVerifier(newJWTConfig(t, `{"jwt": {"issuers": [
+		{"issuer": "https://idp.example.com", "audiences": ["opa", "opa-dev"], "jwks_url": "`+idp.URL+`"},
+		{"issuer": "internal", "jwks_file": "`+file+`"}
+	]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		note   string
+		header string
+		claims string // expected claims, undefined if empty
+		status int
+	}{
+		{
+			note:   "no token",
+			status: http.StatusOK,
+		},
+		{
+			note:   "issuer with JWKS URL",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "opa-dev", "sub": "alice", "exp": exp}),
+			claims: `{"aud":["opa-dev"],"exp":` + strconv.FormatInt(exp, 10) + `,"iss":"https://idp.example.com","sub":"alice"}`,
+			status: http.StatusOK,
+		},
+		{
+			note:   "issuer with JWKS file",
+			header: "Bearer " + internalKey.sign(t, map[string]any{"iss": "internal", "groups": []string{"admins"}, "exp": exp}),
+			claims: `{"exp":` + strconv.FormatInt(exp, 10) + `,"groups":["admins"],"iss":"internal"}`,
+			status: http.StatusOK,
+		},
+		{
+			note:   "not a bearer token",
+			header: "Basic dXNlcjpwYXNz",
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "malformed token",
+			header: "Bearer this-is-not-a-jwt",
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "untrusted issuer",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://evil.example.com", "aud": "opa"}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "invalid signature",
+			header: "Bearer " + otherKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "opa"}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "key of another issuer",
+			header: "Bearer " + internalKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "opa"}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "audience not accepted",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "other"}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "missing audience",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://idp.example.com"}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "expired",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "opa", "exp": time.Now().Add(-time.Hour).Unix()}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			note:   "missing expiry",
+			header: "Bearer " + idpKey.sign(t, map[string]any{"iss": "https://idp.example.com", "aud": "opa"}),
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			mock := &mockHandler{}
+			handler := identifier.NewJWTBased(mock, verifier)
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/data", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("Expected status %d but got %d: %s", tc.status, rec.Code, rec.Body)
+			}
+			if tc.status == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") == "" {
+					t.Fatal("Expected WWW-Authenticate header")
+				}
+				if mock.claimsDefined {
+					t.Fatal("Expected request to be rejected before the inner handler")
+				}
+				return
+			}
+
+			if mock.claimsDefined != (tc.claims != "") {
+				t.Fatalf("Expected claimsDefined to be %v but got %v", tc.claims != "", mock.claimsDefined)
+			}
+			if tc.claims != "" {
+				if act := string(util.MustMarshalJSON(mock.claims)); act != tc.claims {
+					t.Fatalf("Expected claims %s but got %s", tc.claims, act)
+				}
+			}
+		})
+	}
+}
+
+func TestJWTVerifierKeyRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newRSAKey(t, "new")
+
+	var fetches atomic.Int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) == 1 {
+			_, _ = w.Write(jwks(t, oldKey))
+			return
+		}
+		_, _ = w.Write(jwks(t, oldKey, newKey))
+	}))
+	defer idp.Close()
+
+	verifier, err := identifier.NewJWTVerifier(newJWTConfig(t, `{"jwt": {"issuers": [
+		{"issuer": "idp", "jwks_url": "`+idp.URL+`"}
+	]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	if _, err := verifier.Verify(t.Context(), oldKey.sign(t, map[string]any{"iss": "idp", "exp": exp})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(t.Context(), oldKey.sign(t, map[string]any{"iss": "idp", "exp": exp})); err != nil {
+		t.Fatal(err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("Expected key set to be cached, got %d fetches", n)
+	}
+
+	// A token signed with a key that isn't in the cached set triggers a
+	// refresh, which is only done once in a short period.
+	if _, err := verifier.Verify(t.Context(), newKey.sign(t, map[string]any{"iss": "idp", "exp": exp})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(t.Context(), newRSAKey(t, "unknown").sign(t, map[string]any{"iss": "idp", "exp": exp})); err == nil {
+		t.Fatal("Expected error for unknown key")
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Fatalf("Expected 2 fetches, got %d", n)
+	}
+}
+
+func TestJWTVerifierOptionalExpiry(t *testing.T) {
+	key := newECKey(t, "")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, jwks(t, key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := identifier.NewJWTVerifier(newJWTConfig(t, `{"jwt": {"issuers": [
+		{"issuer": "internal", "jwks_file": "`+file+`"}
+	], "require_exp": false}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.Verify(t.Context(), key.sign(t, map[string]any{"iss": "internal"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(t.Context(), key.sign(t, map[string]any{"iss": "internal", "exp": time.Now().Add(-time.Hour).Unix()})); err == nil {
+		t.Fatal("Expected error for expired token")
+	}
+}
+
+func TestNewJWTVerifierErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"keys": 42}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		note   string
+		config string
+	}{
+		{
+			note:   "missing JWKS file",
+			config: `{"jwt": {"issuers": [{"issuer": "a", "jwks_file": "` + filepath.Join(dir, "missing.json") + `"}]}}`,
+		},
+		{
+			note:   "invalid JWKS file",
+			config: `{"jwt": {"issuers": [{"issuer": "a", "jwks_file": "` + invalid + `"}]}}`,
+		},
+		{
+			note:   "duplicate issuer",
+			config: `{"jwt": {"issuers": [{"issuer": "a", "jwks_url": "http://a"}, {"issuer": "a", "jwks_url": "http://b"}]}}`,
+		},
+	} {
+		t.Run(tc.note, func(t *testing.T) {
+			if _, err := identifier.NewJWTVerifier(newJWTConfig(t, tc.config)); err == nil {
+				t.Fatal("Expected error")
+			}
+		})
+	}
+}
@@ -13,9 +13,13 @@ type mockHandler struct {
 
 	clientCertificates        []*x509.Certificate
 	clientCertificatesDefined bool
+
+	claims        map[string]any
+	claimsDefined bool
 }
 
 func (h *mockHandler) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
 	h.identity, h.identityDefined = identifier.Identity(r)
 	h.clientCertificates, h.clientCertificatesDefined = identifier.ClientCertificates(r)
+	h.claims, h.claimsDefined = identifier.Claims(r)
 }
@@ -5,6 +5,8 @@
 package identifier
 
 import (
+	"context"
+	"crypto/tls"
 	"net/http"
 )
 
@@ -21,12 +23,19 @@ func NewTLSBased(inner http.Handler) *TLSBased {
 }
 
 func (h *TLSBased) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if tls := r.TLS; tls != nil {
-		if certs := tls.PeerCertificates; len(certs) > 0 {
-			r = SetIdentity(r, certs[0].Subject.ToRDNSequence().String())
-			r = SetClientCertificates(r, certs)
+	ctx, _ := IdentifyTLS(r.Context(), r.Header, r.TLS)
+	h.inner.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// IdentifyTLS is the IdentifyFunc of TLSBased: the identity is the subject of
+// the client certificate, if any.
+func IdentifyTLS(ctx context.Context, _ http.Header, state *tls.ConnectionState) (context.Context, error) {
+	if state != nil {
+		if certs := state.PeerCertificates; len(certs) > 0 {
+			ctx = WithIdentity(ctx, certs[0].Subject.ToRDNSequence().String())
+			ctx = WithClientCertificates(ctx, certs)
 		}
 	}
 
-	h.inner.ServeHTTP(w, r)
+	return ctx, nil
 }
@@ -1,6 +1,8 @@
 package identifier
 
 import (
+	"context"
+	"crypto/tls"
 	"net/http"
 	"regexp"
 )
@@ -20,14 +22,20 @@ func NewTokenBased(inner http.Handler) *TokenBased {
 var bearerTokenRegexp = regexp.MustCompile(`^Bearer\s+(\S+)$`)
 
 func (h *TokenBased) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, _ := IdentifyToken(r.Context(), r.Header, r.TLS)
+	h.inner.ServeHTTP(w, r.WithContext(ctx))
+}
 
-	value := r.Header.Get("Authorization")
+// IdentifyToken is the IdentifyFunc of TokenBased: the identity is the bearer
+// token of the Authorization header, if any.
+func IdentifyToken(ctx context.Context, header http.Header, _ *tls.ConnectionState) (context.Context, error) {
+	value := header.Get("Authorization")
 	if len(value) > 0 {
 		match := bearerTokenRegexp.FindStringSubmatch(value)
 		if len(match) > 0 {
-			r = SetIdentity(r, match[1])
+			ctx = WithIdentity(ctx, match[1])
 		}
 	}
 
-	h.inner.ServeHTTP(w, r)
+	return ctx, nil
 }
@@ -0,0 +1,318 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/open-policy-agent/opa/v1/config"
+	"github.com/open-policy-agent/opa/v1/logging"
+	serverRateLimitPlugin "github.com/open-policy-agent/opa/v1/plugins/server/ratelimit"
+	"github.com/open-policy-agent/opa/v1/server/identifier"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/writer"
+)
+
+const (
+	// rateLimitClientIdle is how long the limits of a client are kept after
+	// its last request.
+	rateLimitClientIdle    = 10 * time.Minute
+	rateLimitSweepInterval = time.Minute
+
+	// rateLimitInFlightRetry is the Retry-After sent when a client has too many
+	// requests in flight, as there's no telling when one of them finishes.
+	rateLimitInFlightRetry = time.Second
+
+	// rateLimitMaxClients caps the number of clients whose limits are kept,
+	// so that clients making up identities, e.g. header values, can't exhaust
+	// memory. Once reached, new clients are told apart by their remote address.
+	rateLimitMaxClients = 10000
+)
+
+// rateLimiter admits the requests of each client within the limits of the
+// server.rate_limits config. The config can be replaced at runtime, which
+// resets the state of all clients.
+type rateLimiter struct {
+	mtx        sync.Mutex
+	raw        []byte
+	config     *serverRateLimitPlugin.Config // nil if no limits are set
+	clients    map[string]*rateLimitClient
+	maxClients int
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+type rateLimitClient struct {
+	lastSeen  time.Time
+	limits    *rateLimitBucket // client-wide limits
+	endpoints map[string]*rateLimitBucket
+}
+
+type rateLimitBucket struct {
+	limiter     *rate.Limiter // nil without a request rate
+	inFlight    int
+	maxInFlight int // 0 without an in-flight limit
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		clients:    map[string]*rateLimitClient{},
+		maxClients: rateLimitMaxClients,
+		now:        time.Now,
+	}
+}
+
+func newRateLimitBucket(l *serverRateLimitPlugin.Limits) *rateLimitBucket {
+	b := &rateLimitBucket{}
+	if l.RequestsPerSecond != nil {
+		b.limiter = rate.NewLimiter(rate.Limit(*l.RequestsPerSecond), *l.Burst)
+	}
+	if l.MaxInFlight != nil {
+		b.maxInFlight = *l.MaxInFlight
+	}
+	return b
+}
+
+// update parses and applies the raw config, unless it's unchanged.
+func (l *rateLimiter) update(ctx context.Context, raw []byte) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.raw != nil && bytes.Equal(l.raw, raw) {
+		return nil
+	}
+
+	cfg, err := serverRateLimitPlugin.NewConfigBuilder().WithBytes(raw).ParseWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled() {
+		cfg = nil
+	}
+
+	l.raw = bytes.Clone(raw)
+	if l.raw == nil {
+		l.raw = []byte{}
+	}
+	l.config = cfg
+	l.clients = map[string]*rateLimitClient{}
+	return nil
+}
+
+// admit checks the limits for endpoint of the client of a request, made with
+// ctx and header from remoteAddr. If the request is admitted, the returned
+// func must be called when it's done. Otherwise, the returned duration is how
+// long the client should wait before trying again.
+func (l *rateLimiter) admit(ctx context.Context, header http.Header, remoteAddr, endpoint string) (func(), time.Duration, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	cfg := l.config
+	if cfg == nil {
+		return func() {}, 0, true
+	}
+
+	// Health checks are only subject to their own budget, so that probes
+	// aren't failed by clients sharing the prober's address.
+	clientWide := endpoint != PromHandlerHealth && cfg.Limits.Enabled()
+	endpointLimits := cfg.Endpoints[endpoint]
+	if !clientWide && !endpointLimits.Enabled() {
+		return func() {}, 0, true
+	}
+
+	now := l.now()
+	l.sweep(now)
+
+	key := rateLimitClientKey(ctx, header, remoteAddr, cfg)
+	c, ok := l.clients[key]
+	if !ok && len(l.clients) >= l.maxClients {
+		// Remote addresses are always tracked, as they can't be made up.
+		key = rateLimitAddrKey(remoteAddr)
+		c, ok = l.clients[key]
+	}
+	if !ok {
+		c = &rateLimitClient{endpoints: map[string]*rateLimitBucket{}}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+
+	buckets := make([]*rateLimitBucket, 0, 2)
+	if clientWide {
+		if c.limits == nil {
+			c.limits = newRateLimitBucket(&cfg.Limits)
+		}
+		buckets = append(buckets, c.limits)
+	}
+	if endpointLimits.Enabled() {
+		b, ok := c.endpoints[endpoint]
+		if !ok {
+			b = newRateLimitBucket(endpointLimits)
+			c.endpoints[endpoint] = b
+		}
+		buckets = append(buckets, b)
+	}
+
+	for _, b := range buckets {
+		if b.maxInFlight > 0 && b.inFlight >= b.maxInFlight {
+			return nil, rateLimitInFlightRetry, false
+		}
+	}
+
+	// A request takes a token from every bucket, or from none of them.
+	reservations := make([]*rate.Reservation, 0, len(buckets))
+	for _, b := range buckets {
+		if b.limiter == nil {
+			continue
+		}
+		res := b.limiter.ReserveN(now, 1)
+		if delay := res.DelayFrom(now); delay > 0 {
+			res.CancelAt(now)
+			for _, prev := range reservations {
+				prev.CancelAt(now)
+			}
+			return nil, delay, false
+		}
+		reservations = append(reservations, res)
+	}
+
+	for _, b := range buckets {
+		b.inFlight++
+	}
+
+	return func() {
+		l.mtx.Lock()
+		defer l.mtx.Unlock()
+		for _, b := range buckets {
+			b.inFlight--
+		}
+	}, 0, true
+}
+
+// sweep drops the limits of idle clients.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) >= rateLimitClientIdle && !c.busy() {
+			delete(l.clients, key)
+		}
+	}
+}
+
+func (c *rateLimitClient) busy() bool {
+	if c.limits != nil && c.limits.inFlight > 0 {
+		return true
+	}
+	for _, b := range c.endpoints {
+		if b.inFlight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitClientKey returns the key telling the client of a request apart
+// from others. Requests without the configured identity are told apart by
+// their remote address.
+func rateLimitClientKey(ctx context.Context, header http.Header, remoteAddr string, cfg *serverRateLimitPlugin.Config) string {
+	switch cfg.ClientKey {
+	case serverRateLimitPlugin.ClientKeyHeader:
+		if v := header.Get(cfg.ClientHeader); v != "" {
+			return "header:" + v
+		}
+	case serverRateLimitPlugin.ClientKeyIdentity:
+		if claims, ok := identifier.ClaimsFromContext(ctx); ok {
+			return fmt.Sprintf("jwt:%v:%v", claims["iss"], claims["sub"])
+		}
+		if id, ok := identifier.IdentityFromContext(ctx); ok {
+			return "identity:" + id
+		}
+	}
+
+	return rateLimitAddrKey(remoteAddr)
+}
+
+func rateLimitAddrKey(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "addr:" + host
+}
+
+// initRateLimits applies the rate limits of the server config, and again
+// whenever the config is reconfigured, e.g. by discovery. An invalid config
+// fails initialization, while an invalid update keeps the previous limits.
+func (s *Server) initRateLimits(ctx context.Context) error {
+	s.rateLimiter = newRateLimiter()
+
+	if err := s.rateLimiter.update(ctx, rateLimitsRawConfig(s.manager.GetConfig().Server)); err != nil {
+		return err
+	}
+
+	logger := s.manager.Logger()
+	s.manager.RegisterServerConfigTrigger(func(cfg *config.ServerConfig) {
+		s.updateRateLimits(logger, cfg)
+	})
+	return nil
+}
+
+func (s *Server) updateRateLimits(logger logging.Logger, cfg *config.ServerConfig) {
+	if err := s.rateLimiter.update(context.Background(), rateLimitsRawConfig(cfg)); err != nil {
+		logger.Error("Failed to update rate limits, keeping the previous limits: %v", err)
+	}
+}
+
+func rateLimitsRawConfig(cfg *config.ServerConfig) []byte {
+	if cfg == nil {
+		return nil
+	}
+	return cfg.RateLimits
+}
+
+// initHandlerRateLimits checks the rate limits of requests before they are
+// passed on to handler. Requests are limited by the endpoint router routes
+// them to, while requests that aren't routed to an endpoint, e.g. to /metrics,
+// are only subject to the client-wide limits.
+func (s *Server) initHandlerRateLimits(router *http.ServeMux, handler http.Handler) http.Handler {
+	if s.rateLimiter == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var endpoint string
+		limited := http.Handler(http.HandlerFunc(writeRateLimited))
+		if h, _ := router.Handler(r); h != nil {
+			if eh, ok := h.(*endpointHandler); ok {
+				endpoint, limited = eh.label, eh.limited
+			}
+		}
+
+		release, retryAfter, ok := s.rateLimiter.admit(r.Context(), r.Header, r.RemoteAddr, endpoint)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			limited.ServeHTTP(w, r)
+			return
+		}
+		defer release()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func writeRateLimited(w http.ResponseWriter, _ *http.Request) {
+	writer.Error(w, http.StatusTooManyRequests, types.NewErrorV1(types.CodeTooManyRequests, types.MsgRateLimitError))
+}
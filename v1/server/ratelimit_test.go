@@ -0,0 +1,440 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/prometheus"
+	"github.com/open-policy-agent/opa/v1/config"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server/identifier"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestRateLimiter(t *testing.T, raw string) (*rateLimiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	l := newRateLimiter()
+	l.now = clock.Now
+	if err := l.update(t.Context(), []byte(raw)); err != nil {
+		t.Fatal(err)
+	}
+	return l, clock
+}
+
+func newRateLimitRequest(remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/v1/query", nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func expectAdmitted(t *testing.T, l *rateLimiter, r *http.Request, endpoint string) func() {
+	t.Helper()
+	release, retryAfter, ok := l.admit(r.Context(), r.Header, r.RemoteAddr, endpoint)
+	if !ok {
+		t.Fatalf("Expected request to %s to be admitted, but it was limited (retry after %v)", endpoint, retryAfter)
+	}
+	return release
+}
+
+func expectLimited(t *testing.T, l *rateLimiter, r *http.Request, endpoint string, retryAfter time.Duration) {
+	t.Helper()
+	_, act, ok := l.admit(r.Context(), r.Header, r.RemoteAddr, endpoint)
+	if ok {
+		t.Fatalf("Expected request to %s to be limited, but it was admitted", endpoint)
+	}
+	if act != retryAfter {
+		t.Fatalf("Expected retry after %v but got %v", retryAfter, act)
+	}
+}
+
+func TestRateLimiterRequestRate(t *testing.T) {
+	l, clock := newTestRateLimiter(t, `{"requests_per_second": 2, "burst": 2}`)
+	alice := newRateLimitRequest("10.0.0.1:1234")
+	bob := newRateLimitRequest("10.0.0.2:1234")
+
+	expectAdmitted(t, l, alice, PromHandlerV1Query)()
+	expectAdmitted(t, l, alice, PromHandlerV1Data)()
+	expectLimited(t, l, alice, PromHandlerV1Query, 500*time.Millisecond)
+
+	// Other clients have their own budget.
+	expectAdmitted(t, l, bob, PromHandlerV1Query)()
+
+	// Limited requests don't take tokens.
+	clock.Advance(500 * time.Millisecond)
+	expectAdmitted(t, l, alice, PromHandlerV1Query)()
+	expectLimited(t, l, alice, PromHandlerV1Query, 500*time.Millisecond)
+
+	// Health checks aren't subject to the client-wide limits.
+	expectAdmitted(t, l, alice, PromHandlerHealth)()
+}
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	l, _ := newTestRateLimiter(t, `{"max_in_flight": 2}`)
+	r := newRateLimitRequest("10.0.0.1:1234")
+
+	release1 := expectAdmitted(t, l, r, PromHandlerV1Data)
+	release2 := expectAdmitted(t, l, r, PromHandlerV1Query)
+	expectLimited(t, l, r, PromHandlerV1Data, rateLimitInFlightRetry)
+
+	release1()
+	release3 := expectAdmitted(t, l, r, PromHandlerV1Data)
+
+	release2()
+	release3()
+	expectAdmitted(t, l, r, PromHandlerV1Data)()
+}
+
+func TestRateLimiterEndpointBudgets(t *testing.T) {
+	l, clock := newTestRateLimiter(t, `{
+		"requests_per_second": 10,
+		"endpoints": {
+			"v1/query": {"requests_per_second": 1},
+			"v1/compile": {"max_in_flight": 1},
+			"health": {"requests_per_second": 1}
+		}
+	}`)
+	r := newRateLimitRequest("10.0.0.1:1234")
+
+	expectAdmitted(t, l, r, PromHandlerV1Query)()
+	expectLimited(t, l, r, PromHandlerV1Query, time.Second)
+
+	// Other endpoints are only subject to the client-wide limits.
+	expectAdmitted(t, l, r, PromHandlerV1Data)()
+
+	release := expectAdmitted(t, l, r, PromHandlerV1Compile)
+	expectLimited(t, l, r, PromHandlerV1Compile, rateLimitInFlightRetry)
+	release()
+
+	// Health checks are subject to their own budget.
+	expectAdmitted(t, l, r, PromHandlerHealth)()
+	expectLimited(t, l, r, PromHandlerHealth, time.Second)
+
+	// Exhausting the client-wide budget limits all endpoints. A request
+	// limited by it doesn't take a token from the endpoint budget.
+	for range 7 {
+		expectAdmitted(t, l, r, PromHandlerV1Data)()
+	}
+	clock.Advance(time.Second)
+	for range 9 {
+		expectAdmitted(t, l, r, PromHandlerV1Data)()
+	}
+	expectAdmitted(t, l, r, PromHandlerV1Query)()
+	clock.Advance(time.Second)
+	for range 10 {
+		expectAdmitted(t, l, r, PromHandlerV1Data)()
+	}
+	expectLimited(t, l, r, PromHandlerV1Query, 100*time.Millisecond)
+	clock.Advance(100 * time.Millisecond)
+	expectAdmitted(t, l, r, PromHandlerV1Query)()
+}
+
+func TestRateLimiterClientKey(t *testing.T) {
+	withIdentity := func(r *http.Request, id string) *http.Request {
+		return identifier.SetIdentity(r, id)
+	}
+	withClaims := func(r *http.Request, sub string) *http.Request {
+		return identifier.SetClaims(r, map[string]any{"iss": "idp", "sub": sub})
+	}
+	withHeader := func(r *http.Request, value string) *http.Request {
+		r.Header.Set("X-Tenant", value)
+		return r
+	}
+
+	tests := []struct {
+		note     string
+		config   string
+		same     [2]*http.Request
+		separate [2]*http.Request
+	}{
+		{
+			note:     "identity from JWT claims",
+			config:   `{"requests_per_second": 1}`,
+			same:     [2]*http.Request{withClaims(newRateLimitRequest("10.0.0.1:1"), "alice"), withClaims(newRateLimitRequest("10.0.0.2:1"), "alice")},
+			separate: [2]*http.Request{withClaims(newRateLimitRequest("10.0.0.1:1"), "alice"), withClaims(newRateLimitRequest("10.0.0.1:1"), "bob")},
+		},
+		{
+			note:     "identity from token or TLS subject",
+			config:   `{"requests_per_second": 1, "client_key": "identity"}`,
+			same:     [2]*http.Request{withIdentity(newRateLimitRequest("10.0.0.1:1"), "CN=alice"), withIdentity(newRateLimitRequest("10.0.0.2:1"), "CN=alice")},
+			separate: [2]*http.Request{withIdentity(newRateLimitRequest("10.0.0.1:1"), "CN=alice"), withIdentity(newRateLimitRequest("10.0.0.1:1"), "CN=bob")},
+		},
+		{
+			note:     "identity falls back to remote address",
+			config:   `{"requests_per_second": 1}`,
+			same:     [2]*http.Request{newRateLimitRequest("10.0.0.1:1"), newRateLimitRequest("10.0.0.1:2")},
+			separate: [2]*http.Request{newRateLimitRequest("10.0.0.1:1"), withIdentity(newRateLimitRequest("10.0.0.1:1"), "10.0.0.1")},
+		},
+		{
+			note:     "header",
+			config:   `{"requests_per_second": 1, "client_key": "header", "client_header": "X-Tenant"}`,
+			same:     [2]*http.Request{withHeader(newRateLimitRequest("10.0.0.1:1"), "a"), withHeader(newRateLimitRequest("10.0.0.2:1"), "a")},
+			separate: [2]*http.Request{withHeader(newRateLimitRequest("10.0.0.1:1"), "a"), withHeader(newRateLimitRequest("10.0.0.1:1"), "b")},
+		},
+		{
+			note:     "remote address",
+			config:   `{"requests_per_second": 1, "client_key": "remote_addr"}`,
+			same:     [2]*http.Request{withIdentity(newRateLimitRequest("10.0.0.1:1"), "alice"), withIdentity(newRateLimitRequest("10.0.0.1:2"), "bob")},
+			separate: [2]*http.Request{newRateLimitRequest("10.0.0.1:1"), newRateLimitRequest("10.0.0.2:1")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			l, _ := newTestRateLimiter(t, tc.config)
+			expectAdmitted(t, l, tc.same[0], PromHandlerV1Data)()
+			expectLimited(t, l, tc.same[1], PromHandlerV1Data, time.Second)
+
+			l, _ = newTestRateLimiter(t, tc.config)
+			expectAdmitted(t, l, tc.separate[0], PromHandlerV1Data)()
+			expectAdmitted(t, l, tc.separate[1], PromHandlerV1Data)()
+		})
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	l, clock := newTestRateLimiter(t, `{"max_in_flight": 1}`)
+
+	release := expectAdmitted(t, l, newRateLimitRequest("10.0.0.1:1"), PromHandlerV1Data)
+	expectAdmitted(t, l, newRateLimitRequest("10.0.0.2:1"), PromHandlerV1Data)()
+
+	clock.Advance(rateLimitClientIdle)
+	expectAdmitted(t, l, newRateLimitRequest("10.0.0.3:1"), PromHandlerV1Data)()
+
+	// Idle clients are dropped, unless they still have requests in flight.
+	if _, ok := l.clients["addr:10.0.0.2"]; ok {
+		t.Fatal("Expected idle client to be dropped")
+	}
+	if _, ok := l.clients["addr:10.0.0.1"]; !ok {
+		t.Fatal("Expected client with request in flight to be kept")
+	}
+	release()
+}
+
+func TestRateLimiterMaxClients(t *testing.T) {
+	l, _ := newTestRateLimiter(t, `{"requests_per_second": 1, "client_key": "header", "client_header": "X-Tenant"}`)
+	l.maxClients = 2
+
+	tenant := func(remoteAddr, value string) *http.Request {
+		r := newRateLimitRequest(remoteAddr)
+		r.Header.Set("X-Tenant", value)
+		return r
+	}
+
+	expectAdmitted(t, l, tenant("10.0.0.1:1", "a"), PromHandlerV1Data)()
+	expectAdmitted(t, l, tenant("10.0.0.1:1", "b"), PromHandlerV1Data)()
+
+	// Once the cap is reached, new clients are told apart by their remote
+	// address, while tracked clients keep their own budget.
+	expectAdmitted(t, l, tenant("10.0.0.1:1", "c"), PromHandlerV1Data)()
+	expectLimited(t, l, tenant("10.0.0.1:2", "d"), PromHandlerV1Data, time.Second)
+	expectLimited(t, l, tenant("10.0.0.1:1", "a"), PromHandlerV1Data, time.Second)
+	expectAdmitted(t, l, tenant("10.0.0.2:1", "c"), PromHandlerV1Data)()
+
+	if len(l.clients) != 4 {
+		t.Fatalf("Expected 4 tracked clients but got %d", len(l.clients))
+	}
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	l, _ := newTestRateLimiter(t, `{"max_in_flight": 1}`)
+	r := newRateLimitRequest("10.0.0.1:1")
+
+	release := expectAdmitted(t, l, r, PromHandlerV1Data)
+
+	// An unchanged config keeps the state of clients.
+	if err := l.update(t.Context(), []byte(`{"max_in_flight": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectLimited(t, l, r, PromHandlerV1Data, rateLimitInFlightRetry)
+
+	// An invalid config is rejected, keeping the previous limits.
+	if err := l.update(t.Context(), []byte(`{"max_in_flight": 0}`)); err == nil {
+		t.Fatal("Expected error for invalid config")
+	}
+	expectLimited(t, l, r, PromHandlerV1Data, rateLimitInFlightRetry)
+
+	// Removing the limits admits all requests.
+	if err := l.update(t.Context(), nil); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		expectAdmitted(t, l, r, PromHandlerV1Data)
+	}
+	release()
+}
+
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+
+	prom := prometheus.New(metrics.New(), nil, []float64{1})
+	f := newFixtureWithConfig(t, `{"server": {"rate_limits": {
+		"client_key": "header",
+		"client_header": "X-Tenant",
+		"endpoints": {"v1/query": {"requests_per_second": 1}}
+	}}}`, func(s *Server) {
+		s.WithMetrics(prom)
+	})
+
+	query := func(tenant string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/v1/query?q=true", nil)
+		req.Header.Set("X-Tenant", tenant)
+		return req
+	}
+
+	if err := f.executeRequest(query("a"), http.StatusOK, `{"result": [{}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.executeRequest(query("a"), http.StatusTooManyRequests, ""); err != nil {
+		t.Fatal(err)
+	}
+	if act := f.recorder.Header().Get("Retry-After"); act != "1" {
+		t.Fatalf("Expected Retry-After 1 but got %q", act)
+	}
+	var resp types.ErrorV1
+	if err := util.NewJSONDecoder(f.recorder.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != types.CodeTooManyRequests || resp.Message != types.MsgRateLimitError {
+		t.Fatalf("Unexpected error response: %+v", resp)
+	}
+
+	// Other clients and endpoints aren't limited.
+	if err := f.executeRequest(query("b"), http.StatusOK, `{"result": [{}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.v1(http.MethodGet, "/data", "", http.StatusOK, `{"result": {}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	f.reset()
+	f.server.DiagnosticHandler.ServeHTTP(f.recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(f.recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`http_request_duration_seconds_count{code="200",handler="v1/query",method="get"} 2`,
+		`http_request_duration_seconds_count{code="429",handler="v1/query",method="get"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metric %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestRateLimitsBeforeAuthorization(t *testing.T) {
+	t.Parallel()
+
+	store := inmem.NewFromObject(map[string]any{})
+	ctx := t.Context()
+	txn := storage.NewTransactionOrDie(ctx, store, storage.WriteParams)
+	if err := store.UpsertPolicy(ctx, txn, "authz.rego", []byte(`package system.authz
+
+default allow := false`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFixtureWithConfig(t, `{"server": {"rate_limits": {
+		"client_key": "remote_addr",
+		"endpoints": {"v1/data": {"requests_per_second": 1}}
+	}}}`, func(s *Server) {
+		s.WithStore(store).WithAuthorization(AuthorizationBasic)
+	})
+
+	// Requests denied by the authorizer count against the limits, as they are
+	// checked first.
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusUnauthorized, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusTooManyRequests, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitsReconfigure(t *testing.T) {
+	t.Parallel()
+
+	f := newFixtureWithConfig(t, `{}`)
+
+	reconfigure := func(rateLimits string) {
+		t.Helper()
+		cfg, err := config.ParseConfig([]byte(`{"server": {"rate_limits": `+rateLimits+`}}`), "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.server.manager.Reconfigure(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range 3 {
+		if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusOK, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconfigure(`{"requests_per_second": 1, "client_key": "remote_addr"}`)
+
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusTooManyRequests, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid update keeps the previous limits.
+	reconfigure(`{"requests_per_second": -1}`)
+
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusTooManyRequests, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	reconfigure(`{}`)
+
+	if err := f.executeRequest(newReqV1(http.MethodGet, "/data", ""), http.StatusOK, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	store := inmem.New()
+	m, err := plugins.New([]byte(`{"server": {"rate_limits": {"max_in_flight": 0}}}`), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New().
+		WithAddresses([]string{"localhost:8182"}).
+		WithStore(store).
+		WithManager(m).
+		Init(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "server.rate_limits.max_in_flight") {
+		t.Fatalf("Expected configuration error but got: %v", err)
+	}
+}
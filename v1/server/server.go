@@ -34,11 +34,13 @@ import (
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/config"
+	"github.com/open-policy-agent/opa/v1/debug"
 	"github.com/open-policy-agent/opa/v1/hooks"
 	"github.com/open-policy-agent/opa/v1/logging"
 	"github.com/open-policy-agent/opa/v1/metrics"
 	"github.com/open-policy-agent/opa/v1/plugins"
 	bundlePlugin "github.com/open-policy-agent/opa/v1/plugins/bundle"
+	serverAuthenticationPlugin "github.com/open-policy-agent/opa/v1/plugins/server/authentication"
 	serverDecodingPlugin "github.com/open-policy-agent/opa/v1/plugins/server/decoding"
 	serverEncodingPlugin "github.com/open-policy-agent/opa/v1/plugins/server/encoding"
 	"github.com/open-policy-agent/opa/v1/plugins/status"
@@ -67,6 +69,7 @@ const (
 	AuthenticationOff AuthenticationScheme = iota
 	AuthenticationToken
 	AuthenticationTLS
+	AuthenticationJWT
 )
 
 // AuthorizationScheme enumerates the supported authorization schemes. The authorization
@@ -83,11 +86,13 @@ const (
 	// Set of handlers for use in the "handler" dimension of the duration metric.
 	PromHandlerV0Data     = "v0/data"
 	PromHandlerV1Data     = "v1/data"
+	PromHandlerV1Batch    = "v1/batch/data"
 	PromHandlerV1Query    = "v1/query"
 	PromHandlerV1Policies = "v1/policies"
 	PromHandlerV1Compile  = "v1/compile"
 	PromHandlerV1Config   = "v1/config"
 	PromHandlerV1Status   = "v1/status"
+	PromHandlerV1Storage  = "v1/storage"
 	PromHandlerIndex      = "index"
 	PromHandlerCatch      = "catchall"
 	PromHandlerHealth     = "health"
@@ -149,6 +154,17 @@ type Server struct {
 	unixSocketPerm              *string
 	cipherSuites                *[]uint16
 	hooks                       hooks.Hooks
+	debugMtx                    sync.RWMutex
+	debugSessions               []debug.AttachedSession
+	watchMtx                    sync.Mutex
+	watches                     map[*dataWatch]struct{}
+	watchShutdown               chan struct{}
+	rateLimiter                 *rateLimiter
+	grpcAddrs                   []string
+	decodingMaxLength           int64
+	extAuthz                    extAuthz
+	identify                    identifier.IdentifyFunc // authenticates gRPC calls, nil without authentication
+	authorizer                  *authorizer.Basic       // authorizes gRPC calls, nil without authorization
 
 	compileUnknownsCache     *lru.Cache[string, []ast.Ref]
 	compileMaskingRulesCache *lru.Cache[string, ast.Ref]
@@ -192,6 +208,12 @@ func New() *Server {
 // Init initializes the server. This function MUST be called before starting any loops
 // from s.Listeners().
 func (s *Server) Init(ctx context.Context) (*Server, error) {
+	if err := s.initRateLimits(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.initExtAuthz(ctx); err != nil {
+		return nil, err
+	}
 	s.initRouters(ctx)
 	var err error
 	s.hooks.Each(func(h hooks.Hook) {
@@ -225,18 +247,29 @@ func (s *Server) Init(ctx context.Context) (*Server, error) {
 		return nil, err
 	}
 
+	// Watch streams share one trigger, so that they don't need write
+	// transactions of their own.
+	if _, err := s.store.Register(ctx, txn, storage.TriggerConfig{OnCommit: s.notifyWatches}); err != nil {
+		s.store.Abort(ctx, txn)
+		return nil, err
+	}
+
 	s.preparedEvalQueries = newCache(pqMaxCacheSize)
 	s.defaultDecisionPath = s.generateDefaultDecisionPath()
 	s.manager.RegisterNDCacheTrigger(s.updateNDCache)
 
-	s.Handler = s.initHandlerAuthn(s.Handler)
+	authn, err := s.initHandlerAuthn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.Handler = authn(s.Handler)
 
 	// compression handler
 	s.Handler, err = s.initHandlerCompression(ctx, s.Handler)
 	if err != nil {
 		return nil, err
 	}
-	s.DiagnosticHandler = s.initHandlerAuthn(s.DiagnosticHandler)
+	s.DiagnosticHandler = authn(s.DiagnosticHandler)
 
 	s.Handler, err = s.initHandlerDecodingLimits(ctx, s.Handler)
 	if err != nil {
@@ -250,6 +283,9 @@ func (s *Server) Init(ctx context.Context) (*Server, error) {
 // currently in use by the OPA Server. If any exceed the deadline specified
 // by the context an error will be returned.
 func (s *Server) Shutdown(ctx context.Context) error {
+	// Watch streams never finish on their own.
+	s.closeWatches()
+
 	errChan := make(chan error)
 	for _, srvr := range s.httpListeners {
 		go func(s httpListener) {
@@ -293,6 +329,13 @@ func (s *Server) WithDiagnosticAddresses(addrs []string) *Server {
 	return s
 }
 
+// WithGRPCAddresses sets the listening addresses that the server will bind to
+// and serve the gRPC API on.
+func (s *Server) WithGRPCAddresses(addrs []string) *Server {
+	s.grpcAddrs = addrs
+	return s
+}
+
 // WithAuthentication sets authentication scheme to use on the server.
 func (s *Server) WithAuthentication(scheme AuthenticationScheme) *Server {
 	s.authentication = scheme
@@ -486,6 +529,15 @@ func (s *Server) Listeners() ([]Loop, error) {
 		}
 	}
 
+	for _, addr := range s.grpcAddrs {
+		l, listener, err := s.getListenerForGRPC(addr)
+		if err != nil {
+			return nil, err
+		}
+		s.httpListeners = append(s.httpListeners, listener)
+		loops = append(loops, l...)
+	}
+
 	return loops, nil
 }
 
@@ -502,6 +554,13 @@ func (s *Server) DiagnosticAddrs() []string {
 	return s.addrsForType(diagnosticListenerType)
 }
 
+// GRPCAddrs returns a list of addresses that the server is listening on for
+// the gRPC API.
+// If the server hasn't been started it will not return an address.
+func (s *Server) GRPCAddrs() []string {
+	return s.addrsForType(grpcListenerType)
+}
+
 func (s *Server) addrsForType(t httpListenerType) []string {
 	var addrs []string
 	for _, l := range s.httpListeners {
@@ -538,6 +597,7 @@ type httpListenerType int
 const (
 	defaultListenerType httpListenerType = iota
 	diagnosticListenerType
+	grpcListenerType
 )
 
 type httpListener interface {
@@ -642,17 +702,8 @@ func (s *Server) getListener(addr string, h http.Handler, t httpListenerType) ([
 		loops = []Loop{loop}
 	case "https":
 		loop, listener, err = s.getListenerForHTTPSServer(parsedURL, h, t)
-		logger := s.manager.Logger().WithFields(map[string]any{
-			"cert-file":     s.certFile,
-			"cert-key-file": s.certKeyFile,
-		})
-
-		// if a manual cert refresh period has been set, then use the polling behavior,
-		// otherwise use the fsnotify default behavior
-		if s.certRefresh > 0 {
-			loops = []Loop{loop, s.certLoopPolling(logger)}
-		} else if s.certFile != "" || s.certPoolFile != "" {
-			loops = []Loop{loop, s.certLoopNotify(logger)}
+		if certLoops := s.certLoops(); len(certLoops) > 0 {
+			loops = append([]Loop{loop}, certLoops...)
 		}
 	default:
 		err = fmt.Errorf("invalid url scheme %q", parsedURL.Scheme)
@@ -661,6 +712,23 @@ func (s *Server) getListener(addr string, h http.Handler, t httpListenerType) ([
 	return loops, listener, err
 }
 
+// certLoops returns the loops reloading the server's certificates, if any.
+func (s *Server) certLoops() []Loop {
+	logger := s.manager.Logger().WithFields(map[string]any{
+		"cert-file":     s.certFile,
+		"cert-key-file": s.certKeyFile,
+	})
+
+	// if a manual cert refresh period has been set, then use the polling behavior,
+	// otherwise use the fsnotify default behavior
+	if s.certRefresh > 0 {
+		return []Loop{s.certLoopPolling(logger)}
+	} else if s.certFile != "" || s.certPoolFile != "" {
+		return []Loop{s.certLoopNotify(logger)}
+	}
+	return nil
+}
+
 func (s *Server) getListenerForHTTPServer(u *url.URL, h http.Handler, t httpListenerType) (Loop, httpListener, error) {
 	h1s := http.Server{
 		Addr:              u.Host,
@@ -684,8 +752,26 @@ func (s *Server) getListenerForHTTPSServer(u *url.URL, h http.Handler, t httpLis
 		return nil, nil, errors.New("TLS certificate required but not supplied")
 	}
 
-	tlsConfig := tls.Config{
+	httpsServer := http.Server{
+		Addr:              u.Host,
+		Handler:           h,
+		TLSConfig:         s.tlsConfig(),
+		ReadHeaderTimeout: 32 * time.Second,
+	}
+
+	l := newHTTPListener(&httpsServer, t)
+
+	httpsLoop := func() error { return l.ListenAndServeTLS("", "") }
+
+	return httpsLoop, l, nil
+}
+
+// tlsConfig returns the TLS configuration of the server's listeners, offering
+// the given application protocols.
+func (s *Server) tlsConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
 		GetCertificate: s.getCertificate,
+		NextProtos:     nextProtos,
 		// GetConfigForClient is used to ensure that a fresh config is provided containing the latest cert pool.
 		// This is not required, but appears to be how connect time updates config should be done:
 		// https://github.com/golang/go/issues/16066#issuecomment-250606132
@@ -696,6 +782,7 @@ func (s *Server) getListenerForHTTPSServer(u *url.URL, h http.Handler, t httpLis
 			cfg := &tls.Config{
 				GetCertificate: s.getCertificate,
 				ClientCAs:      s.certPool,
+				NextProtos:     nextProtos,
 			}
 
 			if s.authentication == AuthenticationTLS {
@@ -715,22 +802,33 @@ func (s *Server) getListenerForHTTPSServer(u *url.URL, h http.Handler, t httpLis
 			return cfg, nil
 		},
 	}
+}
 
-	httpsServer := http.Server{
-		Addr:              u.Host,
+func (s *Server) getListenerForUNIXSocket(u *url.URL, h http.Handler, t httpListenerType) (Loop, httpListener, error) {
+	domainSocketServer := http.Server{
 		Handler:           h,
-		TLSConfig:         &tlsConfig,
 		ReadHeaderTimeout: 32 * time.Second,
 	}
+	if s.h2cEnabled {
+		p := new(http.Protocols)
+		p.SetHTTP1(true)
+		p.SetUnencryptedHTTP2(true)
+		domainSocketServer.Protocols = p
+	}
+	unixListener, err := s.listenUnixSocket(u)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	l := newHTTPListener(&httpsServer, t)
-
-	httpsLoop := func() error { return l.ListenAndServeTLS("", "") }
+	l := newHTTPUnixSocketListener(&domainSocketServer, unixListener, t)
 
-	return httpsLoop, l, nil
+	domainSocketLoop := func() error { return domainSocketServer.Serve(unixListener) }
+	return domainSocketLoop, l, nil
 }
 
-func (s *Server) getListenerForUNIXSocket(u *url.URL, h http.Handler, t httpListenerType) (Loop, httpListener, error) {
+// listenUnixSocket listens on the unix socket of u, used by both the REST and
+// gRPC APIs, and sets the configured permissions of the socket file.
+func (s *Server) listenUnixSocket(u *url.URL) (net.Listener, error) {
 	socketPath := u.Host + u.Path
 
 	// Recover @ prefix for abstract Unix sockets (Linux-only).
@@ -745,19 +843,9 @@ func (s *Server) getListenerForUNIXSocket(u *url.URL, h http.Handler, t httpList
 		os.Remove(socketPath)
 	}
 
-	domainSocketServer := http.Server{
-		Handler:           h,
-		ReadHeaderTimeout: 32 * time.Second,
-	}
-	if s.h2cEnabled {
-		p := new(http.Protocols)
-		p.SetHTTP1(true)
-		p.SetUnencryptedHTTP2(true)
-		domainSocketServer.Protocols = p
-	}
-	unixListener, err := net.Listen("unix", socketPath)
+	l, err := net.Listen("unix", socketPath)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Skip chmod for abstract Unix sockets — they exist only in the
@@ -765,45 +853,58 @@ func (s *Server) getListenerForUNIXSocket(u *url.URL, h http.Handler, t httpList
 	if s.unixSocketPerm != nil && !isAbstract {
 		modeVal, err := strconv.ParseUint(*s.unixSocketPerm, 8, 32)
 		if err != nil {
-			return nil, nil, err
+			l.Close()
+			return nil, err
 		}
 
 		if err := os.Chmod(socketPath, os.FileMode(modeVal)); err != nil {
-			return nil, nil, err
+			l.Close()
+			return nil, err
 		}
 	}
 
-	l := newHTTPUnixSocketListener(&domainSocketServer, unixListener, t)
-
-	domainSocketLoop := func() error { return domainSocketServer.Serve(unixListener) }
-	return domainSocketLoop, l, nil
+	return l, nil
 }
 
-func (s *Server) initHandlerAuthn(handler http.Handler) http.Handler {
+// initHandlerAuthn returns the function wrapping handlers with the configured
+// authentication scheme. The JWT verifier, and so its key sets, is shared by
+// all wrapped handlers.
+func (s *Server) initHandlerAuthn(ctx context.Context) (func(http.Handler) http.Handler, error) {
 	switch s.authentication {
 	case AuthenticationToken:
-		handler = identifier.NewTokenBased(handler)
+		s.identify = identifier.IdentifyToken
+		return func(handler http.Handler) http.Handler { return identifier.NewTokenBased(handler) }, nil
 	case AuthenticationTLS:
-		handler = identifier.NewTLSBased(handler)
+		s.identify = identifier.IdentifyTLS
+		return func(handler http.Handler) http.Handler { return identifier.NewTLSBased(handler) }, nil
+	case AuthenticationJWT:
+		cfg := s.manager.GetConfig()
+		var authnRawConfig []byte
+		if cfg.Server != nil {
+			authnRawConfig = []byte(cfg.Server.Authentication)
+		}
+		authnConfig, err := serverAuthenticationPlugin.NewConfigBuilder().WithBytes(authnRawConfig).ParseWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if authnConfig.JWT == nil {
+			return nil, errors.New("jwt authentication requires server.authentication.jwt configuration")
+		}
+		verifier, err := identifier.NewJWTVerifier(authnConfig.JWT)
+		if err != nil {
+			return nil, err
+		}
+		s.identify = verifier.Identify
+		return func(handler http.Handler) http.Handler { return identifier.NewJWTBased(handler, verifier) }, nil
 	}
 
-	return handler
+	return func(handler http.Handler) http.Handler { return handler }, nil
 }
 
 func (s *Server) initHandlerAuthz(handler http.Handler) http.Handler {
 	switch s.authorization {
 	case AuthorizationBasic:
-		handler = authorizer.NewBasic(
-			handler,
-			s.getCompiler,
-			s.store,
-			authorizer.Runtime(s.runtime),
-			authorizer.Decision(s.manager.GetConfig().DefaultAuthorizationDecisionRef),
-			authorizer.PrintHook(s.manager.PrintHook()),
-			authorizer.EnablePrintStatements(s.manager.EnablePrintStatements()),
-			authorizer.InterQueryCache(s.interQueryBuiltinCache),
-			authorizer.InterQueryValueCache(s.interQueryBuiltinValueCache),
-			authorizer.URLPathExpectsBodyFunc(s.manager.ExtraAuthorizerRoutes()))
+		handler = authorizer.NewBasic(handler, s.getCompiler, s.store, s.authorizerOptions()...)
 
 		if s.metrics != nil {
 			handler = s.instrumentHandler(handler.ServeHTTP, PromHandlerAPIAuthz)
@@ -813,6 +914,18 @@ func (s *Server) initHandlerAuthz(handler http.Handler) http.Handler {
 	return handler
 }
 
+func (s *Server) authorizerOptions() []func(*authorizer.Basic) {
+	return []func(*authorizer.Basic){
+		authorizer.Runtime(s.runtime),
+		authorizer.Decision(s.manager.GetConfig().DefaultAuthorizationDecisionRef),
+		authorizer.PrintHook(s.manager.PrintHook()),
+		authorizer.EnablePrintStatements(s.manager.EnablePrintStatements()),
+		authorizer.InterQueryCache(s.interQueryBuiltinCache),
+		authorizer.InterQueryValueCache(s.interQueryBuiltinValueCache),
+		authorizer.URLPathExpectsBodyFunc(s.manager.ExtraAuthorizerRoutes()),
+	}
+}
+
 // Enforces request body size limits on incoming requests. For gzipped requests,
 // it passes the size limit down the body-reading method via the request
 // context.
@@ -826,6 +939,7 @@ func (s *Server) initHandlerDecodingLimits(ctx context.Context, handler http.Han
 	if err != nil {
 		return nil, err
 	}
+	s.decodingMaxLength = *decodingConfig.MaxLength
 	decodingHandler := handlers.DecodingLimitsHandler(handler, *decodingConfig.MaxLength, *decodingConfig.Gzip.MaxLength)
 
 	return decodingHandler, nil
@@ -867,6 +981,10 @@ func (s *Server) initRouters(ctx context.Context) {
 	// so that the latter can run first.
 	handlerAuthz := s.initHandlerAuthz(mainRouter)
 
+	if s.authorization == AuthorizationBasic {
+		s.authorizer = authorizer.New(s.getCompiler, s.store, s.authorizerOptions()...)
+	}
+
 	handlerAuthzDiag := s.initHandlerAuthz(diagRouter)
 
 	// All routers get the same base configuration *and* diagnostic API's
@@ -912,6 +1030,8 @@ func (s *Server) initRouters(ctx context.Context) {
 	mainRouter.Handle("PATCH /v1/data", s.instrumentHandler(s.v1DataPatch, PromHandlerV1Data))
 	mainRouter.Handle("POST /v1/data/{path...}", s.instrumentHandler(s.v1DataPost, PromHandlerV1Data))
 	mainRouter.Handle("POST /v1/data", s.instrumentHandler(s.v1DataPost, PromHandlerV1Data))
+	mainRouter.Handle("POST /v1/batch/data/{path...}", s.instrumentHandler(s.v1BatchDataPost, PromHandlerV1Batch))
+	mainRouter.Handle("POST /v1/batch/data", s.instrumentHandler(s.v1BatchDataPost, PromHandlerV1Batch))
 	mainRouter.Handle("GET /v1/policies", s.instrumentHandler(s.v1PoliciesList, PromHandlerV1Policies))
 	mainRouter.Handle("DELETE /v1/policies/{path...}", s.instrumentHandler(s.v1PoliciesDelete, PromHandlerV1Policies))
 	mainRouter.Handle("GET /v1/policies/{path...}", s.instrumentHandler(s.v1PoliciesGet, PromHandlerV1Policies))
@@ -923,6 +1043,7 @@ func (s *Server) initRouters(ctx context.Context) {
 	mainRouter.Handle("GET /v1/compile/{path...}", s.instrumentHandler(s.v1CompileFilters, PromHandlerV1Compile))
 	mainRouter.Handle("GET /v1/config", s.instrumentHandler(s.v1ConfigGet, PromHandlerV1Config))
 	mainRouter.Handle("GET /v1/status", s.instrumentHandler(s.v1StatusGet, PromHandlerV1Status))
+	mainRouter.Handle("GET /v1/storage/backup", s.instrumentHandler(s.v1StorageBackupGet, PromHandlerV1Storage))
 	mainRouter.Handle("POST /{$}", s.instrumentHandler(s.unversionedPost, PromHandlerIndex))
 	mainRouter.Handle("GET /{$}", s.instrumentHandler(s.indexGet, PromHandlerIndex))
 
@@ -931,14 +1052,19 @@ func (s *Server) initRouters(ctx context.Context) {
 	mainRouter.Handle("/v0/data", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/data/{path...}", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/data", s.methodNotAllowedHandler())
+	mainRouter.Handle("/v1/batch/data/{path...}", s.methodNotAllowedHandler())
+	mainRouter.Handle("/v1/batch/data", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/policies", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/policies/{path...}", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/query/{path...}", s.methodNotAllowedHandler())
 	mainRouter.Handle("/v1/query", s.methodNotAllowedHandler())
+	mainRouter.Handle("/v1/storage/backup", s.methodNotAllowedHandler())
 
-	// Add authorization handler in the end so that it can run first
-	s.Handler = handlerAuthz
-	s.DiagnosticHandler = handlerAuthzDiag
+	// Add authorization handler in the end so that it can run first. Rate
+	// limits are checked before, so that limited clients can't keep the
+	// authorizer busy.
+	s.Handler = s.initHandlerRateLimits(mainRouter, handlerAuthz)
+	s.DiagnosticHandler = s.initHandlerRateLimits(diagRouter, handlerAuthzDiag)
 }
 
 func createMiddleware(mw ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
@@ -951,17 +1077,34 @@ func createMiddleware(mw ...func(http.Handler) http.Handler) func(http.Handler)
 	}
 }
 
+// endpointHandler is the handler of an API endpoint. Rate limited requests to
+// the endpoint are responded to by limited, which is instrumented the same way
+// as the endpoint.
+type endpointHandler struct {
+	http.Handler
+	label   string
+	limited http.Handler
+}
+
 func (s *Server) instrumentHandler(handler func(http.ResponseWriter, *http.Request), label string) http.Handler {
 	httpHandler := handlers.DefaultHandler(createMiddleware(
 		s.manager.ExtraMiddlewares()...,
 	)(http.HandlerFunc(handler)))
+	return &endpointHandler{
+		Handler: s.instrument(httpHandler, label),
+		label:   label,
+		limited: s.instrument(http.HandlerFunc(writeRateLimited), label),
+	}
+}
+
+func (s *Server) instrument(handler http.Handler, label string) http.Handler {
 	if len(s.distributedTracingOpts) > 0 {
-		httpHandler = tracing.NewHandler(httpHandler, label, s.distributedTracingOpts)
+		handler = tracing.NewHandler(handler, label, s.distributedTracingOpts)
 	}
 	if s.metrics != nil {
-		return s.metrics.InstrumentHandler(httpHandler, label)
+		return s.metrics.InstrumentHandler(handler, label)
 	}
-	return httpHandler
+	return handler
 }
 
 func (s *Server) methodNotAllowedHandler() http.Handler {
@@ -1297,11 +1440,18 @@ func (*Server) bundlesReady(pluginStatuses map[string]*plugins.Status) bool {
 }
 
 func (s *Server) unversionedGetHealth(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
 	includeBundleStatus := getBoolParam(r.URL, types.ParamBundleActivationV1, true) || //nolint:staticcheck
 		getBoolParam(r.URL, types.ParamBundlesActivationV1, true)
 	includePluginStatus := getBoolParam(r.URL, types.ParamPluginsV1, true)
 	excludePlugin := getStringSliceParam(r.URL, types.ParamExcludePluginV1)
+
+	writeHealthResponse(w, s.checkHealth(r.Context(), includeBundleStatus, includePluginStatus, excludePlugin))
+}
+
+// checkHealth returns why the server isn't healthy, if it isn't. Unless
+// includeBundleStatus or includePluginStatus are set, the server is healthy
+// if it can evaluate a query.
+func (s *Server) checkHealth(ctx context.Context, includeBundleStatus, includePluginStatus bool, excludePlugin []string) error {
 	excludePluginMap := map[string]struct{}{}
 	for _, name := range excludePlugin {
 		excludePluginMap[name] = struct{}{}
@@ -1309,8 +1459,7 @@ func (s *Server) unversionedGetHealth(w http.ResponseWriter, r *http.Request) {
 
 	// Ensure the server can evaluate a simple query
 	if !s.canEval(ctx) {
-		writeHealthResponse(w, errors.New("unable to perform evaluation"))
-		return
+		return errors.New("unable to perform evaluation")
 	}
 
 	pluginStatuses := s.manager.PluginStatus()
@@ -1320,28 +1469,21 @@ func (s *Server) unversionedGetHealth(w http.ResponseWriter, r *http.Request) {
 	// normal bundles that are configured.
 	if includeBundleStatus && !s.bundlesReady(pluginStatuses) {
 		// For backwards compatibility we don't return a payload with statuses for the bundle endpoint
-		writeHealthResponse(w, errors.New("one or more bundles are not activated"))
-		return
+		return errors.New("one or more bundles are not activated")
 	}
 
 	if includePluginStatus {
 		// Ensure that all plugins (if requested to be included in the result) have an OK status.
-		hasErr := false
 		for name, status := range pluginStatuses {
 			if _, exclude := excludePluginMap[name]; exclude {
 				continue
 			}
 			if status != nil && status.State != plugins.StateOK {
-				hasErr = true
-				break
+				return errors.New("one or more plugins are not up")
 			}
 		}
-		if hasErr {
-			writeHealthResponse(w, errors.New("one or more plugins are not up"))
-			return
-		}
 	}
-	writeHealthResponse(w, nil)
+	return nil
 }
 
 func (s *Server) unversionedGetHealthWithPolicy(w http.ResponseWriter, r *http.Request) {
@@ -1429,9 +1571,7 @@ func writeHealthResponse(w http.ResponseWriter, err error) {
 }
 
 func (s *Server) v1CompilePost(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	explainMode := getExplain(r.URL, types.ExplainOffV1)
-	includeInstrumentation := getBoolParam(r.URL, types.ParamInstrumentV1, true)
+	opts := getCompileOptions(r.URL)
 
 	m := metrics.New()
 	m.Timer(metrics.ServerHandler).Start()
@@ -1452,17 +1592,45 @@ func (s *Server) v1CompilePost(w http.ResponseWriter, r *http.Request) {
 
 	m.Timer(metrics.RegoQueryParse).Stop()
 
+	result, err := s.compile(r.Context(), m, request, opts)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writer.JSONOK(w, result, opts.pretty)
+}
+
+// compileOptions are the options of Compile API requests.
+type compileOptions struct {
+	explain    types.ExplainModeV1
+	metrics    bool
+	instrument bool
+	pretty     bool
+}
+
+func getCompileOptions(u *url.URL) compileOptions {
+	return compileOptions{
+		explain:    getExplain(u, types.ExplainOffV1),
+		metrics:    getBoolParam(u, types.ParamMetricsV1, true),
+		instrument: getBoolParam(u, types.ParamInstrumentV1, true),
+		pretty:     getBoolParam(u, types.ParamPrettyV1, true),
+	}
+}
+
+// compile partially evaluates the query of request. The ServerHandler timer
+// of m must have been started.
+func (s *Server) compile(ctx context.Context, m metrics.Metrics, request *compileRequest, opts compileOptions) (*types.CompileResponseV1, error) {
 	c := storage.NewContext().WithMetrics(m)
 	txn, err := s.store.NewTransaction(ctx, storage.TransactionParams{Context: c})
 	if err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return nil, err
 	}
 
 	defer s.store.Abort(ctx, txn)
 
 	var buf *topdown.BufferTracer
-	if explainMode != types.ExplainOffV1 {
+	if opts.explain != types.ExplainOffV1 {
 		buf = topdown.NewBufferTracer()
 	}
 
@@ -1476,7 +1644,7 @@ func (s *Server) v1CompilePost(w http.ResponseWriter, r *http.Request) {
 		rego.DisableInlining(request.Options.DisableInlining),
 		rego.NondeterministicBuiltins(request.Options.NondeterminsiticBuiltins),
 		rego.QueryTracer(buf),
-		rego.Instrument(includeInstrumentation),
+		rego.Instrument(opts.instrument),
 		rego.Metrics(m),
 		rego.Runtime(s.runtime),
 		rego.UnsafeBuiltins(unsafeBuiltinsMap),
@@ -1489,23 +1657,22 @@ func (s *Server) v1CompilePost(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch err := err.(type) {
 		case ast.Errors:
-			writer.Error(w, http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, types.MsgCompileModuleError).WithASTErrors(err))
+			return nil, newAPIError(http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, types.MsgCompileModuleError).WithASTErrors(err))
 		default:
-			writer.ErrorAuto(w, err)
+			return nil, err
 		}
-		return
 	}
 
 	m.Timer(metrics.ServerHandler).Stop()
 
-	result := types.CompileResponseV1{}
+	result := &types.CompileResponseV1{}
 
-	if includeMetrics(r) || includeInstrumentation {
+	if opts.metrics || opts.instrument {
 		result.Metrics = m.All()
 	}
 
-	if explainMode != types.ExplainOffV1 {
-		result.Explanation = s.getExplainResponse(explainMode, *buf, pretty(r))
+	if opts.explain != types.ExplainOffV1 {
+		result.Explanation = s.getExplainResponse(opts.explain, *buf, opts.pretty)
 	}
 
 	var i any = types.PartialEvaluationResultV1{
@@ -1515,7 +1682,7 @@ func (s *Server) v1CompilePost(w http.ResponseWriter, r *http.Request) {
 
 	result.Result = &i
 
-	writer.JSONOK(w, result, pretty(r))
+	return result, nil
 }
 
 func (s *Server) v1DataGet(w http.ResponseWriter, r *http.Request) {
@@ -1528,6 +1695,11 @@ func (s *Server) v1DataGet(w http.ResponseWriter, r *http.Request) {
 	annotateSpan(ctx, decisionID)
 
 	urlPath := escapedPathValue(r, "path")
+	if getBoolParam(r.URL, types.ParamWatchV1, true) {
+		s.v1DataWatch(w, r, urlPath)
+		return
+	}
+
 	explainMode := getExplain(r.URL, types.ExplainOffV1)
 	includeInstrumentation := getBoolParam(r.URL, types.ParamInstrumentV1, true)
 	provenance := getBoolParam(r.URL, types.ParamProvenanceV1, true)
@@ -1627,10 +1799,16 @@ func (s *Server) v1DataGet(w http.ResponseWriter, r *http.Request) {
 		rego.EvalEvaluatedRuleTracker(tracker),
 	}
 
+	debugTracer, debugDone := s.debugTracer(ctx, r.Method+" "+r.URL.Path, decisionID, input)
+	if debugTracer != nil {
+		evalOpts = append(evalOpts, rego.EvalQueryTracer(debugTracer))
+	}
+
 	rs, err := preparedQuery.Eval(
 		ctx,
 		evalOpts...,
 	)
+	debugDone(rs)
 
 	m.Timer(metrics.ServerHandler).Stop()
 
@@ -1748,13 +1926,10 @@ func (s *Server) v1DataPatch(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
-	m := s.getMetrics(r)
+	opts := getDataOptions(r.URL)
+	m := s.newMetrics(opts.metrics, opts.instrument)
 	m.Timer(metrics.ServerHandler).Start()
 
-	decisionID := s.generateDecisionID()
-	ctx := logging.WithDecisionID(r.Context(), decisionID)
-	annotateSpan(ctx, decisionID)
-
 	m.Timer(metrics.RegoInputParse).Start()
 
 	parsed, err := readInputPostV1(r)
@@ -1763,9 +1938,61 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	input := parsed.Value
-	goInput := parsed.GoInput
-	reqMetadata := parsed.Metadata
+	m.Timer(metrics.RegoInputParse).Stop()
+
+	result, err := s.evalData(r.Context(), m, &dataRequest{
+		name:        r.Method + " " + r.URL.Path,
+		path:        escapedPathValue(r, "path"),
+		input:       parsed,
+		dataOptions: opts,
+	})
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+	writer.JSONOK(w, result, opts.pretty)
+}
+
+// dataOptions are the options of Data API decision requests.
+type dataOptions struct {
+	provenance          bool
+	explain             types.ExplainModeV1
+	strictBuiltinErrors bool
+	metrics             bool
+	instrument          bool
+	pretty              bool
+}
+
+func getDataOptions(u *url.URL) dataOptions {
+	return dataOptions{
+		provenance:          getBoolParam(u, types.ParamProvenanceV1, true),
+		explain:             getExplain(u, types.ExplainOffV1),
+		strictBuiltinErrors: getBoolParam(u, types.ParamStrictBuiltinErrors, true),
+		metrics:             getBoolParam(u, types.ParamMetricsV1, true),
+		instrument:          getBoolParam(u, types.ParamInstrumentV1, true),
+		pretty:              getBoolParam(u, types.ParamPrettyV1, true),
+	}
+}
+
+// dataRequest is a decision request of the Data API, made with the REST or
+// the gRPC API.
+type dataRequest struct {
+	name  string // describes the request to debug sessions, e.g. "POST /v1/data/x"
+	path  string // escaped path of the decision under data
+	input *parsedInput
+	dataOptions
+}
+
+// evalData evaluates the decision of req, and logs it. The ServerHandler
+// timer of m must have been started.
+func (s *Server) evalData(ctx context.Context, m metrics.Metrics, req *dataRequest) (*types.DataResponseV1, error) {
+	decisionID := s.generateDecisionID()
+	ctx = logging.WithDecisionID(ctx, decisionID)
+	annotateSpan(ctx, decisionID)
+
+	input := req.input.Value
+	goInput := req.input.GoInput
+	reqMetadata := req.input.Metadata
 
 	respMetadata := map[string]any{}
 	customLog := func() map[string]any {
@@ -1782,26 +2009,20 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		return c
 	}
 
-	m.Timer(metrics.RegoInputParse).Stop()
-
 	txn, err := s.store.NewTransaction(ctx, storage.TransactionParams{Context: storage.NewContext().WithMetrics(m)})
 	if err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return nil, err
 	}
 
 	defer s.store.Abort(ctx, txn)
 
-	provenance := getBoolParam(r.URL, types.ParamProvenanceV1, true)
-
 	var logger decisionLogger
 	var br bundleRevisions
 
-	if s.logger != nil || provenance {
+	if s.logger != nil || req.provenance {
 		br, err = getRevisions(ctx, s.store, txn)
 		if err != nil {
-			writer.ErrorAuto(w, err)
-			return
+			return nil, err
 		}
 		if s.logger != nil {
 			ctx, logger = s.getDecisionLogger(ctx, br)
@@ -1810,8 +2031,7 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 
 	var buf *topdown.BufferTracer
 
-	explainMode := getExplain(r.URL, types.ExplainOffV1)
-	if explainMode != types.ExplainOffV1 {
+	if req.explain != types.ExplainOffV1 {
 		buf = topdown.NewBufferTracer()
 	}
 
@@ -1820,13 +2040,10 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		ndbCache = builtins.NDBCache{}
 	}
 
-	urlPath := escapedPathValue(r, "path")
-
-	strictBuiltinErrors := getBoolParam(r.URL, types.ParamStrictBuiltinErrors, true)
-	includeInstrumentation := getBoolParam(r.URL, types.ParamInstrumentV1, true)
+	urlPath := req.path
 
 	pqID := "v1DataPost::"
-	if strictBuiltinErrors {
+	if req.strictBuiltinErrors {
 		pqID = "v1DataPost::strict-builtin-errors::"
 	}
 	pqID += urlPath
@@ -1845,18 +2062,16 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		rego, err := s.makeRego(ctx, strictBuiltinErrors, txn, input, urlPath, m, includeInstrumentation, buf, opts)
+		rego, err := s.makeRego(ctx, req.strictBuiltinErrors, txn, input, urlPath, m, req.instrument, buf, opts)
 		if err != nil {
 			_ = logger.Log(ctx, txn, urlPath, "", goInput, input, nil, ndbCache, err, m, nil, customLog())
-			writer.ErrorAuto(w, err)
-			return
+			return nil, err
 		}
 
 		pq, err := rego.PrepareForEval(ctx)
 		if err != nil {
 			_ = logger.Log(ctx, txn, urlPath, "", goInput, input, nil, ndbCache, err, m, nil, customLog())
-			writer.ErrorAuto(w, err)
-			return
+			return nil, err
 		}
 		preparedQuery = &pq
 		s.preparedEvalQueries.Insert(pqID, preparedQuery)
@@ -1870,7 +2085,7 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		rego.EvalQueryTracer(buf),
 		rego.EvalInterQueryBuiltinCache(s.interQueryBuiltinCache),
 		rego.EvalInterQueryBuiltinValueCache(s.interQueryBuiltinValueCache),
-		rego.EvalInstrument(includeInstrumentation),
+		rego.EvalInstrument(req.instrument),
 		rego.EvalNDBuiltinCache(ndbCache),
 		rego.EvalResponseMetadata(respMetadata),
 		rego.EvalEvaluatedRuleTracker(tracker),
@@ -1880,18 +2095,23 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		evalOpts = append(evalOpts, rego.EvalRequestMetadata(reqMetadata))
 	}
 
+	debugTracer, debugDone := s.debugTracer(ctx, req.name, decisionID, input)
+	if debugTracer != nil {
+		evalOpts = append(evalOpts, rego.EvalQueryTracer(debugTracer))
+	}
+
 	rs, err := preparedQuery.Eval(ctx, evalOpts...)
+	debugDone(rs)
 
 	m.Timer(metrics.ServerHandler).Stop()
 
 	// Handle results.
 	if err != nil {
 		_ = logger.Log(ctx, txn, urlPath, "", goInput, input, nil, ndbCache, err, m, nil, customLog())
-		writer.ErrorAuto(w, err)
-		return
+		return nil, err
 	}
 
-	result := types.DataResponseV1{
+	result := &types.DataResponseV1{
 		DecisionID: decisionID,
 	}
 
@@ -1903,41 +2123,36 @@ func (s *Server) v1DataPost(w http.ResponseWriter, r *http.Request) {
 		result.Warning = types.NewWarning(types.CodeAPIUsageWarn, types.MsgInputKeyMissing)
 	}
 
-	includeMetrics := getBoolParam(r.URL, types.ParamMetricsV1, true)
-	if includeMetrics || includeInstrumentation {
+	if req.metrics || req.instrument {
 		result.Metrics = m.All()
 	}
 
-	if provenance {
+	if req.provenance {
 		result.Provenance = s.getProvenance(br)
 	}
 
 	if len(rs) == 0 {
-		if explainMode == types.ExplainFullV1 {
-			if result.Explanation, err = types.NewTraceV1(lineage.Full(*buf), pretty(r)); err != nil {
-				writer.ErrorAuto(w, err)
-				return
+		if req.explain == types.ExplainFullV1 {
+			if result.Explanation, err = types.NewTraceV1(lineage.Full(*buf), req.pretty); err != nil {
+				return nil, err
 			}
 		}
 		if err = logger.Log(ctx, txn, urlPath, "", goInput, input, nil, ndbCache, nil, m, nil, customLog()); err != nil {
-			writer.ErrorAuto(w, err)
-			return
+			return nil, err
 		}
-		writer.JSONOK(w, result, pretty(r))
-		return
+		return result, nil
 	}
 
 	result.Result = &rs[0].Expressions[0].Value
 
-	if explainMode != types.ExplainOffV1 {
-		result.Explanation = s.getExplainResponse(explainMode, *buf, pretty(r))
+	if req.explain != types.ExplainOffV1 {
+		result.Explanation = s.getExplainResponse(req.explain, *buf, req.pretty)
 	}
 
 	if err := logger.Log(ctx, txn, urlPath, "", goInput, input, result.Result, ndbCache, nil, m, evaluatedRuleLabels(tracker), customLog()); err != nil {
-		writer.ErrorAuto(w, err)
-		return
+		return nil, err
 	}
-	writer.JSONOK(w, result, pretty(r))
+	return result, nil
 }
 
 func escapedPathValue(r *http.Request, key string) string {
@@ -2474,14 +2689,24 @@ func (s *Server) v1ConfigGet(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) v1StatusGet(w http.ResponseWriter, r *http.Request) {
+	result, err := s.getStatus()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writer.JSONOK(w, result, pretty(r))
+}
+
+// getStatus returns the status of the status plugin.
+func (s *Server) getStatus() (*types.StatusResponseV1, error) {
 	p := status.Lookup(s.manager)
 	if p == nil {
-		writer.ErrorString(w, http.StatusInternalServerError, types.CodeInternal, errors.New("status plugin not enabled"))
-		return
+		return nil, newAPIError(http.StatusInternalServerError, types.NewErrorV1(types.CodeInternal, "status plugin not enabled"))
 	}
 
 	var st any = p.Snapshot()
-	writer.JSONOK(w, types.StatusResponseV1{Result: &st}, pretty(r))
+	return &types.StatusResponseV1{Result: &st}, nil
 }
 
 func (s *Server) checkPolicyIDScope(ctx context.Context, txn storage.Transaction, id string) error {
@@ -2507,11 +2732,10 @@ func (s *Server) checkPolicyPackageScope(ctx context.Context, txn storage.Transa
 	return s.checkPathScope(ctx, txn, path)
 }
 
-func (s *Server) getMetrics(r *http.Request) metrics.Metrics {
-	metricsInQuery := getBoolParam(r.URL, types.ParamMetricsV1, true)
-	instrumentationInQuery := getBoolParam(r.URL, types.ParamInstrumentV1, true)
-
-	if s.logger == nil && !metricsInQuery && !instrumentationInQuery {
+// newMetrics returns the metrics of a request, which are only collected if
+// they are returned or logged.
+func (s *Server) newMetrics(includeMetrics, includeInstrumentation bool) metrics.Metrics {
+	if s.logger == nil && !includeMetrics && !includeInstrumentation {
 		return metrics.NoOp()
 	}
 
@@ -2608,6 +2832,36 @@ func (s *Server) abort(ctx context.Context, txn storage.Transaction, finish func
 	finish()
 }
 
+// apiError is an error reported with the given status and error response,
+// by the REST and gRPC APIs alike.
+type apiError struct {
+	status int
+	resp   *types.ErrorV1
+}
+
+func newAPIError(status int, resp *types.ErrorV1) *apiError {
+	return &apiError{status: status, resp: resp}
+}
+
+func (e *apiError) Error() string {
+	return e.resp.Message
+}
+
+// apiErrorFor returns err as *apiError. Other errors are reported like
+// writer.ErrorAuto reports them.
+func apiErrorFor(err error) *apiError {
+	var e *apiError
+	if errors.As(err, &e) {
+		return e
+	}
+	return newAPIError(writer.AutoStatus(err))
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e := apiErrorFor(err)
+	writer.Error(w, e.status, e.resp)
+}
+
 func (s *Server) abortAuto(ctx context.Context, txn storage.Transaction, w http.ResponseWriter, err error) {
 	s.abort(ctx, txn, func() { writer.ErrorAuto(w, err) })
 }
@@ -3133,6 +3387,7 @@ func (l decisionLogger) Log(
 		rctx = *r
 	}
 	decisionID, _ := logging.DecisionIDFromContext(ctx)
+	batchDecisionID, _ := logging.BatchDecisionIDFromContext(ctx)
 
 	var httpRctx logging.HTTPRequestContext
 
@@ -3147,6 +3402,7 @@ func (l decisionLogger) Log(
 		Bundles:             bundles,
 		Timestamp:           time.Now().UTC(),
 		DecisionID:          decisionID,
+		BatchDecisionID:     batchDecisionID,
 		RemoteAddr:          rctx.ClientAddr,
 		HTTPRequestContext:  httpRctx,
 		Path:                path,
@@ -10,10 +10,13 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"encoding/pem"
@@ -5256,6 +5259,140 @@ allow if {
 	}
 }
 
+func TestJWTAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	secret := []byte("this-is-a-shared-secret-of-32-bytes")
+	jwks := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwks, []byte(`{"keys": [{"kty": "oct", "kid": "k1", "alg": "HS256", "k": "`+
+		base64.RawURLEncoding.EncodeToString(secret)+`"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(key []byte, claims string) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"k1","typ":"JWT"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(claims))
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(payload))
+		return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	store := inmem.New()
+	txn := storage.NewTransactionOrDie(ctx, store, storage.WriteParams)
+	if err := store.UpsertPolicy(ctx, txn, "authz.rego", []byte(`package system.authz
+
+		default allow := false
+
+		allow if input.identity.sub == "alice"
+	`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := plugins.New([]byte(`{"server": {"authentication": {"jwt": {"issuers": [
+		{"issuer": "https://idp.example.com", "audiences": ["opa"], "jwks_file": "`+jwks+`"}
+	]}}}}`), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := New().
+		WithAddresses([]string{"localhost:8182"}).
+		WithStore(store).
+		WithManager(m).
+		WithAuthentication(AuthenticationJWT).
+		WithAuthorization(AuthorizationBasic).
+		Init(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		note    string
+		token   string
+		status  int
+		message string
+	}{
+		{
+			note:   "verified identity allowed by policy",
+			token:  sign(secret, fmt.Sprintf(`{"iss": "https://idp.example.com", "aud": "opa", "sub": "alice", "exp": %d}`, exp)),
+			status: http.StatusOK,
+		},
+		{
+			note:    "verified identity denied by policy",
+			token:   sign(secret, fmt.Sprintf(`{"iss": "https://idp.example.com", "aud": "opa", "sub": "bob", "exp": %d}`, exp)),
+			status:  http.StatusUnauthorized,
+			message: types.MsgUnauthorizedError,
+		},
+		{
+			note:    "forged token",
+			token:   sign([]byte("not-the-shared-secret-of-32-bytes!!"), fmt.Sprintf(`{"iss": "https://idp.example.com", "aud": "opa", "sub": "alice", "exp": %d}`, exp)),
+			status:  http.StatusUnauthorized,
+			message: types.MsgUnauthenticatedError,
+		},
+		{
+			note:    "no token",
+			status:  http.StatusUnauthorized,
+			message: types.MsgUnauthorizedError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://localhost:8182/health", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			validateAuthorizedRequest(t, server, req, tc.status)
+
+			if tc.message != "" {
+				recorder := httptest.NewRecorder()
+				server.Handler.ServeHTTP(recorder, req)
+				var resp types.ErrorV1
+				if err := util.NewJSONDecoder(recorder.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Message != tc.message {
+					t.Fatalf("Expected message %q but got %q", tc.message, resp.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticationRequiresConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	m, err := plugins.New([]byte{}, "test", inmem.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New().
+		WithAddresses([]string{"localhost:8182"}).
+		WithStore(inmem.New()).
+		WithManager(m).
+		WithAuthentication(AuthenticationJWT).
+		Init(ctx)
+	if err == nil || !strings.Contains(err.Error(), "server.authentication.jwt") {
+		t.Fatalf("Expected configuration error but got: %v", err)
+	}
+}
+
 func validateAuthorizedRequest(t *testing.T, s *Server, req *http.Request, exp int) {
 	t.Helper()
 
@@ -0,0 +1,52 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/writer"
+	"github.com/open-policy-agent/opa/v1/storage"
+)
+
+// backupStore is implemented by stores that can write snapshots of their
+// contents, like the disk store.
+type backupStore interface {
+	Backup(ctx context.Context, txn storage.Transaction, w io.Writer) error
+}
+
+// v1StorageBackupGet streams a snapshot of the store, taken in a read
+// transaction, so that the store of a running instance can be backed up.
+func (s *Server) v1StorageBackupGet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bs, ok := s.store.(backupStore)
+	if !ok {
+		writer.ErrorString(w, http.StatusNotImplemented, types.CodeInvalidOperation, errors.New("store does not support backups"))
+		return
+	}
+
+	txn, err := s.store.NewTransaction(ctx)
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+	defer s.store.Abort(ctx, txn)
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="backup.gz"`)
+	w.WriteHeader(http.StatusOK)
+
+	if err := bs.Backup(ctx, txn, w); err != nil {
+		s.manager.Logger().WithFields(map[string]any{"err": err}).Error("Failed to write storage backup.")
+		// The status has been sent already, abort the response so that the
+		// client doesn't mistake it for a complete backup.
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/storage/disk"
+)
+
+func TestV1StorageBackup(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	store, err := disk.New(ctx, logging.NewNoOpLogger(), nil, disk.Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close(ctx)
+
+	f := newFixtureWithStore(t, store)
+
+	if err := f.v1(http.MethodPut, "/data/a", `{"b": 1}`, 204, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.v1(http.MethodPut, "/policies/test", "package test\n\np := 1", 200, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	f.reset()
+	f.server.Handler.ServeHTTP(f.recorder, newReqV1(http.MethodGet, "/storage/backup", ""))
+	if f.recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", f.recorder.Code, f.recorder.Body)
+	}
+	if ct := f.recorder.Header().Get("Content-Type"); ct != "application/gzip" {
+		t.Fatalf("expected gzip content type, got %q", ct)
+	}
+
+	info, err := disk.InspectBackup(f.recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(info.Policies, []string{"test"}) || !slices.Contains(info.Roots, "a") {
+		t.Fatalf("unexpected backup: %+v", info)
+	}
+}
+
+func TestV1StorageBackupUnsupported(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	if err := f.v1(http.MethodGet, "/storage/backup", "", 501, `{
+		"code": "invalid_operation",
+		"message": "store does not support backups"
+	}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.v1(http.MethodPost, "/storage/backup", "", 405, ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -124,6 +124,7 @@ const (
 	CodeResourceNotFound  = "resource_not_found"
 	CodeResourceConflict  = "resource_conflict"
 	CodeUndefinedDocument = "undefined_document"
+	CodeTooManyRequests   = "too_many_requests"
 )
 
 // ErrorV1 models an error response sent to the client.
@@ -175,12 +176,14 @@ const (
 	MsgEvaluationError            = "error(s) occurred while evaluating query"
 	MsgUnauthorizedUndefinedError = "authorization policy missing or undefined"
 	MsgUnauthorizedError          = "request rejected by administrative policy"
+	MsgUnauthenticatedError       = "bearer token could not be verified"
 	MsgUndefinedError             = "document missing or undefined"
 	MsgMissingError               = "document missing"
 	MsgFoundUndefinedError        = "document undefined"
 	MsgPluginConfigError          = "error(s) occurred while configuring plugin(s)"
 	MsgDecodingLimitError         = "request body too large"
 	MsgDecodingGzipLimitError     = "compressed request body too large"
+	MsgRateLimitError             = "rate limit exceeded"
 )
 
 // PatchV1 models a single patch operation against a document.
@@ -292,6 +295,50 @@ func (r DataResponseV1) MarshalJSON() ([]byte, error) {
 	return MarshalExtras[DataResponseV1](alias(r), r.Metadata)
 }
 
+// DataWatchEventV1 models an event streamed by the Data API when the client
+// watches a path. Revisions holds the revisions of the bundles activated by
+// the transaction that made the changes.
+type DataWatchEventV1 struct {
+	Revisions map[string]string   `json:"revisions,omitempty"`
+	Changes   []DataWatchChangeV1 `json:"changes"`
+}
+
+// DataWatchChangeV1 models a change to a base document under a watched path.
+type DataWatchChangeV1 struct {
+	Path    string `json:"path"`
+	Value   *any   `json:"value,omitempty"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+// BatchDataRequestV1 models the request message for batch Data API POST
+// operations. Each entry in Inputs is evaluated as a separate decision and
+// its result is reported under the same key in the response.
+type BatchDataRequestV1 struct {
+	Inputs map[string]*any `json:"inputs"`
+}
+
+// BatchDataResponseV1 models the response message for batch Data API POST
+// operations.
+type BatchDataResponseV1 struct {
+	BatchDecisionID string                             `json:"batch_decision_id,omitempty"`
+	Metrics         MetricsV1                          `json:"metrics,omitempty"`
+	Responses       map[string]BatchDataResponseItemV1 `json:"responses"`
+}
+
+// BatchDataResponseItemV1 models the result of a single evaluation in a
+// batch Data API operation. If the evaluation failed, Error is set and the
+// result fields are omitted.
+type BatchDataResponseItemV1 struct {
+	HTTPStatusCode int           `json:"http_status_code"`
+	DecisionID     string        `json:"decision_id,omitempty"`
+	Provenance     *ProvenanceV1 `json:"provenance,omitempty"`
+	Explanation    TraceV1       `json:"explanation,omitempty"`
+	Metrics        MetricsV1     `json:"metrics,omitempty"`
+	Result         *any          `json:"result,omitempty"`
+	Warning        *Warning      `json:"warning,omitempty"`
+	Error          *ErrorV1      `json:"error,omitempty"`
+}
+
 // Warning models DataResponse warnings
 type Warning struct {
 	Code    string `json:"code,omitempty"`
@@ -608,6 +655,11 @@ const (
 	// ParamStrictBuiltinErrors names the HTTP URL parameter that indicates the client
 	// wants built-in function errors to be treated as fatal.
 	ParamStrictBuiltinErrors = "strict-builtin-errors"
+
+	// ParamWatchV1 defines the name of the HTTP URL parameter that indicates
+	// the client wants to receive a stream of changes to the data instead of
+	// a single result.
+	ParamWatchV1 = "watch"
 )
 
 // BadRequestErr represents an error condition raised if the caller passes
@@ -0,0 +1,1328 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v7.35.1
+// source: v1/server/server.proto
+
+package v1pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DataRequest mirrors `types.DataRequestV1` in v1/server/types/types.go,
+// together with the query parameters of `POST /v1/data/{path}`.
+type DataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slash-separated path of the decision under `data`, e.g. `authz/allow`.
+	Path *string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// Input document. The decision is evaluated without input if unset.
+	Input *structpb.Value `protobuf:"bytes,2,opt,name=input" json:"input,omitempty"`
+	// Additional top-level fields of the request body, passed on as request
+	// metadata.
+	Metadata *structpb.Struct `protobuf:"bytes,3,opt,name=metadata" json:"metadata,omitempty"`
+	// Query parameters: `provenance`, `explain`, `metrics`, `instrument` and
+	// `strict-builtin-errors`.
+	Provenance          *bool   `protobuf:"varint,4,opt,name=provenance" json:"provenance,omitempty"`
+	Explain             *string `protobuf:"bytes,5,opt,name=explain" json:"explain,omitempty"`
+	Metrics             *bool   `protobuf:"varint,6,opt,name=metrics" json:"metrics,omitempty"`
+	Instrument          *bool   `protobuf:"varint,7,opt,name=instrument" json:"instrument,omitempty"`
+	StrictBuiltinErrors *bool   `protobuf:"varint,8,opt,name=strict_builtin_errors,json=strictBuiltinErrors" json:"strict_builtin_errors,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DataRequest) Reset() {
+	*x = DataRequest{}
+	mi := &file_v1_server_server_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataRequest) ProtoMessage() {}
+
+func (x *DataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataRequest.ProtoReflect.Descriptor instead.
+func (*DataRequest) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DataRequest) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *DataRequest) GetInput() *structpb.Value {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *DataRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *DataRequest) GetProvenance() bool {
+	if x != nil && x.Provenance != nil {
+		return *x.Provenance
+	}
+	return false
+}
+
+func (x *DataRequest) GetExplain() string {
+	if x != nil && x.Explain != nil {
+		return *x.Explain
+	}
+	return ""
+}
+
+func (x *DataRequest) GetMetrics() bool {
+	if x != nil && x.Metrics != nil {
+		return *x.Metrics
+	}
+	return false
+}
+
+func (x *DataRequest) GetInstrument() bool {
+	if x != nil && x.Instrument != nil {
+		return *x.Instrument
+	}
+	return false
+}
+
+func (x *DataRequest) GetStrictBuiltinErrors() bool {
+	if x != nil && x.StrictBuiltinErrors != nil {
+		return *x.StrictBuiltinErrors
+	}
+	return false
+}
+
+// DataResponse mirrors `types.DataResponseV1` in v1/server/types/types.go.
+type DataResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DecisionId *string                `protobuf:"bytes,1,opt,name=decision_id,json=decisionId" json:"decision_id,omitempty"`
+	// Decision result, unset if the decision is undefined.
+	Result      *structpb.Value  `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
+	Provenance  *Provenance      `protobuf:"bytes,3,opt,name=provenance" json:"provenance,omitempty"`
+	Explanation *structpb.Value  `protobuf:"bytes,4,opt,name=explanation" json:"explanation,omitempty"`
+	Metrics     *structpb.Struct `protobuf:"bytes,5,opt,name=metrics" json:"metrics,omitempty"`
+	Warning     *Warning         `protobuf:"bytes,6,opt,name=warning" json:"warning,omitempty"`
+	// Response metadata set by the policy. The REST API returns these as
+	// additional top-level fields.
+	Metadata *structpb.Struct `protobuf:"bytes,7,opt,name=metadata" json:"metadata,omitempty"`
+	// Error of a failed decision. Only set on StreamData responses; a failed
+	// GetData call returns an error status instead.
+	Error         *Error `protobuf:"bytes,8,opt,name=error" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataResponse) Reset() {
+	*x = DataResponse{}
+	mi := &file_v1_server_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataResponse) ProtoMessage() {}
+
+func (x *DataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataResponse.ProtoReflect.Descriptor instead.
+func (*DataResponse) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DataResponse) GetDecisionId() string {
+	if x != nil && x.DecisionId != nil {
+		return *x.DecisionId
+	}
+	return ""
+}
+
+func (x *DataResponse) GetResult() *structpb.Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *DataResponse) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *DataResponse) GetExplanation() *structpb.Value {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+func (x *DataResponse) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *DataResponse) GetWarning() *Warning {
+	if x != nil {
+		return x.Warning
+	}
+	return nil
+}
+
+func (x *DataResponse) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *DataResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// BatchDataRequest mirrors `types.BatchDataRequestV1` in
+// v1/server/types/types.go, together with the query parameters of
+// `POST /v1/batch/data/{path}`.
+type BatchDataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slash-separated path of the decision under `data`, e.g. `authz/allow`.
+	Path *string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// Input documents keyed by caller-chosen IDs.
+	Inputs              map[string]*structpb.Value `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Provenance          *bool                      `protobuf:"varint,3,opt,name=provenance" json:"provenance,omitempty"`
+	Explain             *string                    `protobuf:"bytes,4,opt,name=explain" json:"explain,omitempty"`
+	Metrics             *bool                      `protobuf:"varint,5,opt,name=metrics" json:"metrics,omitempty"`
+	Instrument          *bool                      `protobuf:"varint,6,opt,name=instrument" json:"instrument,omitempty"`
+	StrictBuiltinErrors *bool                      `protobuf:"varint,7,opt,name=strict_builtin_errors,json=strictBuiltinErrors" json:"strict_builtin_errors,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *BatchDataRequest) Reset() {
+	*x = BatchDataRequest{}
+	mi := &file_v1_server_server_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDataRequest) ProtoMessage() {}
+
+func (x *BatchDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDataRequest.ProtoReflect.Descriptor instead.
+func (*BatchDataRequest) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchDataRequest) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *BatchDataRequest) GetInputs() map[string]*structpb.Value {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *BatchDataRequest) GetProvenance() bool {
+	if x != nil && x.Provenance != nil {
+		return *x.Provenance
+	}
+	return false
+}
+
+func (x *BatchDataRequest) GetExplain() string {
+	if x != nil && x.Explain != nil {
+		return *x.Explain
+	}
+	return ""
+}
+
+func (x *BatchDataRequest) GetMetrics() bool {
+	if x != nil && x.Metrics != nil {
+		return *x.Metrics
+	}
+	return false
+}
+
+func (x *BatchDataRequest) GetInstrument() bool {
+	if x != nil && x.Instrument != nil {
+		return *x.Instrument
+	}
+	return false
+}
+
+func (x *BatchDataRequest) GetStrictBuiltinErrors() bool {
+	if x != nil && x.StrictBuiltinErrors != nil {
+		return *x.StrictBuiltinErrors
+	}
+	return false
+}
+
+// BatchDataResponse mirrors `types.BatchDataResponseV1` in
+// v1/server/types/types.go.
+type BatchDataResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BatchDecisionId *string                `protobuf:"bytes,1,opt,name=batch_decision_id,json=batchDecisionId" json:"batch_decision_id,omitempty"`
+	Metrics         *structpb.Struct       `protobuf:"bytes,2,opt,name=metrics" json:"metrics,omitempty"`
+	// Responses keyed by the IDs of their inputs.
+	Responses     map[string]*BatchDataResponseItem `protobuf:"bytes,3,rep,name=responses" json:"responses,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDataResponse) Reset() {
+	*x = BatchDataResponse{}
+	mi := &file_v1_server_server_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDataResponse) ProtoMessage() {}
+
+func (x *BatchDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDataResponse.ProtoReflect.Descriptor instead.
+func (*BatchDataResponse) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchDataResponse) GetBatchDecisionId() string {
+	if x != nil && x.BatchDecisionId != nil {
+		return *x.BatchDecisionId
+	}
+	return ""
+}
+
+func (x *BatchDataResponse) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *BatchDataResponse) GetResponses() map[string]*BatchDataResponseItem {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+// BatchDataResponseItem mirrors `types.BatchDataResponseItemV1` in
+// v1/server/types/types.go.
+type BatchDataResponseItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HTTP status code the decision would have been answered with by the
+	// REST API, e.g. 200 or 500.
+	HttpStatusCode *int32           `protobuf:"varint,1,opt,name=http_status_code,json=httpStatusCode" json:"http_status_code,omitempty"`
+	DecisionId     *string          `protobuf:"bytes,2,opt,name=decision_id,json=decisionId" json:"decision_id,omitempty"`
+	Provenance     *Provenance      `protobuf:"bytes,3,opt,name=provenance" json:"provenance,omitempty"`
+	Explanation    *structpb.Value  `protobuf:"bytes,4,opt,name=explanation" json:"explanation,omitempty"`
+	Metrics        *structpb.Struct `protobuf:"bytes,5,opt,name=metrics" json:"metrics,omitempty"`
+	Result         *structpb.Value  `protobuf:"bytes,6,opt,name=result" json:"result,omitempty"`
+	Warning        *Warning         `protobuf:"bytes,7,opt,name=warning" json:"warning,omitempty"`
+	Error          *Error           `protobuf:"bytes,8,opt,name=error" json:"error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchDataResponseItem) Reset() {
+	*x = BatchDataResponseItem{}
+	mi := &file_v1_server_server_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDataResponseItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDataResponseItem) ProtoMessage() {}
+
+func (x *BatchDataResponseItem) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDataResponseItem.ProtoReflect.Descriptor instead.
+func (*BatchDataResponseItem) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchDataResponseItem) GetHttpStatusCode() int32 {
+	if x != nil && x.HttpStatusCode != nil {
+		return *x.HttpStatusCode
+	}
+	return 0
+}
+
+func (x *BatchDataResponseItem) GetDecisionId() string {
+	if x != nil && x.DecisionId != nil {
+		return *x.DecisionId
+	}
+	return ""
+}
+
+func (x *BatchDataResponseItem) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *BatchDataResponseItem) GetExplanation() *structpb.Value {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+func (x *BatchDataResponseItem) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *BatchDataResponseItem) GetResult() *structpb.Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchDataResponseItem) GetWarning() *Warning {
+	if x != nil {
+		return x.Warning
+	}
+	return nil
+}
+
+func (x *BatchDataResponseItem) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// CompileRequest mirrors the request body of `POST /v1/compile`, together
+// with its query parameters and `Accept` header.
+type CompileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slash-separated path of a filter rule under `data`. If set, the request
+	// is served like `POST /v1/compile/{path}`.
+	Path     *string         `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	Query    *string         `protobuf:"bytes,2,opt,name=query" json:"query,omitempty"`
+	Input    *structpb.Value `protobuf:"bytes,3,opt,name=input" json:"input,omitempty"`
+	Unknowns []string        `protobuf:"bytes,4,rep,name=unknowns" json:"unknowns,omitempty"`
+	// The `options` object of the request body, e.g. `targetDialects`.
+	Options *structpb.Struct `protobuf:"bytes,5,opt,name=options" json:"options,omitempty"`
+	// Media type of the result, like the `Accept` header, e.g.
+	// `application/vnd.opa.ucast.prisma+json`.
+	Target        *string `protobuf:"bytes,6,opt,name=target" json:"target,omitempty"`
+	Explain       *string `protobuf:"bytes,7,opt,name=explain" json:"explain,omitempty"`
+	Metrics       *bool   `protobuf:"varint,8,opt,name=metrics" json:"metrics,omitempty"`
+	Instrument    *bool   `protobuf:"varint,9,opt,name=instrument" json:"instrument,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompileRequest) Reset() {
+	*x = CompileRequest{}
+	mi := &file_v1_server_server_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompileRequest) ProtoMessage() {}
+
+func (x *CompileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompileRequest.ProtoReflect.Descriptor instead.
+func (*CompileRequest) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CompileRequest) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetQuery() string {
+	if x != nil && x.Query != nil {
+		return *x.Query
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetInput() *structpb.Value {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetUnknowns() []string {
+	if x != nil {
+		return x.Unknowns
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetOptions() *structpb.Struct {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetTarget() string {
+	if x != nil && x.Target != nil {
+		return *x.Target
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetExplain() string {
+	if x != nil && x.Explain != nil {
+		return *x.Explain
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetMetrics() bool {
+	if x != nil && x.Metrics != nil {
+		return *x.Metrics
+	}
+	return false
+}
+
+func (x *CompileRequest) GetInstrument() bool {
+	if x != nil && x.Instrument != nil {
+		return *x.Instrument
+	}
+	return false
+}
+
+// CompileResponse mirrors `types.CompileResponseV1` in
+// v1/server/types/types.go.
+type CompileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *structpb.Value        `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
+	Explanation   *structpb.Value        `protobuf:"bytes,2,opt,name=explanation" json:"explanation,omitempty"`
+	Metrics       *structpb.Struct       `protobuf:"bytes,3,opt,name=metrics" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompileResponse) Reset() {
+	*x = CompileResponse{}
+	mi := &file_v1_server_server_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompileResponse) ProtoMessage() {}
+
+func (x *CompileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompileResponse.ProtoReflect.Descriptor instead.
+func (*CompileResponse) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CompileResponse) GetResult() *structpb.Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetExplanation() *structpb.Value {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+func (x *CompileResponse) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+// HealthRequest mirrors the query parameters of `GET /health`.
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundles       *bool                  `protobuf:"varint,1,opt,name=bundles" json:"bundles,omitempty"`
+	Plugins       *bool                  `protobuf:"varint,2,opt,name=plugins" json:"plugins,omitempty"`
+	ExcludePlugin []string               `protobuf:"bytes,3,rep,name=exclude_plugin,json=excludePlugin" json:"exclude_plugin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_v1_server_server_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HealthRequest) GetBundles() bool {
+	if x != nil && x.Bundles != nil {
+		return *x.Bundles
+	}
+	return false
+}
+
+func (x *HealthRequest) GetPlugins() bool {
+	if x != nil && x.Plugins != nil {
+		return *x.Plugins
+	}
+	return false
+}
+
+func (x *HealthRequest) GetExcludePlugin() []string {
+	if x != nil {
+		return x.ExcludePlugin
+	}
+	return nil
+}
+
+// HealthResponse is empty: a healthy OPA answers Check successfully.
+type HealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_v1_server_server_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{8}
+}
+
+// StatusRequest is empty: the Status API has no parameters.
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_v1_server_server_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{9}
+}
+
+// StatusResponse mirrors `types.StatusResponseV1` in
+// v1/server/types/types.go.
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *structpb.Value        `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_v1_server_server_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StatusResponse) GetResult() *structpb.Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Provenance mirrors `types.ProvenanceV1` in v1/server/types/types.go.
+type Provenance struct {
+	state          protoimpl.MessageState       `protogen:"open.v1"`
+	Version        *string                      `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
+	BuildCommit    *string                      `protobuf:"bytes,2,opt,name=build_commit,json=buildCommit" json:"build_commit,omitempty"`
+	BuildTimestamp *string                      `protobuf:"bytes,3,opt,name=build_timestamp,json=buildTimestamp" json:"build_timestamp,omitempty"`
+	BuildHostname  *string                      `protobuf:"bytes,4,opt,name=build_hostname,json=buildHostname" json:"build_hostname,omitempty"`
+	Bundles        map[string]*ProvenanceBundle `protobuf:"bytes,5,rep,name=bundles" json:"bundles,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	mi := &file_v1_server_server_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Provenance) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
+func (x *Provenance) GetBuildCommit() string {
+	if x != nil && x.BuildCommit != nil {
+		return *x.BuildCommit
+	}
+	return ""
+}
+
+func (x *Provenance) GetBuildTimestamp() string {
+	if x != nil && x.BuildTimestamp != nil {
+		return *x.BuildTimestamp
+	}
+	return ""
+}
+
+func (x *Provenance) GetBuildHostname() string {
+	if x != nil && x.BuildHostname != nil {
+		return *x.BuildHostname
+	}
+	return ""
+}
+
+func (x *Provenance) GetBundles() map[string]*ProvenanceBundle {
+	if x != nil {
+		return x.Bundles
+	}
+	return nil
+}
+
+// ProvenanceBundle mirrors `types.ProvenanceBundleV1` in
+// v1/server/types/types.go.
+type ProvenanceBundle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      *string                `protobuf:"bytes,1,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProvenanceBundle) Reset() {
+	*x = ProvenanceBundle{}
+	mi := &file_v1_server_server_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProvenanceBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProvenanceBundle) ProtoMessage() {}
+
+func (x *ProvenanceBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProvenanceBundle.ProtoReflect.Descriptor instead.
+func (*ProvenanceBundle) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ProvenanceBundle) GetRevision() string {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return ""
+}
+
+// Warning mirrors `types.Warning` in v1/server/types/types.go.
+type Warning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_v1_server_server_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Warning) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ""
+}
+
+func (x *Warning) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+// Error mirrors `types.ErrorV1` in v1/server/types/types.go.
+type Error struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	Message *string                `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	// Detailed errors, e.g. the compilation errors of a policy.
+	Errors        []*structpb.Struct `protobuf:"bytes,3,rep,name=errors" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_v1_server_server_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_server_server_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_v1_server_server_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetErrors() []*structpb.Struct {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+var File_v1_server_server_proto protoreflect.FileDescriptor
+
+const file_v1_server_server_proto_rawDesc = "" +
+	"\n" +
+	"\x16v1/server/server.proto\x12\ropa.server.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xac\x02\n" +
+	"\vDataRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12,\n" +
+	"\x05input\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05input\x123\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x1e\n" +
+	"\n" +
+	"provenance\x18\x04 \x01(\bR\n" +
+	"provenance\x12\x18\n" +
+	"\aexplain\x18\x05 \x01(\tR\aexplain\x12\x18\n" +
+	"\ametrics\x18\x06 \x01(\bR\ametrics\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\a \x01(\bR\n" +
+	"instrument\x122\n" +
+	"\x15strict_builtin_errors\x18\b \x01(\bR\x13strictBuiltinErrors\"\x9a\x03\n" +
+	"\fDataResponse\x12\x1f\n" +
+	"\vdecision_id\x18\x01 \x01(\tR\n" +
+	"decisionId\x12.\n" +
+	"\x06result\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x06result\x129\n" +
+	"\n" +
+	"provenance\x18\x03 \x01(\v2\x19.opa.server.v1.ProvenanceR\n" +
+	"provenance\x128\n" +
+	"\vexplanation\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\vexplanation\x121\n" +
+	"\ametrics\x18\x05 \x01(\v2\x17.google.protobuf.StructR\ametrics\x120\n" +
+	"\awarning\x18\x06 \x01(\v2\x16.opa.server.v1.WarningR\awarning\x123\n" +
+	"\bmetadata\x18\a \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12*\n" +
+	"\x05error\x18\b \x01(\v2\x14.opa.server.v1.ErrorR\x05error\"\xe6\x02\n" +
+	"\x10BatchDataRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12C\n" +
+	"\x06inputs\x18\x02 \x03(\v2+.opa.server.v1.BatchDataRequest.InputsEntryR\x06inputs\x12\x1e\n" +
+	"\n" +
+	"provenance\x18\x03 \x01(\bR\n" +
+	"provenance\x12\x18\n" +
+	"\aexplain\x18\x04 \x01(\tR\aexplain\x12\x18\n" +
+	"\ametrics\x18\x05 \x01(\bR\ametrics\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\x06 \x01(\bR\n" +
+	"instrument\x122\n" +
+	"\x15strict_builtin_errors\x18\a \x01(\bR\x13strictBuiltinErrors\x1aQ\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\"\xa5\x02\n" +
+	"\x11BatchDataResponse\x12*\n" +
+	"\x11batch_decision_id\x18\x01 \x01(\tR\x0fbatchDecisionId\x121\n" +
+	"\ametrics\x18\x02 \x01(\v2\x17.google.protobuf.StructR\ametrics\x12M\n" +
+	"\tresponses\x18\x03 \x03(\v2/.opa.server.v1.BatchDataResponse.ResponsesEntryR\tresponses\x1ab\n" +
+	"\x0eResponsesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12:\n" +
+	"\x05value\x18\x02 \x01(\v2$.opa.server.v1.BatchDataResponseItemR\x05value:\x028\x01\"\x98\x03\n" +
+	"\x15BatchDataResponseItem\x12(\n" +
+	"\x10http_status_code\x18\x01 \x01(\x05R\x0ehttpStatusCode\x12\x1f\n" +
+	"\vdecision_id\x18\x02 \x01(\tR\n" +
+	"decisionId\x129\n" +
+	"\n" +
+	"provenance\x18\x03 \x01(\v2\x19.opa.server.v1.ProvenanceR\n" +
+	"provenance\x128\n" +
+	"\vexplanation\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\vexplanation\x121\n" +
+	"\ametrics\x18\x05 \x01(\v2\x17.google.protobuf.StructR\ametrics\x12.\n" +
+	"\x06result\x18\x06 \x01(\v2\x16.google.protobuf.ValueR\x06result\x120\n" +
+	"\awarning\x18\a \x01(\v2\x16.opa.server.v1.WarningR\awarning\x12*\n" +
+	"\x05error\x18\b \x01(\v2\x14.opa.server.v1.ErrorR\x05error\"\xa3\x02\n" +
+	"\x0eCompileRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12,\n" +
+	"\x05input\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x05input\x12\x1a\n" +
+	"\bunknowns\x18\x04 \x03(\tR\bunknowns\x121\n" +
+	"\aoptions\x18\x05 \x01(\v2\x17.google.protobuf.StructR\aoptions\x12\x16\n" +
+	"\x06target\x18\x06 \x01(\tR\x06target\x12\x18\n" +
+	"\aexplain\x18\a \x01(\tR\aexplain\x12\x18\n" +
+	"\ametrics\x18\b \x01(\bR\ametrics\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\t \x01(\bR\n" +
+	"instrument\"\xae\x01\n" +
+	"\x0fCompileResponse\x12.\n" +
+	"\x06result\x18\x01 \x01(\v2\x16.google.protobuf.ValueR\x06result\x128\n" +
+	"\vexplanation\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\vexplanation\x121\n" +
+	"\ametrics\x18\x03 \x01(\v2\x17.google.protobuf.StructR\ametrics\"j\n" +
+	"\rHealthRequest\x12\x18\n" +
+	"\abundles\x18\x01 \x01(\bR\abundles\x12\x18\n" +
+	"\aplugins\x18\x02 \x01(\bR\aplugins\x12%\n" +
+	"\x0eexclude_plugin\x18\x03 \x03(\tR\rexcludePlugin\"\x10\n" +
+	"\x0eHealthResponse\"\x0f\n" +
+	"\rStatusRequest\"@\n" +
+	"\x0eStatusResponse\x12.\n" +
+	"\x06result\x18\x01 \x01(\v2\x16.google.protobuf.ValueR\x06result\"\xb8\x02\n" +
+	"\n" +
+	"Provenance\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12!\n" +
+	"\fbuild_commit\x18\x02 \x01(\tR\vbuildCommit\x12'\n" +
+	"\x0fbuild_timestamp\x18\x03 \x01(\tR\x0ebuildTimestamp\x12%\n" +
+	"\x0ebuild_hostname\x18\x04 \x01(\tR\rbuildHostname\x12@\n" +
+	"\abundles\x18\x05 \x03(\v2&.opa.server.v1.Provenance.BundlesEntryR\abundles\x1a[\n" +
+	"\fBundlesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x125\n" +
+	"\x05value\x18\x02 \x01(\v2\x1f.opa.server.v1.ProvenanceBundleR\x05value:\x028\x01\".\n" +
+	"\x10ProvenanceBundle\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision\"7\n" +
+	"\aWarning\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"f\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
+	"\x06errors\x18\x03 \x03(\v2\x17.google.protobuf.StructR\x06errors2\xef\x01\n" +
+	"\vDataService\x12B\n" +
+	"\aGetData\x12\x1a.opa.server.v1.DataRequest\x1a\x1b.opa.server.v1.DataResponse\x12Q\n" +
+	"\fBatchGetData\x12\x1f.opa.server.v1.BatchDataRequest\x1a .opa.server.v1.BatchDataResponse\x12I\n" +
+	"\n" +
+	"StreamData\x12\x1a.opa.server.v1.DataRequest\x1a\x1b.opa.server.v1.DataResponse(\x010\x012Z\n" +
+	"\x0eCompileService\x12H\n" +
+	"\aCompile\x12\x1d.opa.server.v1.CompileRequest\x1a\x1e.opa.server.v1.CompileResponse2U\n" +
+	"\rHealthService\x12D\n" +
+	"\x05Check\x12\x1c.opa.server.v1.HealthRequest\x1a\x1d.opa.server.v1.HealthResponse2Y\n" +
+	"\rStatusService\x12H\n" +
+	"\tGetStatus\x12\x1c.opa.server.v1.StatusRequest\x1a\x1d.opa.server.v1.StatusResponseB3P\x01Z/github.com/open-policy-agent/opa/v1/server/v1pbb\beditionsp\xe8\a"
+
+var (
+	file_v1_server_server_proto_rawDescOnce sync.Once
+	file_v1_server_server_proto_rawDescData []byte
+)
+
+func file_v1_server_server_proto_rawDescGZIP() []byte {
+	file_v1_server_server_proto_rawDescOnce.Do(func() {
+		file_v1_server_server_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_v1_server_server_proto_rawDesc), len(file_v1_server_server_proto_rawDesc)))
+	})
+	return file_v1_server_server_proto_rawDescData
+}
+
+var file_v1_server_server_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_v1_server_server_proto_goTypes = []any{
+	(*DataRequest)(nil),           // 0: opa.server.v1.DataRequest
+	(*DataResponse)(nil),          // 1: opa.server.v1.DataResponse
+	(*BatchDataRequest)(nil),      // 2: opa.server.v1.BatchDataRequest
+	(*BatchDataResponse)(nil),     // 3: opa.server.v1.BatchDataResponse
+	(*BatchDataResponseItem)(nil), // 4: opa.server.v1.BatchDataResponseItem
+	(*CompileRequest)(nil),        // 5: opa.server.v1.CompileRequest
+	(*CompileResponse)(nil),       // 6: opa.server.v1.CompileResponse
+	(*HealthRequest)(nil),         // 7: opa.server.v1.HealthRequest
+	(*HealthResponse)(nil),        // 8: opa.server.v1.HealthResponse
+	(*StatusRequest)(nil),         // 9: opa.server.v1.StatusRequest
+	(*StatusResponse)(nil),        // 10: opa.server.v1.StatusResponse
+	(*Provenance)(nil),            // 11: opa.server.v1.Provenance
+	(*ProvenanceBundle)(nil),      // 12: opa.server.v1.ProvenanceBundle
+	(*Warning)(nil),               // 13: opa.server.v1.Warning
+	(*Error)(nil),                 // 14: opa.server.v1.Error
+	nil,                           // 15: opa.server.v1.BatchDataRequest.InputsEntry
+	nil,                           // 16: opa.server.v1.BatchDataResponse.ResponsesEntry
+	nil,                           // 17: opa.server.v1.Provenance.BundlesEntry
+	(*structpb.Value)(nil),        // 18: google.protobuf.Value
+	(*structpb.Struct)(nil),       // 19: google.protobuf.Struct
+}
+var file_v1_server_server_proto_depIdxs = []int32{
+	18, // 0: opa.server.v1.DataRequest.input:type_name -> google.protobuf.Value
+	19, // 1: opa.server.v1.DataRequest.metadata:type_name -> google.protobuf.Struct
+	18, // 2: opa.server.v1.DataResponse.result:type_name -> google.protobuf.Value
+	11, // 3: opa.server.v1.DataResponse.provenance:type_name -> opa.server.v1.Provenance
+	18, // 4: opa.server.v1.DataResponse.explanation:type_name -> google.protobuf.Value
+	19, // 5: opa.server.v1.DataResponse.metrics:type_name -> google.protobuf.Struct
+	13, // 6: opa.server.v1.DataResponse.warning:type_name -> opa.server.v1.Warning
+	19, // 7: opa.server.v1.DataResponse.metadata:type_name -> google.protobuf.Struct
+	14, // 8: opa.server.v1.DataResponse.error:type_name -> opa.server.v1.Error
+	15, // 9: opa.server.v1.BatchDataRequest.inputs:type_name -> opa.server.v1.BatchDataRequest.InputsEntry
+	19, // 10: opa.server.v1.BatchDataResponse.metrics:type_name -> google.protobuf.Struct
+	16, // 11: opa.server.v1.BatchDataResponse.responses:type_name -> opa.server.v1.BatchDataResponse.ResponsesEntry
+	11, // 12: opa.server.v1.BatchDataResponseItem.provenance:type_name -> opa.server.v1.Provenance
+	18, // 13: opa.server.v1.BatchDataResponseItem.explanation:type_name -> google.protobuf.Value
+	19, // 14: opa.server.v1.BatchDataResponseItem.metrics:type_name -> google.protobuf.Struct
+	18, // 15: opa.server.v1.BatchDataResponseItem.result:type_name -> google.protobuf.Value
+	13, // 16: opa.server.v1.BatchDataResponseItem.warning:type_name -> opa.server.v1.Warning
+	14, // 17: opa.server.v1.BatchDataResponseItem.error:type_name -> opa.server.v1.Error
+	18, // 18: opa.server.v1.CompileRequest.input:type_name -> google.protobuf.Value
+	19, // 19: opa.server.v1.CompileRequest.options:type_name -> google.protobuf.Struct
+	18, // 20: opa.server.v1.CompileResponse.result:type_name -> google.protobuf.Value
+	18, // 21: opa.server.v1.CompileResponse.explanation:type_name -> google.protobuf.Value
+	19, // 22: opa.server.v1.CompileResponse.metrics:type_name -> google.protobuf.Struct
+	18, // 23: opa.server.v1.StatusResponse.result:type_name -> google.protobuf.Value
+	17, // 24: opa.server.v1.Provenance.bundles:type_name -> opa.server.v1.Provenance.BundlesEntry
+	19, // 25: opa.server.v1.Error.errors:type_name -> google.protobuf.Struct
+	18, // 26: opa.server.v1.BatchDataRequest.InputsEntry.value:type_name -> google.protobuf.Value
+	4,  // 27: opa.server.v1.BatchDataResponse.ResponsesEntry.value:type_name -> opa.server.v1.BatchDataResponseItem
+	12, // 28: opa.server.v1.Provenance.BundlesEntry.value:type_name -> opa.server.v1.ProvenanceBundle
+	0,  // 29: opa.server.v1.DataService.GetData:input_type -> opa.server.v1.DataRequest
+	2,  // 30: opa.server.v1.DataService.BatchGetData:input_type -> opa.server.v1.BatchDataRequest
+	0,  // 31: opa.server.v1.DataService.StreamData:input_type -> opa.server.v1.DataRequest
+	5,  // 32: opa.server.v1.CompileService.Compile:input_type -> opa.server.v1.CompileRequest
+	7,  // 33: opa.server.v1.HealthService.Check:input_type -> opa.server.v1.HealthRequest
+	9,  // 34: opa.server.v1.StatusService.GetStatus:input_type -> opa.server.v1.StatusRequest
+	1,  // 35: opa.server.v1.DataService.GetData:output_type -> opa.server.v1.DataResponse
+	3,  // 36: opa.server.v1.DataService.BatchGetData:output_type -> opa.server.v1.BatchDataResponse
+	1,  // 37: opa.server.v1.DataService.StreamData:output_type -> opa.server.v1.DataResponse
+	6,  // 38: opa.server.v1.CompileService.Compile:output_type -> opa.server.v1.CompileResponse
+	8,  // 39: opa.server.v1.HealthService.Check:output_type -> opa.server.v1.HealthResponse
+	10, // 40: opa.server.v1.StatusService.GetStatus:output_type -> opa.server.v1.StatusResponse
+	35, // [35:41] is the sub-list for method output_type
+	29, // [29:35] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
+}
+
+func init() { file_v1_server_server_proto_init() }
+func file_v1_server_server_proto_init() {
+	if File_v1_server_server_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_server_server_proto_rawDesc), len(file_v1_server_server_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_v1_server_server_proto_goTypes,
+		DependencyIndexes: file_v1_server_server_proto_depIdxs,
+		MessageInfos:      file_v1_server_server_proto_msgTypes,
+	}.Build()
+	File_v1_server_server_proto = out.File
+	file_v1_server_server_proto_goTypes = nil
+	file_v1_server_server_proto_depIdxs = nil
+}
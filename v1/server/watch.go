@@ -0,0 +1,427 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/server/writer"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	// watchBufferSize is the number of change events buffered for a watch
+	// stream. Streams that fall further behind are closed.
+	watchBufferSize = 64
+
+	watchEventSnapshot = "snapshot"
+	watchEventChange   = "change"
+	watchEventError    = "error"
+)
+
+// watchKeepAliveInterval is the interval at which comments are written to idle
+// watch streams so that proxies don't close them.
+var watchKeepAliveInterval = 30 * time.Second
+
+var bundlesStoragePath = storage.Path{"system", "bundles"}
+
+// v1DataWatch streams changes to the base documents under path as server-sent
+// events. The stream starts with a snapshot of the current value, followed by
+// one change event per committed transaction that modified the subtree.
+func (s *Server) v1DataWatch(w http.ResponseWriter, r *http.Request, urlPath string) {
+	ctx := r.Context()
+
+	path, ok := storage.ParsePathEscaped("/" + strings.Trim(urlPath, "/"))
+	if !ok {
+		writer.ErrorString(w, http.StatusBadRequest, types.CodeInvalidParameter, fmt.Errorf("invalid path: %v", urlPath))
+		return
+	}
+
+	watch := &dataWatch{
+		path:     path,
+		commits:  make(chan *watchCommit, watchBufferSize),
+		overflow: make(chan struct{}),
+	}
+
+	// Commits can't run their triggers while a read transaction is open, so
+	// adding the watch before the snapshot is read ensures that no change is
+	// missed in between.
+	txn, err := s.store.NewTransaction(ctx)
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+
+	s.addWatch(watch)
+	defer s.removeWatch(watch)
+
+	snapshot, err := s.watchSnapshot(ctx, txn, path)
+	s.store.Abort(ctx, txn)
+	if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams are long-lived, so the server's write timeout doesn't apply.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var id int
+	send := func(event string, v any) error {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event, bs); err != nil {
+			return err
+		}
+		id++
+		return rc.Flush()
+	}
+
+	sendCommit := func(commit *watchCommit) error {
+		evt, ok := s.watchEvent(path, commit)
+		if !ok {
+			return nil
+		}
+		return send(watchEventChange, evt)
+	}
+
+	if err := send(watchEventSnapshot, snapshot); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case commit := <-watch.commits:
+			if err := sendCommit(commit); err != nil {
+				return
+			}
+		case <-watch.overflow:
+			// Drain the events that were buffered before the stream fell behind,
+			// so that clients know which changes they've seen.
+			for len(watch.commits) > 0 {
+				if err := sendCommit(<-watch.commits); err != nil {
+					return
+				}
+			}
+			_ = send(watchEventError, types.NewErrorV1(types.CodeInternal, "watch stream fell behind, reconnect to resynchronize"))
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-s.watchDone():
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dataWatch is a watch stream registered with the server.
+type dataWatch struct {
+	path     storage.Path
+	commits  chan *watchCommit
+	overflow chan struct{}
+}
+
+// watchCommit holds the changes a committed transaction made to the watched
+// subtrees. The values are encoded once per commit, because the store may
+// modify the values of trigger events after the commit, and decoded by each
+// watch stream.
+type watchCommit struct {
+	data      []watchData
+	revisions map[string]string
+}
+
+type watchData struct {
+	path    storage.Path
+	value   json.RawMessage
+	removed bool
+}
+
+func (s *Server) addWatch(watch *dataWatch) {
+	s.watchMtx.Lock()
+	defer s.watchMtx.Unlock()
+
+	if s.watches == nil {
+		s.watches = map[*dataWatch]struct{}{}
+	}
+	s.watches[watch] = struct{}{}
+}
+
+func (s *Server) removeWatch(watch *dataWatch) {
+	s.watchMtx.Lock()
+	defer s.watchMtx.Unlock()
+
+	delete(s.watches, watch)
+}
+
+// notifyWatches is the storage trigger that passes committed changes on to the
+// watch streams. It only encodes the changes that overlap a watched subtree;
+// building the events is left to the streams.
+func (s *Server) notifyWatches(ctx context.Context, txn storage.Transaction, event storage.TriggerEvent) {
+	if len(event.Data) == 0 {
+		return
+	}
+
+	s.watchMtx.Lock()
+	defer s.watchMtx.Unlock()
+
+	if len(s.watches) == 0 {
+		return
+	}
+
+	commit := &watchCommit{}
+	var bundles []string
+
+	for _, de := range event.Data {
+		switch {
+		case de.Path.HasPrefix(bundlesStoragePath) && len(de.Path) > len(bundlesStoragePath):
+			bundles = append(bundles, de.Path[len(bundlesStoragePath)])
+		case bundlesStoragePath.HasPrefix(de.Path) && !de.Removed:
+			// stores may report nested writes as one write of an ancestor
+			if value, ok := lookupWatchValue(de.Data, bundlesStoragePath[len(de.Path):]); ok {
+				if obj, ok := value.(map[string]any); ok {
+					for name := range obj {
+						bundles = append(bundles, name)
+					}
+				}
+			}
+		}
+
+		if !s.watched(de.Path) {
+			continue
+		}
+
+		data := watchData{path: de.Path, removed: de.Removed}
+		if !de.Removed {
+			var err error
+			if data.value, err = encodeWatchValue(de.Data); err != nil {
+				s.manager.Logger().Error("Failed to encode data watch event on %v: %v.", de.Path, err)
+				continue
+			}
+		}
+		commit.data = append(commit.data, data)
+	}
+
+	if len(commit.data) == 0 {
+		return
+	}
+
+	if len(bundles) > 0 {
+		br, err := getRevisions(ctx, s.store, txn)
+		if err != nil {
+			s.manager.Logger().Error("Failed to read bundle revisions for data watches: %v.", err)
+		}
+		for _, name := range bundles {
+			if rev, ok := br.Revisions[name]; ok {
+				if commit.revisions == nil {
+					commit.revisions = map[string]string{}
+				}
+				commit.revisions[name] = rev
+			}
+		}
+	}
+
+	for watch := range s.watches {
+		select {
+		case watch.commits <- commit:
+		case <-watch.overflow:
+		default:
+			close(watch.overflow)
+		}
+	}
+}
+
+// watched returns true if a change at path overlaps any watched subtree. The
+// caller must hold s.watchMtx.
+func (s *Server) watched(path storage.Path) bool {
+	for watch := range s.watches {
+		if path.HasPrefix(watch.path) || watch.path.HasPrefix(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchSnapshot returns the event describing the current value at path.
+func (s *Server) watchSnapshot(ctx context.Context, txn storage.Transaction, path storage.Path) (types.DataWatchEventV1, error) {
+	br, err := getRevisions(ctx, s.store, txn)
+	if err != nil {
+		return types.DataWatchEventV1{}, err
+	}
+
+	change := types.DataWatchChangeV1{Path: path.String()}
+	value, err := s.store.Read(ctx, txn, path)
+	switch {
+	case storage.IsNotFound(err):
+		change.Removed = true
+	case err != nil:
+		return types.DataWatchEventV1{}, err
+	default:
+		value, err = watchValue(value)
+		if err != nil {
+			return types.DataWatchEventV1{}, err
+		}
+		change.Value = &value
+	}
+
+	evt := types.DataWatchEventV1{Changes: []types.DataWatchChangeV1{change}}
+	if len(br.Revisions) > 0 {
+		evt.Revisions = br.Revisions
+	}
+	return evt, nil
+}
+
+// watchEvent returns the changes the commit made to the subtree at path, and
+// the revisions of the bundles that were activated by the transaction. It
+// returns false if the subtree wasn't changed.
+func (s *Server) watchEvent(path storage.Path, commit *watchCommit) (types.DataWatchEventV1, bool) {
+	var evt types.DataWatchEventV1
+
+	for _, data := range commit.data {
+		switch {
+		case data.path.HasPrefix(path):
+			// change inside the subtree
+			change := types.DataWatchChangeV1{Path: data.path.String(), Removed: data.removed}
+			if !data.removed {
+				value, err := watchValue(data.value)
+				if err != nil {
+					s.manager.Logger().Error("Failed to convert data watch event on %v: %v.", path, err)
+					continue
+				}
+				change.Value = &value
+			}
+			evt.Changes = append(evt.Changes, change)
+
+		case path.HasPrefix(data.path):
+			// change above the subtree, which replaces the subtree
+			change := types.DataWatchChangeV1{Path: path.String(), Removed: true}
+			if !data.removed {
+				if value, ok := lookupWatchValue(data.value, path[len(data.path):]); ok {
+					value, err := watchValue(value)
+					if err != nil {
+						s.manager.Logger().Error("Failed to convert data watch event on %v: %v.", path, err)
+						continue
+					}
+					change.Value = &value
+					change.Removed = false
+				}
+			}
+			evt.Changes = append(evt.Changes, change)
+		}
+	}
+
+	if len(evt.Changes) == 0 {
+		return evt, false
+	}
+
+	evt.Revisions = commit.revisions
+	return evt, true
+}
+
+// encodeWatchValue encodes values from trigger events, which depend on the
+// store implementation, as JSON.
+func encodeWatchValue(value any) (json.RawMessage, error) {
+	switch v := value.(type) {
+	case json.RawMessage:
+		return v, nil
+	case []byte:
+		return v, nil
+	}
+	return json.Marshal(value)
+}
+
+// watchValue converts values from trigger events, which depend on the store
+// implementation, to JSON compatible Go values.
+func watchValue(value any) (any, error) {
+	switch v := value.(type) {
+	case json.RawMessage:
+		var result any
+		return result, util.UnmarshalJSON(v, &result)
+	case []byte:
+		var result any
+		return result, util.UnmarshalJSON(v, &result)
+	}
+	err := util.RoundTrip(&value)
+	return value, err
+}
+
+// lookupWatchValue returns the value at path inside a value from a trigger
+// event.
+func lookupWatchValue(value any, path storage.Path) (any, bool) {
+	for _, key := range path {
+		switch value.(type) {
+		case json.RawMessage, []byte:
+			var err error
+			if value, err = watchValue(value); err != nil {
+				return nil, false
+			}
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// watchDone returns a channel that is closed when the server shuts down, which
+// ends all watch streams.
+func (s *Server) watchDone() <-chan struct{} {
+	s.watchMtx.Lock()
+	defer s.watchMtx.Unlock()
+
+	if s.watchShutdown == nil {
+		s.watchShutdown = make(chan struct{})
+	}
+	return s.watchShutdown
+}
+
+func (s *Server) closeWatches() {
+	done := s.watchDone()
+
+	s.watchMtx.Lock()
+	defer s.watchMtx.Unlock()
+
+	select {
+	case <-done:
+	default:
+		close(s.watchShutdown)
+	}
+}
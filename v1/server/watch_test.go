@@ -0,0 +1,268 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type testWatchEvent struct {
+	id    string
+	event string
+	data  types.DataWatchEventV1
+}
+
+type testWatchStream struct {
+	t    *testing.T
+	body io.ReadCloser
+	r    *bufio.Reader
+}
+
+func newTestWatchStream(t *testing.T, f *fixture, path string) *testWatchStream {
+	t.Helper()
+
+	ts := httptest.NewServer(f.server.Handler)
+	t.Cleanup(ts.Close)
+
+	// The default transport asks for gzip compressed responses, which must
+	// not delay the events.
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK {
+		bs, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, bs)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected event stream, got %q", ct)
+	}
+
+	return &testWatchStream{t: t, body: resp.Body, r: bufio.NewReader(resp.Body)}
+}
+
+func (s *testWatchStream) next() testWatchEvent {
+	s.t.Helper()
+
+	var evt testWatchEvent
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			s.t.Fatalf("failed to read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if evt.event != "" {
+				return evt
+			}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			evt.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			evt.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &evt.data); err != nil {
+				s.t.Fatal(err)
+			}
+		}
+	}
+}
+
+func watchChange(path string, value any) types.DataWatchChangeV1 {
+	if value == nil {
+		return types.DataWatchChangeV1{Path: path, Removed: true}
+	}
+	return types.DataWatchChangeV1{Path: path, Value: &value}
+}
+
+func TestDataWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.NewFromObject(map[string]any{"a": map[string]any{"b": map[string]any{"c": 1.0}}})
+	f := newFixtureWithStore(t, store)
+
+	write := func(path string, value string) {
+		t.Helper()
+		if err := storage.WriteOne(ctx, store, storage.AddOp, storage.MustParsePath(path), util.MustUnmarshalJSON([]byte(value))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream := newTestWatchStream(t, f, "/v1/data/a/b?watch=true")
+
+	snapshot := stream.next()
+	if snapshot.event != "snapshot" || snapshot.id != "0" {
+		t.Fatalf("expected snapshot event, got %+v", snapshot)
+	}
+	if exp := []types.DataWatchChangeV1{watchChange("/a/b", map[string]any{"c": 1.0})}; !reflect.DeepEqual(exp, snapshot.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, snapshot.data.Changes)
+	}
+
+	// writes outside of the subtree are skipped
+	write("/x", `true`)
+
+	// writes inside the subtree
+	write("/a/b/d", `"foo"`)
+
+	evt := stream.next()
+	if evt.event != "change" || evt.id != "1" {
+		t.Fatalf("expected change event, got %+v", evt)
+	}
+	if exp := []types.DataWatchChangeV1{watchChange("/a/b/d", "foo")}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+
+	// writes above the subtree replace it
+	write("/a", `{"b": {"e": 2}}`)
+
+	evt = stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/a/b", map[string]any{"e": 2.0})}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+
+	write("/a", `{"f": 3}`)
+
+	evt = stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/a/b", nil)}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+
+	// changes made while activating bundles include their revisions
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		if err := store.Write(ctx, txn, storage.AddOp, storage.MustParsePath("/a/b"), map[string]any{"g": 4.0}); err != nil {
+			return err
+		}
+		return bundle.WriteManifestToStore(ctx, store, txn, "authz", bundle.Manifest{Revision: "rev1", Roots: &[]string{"a"}})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evt = stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/a/b", map[string]any{"g": 4.0})}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+	if exp := map[string]string{"authz": "rev1"}; !reflect.DeepEqual(exp, evt.data.Revisions) {
+		t.Fatalf("expected revisions %v, got %v", exp, evt.data.Revisions)
+	}
+
+	// streams end when the server shuts down
+	if err := f.server.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(stream.body); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataWatchSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.New()
+	f := newFixtureWithStore(t, store)
+
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return bundle.WriteManifestToStore(ctx, store, txn, "authz", bundle.Manifest{Revision: "rev1", Roots: &[]string{"a"}})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := newTestWatchStream(t, f, "/v1/data/missing?watch")
+
+	snapshot := stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/missing", nil)}; !reflect.DeepEqual(exp, snapshot.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, snapshot.data.Changes)
+	}
+	if exp := map[string]string{"authz": "rev1"}; !reflect.DeepEqual(exp, snapshot.data.Revisions) {
+		t.Fatalf("expected revisions %v, got %v", exp, snapshot.data.Revisions)
+	}
+}
+
+func TestDataWatchOverflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.New()
+	f := newFixtureWithStore(t, store)
+
+	stream := newTestWatchStream(t, f, "/v1/data?watch=true")
+	stream.next()
+
+	// the stream isn't read while these changes are made, so they overflow the buffer
+	for i := range watchBufferSize * 4 {
+		if err := storage.WriteOne(ctx, store, storage.AddOp, storage.MustParsePath("/x"), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for {
+		evt := stream.next()
+		if evt.event == "error" {
+			break
+		}
+		if evt.event != "change" {
+			t.Fatalf("expected change or error event, got %+v", evt)
+		}
+	}
+
+	if _, err := io.ReadAll(stream.body); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataWatchOpenWriteTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := inmem.New()
+	f := newFixtureWithStore(t, store)
+
+	txn := storage.NewTransactionOrDie(ctx, store, storage.WriteParams)
+
+	// watches don't need write transactions, so they don't wait for the open one
+	stream := newTestWatchStream(t, f, "/v1/data/x?watch=true")
+	stream.next()
+
+	if err := store.Write(ctx, txn, storage.AddOp, storage.MustParsePath("/x"), map[string]any{"y": 1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Commit(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+
+	evt := stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/x", map[string]any{"y": 1.0})}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+
+	// values are copied before later writes modify them in place
+	if err := storage.WriteOne(ctx, store, storage.AddOp, storage.MustParsePath("/x/y"), 2.0); err != nil {
+		t.Fatal(err)
+	}
+
+	evt = stream.next()
+	if exp := []types.DataWatchChangeV1{watchChange("/x/y", 2.0)}; !reflect.DeepEqual(exp, evt.data.Changes) {
+		t.Fatalf("expected changes %v, got %v", exp, evt.data.Changes)
+	}
+}
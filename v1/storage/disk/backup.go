@@ -0,0 +1,384 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package disk
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	"github.com/open-policy-agent/opa/v1/storage"
+)
+
+// backupVersion is the version of the backup format written by this OPA.
+const backupVersion = 1
+
+// Backup files are gzip-compressed streams of JSON entries: a header, one
+// entry per policy and per data key, and a footer with the number of entries
+// to detect truncated files. Data entries hold the logical storage path and
+// value of each key, so backups don't depend on the partitioning of the store
+// they were taken from.
+type backupEntry struct {
+	Type string `json:"type"`
+
+	// header
+	Version    int        `json:"version,omitempty"`
+	Created    *time.Time `json:"created,omitempty"`
+	Partitions []string   `json:"partitions,omitempty"`
+
+	// policy
+	ID  string `json:"id,omitempty"`
+	Raw []byte `json:"raw,omitempty"`
+
+	// data
+	Path  storage.Path    `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+
+	// footer
+	Policies *int `json:"policies,omitempty"`
+	Data     *int `json:"data,omitempty"`
+}
+
+const (
+	backupEntryHeader = "header"
+	backupEntryPolicy = "policy"
+	backupEntryData   = "data"
+	backupEntryFooter = "footer"
+)
+
+// BackupInfo describes a backup written by Store.Backup.
+type BackupInfo struct {
+	Version    int       `json:"version"`
+	Created    time.Time `json:"created"`
+	Partitions []string  `json:"partitions"` // partitions of the store the backup was taken from
+	Policies   []string  `json:"policies"`   // IDs of the policies in the backup
+	Roots      []string  `json:"roots"`      // top-level keys of the data in the backup
+	DataKeys   int       `json:"data_keys"`  // number of data entries in the backup
+	DataBytes  int       `json:"data_bytes"` // size of the JSON encoded data values in the backup
+}
+
+// Backup writes a consistent snapshot of the data and policies visible in txn
+// to w. The snapshot can be restored into another store with Restore, even
+// if that store is configured with different partitions.
+func (db *Store) Backup(ctx context.Context, txn storage.Transaction, w io.Writer) error {
+	underlying, err := db.underlying(txn)
+	if err != nil {
+		return err
+	}
+
+	var m metadata
+	if _, err := db.loadMetadata(underlying.underlying, &m); err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	enc := json.NewEncoder(gw)
+
+	created := time.Now().UTC()
+	header := backupEntry{Type: backupEntryHeader, Version: backupVersion, Created: &created}
+	for _, p := range m.Partitions {
+		header.Partitions = append(header.Partitions, "/"+strings.Join(p, "/"))
+	}
+	if err := enc.Encode(header); err != nil {
+		return wrapError(err)
+	}
+
+	var policies, data int
+
+	err = db.backupPrefix(ctx, underlying.underlying, db.pm.PolicyIDPrefix(), func(key, value []byte) error {
+		policies++
+		return enc.Encode(backupEntry{Type: backupEntryPolicy, ID: db.pm.PolicyKey2ID(key), Raw: value})
+	})
+	if err != nil {
+		return err
+	}
+
+	prefix, err := db.pm.DataPrefix2Key(storage.RootPath)
+	if err != nil {
+		return err
+	}
+
+	err = db.backupPrefix(ctx, underlying.underlying, prefix, func(key, value []byte) error {
+		path, err := db.pm.DataKey2Path(key)
+		if err != nil {
+			return err
+		}
+		data++
+		return enc.Encode(backupEntry{Type: backupEntryData, Path: path, Value: value})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := enc.Encode(backupEntry{Type: backupEntryFooter, Policies: &policies, Data: &data}); err != nil {
+		return wrapError(err)
+	}
+
+	return wrapError(gw.Close())
+}
+
+func (*Store) backupPrefix(ctx context.Context, txn *badger.Txn, prefix []byte, f func(key, value []byte) error) error {
+	it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		value, err := it.Item().ValueCopy(nil)
+		if err != nil {
+			return wrapError(err)
+		}
+
+		if err := f(it.Item().KeyCopy(nil), value); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	return nil
+}
+
+// Restore replaces the data and policies in the store with the snapshot read
+// from r, which must have been written by Backup. This method must be called
+// with a write transaction; like Truncate, the changes are undone if the
+// transaction is aborted.
+func (db *Store) Restore(ctx context.Context, txn storage.Transaction, r io.Reader) error {
+	br, err := newBackupReader(r)
+	if err != nil {
+		return err
+	}
+
+	ids, err := db.ListPolicies(ctx, txn)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := db.DeletePolicy(ctx, txn, id); err != nil {
+			return err
+		}
+	}
+
+	underlying, err := db.underlying(txn)
+	if err != nil {
+		return err
+	}
+
+	it := &restoreIterator{br: br, partitions: db.partitions}
+	params := storage.TransactionParams{Write: true, Context: underlying.event.Context, RootOverwrite: true}
+	return db.Truncate(ctx, txn, params, it)
+}
+
+// restoreIterator turns backup entries into updates for Truncate. Data
+// entries below a key of this store, i.e. below a path that isn't partitioned
+// any further, are merged and written as one value once all entries are read.
+type restoreIterator struct {
+	br         *backupReader
+	partitions *partitionTrie
+	merged     map[string]any
+	pending    []*storage.Update
+}
+
+func (it *restoreIterator) Next() (*storage.Update, error) {
+	for {
+		if len(it.pending) > 0 {
+			u := it.pending[0]
+			it.pending = it.pending[1:]
+			return u, nil
+		}
+
+		if it.br == nil {
+			return nil, io.EOF
+		}
+
+		entry, err := it.br.Next()
+		if err == io.EOF {
+			it.br = nil
+			if err := it.flush(storage.RootPath, it.merged); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch entry.Type {
+		case backupEntryPolicy:
+			return &storage.Update{Path: storage.Path{entry.ID}, Value: entry.Raw, IsPolicy: true}, nil
+		case backupEntryData:
+			if i, node := it.partitions.Find(entry.Path); node != nil || i == len(entry.Path) {
+				return &storage.Update{Path: entry.Path, Value: entry.Value}, nil
+			}
+			if err := it.merge(entry.Path, entry.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (it *restoreIterator) merge(path storage.Path, value json.RawMessage) error {
+	if it.merged == nil {
+		it.merged = map[string]any{}
+	}
+
+	node := it.merged
+	for i, k := range path[:len(path)-1] {
+		child, ok := node[k]
+		if !ok {
+			child = map[string]any{}
+			node[k] = child
+		}
+		obj, ok := child.(map[string]any)
+		if !ok {
+			return &storage.Error{Code: storage.InternalErr, Message: fmt.Sprintf("invalid backup: conflicting values at %v", path[:i+1])}
+		}
+		node = obj
+	}
+	node[path[len(path)-1]] = value
+
+	return nil
+}
+
+// flush queues the merged values, one update per key of this store.
+func (it *restoreIterator) flush(path storage.Path, value any) error {
+	if _, node := it.partitions.Find(path); node != nil {
+		obj, _ := value.(map[string]any)
+		for _, k := range sortedKeys(obj) {
+			if err := it.flush(append(slices.Clone(path), k), obj[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return wrapError(err)
+	}
+	it.pending = append(it.pending, &storage.Update{Path: path, Value: bs})
+	return nil
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// InspectBackup reads the backup from r, which must have been written by
+// Store.Backup, and returns a description of its contents.
+func InspectBackup(r io.Reader) (*BackupInfo, error) {
+	br, err := newBackupReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info := BackupInfo{
+		Version:    br.header.Version,
+		Created:    *br.header.Created,
+		Partitions: br.header.Partitions,
+		Policies:   []string{},
+		Roots:      []string{},
+	}
+
+	for {
+		entry, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch entry.Type {
+		case backupEntryPolicy:
+			info.Policies = append(info.Policies, entry.ID)
+		case backupEntryData:
+			info.DataKeys++
+			info.DataBytes += len(entry.Value)
+			if root := entry.Path[0]; !slices.Contains(info.Roots, root) {
+				info.Roots = append(info.Roots, root)
+			}
+		}
+	}
+
+	slices.Sort(info.Roots)
+	return &info, nil
+}
+
+type backupReader struct {
+	dec      *json.Decoder
+	header   backupEntry
+	policies int
+	data     int
+	done     bool
+}
+
+func newBackupReader(r io.Reader) (*backupReader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+
+	br := &backupReader{dec: json.NewDecoder(gr)}
+	if err := br.dec.Decode(&br.header); err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	if br.header.Type != backupEntryHeader || br.header.Created == nil {
+		return nil, errors.New("invalid backup: missing header")
+	}
+	if br.header.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", br.header.Version)
+	}
+	return br, nil
+}
+
+// Next returns the next policy or data entry, or io.EOF after the footer was
+// read and checked.
+func (br *backupReader) Next() (*backupEntry, error) {
+	if br.done {
+		return nil, io.EOF
+	}
+
+	var entry backupEntry
+	if err := br.dec.Decode(&entry); err != nil {
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("invalid backup: missing footer, the backup may be truncated")
+		}
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+
+	switch entry.Type {
+	case backupEntryPolicy:
+		br.policies++
+	case backupEntryData:
+		if len(entry.Path) == 0 {
+			return nil, errors.New("invalid backup: data entry without path")
+		}
+		br.data++
+	case backupEntryFooter:
+		if entry.Policies == nil || entry.Data == nil || *entry.Policies != br.policies || *entry.Data != br.data {
+			return nil, errors.New("invalid backup: footer doesn't match the number of entries")
+		}
+		br.done = true
+		return nil, io.EOF
+	default:
+		return nil, fmt.Errorf("invalid backup: unknown entry type %q", entry.Type)
+	}
+
+	return &entry, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package disk
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const testBackupData = `{
+	"foo": {"a": {"x": 1, "y": [1, 2]}, "b": {"x": 2}},
+	"bar": {"baz": {"qux": "quux"}, "corge": true},
+	"grault": null
+}`
+
+func newTestBackupStore(t *testing.T, partitions ...string) *Store {
+	t.Helper()
+
+	opts := Options{Dir: t.TempDir()}
+	for _, p := range partitions {
+		opts.Partitions = append(opts.Partitions, storage.MustParsePath(p))
+	}
+
+	s, err := New(t.Context(), logging.NewNoOpLogger(), nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close(context.Background()) })
+	return s
+}
+
+func writeTestBackup(t *testing.T, partitions ...string) []byte {
+	t.Helper()
+	ctx := t.Context()
+
+	s := newTestBackupStore(t, partitions...)
+
+	err := storage.Txn(ctx, s, storage.WriteParams, func(txn storage.Transaction) error {
+		if err := s.Write(ctx, txn, storage.AddOp, storage.RootPath, util.MustUnmarshalJSON([]byte(testBackupData))); err != nil {
+			return err
+		}
+		if err := s.UpsertPolicy(ctx, txn, "authz/policy.rego", []byte("package authz")); err != nil {
+			return err
+		}
+		return s.UpsertPolicy(ctx, txn, "other.rego", []byte("package other"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = storage.Txn(ctx, s, storage.TransactionParams{}, func(txn storage.Transaction) error {
+		return s.Backup(ctx, txn, &buf)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func assertTestBackupContents(t *testing.T, s *Store, expData any, expPolicies map[string]string) {
+	t.Helper()
+	ctx := t.Context()
+
+	txn := storage.NewTransactionOrDie(ctx, s)
+	defer s.Abort(ctx, txn)
+
+	data, err := s.Read(ctx, txn, storage.RootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expData, data) {
+		t.Fatalf("expected data %v, got %v", expData, data)
+	}
+
+	ids, err := s.ListPolicies(ctx, txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies := make(map[string]string, len(ids))
+	for _, id := range ids {
+		bs, err := s.GetPolicy(ctx, txn, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policies[id] = string(bs)
+	}
+	if !reflect.DeepEqual(expPolicies, policies) {
+		t.Fatalf("expected policies %v, got %v", expPolicies, policies)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	expData := util.MustUnmarshalJSON([]byte(testBackupData))
+	expPolicies := map[string]string{"authz/policy.rego": "package authz", "other.rego": "package other"}
+
+	tests := []struct {
+		note       string
+		source     []string
+		target     []string
+		targetKeys int
+	}{
+		{
+			note:       "same partitions",
+			source:     []string{"/foo/*", "/bar"},
+			target:     []string{"/foo/*", "/bar"},
+			targetKeys: 6,
+		},
+		{
+			note:       "unpartitioned target",
+			source:     []string{"/foo/*", "/bar"},
+			targetKeys: 3,
+		},
+		{
+			note:       "more partitions in target",
+			target:     []string{"/foo/*", "/bar/baz"},
+			targetKeys: 6,
+		},
+		{
+			note:       "fewer partitions in target",
+			source:     []string{"/foo/*", "/bar/baz"},
+			target:     []string{"/bar"},
+			targetKeys: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			backup := writeTestBackup(t, tc.source...)
+
+			s := newTestBackupStore(t, tc.target...)
+
+			// existing data and policies are replaced
+			err := storage.Txn(ctx, s, storage.WriteParams, func(txn storage.Transaction) error {
+				if err := s.Write(ctx, txn, storage.AddOp, storage.MustParsePath("/stale"), true); err != nil {
+					return err
+				}
+				return s.UpsertPolicy(ctx, txn, "stale.rego", []byte("package stale"))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = storage.Txn(ctx, s, storage.WriteParams, func(txn storage.Transaction) error {
+				return s.Restore(ctx, txn, bytes.NewReader(backup))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertTestBackupContents(t, s, expData, expPolicies)
+
+			// backups of the restored store are split into the target's keys
+			var buf bytes.Buffer
+			err = storage.Txn(ctx, s, storage.TransactionParams{}, func(txn storage.Transaction) error {
+				return s.Backup(ctx, txn, &buf)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := InspectBackup(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.DataKeys != tc.targetKeys {
+				t.Fatalf("expected %d data keys, got %d", tc.targetKeys, info.DataKeys)
+			}
+		})
+	}
+}
+
+func TestRestoreInvalidBackup(t *testing.T) {
+	t.Parallel()
+
+	backup := writeTestBackup(t, "/foo/*")
+
+	tests := []struct {
+		note   string
+		backup []byte
+		err    string
+	}{
+		{
+			note:   "not a backup",
+			backup: []byte(`{"foo": "bar"}`),
+			err:    "invalid backup",
+		},
+		{
+			note:   "truncated",
+			backup: backup[:len(backup)-20],
+			err:    "invalid backup",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			s := newTestBackupStore(t)
+			err := storage.Txn(ctx, s, storage.WriteParams, func(txn storage.Transaction) error {
+				if err := s.Write(ctx, txn, storage.AddOp, storage.MustParsePath("/existing"), true); err != nil {
+					return err
+				}
+				return s.UpsertPolicy(ctx, txn, "existing.rego", []byte("package existing"))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = storage.Txn(ctx, s, storage.WriteParams, func(txn storage.Transaction) error {
+				return s.Restore(ctx, txn, bytes.NewReader(tc.backup))
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+
+			// the store is left untouched
+			assertTestBackupContents(t, s, map[string]any{"existing": true}, map[string]string{"existing.rego": "package existing"})
+		})
+	}
+}
+
+func TestInspectBackup(t *testing.T) {
+	t.Parallel()
+
+	backup := writeTestBackup(t, "/foo/*", "/bar")
+
+	info, err := InspectBackup(bytes.NewReader(backup))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Version != backupVersion || info.Created.IsZero() {
+		t.Fatalf("unexpected header: %+v", info)
+	}
+	if !slices.Equal(info.Partitions, []string{"/bar", "/foo/*", "/system/*"}) {
+		t.Fatalf("unexpected partitions: %v", info.Partitions)
+	}
+	if !slices.Equal(info.Policies, []string{"authz/policy.rego", "other.rego"}) {
+		t.Fatalf("unexpected policies: %v", info.Policies)
+	}
+	if !slices.Equal(info.Roots, []string{"bar", "foo", "grault"}) || info.DataKeys != 6 || info.DataBytes == 0 {
+		t.Fatalf("unexpected data: %+v", info)
+	}
+
+	if _, err := InspectBackup(bytes.NewReader(backup[:len(backup)/2])); err == nil {
+		t.Fatal("expected error for truncated backup")
+	}
+}
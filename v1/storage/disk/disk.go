@@ -272,6 +272,14 @@ func (db *Store) Truncate(ctx context.Context, txn storage.Transaction, params s
 		return wrapError(err)
 	}
 
+	// Open write txn on the existing store in-case there are more write operations.
+	// The caller will either commit or abort this transaction; aborting it also
+	// restores the backup if truncating the store fails.
+	defer func() {
+		uTxn.stale = false
+		uTxn.underlying = db.db.NewTransaction(true)
+	}()
+
 	// write new bundle policy and data into the existing DB
 	underlying := db.db.NewTransaction(true)
 	xid := db.xid.Add(uint64(1))
@@ -370,16 +378,7 @@ func (db *Store) Truncate(ctx context.Context, txn storage.Transaction, params s
 
 	// commit active transaction on existing store
 	_, err = underlyingTxn.Commit(ctx)
-	if err != nil {
-		return wrapError(err)
-	}
-
-	// Open write txn on the existing store in-case there are more write operations.
-	// The caller will either commit or abort this transaction
-	uTxn.stale = false
-	uTxn.underlying = db.db.NewTransaction(true)
-
-	return nil
+	return wrapError(err)
 }
 
 func (db *Store) doTruncateData(ctx context.Context, underlying *transaction, badgerdb *badger.DB,
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/internal/sarif"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/cover"
 	"github.com/open-policy-agent/opa/v1/topdown"
@@ -287,6 +288,50 @@ func (r JSONReporter) Report(ch chan *Result) error {
 	return err
 }
 
+// SARIFReporter reports failed tests as a SARIF log, for code scanning tools.
+// Failed tests are reported for the rego_test_failure rule, and tests that
+// encountered an error for a rule named by the error's code.
+type SARIFReporter struct {
+	Output io.Writer
+}
+
+// Report prints the test report to the reporter's output.
+func (r SARIFReporter) Report(ch chan *Result) error {
+	log := sarif.New("opa test")
+	run := log.Run()
+
+	for tr := range ch {
+		name := tr.Package + "." + tr.Name
+		switch {
+		case tr.Error != nil:
+			code, loc := sarif.TestError, tr.Location
+			var astErr *ast.Error
+			var topdownErr *topdown.Error
+			switch {
+			case errors.As(tr.Error, &topdownErr):
+				code = topdownErr.Code
+				if topdownErr.Location != nil {
+					loc = topdownErr.Location
+				}
+			case errors.As(tr.Error, &astErr):
+				code = astErr.Code
+				if astErr.Location != nil {
+					loc = astErr.Location
+				}
+			}
+			run.AddCodeResult(code, sarif.LevelError, fmt.Sprintf("%s: %v", name, tr.Error), loc)
+		case tr.Fail:
+			loc := tr.Location
+			if tr.FailedAt != nil && tr.FailedAt.Location != nil {
+				loc = tr.FailedAt.Location
+			}
+			run.AddCodeResult(sarif.TestFailure, sarif.LevelError, name+" failed", loc)
+		}
+	}
+
+	return log.Write(r.Output)
+}
+
 // JSONCoverageReporter reports coverage as a JSON structure.
 type JSONCoverageReporter struct {
 	Cover     *cover.Cover
@@ -1371,3 +1371,82 @@ func resultsChan(ts []*Result) chan *Result {
 	}()
 	return ch
 }
+
+func TestSARIFReporter(t *testing.T) {
+	t.Parallel()
+
+	failedAt := ast.MustParseExpr("x == 2")
+	failedAt.Location = &ast.Location{File: "policy_test.rego", Row: 5, Col: 2}
+
+	ch := make(chan *Result, 4)
+	ch <- &Result{Package: "data.foo", Name: "test_pass", Location: &ast.Location{File: "policy_test.rego", Row: 1, Col: 1}}
+	ch <- &Result{Package: "data.foo", Name: "test_fail", Fail: true, FailedAt: failedAt, Location: &ast.Location{File: "policy_test.rego", Row: 4, Col: 1}}
+	ch <- &Result{Package: "data.foo", Name: "test_error", Location: &ast.Location{File: "policy_test.rego", Row: 7, Col: 1}, Error: &topdown.Error{
+		Code:     topdown.ConflictErr,
+		Message:  "functions must not produce multiple outputs for same inputs",
+		Location: &ast.Location{File: "policy.rego", Row: 3, Col: 1},
+	}}
+	ch <- &Result{Package: "data.foo", Name: "test_skip", Skip: true}
+	close(ch)
+
+	var buf bytes.Buffer
+	if err := (SARIFReporter{Output: &buf}).Report(ch); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %s", buf.String())
+	}
+
+	exp := []struct {
+		rule, message, file string
+		line                int
+	}{
+		{"rego_test_failure", "data.foo.test_fail failed", "policy_test.rego", 5},
+		{topdown.ConflictErr, "data.foo.test_error: policy.rego:3: eval_conflict_error: functions must not produce multiple outputs for same inputs", "policy.rego", 3},
+	}
+	for i, e := range exp {
+		r := results[i]
+		loc := r.Locations[0].PhysicalLocation
+		if r.RuleID != e.rule || r.Level != "error" || r.Message.Text != e.message || loc.ArtifactLocation.URI != e.file || loc.Region.StartLine != e.line {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+		if log.Runs[0].Tool.Driver.Rules[r.RuleIndex].ID != e.rule {
+			t.Fatalf("unexpected rule index for result %d", i)
+		}
+	}
+}
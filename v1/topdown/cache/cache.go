@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"reflect"
 	"sync"
 	"time"
 
@@ -116,10 +117,14 @@ func (i *InterQueryBuiltinValueCacheConfig) Clone() *InterQueryBuiltinValueCache
 // MaxSizeBytes - max capacity of cache in bytes
 // ForcedEvictionThresholdPercentage - capacity usage in percentage after which forced FIFO eviction starts
 // StaleEntryEvictionPeriodSeconds - time period between end of previous and start of new stale entry eviction routine
+// Shared - shared backend that the cache reads through and writes through to
+// Disk - directory that the cache persists its values in
 type InterQueryBuiltinCacheConfig struct {
-	MaxSizeBytes                      *int64 `json:"max_size_bytes,omitempty"`
-	ForcedEvictionThresholdPercentage *int64 `json:"forced_eviction_threshold_percentage,omitempty"`
-	StaleEntryEvictionPeriodSeconds   *int64 `json:"stale_entry_eviction_period_seconds,omitempty"`
+	MaxSizeBytes                      *int64             `json:"max_size_bytes,omitempty"`
+	ForcedEvictionThresholdPercentage *int64             `json:"forced_eviction_threshold_percentage,omitempty"`
+	StaleEntryEvictionPeriodSeconds   *int64             `json:"stale_entry_eviction_period_seconds,omitempty"`
+	Shared                            *SharedCacheConfig `json:"shared,omitempty"`
+	Disk                              *DiskCacheConfig   `json:"disk,omitempty"`
 }
 
 // Clone creates a deep copy of InterQueryBuiltinCacheConfig.
@@ -145,6 +150,9 @@ func (i *InterQueryBuiltinCacheConfig) Clone() *InterQueryBuiltinCacheConfig {
 		clone.StaleEntryEvictionPeriodSeconds = &period
 	}
 
+	clone.Shared = i.Shared.Clone()
+	clone.Disk = i.Disk.Clone()
+
 	return clone
 }
 
@@ -212,6 +220,16 @@ func (c *Config) validateAndInjectDefaults() error {
 			return fmt.Errorf("invalid stale_entry_eviction_period_seconds %v", period)
 		}
 	}
+	if c.InterQueryBuiltinCache.Shared != nil {
+		if err := c.InterQueryBuiltinCache.Shared.validateAndInjectDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.InterQueryBuiltinCache.Disk != nil {
+		if err := c.InterQueryBuiltinCache.Disk.validateAndInjectDefaults(); err != nil {
+			return err
+		}
+	}
 
 	if c.InterQueryBuiltinValueCache.MaxNumEntries == nil {
 		maxSize := new(int)
@@ -272,10 +290,12 @@ func NewInterQueryCache(config *Config) InterQueryCache {
 //
 // Parameters:
 //
-//	ctx - used to control lifecycle of the stale entry cleanup routine
+//	ctx - used to control lifecycle of the stale entry cleanup routine, the shared backend and the disk tier
 //	config - to configure the InterQueryCache
 func NewInterQueryCacheWithContext(ctx context.Context, config *Config) InterQueryCache {
 	iqCache := newCache(config)
+	context.AfterFunc(ctx, iqCache.closeBackend)
+	context.AfterFunc(ctx, iqCache.closeDisk)
 	if iqCache.staleEntryEvictionTimePeriodSeconds() > 0 {
 		go func() {
 			cleanupTicker := time.NewTicker(time.Duration(iqCache.staleEntryEvictionTimePeriodSeconds()) * time.Second)
@@ -311,23 +331,38 @@ type cache struct {
 	config *Config
 	l      *list.List
 	mtx    sync.Mutex
+
+	shared        *sharedBackend
+	customBackend Backend
+	disk          *diskTier
 }
 
 func newCache(config *Config) *cache {
-	return &cache{
+	c := &cache{
 		items:  map[string]cacheItem{},
 		usage:  0,
 		config: config,
 		l:      list.New(),
 	}
+	c.shared = newSharedBackend(c.sharedConfig(), nil)
+	c.unsafeOpenDisk()
+	return c
 }
 
 // InsertWithExpiry inserts a key k into the cache with value v with an expiration time expiresAt.
 // A zero time value for expiresAt indicates no expiry
+// If the cache has a shared backend, the value is also written to the backend.
 func (c *cache) InsertWithExpiry(k ast.Value, v InterQueryCacheValue, expiresAt time.Time) (dropped int) {
 	c.mtx.Lock()
-	defer c.mtx.Unlock()
-	return c.unsafeInsert(k, v, expiresAt)
+	dropped = c.unsafeInsert(k, v, expiresAt)
+	c.unsafePersist(k, v, expiresAt)
+	shared := c.shared
+	c.mtx.Unlock()
+
+	if shared != nil {
+		shared.set(k, v, expiresAt)
+	}
+	return dropped
 }
 
 // Insert inserts a key k into the cache with value v with no expiration time.
@@ -335,23 +370,51 @@ func (c *cache) Insert(k ast.Value, v InterQueryCacheValue) (dropped int) {
 	return c.InsertWithExpiry(k, v, time.Time{})
 }
 
-// Get returns the value in the cache for k.
+// Get returns the value in the cache for k. If the value isn't held in memory,
+// it is read from disk or, if the cache has a shared backend, from the backend.
 func (c *cache) Get(k ast.Value) (InterQueryCacheValue, bool) {
 	c.mtx.Lock()
-	defer c.mtx.Unlock()
 	cacheItem, ok := c.unsafeGet(k)
+	shared := c.shared
+	disk := c.disk
+	read := !ok && disk != nil && disk.beginRead(k)
+	c.mtx.Unlock()
 
 	if ok {
 		return cacheItem.value, true
 	}
-	return nil, false
+	if read {
+		if value, ok := c.readDisk(disk, k); ok {
+			return value, true
+		}
+	}
+	if shared == nil {
+		return nil, false
+	}
+
+	value, expiresAt, ok := shared.get(k)
+	if !ok {
+		return nil, false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.unsafeInsert(k, value, expiresAt)
+	c.unsafePersist(k, value, expiresAt)
+	return value, true
 }
 
-// Delete deletes the value in the cache for k.
+// Delete deletes the value in the cache for k, and from the shared backend if
+// the cache has one.
 func (c *cache) Delete(k ast.Value) {
 	c.mtx.Lock()
-	defer c.mtx.Unlock()
 	c.unsafeDelete(k)
+	shared := c.shared
+	c.mtx.Unlock()
+
+	if shared != nil {
+		shared.delete(k)
+	}
 }
 
 func (c *cache) UpdateConfig(config *Config) {
@@ -360,7 +423,12 @@ func (c *cache) UpdateConfig(config *Config) {
 	}
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
+	previous := c.config
 	c.config = config
+	c.unsafeUpdateSharedConfig(previous.sharedConfig())
+	if !reflect.DeepEqual(previous.diskConfig(), config.diskConfig()) {
+		c.unsafeOpenDisk()
+	}
 }
 
 func (c *cache) Clone(value InterQueryCacheValue) (InterQueryCacheValue, error) {
@@ -371,7 +439,7 @@ func (c *cache) Clone(value InterQueryCacheValue) (InterQueryCacheValue, error)
 
 func (c *cache) unsafeInsert(k ast.Value, v InterQueryCacheValue, expiresAt time.Time) (dropped int) {
 	size := v.SizeInBytes()
-	limit := int64(math.Ceil(float64(c.forcedEvictionThresholdPercentage())/100.0) * (float64(c.maxSizeBytes())))
+	limit := c.evictionLimit()
 	if limit > 0 {
 		if size > limit {
 			dropped++
@@ -411,12 +479,21 @@ func (c *cache) unsafeDelete(k ast.Value) {
 	c.usage -= cacheItem.value.SizeInBytes()
 	delete(c.items, k.String())
 	c.l.Remove(cacheItem.keyElement)
+
+	if c.disk != nil {
+		c.disk.remove(k)
+	}
 }
 
 func (*cache) unsafeClone(value InterQueryCacheValue) (InterQueryCacheValue, error) {
 	return value.Clone()
 }
 
+// evictionLimit returns the size in bytes at which values are evicted.
+func (c *cache) evictionLimit() int64 {
+	return int64(math.Ceil(float64(c.forcedEvictionThresholdPercentage())/100.0) * (float64(c.maxSizeBytes())))
+}
+
 func (c *cache) maxSizeBytes() int64 {
 	if c.config == nil {
 		return defaultMaxSizeBytes
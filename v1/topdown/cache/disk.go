@@ -0,0 +1,417 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+const diskEntryExt = ".json"
+
+// maxQueuedDiskWrites bounds the number of values held for writing to disk.
+const maxQueuedDiskWrites = 10000
+
+// DiskCacheConfig represents the configuration of the directory that the
+// inter-query cache persists its values in, so that they survive restarts.
+// Directory - path of the directory, created if it doesn't exist
+type DiskCacheConfig struct {
+	Directory string `json:"directory"`
+}
+
+// Clone creates a deep copy of DiskCacheConfig.
+func (d *DiskCacheConfig) Clone() *DiskCacheConfig {
+	if d == nil {
+		return nil
+	}
+
+	clone := *d
+	return &clone
+}
+
+func (d *DiskCacheConfig) validateAndInjectDefaults() error {
+	if d.Directory == "" {
+		return errors.New("missing disk directory")
+	}
+	return nil
+}
+
+// diskTier persists the values of an inter-query cache in a directory, with
+// one file per value, named by the hash of its key. Values are read from disk
+// when they aren't held in memory, and values evicted from memory, e.g.
+// because the cache exceeds max_size_bytes or the values are stale, are
+// removed from disk too. Values that can't be serialized are only held in
+// memory. Failing disk operations don't affect the in-memory cache.
+//
+// Files are written and removed by a background goroutine, in the order the
+// cache queued the operations, so that disk I/O never happens while the cache
+// is locked. Only the last queued operation on a value is kept, and values
+// queued while maxQueuedDiskWrites values are waiting to be written aren't
+// persisted: their files are removed instead. When the tier is opened, the goroutine first removes the files of
+// expired and invalid values, and the oldest values exceeding limit.
+type diskTier struct {
+	dir   string
+	limit int64
+
+	mtx     sync.Mutex
+	ops     []diskOp
+	queued  map[string]int // index in ops of the operation not yet taken by key hash
+	writes  int            // number of writes in ops
+	pending map[string]int // number of queued operations by key hash
+	loads   map[string]int // number of reads in progress by key hash
+	stale   map[string]bool
+	notify  chan struct{}
+	done    chan struct{}
+}
+
+type diskOp struct {
+	hash      string
+	key       string
+	value     InterQueryCacheValue
+	expiresAt time.Time
+	remove    bool
+	flushed   chan struct{}
+}
+
+func newDiskTier(config *DiskCacheConfig, limit int64) *diskTier {
+	if config == nil || config.Directory == "" {
+		return nil
+	}
+	d := &diskTier{
+		dir:     config.Directory,
+		limit:   limit,
+		queued:  map[string]int{},
+		pending: map[string]int{},
+		loads:   map[string]int{},
+		stale:   map[string]bool{},
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *diskTier) path(hash string) string {
+	return filepath.Join(d.dir, hash+diskEntryExt)
+}
+
+// write queues writing the value for k.
+func (d *diskTier) write(k ast.Value, v InterQueryCacheValue, expiresAt time.Time) {
+	key := k.String()
+	d.queue(diskOp{hash: hashKey(key), key: key, value: v, expiresAt: expiresAt})
+}
+
+// remove queues removing the value for k.
+func (d *diskTier) remove(k ast.Value) {
+	d.queue(diskOp{hash: hashKey(k.String()), remove: true})
+}
+
+func (d *diskTier) queue(op diskOp) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if op.hash != "" {
+		if d.loads[op.hash] > 0 {
+			d.stale[op.hash] = true
+		}
+
+		i, queued := d.queued[op.hash]
+		if queued && !d.ops[i].remove {
+			d.writes--
+		}
+		if !op.remove {
+			if d.writes >= maxQueuedDiskWrites {
+				op = diskOp{hash: op.hash, remove: true}
+			} else {
+				d.writes++
+			}
+		}
+		if queued {
+			d.ops[i] = op
+			return
+		}
+		d.queued[op.hash] = len(d.ops)
+		d.pending[op.hash]++
+	}
+	d.ops = append(d.ops, op)
+
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+}
+
+// flush waits until the operations queued so far are done.
+func (d *diskTier) flush() {
+	flushed := make(chan struct{})
+	d.queue(diskOp{flushed: flushed})
+	<-flushed
+}
+
+// close stops the background goroutine once the queued operations are done.
+func (d *diskTier) close() {
+	close(d.done)
+}
+
+func (d *diskTier) run() {
+	_ = os.MkdirAll(d.dir, 0o700)
+	d.sweep()
+
+	for {
+		select {
+		case <-d.notify:
+		case <-d.done:
+			d.apply(d.take())
+			return
+		}
+		d.apply(d.take())
+	}
+}
+
+func (d *diskTier) take() []diskOp {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	ops := d.ops
+	d.ops = nil
+	d.writes = 0
+	clear(d.queued)
+	return ops
+}
+
+func (d *diskTier) apply(ops []diskOp) {
+	for _, op := range ops {
+		switch {
+		case op.flushed != nil:
+			close(op.flushed)
+			continue
+		case op.remove:
+			_ = os.Remove(d.path(op.hash))
+		default:
+			d.store(op)
+		}
+
+		d.mtx.Lock()
+		if d.pending[op.hash]--; d.pending[op.hash] == 0 {
+			delete(d.pending, op.hash)
+		}
+		d.mtx.Unlock()
+	}
+}
+
+func (d *diskTier) store(op diskOp) {
+	bs, ok := encodeEntry(op.key, op.value, op.expiresAt)
+	if !ok {
+		return
+	}
+
+	// Values are written to a temporary file first, so that restarts never
+	// observe partially written values.
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(bs)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(op.hash))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// beginRead returns false if the value for k can't be read from disk, because
+// an operation on it is still queued. Otherwise, it must be followed by a call
+// to endRead.
+func (d *diskTier) beginRead(k ast.Value) bool {
+	hash := hashKey(k.String())
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.pending[hash] > 0 {
+		return false
+	}
+	d.loads[hash]++
+	return true
+}
+
+// endRead returns false if an operation on the value for k was queued since
+// the read began, so that the value read is outdated.
+func (d *diskTier) endRead(k ast.Value) bool {
+	hash := hashKey(k.String())
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	stale := d.stale[hash]
+	if d.loads[hash]--; d.loads[hash] == 0 {
+		delete(d.loads, hash)
+		delete(d.stale, hash)
+	}
+	return !stale
+}
+
+// read returns the value persisted for k. It returns false if there is none,
+// and true for invalid if the file holds an expired or invalid value.
+func (d *diskTier) read(k ast.Value) (value InterQueryCacheValue, expiresAt time.Time, ok bool, invalid bool) {
+	key := k.String()
+	bs, err := os.ReadFile(d.path(hashKey(key)))
+	if err != nil {
+		return nil, time.Time{}, false, false
+	}
+
+	entry, value, ok := decodeEntry(bs, key)
+	if !ok {
+		return nil, time.Time{}, false, true
+	}
+	return value, entry.ExpiresAt, true, false
+}
+
+// sweep removes the files of expired and invalid values, temporary files left
+// behind by interrupted writes, and the files of the oldest values exceeding
+// the size limit.
+func (d *diskTier) sweep() {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	type sweptEntry struct {
+		path      string
+		size      int64
+		createdAt time.Time
+	}
+
+	var entries []sweptEntry
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(d.dir, file.Name())
+
+		if strings.HasPrefix(file.Name(), ".tmp-") {
+			_ = os.Remove(path)
+			continue
+		}
+		if !strings.HasSuffix(file.Name(), diskEntryExt) {
+			continue
+		}
+
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		entry, value, ok := decodeEntry(bs, "")
+		if !ok || d.path(entry.KeyHash) != path {
+			_ = os.Remove(path)
+			continue
+		}
+		entries = append(entries, sweptEntry{path: path, size: value.SizeInBytes(), createdAt: entry.CreatedAt})
+	}
+
+	if d.limit <= 0 {
+		return
+	}
+
+	slices.SortStableFunc(entries, func(a, b sweptEntry) int {
+		return b.createdAt.Compare(a.createdAt)
+	})
+
+	var usage int64
+	for _, entry := range entries {
+		if usage += entry.size; usage > d.limit {
+			_ = os.Remove(entry.path)
+		}
+	}
+}
+
+// unsafeOpenDisk starts persisting values in the configured directory. Values
+// already held in memory are persisted too.
+func (c *cache) unsafeOpenDisk() {
+	if c.disk != nil {
+		c.disk.close()
+	}
+
+	c.disk = newDiskTier(c.config.diskConfig(), c.evictionLimit())
+	if c.disk == nil {
+		return
+	}
+
+	for e := c.l.Front(); e != nil; e = e.Next() {
+		k := e.Value.(ast.Value)
+		item, _ := c.unsafeGet(k)
+		c.disk.write(k, item.value, item.expiresAt)
+	}
+}
+
+// closeDisk stops persisting values.
+func (c *cache) closeDisk() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.disk != nil {
+		c.disk.close()
+		c.disk = nil
+	}
+}
+
+func (c *Config) diskConfig() *DiskCacheConfig {
+	if c == nil {
+		return nil
+	}
+	return c.InterQueryBuiltinCache.Disk
+}
+
+// unsafePersist writes the value for k to disk, unless it was dropped while
+// inserting.
+func (c *cache) unsafePersist(k ast.Value, v InterQueryCacheValue, expiresAt time.Time) {
+	if c.disk == nil {
+		return
+	}
+	if _, ok := c.unsafeGet(k); ok {
+		c.disk.write(k, v, expiresAt)
+	}
+}
+
+// readDisk returns the value persisted for k, and inserts it. The read must
+// have been started with beginRead while holding the cache's lock, which isn't
+// held while the file is read.
+func (c *cache) readDisk(disk *diskTier, k ast.Value) (InterQueryCacheValue, bool) {
+	value, expiresAt, ok, invalid := disk.read(k)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !disk.endRead(k) || c.disk != disk {
+		return nil, false
+	}
+	if item, found := c.unsafeGet(k); found {
+		return item.value, true
+	}
+	if invalid {
+		disk.remove(k)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	c.unsafeInsert(k, value, expiresAt)
+	if _, found := c.unsafeGet(k); !found {
+		// dropped, as it exceeds the size limit on its own
+		disk.remove(k)
+	}
+	return value, true
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+func newTestDiskConfig(t *testing.T, dir string, maxSizeBytes int64) *Config {
+	t.Helper()
+
+	config, err := ParseCachingConfig(fmt.Appendf(nil, `{
+		"inter_query_builtin_cache": {"max_size_bytes": %d, "disk": {"directory": %q}}
+	}`, maxSizeBytes, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// countDiskEntries returns the number of files in dir, once the operations
+// queued by c are done.
+func countDiskEntries(t *testing.T, c InterQueryCache, dir string) int {
+	t.Helper()
+
+	if disk := c.(*cache).disk; disk != nil {
+		disk.flush()
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestDiskCacheReload(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	config := newTestDiskConfig(t, dir, 0)
+
+	c := NewInterQueryCache(config)
+	c.InsertWithExpiry(ast.String("valid"), &testSharedValue{Data: "foo"}, time.Now().Add(time.Hour))
+	c.InsertWithExpiry(ast.String("expiring"), &testSharedValue{Data: "bar"}, time.Now().Add(50*time.Millisecond))
+	c.Insert(ast.MustParseTerm(`{"url": "https://example.com"}`).Value, &testSharedValue{Data: "baz"})
+	c.Insert(ast.String("local"), newInterQueryCacheValue(ast.String("qux"), 3))
+	c.Insert(ast.String("deleted"), &testSharedValue{Data: "quux"})
+	c.Delete(ast.String("deleted"))
+
+	if n := countDiskEntries(t, c, dir); n != 3 {
+		t.Fatalf("expected 3 persisted values, got %d", n)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// a restarted cache reads the values that haven't expired when they are
+	// requested
+	reloaded := NewInterQueryCache(newTestDiskConfig(t, dir, 0))
+	if n := len(reloaded.(*cache).items); n != 0 {
+		t.Fatalf("expected no values in memory, got %d", n)
+	}
+
+	for key, exp := range map[string]string{`"valid"`: "foo", `{"url": "https://example.com"}`: "baz"} {
+		value, found := reloaded.Get(ast.MustParseTerm(key).Value)
+		if !found || value.(*testSharedValue).Data != exp {
+			t.Fatalf("expected %v for %v, got %v (found: %v)", exp, key, value, found)
+		}
+	}
+	for _, key := range []string{"expiring", "local", "deleted"} {
+		if _, found := reloaded.Get(ast.String(key)); found {
+			t.Fatalf("expected %v to be missing", key)
+		}
+	}
+
+	// files of expired values are removed
+	if n := countDiskEntries(t, reloaded, dir); n != 2 {
+		t.Fatalf("expected 2 persisted values, got %d", n)
+	}
+}
+
+func TestDiskCacheMaxSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	c := NewInterQueryCache(newTestDiskConfig(t, dir, 10))
+	for i := range 4 {
+		c.Insert(ast.Number(fmt.Sprint(i)), &testSharedValue{Data: "1234"})
+	}
+
+	// evicted values are removed from disk too
+	if n := countDiskEntries(t, c, dir); n != 2 {
+		t.Fatalf("expected 2 persisted values, got %d", n)
+	}
+
+	// and reloading keeps the newest values within a lower limit
+	reloaded := NewInterQueryCache(newTestDiskConfig(t, dir, 5))
+	reloaded.(*cache).disk.flush()
+	if _, found := reloaded.Get(ast.Number("3")); !found {
+		t.Fatal("expected newest value")
+	}
+	if _, found := reloaded.Get(ast.Number("2")); found {
+		t.Fatal("expected older value to be evicted")
+	}
+	if n := countDiskEntries(t, reloaded, dir); n != 1 {
+		t.Fatalf("expected 1 persisted value, got %d", n)
+	}
+}
+
+func TestDiskTierQueue(t *testing.T) {
+	t.Parallel()
+
+	// not running, so that operations stay queued
+	d := &diskTier{
+		queued:  map[string]int{},
+		pending: map[string]int{},
+		loads:   map[string]int{},
+		stale:   map[string]bool{},
+		notify:  make(chan struct{}, 1),
+	}
+
+	// only the last operation on a value is kept
+	d.write(ast.String("a"), &testSharedValue{Data: "foo"}, time.Time{})
+	d.write(ast.String("a"), &testSharedValue{Data: "bar"}, time.Time{})
+	d.remove(ast.String("a"))
+	d.write(ast.String("a"), &testSharedValue{Data: "baz"}, time.Time{})
+	if len(d.ops) != 1 || d.ops[0].value.(*testSharedValue).Data != "baz" || d.writes != 1 || d.pending[hashKey(`"a"`)] != 1 {
+		t.Fatalf("expected single write, got %+v", d.ops)
+	}
+
+	// values exceeding the limit are removed instead of written
+	for i := range maxQueuedDiskWrites {
+		d.write(ast.Number(fmt.Sprint(i)), &testSharedValue{Data: "foo"}, time.Time{})
+	}
+	if len(d.ops) != maxQueuedDiskWrites+1 || d.writes != maxQueuedDiskWrites {
+		t.Fatalf("expected %d queued writes, got %d", maxQueuedDiskWrites, d.writes)
+	}
+	if op := d.ops[len(d.ops)-1]; !op.remove || op.value != nil {
+		t.Fatalf("expected last value to be removed, got %+v", op)
+	}
+
+	// writes replacing queued writes are kept
+	d.write(ast.String("a"), &testSharedValue{Data: "qux"}, time.Time{})
+	if op := d.ops[0]; op.remove || op.value.(*testSharedValue).Data != "qux" {
+		t.Fatalf("expected write, got %+v", op)
+	}
+
+	if ops := d.take(); len(ops) != maxQueuedDiskWrites+1 || d.writes != 0 || len(d.queued) != 0 {
+		t.Fatalf("expected queue to be emptied, got %d operations", len(ops))
+	}
+}
+
+func TestDiskCacheUpdateConfig(t *testing.T) {
+	t.Parallel()
+
+	c := NewInterQueryCache(nil)
+	c.Insert(ast.String("foo"), &testSharedValue{Data: "foo"})
+
+	// values held in memory are persisted when the disk tier is enabled
+	dir := t.TempDir()
+	c.UpdateConfig(newTestDiskConfig(t, dir, 0))
+	if n := countDiskEntries(t, c, dir); n != 1 {
+		t.Fatalf("expected 1 persisted value, got %d", n)
+	}
+
+	c.UpdateConfig(newTestDiskConfig(t, dir, 0))
+	c.Insert(ast.String("bar"), &testSharedValue{Data: "bar"})
+	if n := countDiskEntries(t, c, dir); n != 2 {
+		t.Fatalf("expected 2 persisted values, got %d", n)
+	}
+
+	c.UpdateConfig(newTestDiskConfig(t, t.TempDir(), 0))
+	c.Delete(ast.String("bar"))
+	if n := countDiskEntries(t, c, dir); n != 2 {
+		t.Fatalf("expected values in previous directory to be kept, got %d", n)
+	}
+}
+
+func TestParseCachingConfigDisk(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseCachingConfig([]byte(`{"inter_query_builtin_cache": {"disk": {}}}`)); err == nil || err.Error() != "missing disk directory" {
+		t.Fatalf("expected missing directory error, got %v", err)
+	}
+
+	// the directory is created when the cache is built, not when the
+	// configuration is parsed
+	dir := filepath.Join(t.TempDir(), "cache")
+	config := newTestDiskConfig(t, dir, 0)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected missing directory, got %v", err)
+	}
+
+	c := NewInterQueryCache(config)
+	c.Insert(ast.String("foo"), &testSharedValue{Data: "foo"})
+	if n := countDiskEntries(t, c, dir); n != 1 {
+		t.Fatalf("expected 1 persisted value, got %d", n)
+	}
+}
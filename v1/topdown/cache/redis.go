@@ -0,0 +1,301 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisCacheConfig represents the configuration of the shared backend for
+// stores that speak the Redis protocol (RESP2).
+// Address - host and port of the store
+// Username, Password - credentials sent with AUTH, if set
+// DB - database selected with SELECT, if non-zero
+// TLS - connect over TLS
+// MaxIdleConnections - max number of idle connections kept open
+type RedisCacheConfig struct {
+	Address            string `json:"address"`
+	Username           string `json:"username,omitempty"`
+	Password           string `json:"password,omitempty"`
+	DB                 int    `json:"db,omitempty"`
+	TLS                bool   `json:"tls,omitempty"`
+	MaxIdleConnections *int   `json:"max_idle_connections,omitempty"`
+}
+
+// Clone creates a deep copy of RedisCacheConfig.
+func (r *RedisCacheConfig) Clone() *RedisCacheConfig {
+	if r == nil {
+		return nil
+	}
+
+	clone := *r
+
+	if r.MaxIdleConnections != nil {
+		maxIdle := *r.MaxIdleConnections
+		clone.MaxIdleConnections = &maxIdle
+	}
+
+	return &clone
+}
+
+func (r *RedisCacheConfig) validateAndInjectDefaults() error {
+	if r.Address == "" {
+		return errors.New("missing shared redis address")
+	}
+	if r.DB < 0 {
+		return fmt.Errorf("invalid shared redis db %v", r.DB)
+	}
+	if r.MaxIdleConnections == nil {
+		maxIdle := defaultRedisMaxIdleConnections
+		r.MaxIdleConnections = &maxIdle
+	} else if *r.MaxIdleConnections < 0 {
+		return fmt.Errorf("invalid shared redis max_idle_connections %v", *r.MaxIdleConnections)
+	}
+	return nil
+}
+
+func (r *RedisCacheConfig) maxIdleConnections() int {
+	if r.MaxIdleConnections == nil {
+		return defaultRedisMaxIdleConnections
+	}
+	return *r.MaxIdleConnections
+}
+
+// redisError is an error reply sent by the store. Connections remain usable
+// after error replies.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisBackend is a Backend for stores that speak the Redis protocol. It only
+// implements the handful of commands the cache needs, over a small pool of
+// connections.
+type redisBackend struct {
+	config RedisCacheConfig
+	idle   chan *redisConn
+
+	mtx    sync.Mutex
+	closed bool
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+func newRedisBackend(config *RedisCacheConfig) *redisBackend {
+	return &redisBackend{
+		config: *config,
+		idle:   make(chan *redisConn, config.maxIdleConnections()),
+	}
+}
+
+func (b *redisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := b.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	switch reply := reply.(type) {
+	case nil:
+		return nil, false, nil
+	case []byte:
+		return reply, true, nil
+	default:
+		return nil, false, fmt.Errorf("redis: unexpected reply to GET: %v", reply)
+	}
+}
+
+func (b *redisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		// round up so that short-lived values aren't stored without expiry
+		args = append(args, "PX", strconv.FormatInt((ttl+time.Millisecond-1).Milliseconds(), 10))
+	}
+	reply, err := b.do(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if reply != "OK" {
+		return fmt.Errorf("redis: unexpected reply to SET: %v", reply)
+	}
+	return nil
+}
+
+func (b *redisBackend) Delete(ctx context.Context, key string) error {
+	_, err := b.do(ctx, "DEL", key)
+	return err
+}
+
+// Close closes the idle connections of the backend. Connections in use are
+// closed when they're released.
+func (b *redisBackend) Close() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.closed = true
+	for {
+		select {
+		case c := <-b.idle:
+			c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (b *redisBackend) do(ctx context.Context, args ...string) (any, error) {
+	c, err := b.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(ctx, args...)
+
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		c.conn.Close()
+		return nil, err
+	}
+	b.release(c)
+	return reply, err
+}
+
+func (b *redisBackend) acquire(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-b.idle:
+		return c, nil
+	default:
+	}
+
+	var conn net.Conn
+	var err error
+	if b.config.TLS {
+		conn, err = (&tls.Dialer{}).DialContext(ctx, "tcp", b.config.Address)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", b.config.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	var handshake [][]string
+	switch {
+	case b.config.Username != "":
+		handshake = append(handshake, []string{"AUTH", b.config.Username, b.config.Password})
+	case b.config.Password != "":
+		handshake = append(handshake, []string{"AUTH", b.config.Password})
+	}
+	if b.config.DB != 0 {
+		handshake = append(handshake, []string{"SELECT", strconv.Itoa(b.config.DB)})
+	}
+	for _, args := range handshake {
+		if _, err := c.do(ctx, args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (b *redisBackend) release(c *redisConn) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !b.closed {
+		select {
+		case b.idle <- c:
+			return
+		default:
+		}
+	}
+	c.conn.Close()
+}
+
+func (c *redisConn) do(ctx context.Context, args ...string) (any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeRedisCommand(c.w, args...); err != nil {
+		return nil, err
+	}
+	return readRedisReply(c.r)
+}
+
+func writeRedisCommand(w *bufio.Writer, args ...string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return w.Flush()
+}
+
+// readRedisReply reads one reply. Simple strings are returned as strings, bulk
+// strings as byte slices, integers as int64 and arrays as slices of replies.
+// Null replies are returned as nil and error replies as redisError.
+func readRedisReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: invalid reply %q", line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		replies := make([]any, n)
+		for i := range replies {
+			if replies[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	default:
+		return nil, fmt.Errorf("redis: invalid reply %q", line)
+	}
+}
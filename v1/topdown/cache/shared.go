@@ -0,0 +1,386 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+const (
+	defaultSharedKeyPrefix          = "opa:iqc:"
+	defaultSharedTimeoutMillis      = int64(250)
+	defaultSharedRetryPeriodSeconds = int64(10)
+	defaultRedisMaxIdleConnections  = 8
+)
+
+// Backend defines the interface for stores that inter-query caches of several
+// OPA instances share. Keys and values are opaque to backends. Implementations
+// must be safe for concurrent use, and may implement io.Closer to release
+// their resources when the cache stops using them.
+type Backend interface {
+	// Get returns the value stored for key, or false if there is none.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set stores value for key. A zero ttl indicates no expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored for key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// SharedInterQueryCache is implemented by inter-query caches that read through
+// and write through to a shared Backend. Implementations of
+// hooks.InterQueryCacheHook can use it to plug in custom backends, which take
+// precedence over the backend configured in caching.inter_query_builtin_cache.shared.
+type SharedInterQueryCache interface {
+	InterQueryCache
+	// SetBackend sets the backend the cache shares its values through. Passing
+	// nil restores the configured backend.
+	SetBackend(backend Backend)
+}
+
+// SerializableInterQueryCacheValue is implemented by inter-query cache values
+// that can be stored in a shared backend. Values of other types are only
+// cached locally.
+type SerializableInterQueryCacheValue interface {
+	InterQueryCacheValue
+	// InterQueryCacheValueType returns the name the value's decoder is
+	// registered with, see RegisterInterQueryCacheValueType.
+	InterQueryCacheValueType() string
+	MarshalBinary() ([]byte, error)
+}
+
+// InterQueryCacheValueDecoder decodes values encoded by the MarshalBinary
+// method of a SerializableInterQueryCacheValue.
+type InterQueryCacheValueDecoder func([]byte) (InterQueryCacheValue, error)
+
+var interQueryCacheValueDecoders = map[string]InterQueryCacheValueDecoder{}
+
+// RegisterInterQueryCacheValueType registers the decoder for the values of a
+// SerializableInterQueryCacheValue type. Values read from shared backends are
+// dropped if no decoder is registered for their type.
+func RegisterInterQueryCacheValueType(name string, decoder InterQueryCacheValueDecoder) {
+	interQueryCacheValueDecoders[name] = decoder
+}
+
+// SharedCacheConfig represents the configuration of the backend that the
+// inter-query cache shares its values through.
+// KeyPrefix - prefix of the keys stored in the backend
+// TimeoutMillis - timeout of backend operations in milliseconds
+// RetryPeriodSeconds - time period the backend is skipped for after it failed
+// Redis - configuration of the reference backend for Redis compatible stores
+type SharedCacheConfig struct {
+	KeyPrefix          *string           `json:"key_prefix,omitempty"`
+	TimeoutMillis      *int64            `json:"timeout_ms,omitempty"`
+	RetryPeriodSeconds *int64            `json:"retry_period_seconds,omitempty"`
+	Redis              *RedisCacheConfig `json:"redis,omitempty"`
+}
+
+// Clone creates a deep copy of SharedCacheConfig.
+func (s *SharedCacheConfig) Clone() *SharedCacheConfig {
+	if s == nil {
+		return nil
+	}
+
+	clone := &SharedCacheConfig{}
+
+	if s.KeyPrefix != nil {
+		prefix := *s.KeyPrefix
+		clone.KeyPrefix = &prefix
+	}
+
+	if s.TimeoutMillis != nil {
+		timeout := *s.TimeoutMillis
+		clone.TimeoutMillis = &timeout
+	}
+
+	if s.RetryPeriodSeconds != nil {
+		period := *s.RetryPeriodSeconds
+		clone.RetryPeriodSeconds = &period
+	}
+
+	clone.Redis = s.Redis.Clone()
+
+	return clone
+}
+
+func (s *SharedCacheConfig) validateAndInjectDefaults() error {
+	if s.KeyPrefix == nil {
+		prefix := defaultSharedKeyPrefix
+		s.KeyPrefix = &prefix
+	}
+	if s.TimeoutMillis == nil {
+		timeout := defaultSharedTimeoutMillis
+		s.TimeoutMillis = &timeout
+	} else if *s.TimeoutMillis <= 0 {
+		return fmt.Errorf("invalid shared timeout_ms %v", *s.TimeoutMillis)
+	}
+	if s.RetryPeriodSeconds == nil {
+		period := defaultSharedRetryPeriodSeconds
+		s.RetryPeriodSeconds = &period
+	} else if *s.RetryPeriodSeconds < 0 {
+		return fmt.Errorf("invalid shared retry_period_seconds %v", *s.RetryPeriodSeconds)
+	}
+	if s.Redis != nil {
+		return s.Redis.validateAndInjectDefaults()
+	}
+	return nil
+}
+
+func (s *SharedCacheConfig) keyPrefix() string {
+	if s == nil || s.KeyPrefix == nil {
+		return defaultSharedKeyPrefix
+	}
+	return *s.KeyPrefix
+}
+
+func (s *SharedCacheConfig) timeout() time.Duration {
+	if s == nil || s.TimeoutMillis == nil {
+		return time.Duration(defaultSharedTimeoutMillis) * time.Millisecond
+	}
+	return time.Duration(*s.TimeoutMillis) * time.Millisecond
+}
+
+func (s *SharedCacheConfig) retryPeriod() time.Duration {
+	if s == nil || s.RetryPeriodSeconds == nil {
+		return time.Duration(defaultSharedRetryPeriodSeconds) * time.Second
+	}
+	return time.Duration(*s.RetryPeriodSeconds) * time.Second
+}
+
+// storedEntry is the envelope of the values stored outside of the process, in
+// shared backends or on disk. Only the hash of the key is stored, as keys can
+// hold credentials, e.g. the headers of http.send requests. It is checked so
+// that values are never returned for other keys, e.g. for files that were
+// renamed.
+type storedEntry struct {
+	KeyHash   string    `json:"key_hash"`
+	Type      string    `json:"type"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	Value     []byte    `json:"value"`
+}
+
+// encodeEntry returns the stored entry for v, or false if v can't be
+// serialized.
+func encodeEntry(key string, v InterQueryCacheValue, expiresAt time.Time) ([]byte, bool) {
+	sv, ok := v.(SerializableInterQueryCacheValue)
+	if !ok {
+		return nil, false
+	}
+	value, err := sv.MarshalBinary()
+	if err != nil {
+		return nil, false
+	}
+	bs, err := json.Marshal(storedEntry{
+		KeyHash:   hashKey(key),
+		Type:      sv.InterQueryCacheValueType(),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+		Value:     value,
+	})
+	if err != nil {
+		return nil, false
+	}
+	return bs, true
+}
+
+// decodeEntry returns the value of a stored entry, or false if the entry is
+// invalid, has expired or has a type without registered decoder. If key is
+// non-empty, entries stored for other keys are rejected too.
+func decodeEntry(bs []byte, key string) (storedEntry, InterQueryCacheValue, bool) {
+	var entry storedEntry
+	if err := json.Unmarshal(bs, &entry); err != nil || (key != "" && entry.KeyHash != hashKey(key)) {
+		return entry, nil, false
+	}
+	if !entry.ExpiresAt.IsZero() && entry.ExpiresAt.Before(time.Now()) {
+		return entry, nil, false
+	}
+
+	decode, ok := interQueryCacheValueDecoders[entry.Type]
+	if !ok {
+		return entry, nil, false
+	}
+	value, err := decode(entry.Value)
+	if err != nil {
+		return entry, nil, false
+	}
+	return entry, value, true
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// sharedBackend reads and writes the values of an inter-query cache from and
+// to a Backend. After a backend operation fails, the backend is skipped for the
+// retry period and the cache only uses its local values.
+type sharedBackend struct {
+	backend     Backend
+	prefix      string
+	timeout     time.Duration
+	retryPeriod time.Duration
+
+	mtx      sync.Mutex
+	failedAt time.Time
+}
+
+func newSharedBackend(config *SharedCacheConfig, backend Backend) *sharedBackend {
+	if backend == nil {
+		if config == nil || config.Redis == nil {
+			return nil
+		}
+		backend = newRedisBackend(config.Redis)
+	}
+	return &sharedBackend{
+		backend:     backend,
+		prefix:      config.keyPrefix(),
+		timeout:     config.timeout(),
+		retryPeriod: config.retryPeriod(),
+	}
+}
+
+func (b *sharedBackend) key(k string) string {
+	return b.prefix + hashKey(k)
+}
+
+func (b *sharedBackend) available() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.failedAt.IsZero() || time.Since(b.failedAt) >= b.retryPeriod
+}
+
+func (b *sharedBackend) done(err error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if err != nil {
+		b.failedAt = time.Now()
+	} else {
+		b.failedAt = time.Time{}
+	}
+}
+
+func (b *sharedBackend) get(k ast.Value) (InterQueryCacheValue, time.Time, bool) {
+	if !b.available() {
+		return nil, time.Time{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	key := k.String()
+	bs, found, err := b.backend.Get(ctx, b.key(key))
+	b.done(err)
+	if err != nil || !found {
+		return nil, time.Time{}, false
+	}
+
+	entry, value, ok := decodeEntry(bs, key)
+	return value, entry.ExpiresAt, ok
+}
+
+func (b *sharedBackend) set(k ast.Value, v InterQueryCacheValue, expiresAt time.Time) {
+	if !b.available() {
+		return
+	}
+
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		if ttl = time.Until(expiresAt); ttl <= 0 {
+			return
+		}
+	}
+
+	key := k.String()
+	bs, ok := encodeEntry(key, v, expiresAt)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	b.done(b.backend.Set(ctx, b.key(key), bs, ttl))
+}
+
+func (b *sharedBackend) delete(k ast.Value) {
+	if !b.available() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	b.done(b.backend.Delete(ctx, b.key(k.String())))
+}
+
+func (b *sharedBackend) close() error {
+	if b == nil {
+		return nil
+	}
+	if c, ok := b.backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// SetBackend sets the backend the cache shares its values through, see
+// SharedInterQueryCache.
+func (c *cache) SetBackend(backend Backend) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Only backends created from the configuration are owned by the cache.
+	if c.customBackend == nil {
+		_ = c.shared.close()
+	}
+	c.customBackend = backend
+
+	c.shared = newSharedBackend(c.sharedConfig(), backend)
+}
+
+// unsafeUpdateSharedConfig replaces the configured backend if its
+// configuration has changed.
+func (c *cache) unsafeUpdateSharedConfig(previous *SharedCacheConfig) {
+	config := c.sharedConfig()
+	if c.customBackend != nil {
+		c.shared = newSharedBackend(config, c.customBackend)
+		return
+	}
+	if reflect.DeepEqual(previous, config) {
+		return
+	}
+	_ = c.shared.close()
+	c.shared = newSharedBackend(config, nil)
+}
+
+func (c *cache) sharedConfig() *SharedCacheConfig {
+	return c.config.sharedConfig()
+}
+
+func (c *Config) sharedConfig() *SharedCacheConfig {
+	if c == nil {
+		return nil
+	}
+	return c.InterQueryBuiltinCache.Shared
+}
+
+// closeBackend stops using the configured backend and releases its resources.
+func (c *cache) closeBackend() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.customBackend == nil {
+		_ = c.shared.close()
+		c.shared = nil
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+type testSharedValue struct {
+	Data string
+}
+
+func (v *testSharedValue) SizeInBytes() int64 {
+	return int64(len(v.Data))
+}
+
+func (v *testSharedValue) Clone() (InterQueryCacheValue, error) {
+	return &testSharedValue{Data: v.Data}, nil
+}
+
+func (*testSharedValue) InterQueryCacheValueType() string {
+	return "test"
+}
+
+func (v *testSharedValue) MarshalBinary() ([]byte, error) {
+	return []byte(v.Data), nil
+}
+
+func init() {
+	RegisterInterQueryCacheValueType("test", func(bs []byte) (InterQueryCacheValue, error) {
+		return &testSharedValue{Data: string(bs)}, nil
+	})
+}
+
+// fakeRedis is an in-process stand-in for a Redis compatible store, which
+// implements the commands used by the redis backend.
+type fakeRedis struct {
+	addr     string
+	password string
+
+	mtx      sync.Mutex
+	values   map[string][]byte
+	ttls     map[string]time.Duration
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	f := &fakeRedis{
+		addr:     ln.Addr().String(),
+		password: password,
+		values:   map[string][]byte{},
+		ttls:     map[string]time.Duration{},
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	authenticated := f.password == ""
+
+	for {
+		req, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range req.([]any) {
+			args = append(args, string(arg.([]byte)))
+		}
+		cmd := strings.ToUpper(args[0])
+
+		f.mtx.Lock()
+		f.commands = append(f.commands, cmd)
+		switch {
+		case cmd == "AUTH":
+			if authenticated = args[len(args)-1] == f.password; authenticated {
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid password\r\n")
+			}
+		case !authenticated:
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		case cmd == "SELECT":
+			w.WriteString("+OK\r\n")
+		case cmd == "GET":
+			if v, ok := f.values[args[1]]; ok {
+				fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				w.WriteString("$-1\r\n")
+			}
+		case cmd == "SET":
+			f.values[args[1]] = []byte(args[2])
+			delete(f.ttls, args[1])
+			if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+				ms, _ := strconv.Atoi(args[4])
+				f.ttls[args[1]] = time.Duration(ms) * time.Millisecond
+			}
+			w.WriteString("+OK\r\n")
+		case cmd == "DEL":
+			_, ok := f.values[args[1]]
+			delete(f.values, args[1])
+			if ok {
+				w.WriteString(":1\r\n")
+			} else {
+				w.WriteString(":0\r\n")
+			}
+		default:
+			fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", cmd)
+		}
+		f.mtx.Unlock()
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) snapshot() (map[string]time.Duration, []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	keys := make(map[string]time.Duration, len(f.values))
+	for k := range f.values {
+		keys[k] = f.ttls[k]
+	}
+	return keys, slices.Clone(f.commands)
+}
+
+// testBackend is a Backend that keeps values in memory and ignores TTLs.
+type testBackend struct {
+	mtx    sync.Mutex
+	values map[string][]byte
+	err    error
+	calls  int
+}
+
+func (b *testBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.calls++
+	v, ok := b.values[key]
+	return v, ok, b.err
+}
+
+func (b *testBackend) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.calls++
+	if b.err == nil {
+		b.values[key] = value
+	}
+	return b.err
+}
+
+func (b *testBackend) Delete(_ context.Context, key string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.calls++
+	delete(b.values, key)
+	return b.err
+}
+
+func TestSharedCacheRedis(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedis(t, "secret")
+
+	config, err := ParseCachingConfig(fmt.Appendf(nil, `{
+		"inter_query_builtin_cache": {
+			"shared": {
+				"key_prefix": "test:",
+				"redis": {"address": %q, "password": "secret", "db": 2}
+			}
+		}
+	}`, fake.addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := t.Context()
+	a := NewInterQueryCacheWithContext(ctx, config)
+	b := NewInterQueryCacheWithContext(ctx, config)
+
+	k := ast.MustParseTerm(`{"url": "https://example.com"}`).Value
+	a.InsertWithExpiry(k, &testSharedValue{Data: "foo"}, time.Now().Add(time.Hour))
+
+	keys, commands := fake.snapshot()
+	if len(keys) != 1 {
+		t.Fatalf("expected one shared value, got %v", keys)
+	}
+	for key, ttl := range keys {
+		if !strings.HasPrefix(key, "test:") || ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Fatalf("unexpected shared key %q with ttl %v", key, ttl)
+		}
+
+		// keys can hold credentials, so only their hashes are stored
+		fake.mtx.Lock()
+		stored := string(fake.values[key])
+		fake.mtx.Unlock()
+		if strings.Contains(stored, "example.com") {
+			t.Fatalf("expected stored value without key, got %s", stored)
+		}
+	}
+	if !slices.Equal(commands, []string{"AUTH", "SELECT", "SET"}) {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+
+	// values missing locally are read from the shared backend
+	value, found := b.Get(k)
+	if !found || value.(*testSharedValue).Data != "foo" {
+		t.Fatalf("expected shared value, got %v (found: %v)", value, found)
+	}
+
+	// and then cached locally
+	if _, found := b.Get(k); !found {
+		t.Fatal("expected local value")
+	}
+	if _, commands := fake.snapshot(); !slices.Equal(commands, []string{"AUTH", "SELECT", "SET", "AUTH", "SELECT", "GET"}) {
+		t.Fatalf("expected one GET, got commands %v", commands)
+	}
+
+	// deleting removes the shared value
+	b.Delete(k)
+	if keys, _ := fake.snapshot(); len(keys) != 0 {
+		t.Fatalf("expected no shared values, got %v", keys)
+	}
+
+	// values that can't be serialized are only cached locally
+	k2 := ast.String("local")
+	a.Insert(k2, newInterQueryCacheValue(ast.String("bar"), 3))
+	if keys, _ := fake.snapshot(); len(keys) != 0 {
+		t.Fatalf("expected no shared values, got %v", keys)
+	}
+	if _, found := b.Get(k2); found {
+		t.Fatal("expected local value to be missing from other cache")
+	}
+}
+
+func TestSharedCacheRedisErrors(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedis(t, "secret")
+
+	for _, address := range []string{fake.addr, "127.0.0.1:1"} {
+		backend := newRedisBackend(&RedisCacheConfig{Address: address, Password: "wrong"})
+		defer backend.Close()
+
+		if _, _, err := backend.Get(t.Context(), "foo"); err == nil {
+			t.Fatalf("expected error for %v", address)
+		}
+	}
+}
+
+func TestSharedCacheFallback(t *testing.T) {
+	t.Parallel()
+
+	backend := &testBackend{values: map[string][]byte{}, err: errors.New("unavailable")}
+
+	c := NewInterQueryCache(nil)
+	c.(SharedInterQueryCache).SetBackend(backend)
+
+	// failing backends don't affect the local cache
+	k := ast.String("foo")
+	c.Insert(k, &testSharedValue{Data: "foo"})
+	if value, found := c.Get(k); !found || value.(*testSharedValue).Data != "foo" {
+		t.Fatalf("expected local value, got %v (found: %v)", value, found)
+	}
+
+	// and are skipped until the retry period has passed
+	if _, found := c.Get(ast.String("bar")); found {
+		t.Fatal("expected value to be missing")
+	}
+	if backend.calls != 1 {
+		t.Fatalf("expected one backend call, got %d", backend.calls)
+	}
+
+	backend.err = nil
+	c.(*cache).shared.failedAt = time.Now().Add(-time.Hour)
+
+	c.Insert(ast.String("bar"), &testSharedValue{Data: "bar"})
+	if backend.calls != 2 || len(backend.values) != 1 {
+		t.Fatalf("expected backend to be used again, got %d calls and values %v", backend.calls, backend.values)
+	}
+}
+
+func TestSharedCacheExpiry(t *testing.T) {
+	t.Parallel()
+
+	backend := &testBackend{values: map[string][]byte{}}
+
+	a := NewInterQueryCache(nil)
+	a.(SharedInterQueryCache).SetBackend(backend)
+	b := NewInterQueryCache(nil)
+	b.(SharedInterQueryCache).SetBackend(backend)
+
+	// expired values aren't written
+	a.InsertWithExpiry(ast.String("expired"), &testSharedValue{Data: "foo"}, time.Now().Add(-time.Second))
+	if len(backend.values) != 0 {
+		t.Fatalf("expected no shared values, got %v", backend.values)
+	}
+
+	// and values that expired in backends ignoring TTLs aren't read
+	a.InsertWithExpiry(ast.String("expiring"), &testSharedValue{Data: "foo"}, time.Now().Add(50*time.Millisecond))
+	if len(backend.values) != 1 {
+		t.Fatalf("expected one shared value, got %v", backend.values)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, found := b.Get(ast.String("expiring")); found {
+		t.Fatal("expected expired value to be missing")
+	}
+
+	// values without expiry are shared
+	a.Insert(ast.String("forever"), &testSharedValue{Data: "foo"})
+	if _, found := b.Get(ast.String("forever")); !found {
+		t.Fatal("expected shared value")
+	}
+}
+
+func TestSharedCacheUpdateConfig(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedis(t, "")
+
+	parse := func(prefix string) *Config {
+		t.Helper()
+		config, err := ParseCachingConfig(fmt.Appendf(nil, `{
+			"inter_query_builtin_cache": {"shared": {"key_prefix": %q, "redis": {"address": %q}}}
+		}`, prefix, fake.addr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+
+	c := NewInterQueryCache(nil).(*cache)
+	if c.shared != nil {
+		t.Fatal("expected no shared backend")
+	}
+
+	c.UpdateConfig(parse("a:"))
+	first := c.shared
+	if first == nil || first.prefix != "a:" {
+		t.Fatalf("expected shared backend, got %+v", first)
+	}
+
+	// unchanged configurations keep the backend
+	c.UpdateConfig(parse("a:"))
+	if c.shared != first {
+		t.Fatal("expected shared backend to be kept")
+	}
+
+	c.UpdateConfig(parse("b:"))
+	if c.shared == first || c.shared.prefix != "b:" {
+		t.Fatalf("expected new shared backend, got %+v", c.shared)
+	}
+
+	// custom backends take precedence over the configuration
+	backend := &testBackend{values: map[string][]byte{}}
+	c.SetBackend(backend)
+	c.UpdateConfig(parse("c:"))
+	if c.shared.backend != backend || c.shared.prefix != "c:" {
+		t.Fatalf("expected custom backend, got %+v", c.shared)
+	}
+
+	c.SetBackend(nil)
+	if _, ok := c.shared.backend.(*redisBackend); !ok {
+		t.Fatalf("expected configured backend, got %+v", c.shared)
+	}
+}
+
+func TestParseCachingConfigShared(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"missing address": {
+			input: `{"inter_query_builtin_cache": {"shared": {"redis": {}}}}`,
+			err:   "missing shared redis address",
+		},
+		"invalid timeout": {
+			input: `{"inter_query_builtin_cache": {"shared": {"timeout_ms": 0}}}`,
+			err:   "invalid shared timeout_ms 0",
+		},
+		"invalid retry period": {
+			input: `{"inter_query_builtin_cache": {"shared": {"retry_period_seconds": -1}}}`,
+			err:   "invalid shared retry_period_seconds -1",
+		},
+		"invalid db": {
+			input: `{"inter_query_builtin_cache": {"shared": {"redis": {"address": "localhost:6379", "db": -1}}}}`,
+			err:   "invalid shared redis db -1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseCachingConfig([]byte(tc.input))
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+
+	config, err := ParseCachingConfig([]byte(`{"inter_query_builtin_cache": {"shared": {"redis": {"address": "localhost:6379"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := config.InterQueryBuiltinCache.Shared
+	if *shared.KeyPrefix != defaultSharedKeyPrefix || *shared.TimeoutMillis != defaultSharedTimeoutMillis ||
+		*shared.RetryPeriodSeconds != defaultSharedRetryPeriodSeconds || *shared.Redis.MaxIdleConnections != defaultRedisMaxIdleConnections {
+		t.Fatalf("expected defaults, got %+v", shared)
+	}
+
+	clone := config.Clone()
+	*clone.InterQueryBuiltinCache.Shared.Redis.MaxIdleConnections = 1
+	if *shared.Redis.MaxIdleConnections != defaultRedisMaxIdleConnections {
+		t.Fatal("expected clone to be a deep copy")
+	}
+}
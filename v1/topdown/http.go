@@ -41,6 +41,11 @@ const (
 	httpSendInterQueryCacheHits              = httpSendLatencyMetricKey + "_interquery_cache_hits"
 	httpSendNetworkRequests                  = httpSendLatencyMetricKey + "_network_requests"
 
+	// interQueryCacheValueType and interQueryCacheDataType are the types of the
+	// http.send values stored in shared inter-query cache backends.
+	interQueryCacheValueType = "http.send"
+	interQueryCacheDataType  = "http.send/deserialized"
+
 	// httpSendBuiltinCacheKey is the key in the builtin context cache that
 	// points to the http.send() specific cache resides at.
 	httpSendBuiltinCacheKey httpSendKey = "HTTP_SEND_CACHE_KEY"
@@ -300,6 +305,17 @@ func init() {
 	createCacheableHTTPStatusCodes()
 	initDefaults()
 	RegisterBuiltinFunc(ast.HTTPSend.Name, builtinHTTPSend)
+
+	cache.RegisterInterQueryCacheValueType(interQueryCacheValueType, func(bs []byte) (cache.InterQueryCacheValue, error) {
+		return &interQueryCacheValue{Data: bs}, nil
+	})
+	cache.RegisterInterQueryCacheValueType(interQueryCacheDataType, func(bs []byte) (cache.InterQueryCacheValue, error) {
+		var data interQueryCacheData
+		if err := util.UnmarshalJSON(bs, &data); err != nil {
+			return nil, err
+		}
+		return &data, nil
+	})
 }
 
 func handleHTTPSendErr(ctx context.Context, loc *ast.Location, err error) error {
@@ -1096,6 +1112,16 @@ func (cb interQueryCacheValue) SizeInBytes() int64 {
 	return int64(len(cb.Data))
 }
 
+func (interQueryCacheValue) InterQueryCacheValueType() string {
+	return interQueryCacheValueType
+}
+
+// MarshalBinary returns the serialized response, which is how the value is
+// held in memory too.
+func (cb interQueryCacheValue) MarshalBinary() ([]byte, error) {
+	return cb.Data, nil
+}
+
 func (cb *interQueryCacheValue) copyCacheData() (res *interQueryCacheData, err error) {
 	err = util.UnmarshalJSON(cb.Data, &res)
 	return res, err
@@ -1160,6 +1186,14 @@ func (*interQueryCacheData) SizeInBytes() int64 {
 	return 0
 }
 
+func (*interQueryCacheData) InterQueryCacheValueType() string {
+	return interQueryCacheDataType
+}
+
+func (c *interQueryCacheData) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
 func (c *interQueryCacheData) Clone() (cache.InterQueryCacheValue, error) {
 	return &interQueryCacheData{
 		ExpiresAt:  c.ExpiresAt,
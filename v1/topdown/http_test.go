@@ -20,10 +20,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -33,6 +36,7 @@ import (
 	"github.com/open-policy-agent/opa/v1/topdown/builtins"
 	"github.com/open-policy-agent/opa/v1/tracing"
 	"github.com/open-policy-agent/opa/v1/util"
+	"github.com/open-policy-agent/opa/v1/util/test"
 
 	inmem "github.com/open-policy-agent/opa/v1/storage/inmem/test"
 	iCache "github.com/open-policy-agent/opa/v1/topdown/cache"
@@ -1924,6 +1928,126 @@ func TestHTTPSendInterQueryCachingNewResp(t *testing.T) {
 	}
 }
 
+// testSharedCacheBackend is an in-memory shared inter-query cache backend.
+type testSharedCacheBackend struct {
+	mtx    sync.Mutex
+	values map[string][]byte
+}
+
+func (b *testSharedCacheBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	v, ok := b.values[key]
+	return v, ok, nil
+}
+
+func (b *testSharedCacheBackend) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.values[key] = value
+	return nil
+}
+
+func (b *testSharedCacheBackend) Delete(_ context.Context, key string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	delete(b.values, key)
+	return nil
+}
+
+func TestHTTPSendInterQuerySharedCaching(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []cachingMode{defaultCachingMode, cachingModeDeserialized} {
+		t.Run(string(mode), func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Cache-Control", "max-age=290304000, public")
+				_, _ = w.Write([]byte(`{"x": 1}`))
+			}))
+			defer ts.Close()
+
+			backend := &testSharedCacheBackend{values: map[string][]byte{}}
+			query := fmt.Sprintf(`http.send({"method": "get", "url": %q, "force_json_decode": true, "cache": true, "caching_mode": %q}, x)`, ts.URL, mode)
+
+			// each query is run by an instance with its own cache, sharing the backend
+			for i := range 3 {
+				interQueryCache := iCache.NewInterQueryCache(nil)
+				interQueryCache.(iCache.SharedInterQueryCache).SetBackend(backend)
+
+				res, err := NewQuery(ast.MustParseBody(query)).
+					WithCompiler(ast.NewCompiler()).
+					WithInterQueryBuiltinCache(interQueryCache).
+					WithStore(inmem.New()).
+					Run(t.Context())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				body := res[0]["x"].Value.(ast.Object).Get(ast.StringTerm("body"))
+				if exp := ast.MustParseTerm(`{"x": 1}`); !exp.Equal(body) {
+					t.Fatalf("expected body %v on query %d, got %v", exp, i, body)
+				}
+			}
+
+			if n := requests.Load(); n != 1 {
+				t.Fatalf("expected 1 request, got %d", n)
+			}
+		})
+	}
+}
+
+func TestHTTPSendInterQueryDiskCaching(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "max-age=290304000, public")
+		_, _ = w.Write([]byte(`{"x": 1}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	config, err := iCache.ParseCachingConfig(fmt.Appendf(nil, `{"inter_query_builtin_cache": {"disk": {"directory": %q}}}`, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := fmt.Sprintf(`http.send({"method": "get", "url": %q, "force_json_decode": true, "cache": true}, x)`, ts.URL)
+
+	// each query is run after a restart, with a new cache reading the persisted values
+	for i := range 3 {
+		if i > 0 {
+			// values are written in the background
+			test.EventuallyOrFatal(t, 5*time.Second, func() bool {
+				files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+				return len(files) == 1
+			})
+		}
+
+		res, err := NewQuery(ast.MustParseBody(query)).
+			WithCompiler(ast.NewCompiler()).
+			WithInterQueryBuiltinCache(iCache.NewInterQueryCache(config)).
+			WithStore(inmem.New()).
+			Run(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := res[0]["x"].Value.(ast.Object).Get(ast.StringTerm("body"))
+		if exp := ast.MustParseTerm(`{"x": 1}`); !exp.Equal(body) {
+			t.Fatalf("expected body %v on query %d, got %v", exp, i, body)
+		}
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+}
+
 func newQuery(qStr string, t0 time.Time) *Query {
 	config, _ := iCache.ParseCachingConfig([]byte(`{"inter_query_builtin_cache": {"max_size_bytes": 500, "stale_entry_eviction_period_seconds": 1, "forced_eviction_threshold_percentage": 80},}`))
 	interQueryCache := iCache.NewInterQueryCacheWithContext(context.Background(), config)
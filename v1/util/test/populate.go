@@ -95,6 +95,16 @@ func populateDefaultTypes(t *testing.T, fieldType reflect.Type, fieldValue refle
 			return true
 		}
 
+	case reflect.String:
+		fieldValue.SetString(fmt.Sprintf("test-value-%d", index))
+
+		return true
+
+	case reflect.Int:
+		fieldValue.SetInt(int64(100 + index))
+
+		return true
+
 	case reflect.Bool:
 		fieldValue.SetBool(true)
 